@@ -2,13 +2,14 @@ package api
 
 import (
 	"database/sql"
-	"fmt"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/julianinsua/the_simp_bank/internal/database"
 	"github.com/julianinsua/the_simp_bank/token"
+	"github.com/julianinsua/the_simp_bank/util"
 	"github.com/lib/pq"
 )
 
@@ -16,39 +17,64 @@ import (
 Account creation body
 */
 type createAccountRequest struct {
-	Currency string `json:"Currency" binding:"required,currency"`
+	Currency string     `json:"Currency" binding:"required,currency"`
+	Name     string     `json:"name" binding:"omitempty,max=64"`
+	ParentID *uuid.UUID `json:"parentId"` // Creates a pot inside one of the user's accounts
 }
 
+var (
+	errAccountNameTaken = errors.New("an account with this name already exists")
+)
+
 /*
-Account creation handler
+Account creation handler. Accounts are named after their currency unless a name is given, so a second account in the
+same currency needs its own name.
 */
 func (s Server) createAccount(ctx *gin.Context) {
 	var req createAccountRequest
 	err := ctx.ShouldBindJSON(&req)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondWithError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
 
+	if !s.accountCreationAllowed(ctx, authPayload.Username) {
+		return
+	}
+
 	params := database.CreateAccountParams{
 		Owner:    authPayload.Username,
 		Balance:  0.0,
 		Currency: req.Currency,
+		Name:     req.Name,
+	}
+	if params.Name == "" {
+		params.Name = req.Currency
 	}
 
-	acc, err := s.store.CreateAccount(ctx, params)
-	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok {
+	if req.ParentID != nil {
+		if !s.validParentAccount(ctx, *req.ParentID, authPayload.Username, req.Currency) {
+			return
+		}
+		params.ParentID = uuid.NullUUID{UUID: *req.ParentID, Valid: true}
+	}
 
+	acc, err := s.store.CreateAccountTx(ctx, params)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
 			switch pqErr.Code.Name() {
-			case "foreign_key_violation", "unique_violation":
-				ctx.JSON(http.StatusForbidden, errorResponse(err))
+			case "unique_violation":
+				respondWithError(ctx, http.StatusConflict, errAccountNameTaken)
+				return
+			case "foreign_key_violation":
+				respondWithError(ctx, http.StatusForbidden, err)
 				return
 			}
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondWithError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -69,61 +95,111 @@ func (s Server) getAccount(ctx *gin.Context) {
 	var req GetAccountRequest
 	err := ctx.ShouldBindUri(&req)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondWithError(ctx, http.StatusBadRequest, err)
 		return
 	}
 	accID, err := uuid.Parse(req.ID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondWithError(ctx, http.StatusBadRequest, err)
 		return
 	}
 	acc, err := s.store.GetAccount(ctx, accID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			respondWithError(ctx, http.StatusNotFound, err)
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondWithError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
-	if acc.Owner != authPayload.Username {
-		err := fmt.Errorf("User %s declared in token is unauthorized to access account %s", authPayload.Username, accID.String())
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	if !s.accountViewAllowed(ctx, acc.ID, authPayload.Username) {
 		return
 	}
 	ctx.JSON(http.StatusOK, acc)
 }
 
 /*
-Get Account List Query parameters
+A page of accounts along with the total balance per currency across every account the user holds
 */
+type accountListResponse struct {
+	Accounts []database.Account                       `json:"accounts"`
+	Balances []database.ListHolderCurrencyBalancesRow `json:"balances"`
+	pageLinks
+}
 
-type GetAccountListRequest struct {
-	Page int32 `form:"page" binding:"required,min=1"`
-	Size int32 `form:"size" binding:"required,min=5,max=10"`
+func accountCursor(acc database.Account) util.PageCursor {
+	return util.PageCursor{CreatedAt: acc.CreatedAt, ID: acc.ID}
 }
 
 /*
-Get account list handler
+Get account list handler, lists every account the user holds oldest first
 */
 func (s Server) getAccountList(ctx *gin.Context) {
-	var req GetAccountListRequest
-	err := ctx.ShouldBindQuery(&req)
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+	cur, size, ok := s.bindPage(ctx)
+	if !ok {
 		return
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
-	accs, err := s.store.GetAccountsList(ctx, database.GetAccountsListParams{
-		Owner:  authPayload.Username,
-		Limit:  req.Size,
-		Offset: (req.Page - 1) * req.Size,
+	var accs []database.Account
+	var err error
+	if cur.Before {
+		accs, err = s.store.ListHolderAccountsBefore(ctx, database.ListHolderAccountsBeforeParams{
+			Username:        authPayload.Username,
+			CursorCreatedAt: cur.CreatedAt,
+			CursorID:        cur.ID,
+			PageSize:        size + 1,
+		})
+	} else {
+		accs, err = s.store.ListHolderAccountsAfter(ctx, database.ListHolderAccountsAfterParams{
+			Username:        authPayload.Username,
+			CursorCreatedAt: cur.CreatedAt,
+			CursorID:        cur.ID,
+			PageSize:        size + 1,
+		})
+	}
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	accs, next, prev := util.Paginate(accs, size, cur, accountCursor)
+
+	balances, err := s.store.ListHolderCurrencyBalances(ctx, authPayload.Username)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, accountListResponse{
+		Accounts:  accs,
+		Balances:  balances,
+		pageLinks: newPageLinks(ctx, size, next, prev),
 	})
+}
+
+/*
+Lists the pots inside an account
+*/
+func (s Server) listSubAccounts(ctx *gin.Context) {
+	var uri accountHolderURI
+	err := ctx.ShouldBindUri(&uri)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	accID := uuid.MustParse(uri.ID)
+
+	if !s.accountViewAllowed(ctx, accID, authPayload.Username) {
+		return
+	}
+
+	accs, err := s.store.ListSubAccounts(ctx, uuid.NullUUID{UUID: accID, Valid: true})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondWithError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/token"
+)
+
+/*
+Admin handler to freeze an account. Frozen accounts can neither send nor receive transfers until they're unfrozen,
+freezing an account that is already frozen keeps the original freeze
+*/
+func (srv *Server) freezeAccount(ctx *gin.Context) {
+	accID, ok := bindAccountID(ctx)
+	if !ok {
+		return
+	}
+
+	acc, err := srv.store.GetAccount(ctx, accID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if acc.FrozenAt.Valid {
+		ctx.JSON(http.StatusOK, acc)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	acc, err = srv.store.FreezeAccount(ctx, database.FreezeAccountParams{
+		ID:       accID,
+		FrozenBy: sql.NullString{String: authPayload.Username, Valid: true},
+	})
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, acc)
+}
+
+/*
+Admin handler to lift the freeze of an account
+*/
+func (srv *Server) unfreezeAccount(ctx *gin.Context) {
+	accID, ok := bindAccountID(ctx)
+	if !ok {
+		return
+	}
+
+	acc, err := srv.store.UnfreezeAccount(ctx, accID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, acc)
+}
+
+/*
+Binds the account id of the url, responds with a bad request when it's missing or not a uuid
+*/
+func bindAccountID(ctx *gin.Context) (uuid.UUID, bool) {
+	var req GetAccountRequest
+	err := ctx.ShouldBindUri(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return uuid.UUID{}, false
+	}
+	accID, err := uuid.Parse(req.ID)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return uuid.UUID{}, false
+	}
+	return accID, true
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/token"
+	"github.com/lib/pq"
+)
+
+var (
+	errGrantExpired    = errors.New("grant expiry must be in the future")
+	errGrantToSelf     = errors.New("grants can't be given to yourself")
+	errGrantNotFound   = errors.New("there is no active grant with this id on the account")
+	errGranteeNotFound = errors.New("grantee does not exist")
+)
+
+type accountGrantURI struct {
+	ID      string `uri:"id" binding:"required,uuid"`
+	GrantID string `uri:"grantId" binding:"required,uuid"`
+}
+
+type createAccountGrantRequest struct {
+	Grantee       string    `json:"grantee" binding:"required,alphanum"`
+	TransferLimit float64   `json:"transferLimit" binding:"gte=0"`
+	CanApprove    bool      `json:"canApprove"`
+	ExpiresAt     time.Time `json:"expiresAt" binding:"required"`
+}
+
+/*
+Delegates access to an account to another user, only the account owner can grant access. Every grant allows viewing
+the account, a transfer limit above zero allows transferring up to that amount in total over the life of the grant.
+A new grant replaces whatever grant the grantee held on the account.
+*/
+func (s Server) createAccountGrant(ctx *gin.Context) {
+	var uri accountHolderURI
+	err := ctx.ShouldBindUri(&uri)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var req createAccountGrantRequest
+	err = ctx.ShouldBindJSON(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if !req.ExpiresAt.After(time.Now()) {
+		respondWithError(ctx, http.StatusBadRequest, errGrantExpired)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	if req.Grantee == authPayload.Username {
+		respondWithError(ctx, http.StatusBadRequest, errGrantToSelf)
+		return
+	}
+
+	accID := uuid.MustParse(uri.ID)
+	_, allowed := s.accountHolderAllowed(ctx, accID, authPayload.Username, database.AccountHolderOwner)
+	if !allowed {
+		return
+	}
+
+	grant, err := s.store.CreateAccountGrantTx(ctx, database.CreateAccountGrantParams{
+		AccountID:     accID,
+		Grantor:       authPayload.Username,
+		Grantee:       req.Grantee,
+		TransferLimit: req.TransferLimit,
+		CanApprove:    req.CanApprove,
+		ExpiresAt:     req.ExpiresAt,
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code.Name() == "foreign_key_violation" {
+			respondWithError(ctx, http.StatusNotFound, errGranteeNotFound)
+			return
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, grant)
+}
+
+/*
+Lists every grant ever given on an account, including expired and revoked ones. Only the owner can see them.
+*/
+func (s Server) listAccountGrants(ctx *gin.Context) {
+	var uri accountHolderURI
+	err := ctx.ShouldBindUri(&uri)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	accID := uuid.MustParse(uri.ID)
+
+	_, allowed := s.accountHolderAllowed(ctx, accID, authPayload.Username, database.AccountHolderOwner)
+	if !allowed {
+		return
+	}
+
+	grants, err := s.store.ListAccountGrants(ctx, accID)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, grants)
+}
+
+/*
+Revokes an active grant, effective immediately
+*/
+func (s Server) revokeAccountGrant(ctx *gin.Context) {
+	var uri accountGrantURI
+	err := ctx.ShouldBindUri(&uri)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	accID := uuid.MustParse(uri.ID)
+
+	_, allowed := s.accountHolderAllowed(ctx, accID, authPayload.Username, database.AccountHolderOwner)
+	if !allowed {
+		return
+	}
+
+	_, err = s.store.RevokeAccountGrant(ctx, database.RevokeAccountGrantParams{
+		ID:        uuid.MustParse(uri.GrantID),
+		AccountID: accID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(ctx, http.StatusNotFound, errGrantNotFound)
+			return
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+/*
+Lists the active grants the authenticated user received
+*/
+func (s Server) listReceivedGrants(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+
+	grants, err := s.store.ListGranteeGrants(ctx, authPayload.Username)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, grants)
+}
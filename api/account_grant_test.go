@@ -0,0 +1,281 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/policy"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+// Stubs a missing holding and an active grant of the account to the user, with transferred already moved under it
+func stubAccountGrant(store *mock_db.MockStore, accountID uuid.UUID, grantee string, transferLimit, transferred float64) database.AccountGrant {
+	grant := database.AccountGrant{
+		ID:            uuid.New(),
+		AccountID:     accountID,
+		Grantee:       grantee,
+		TransferLimit: transferLimit,
+		Transferred:   transferred,
+		ExpiresAt:     time.Now().Add(time.Hour),
+	}
+	store.EXPECT().GetAccountHolder(gomock.Any(), gomock.Any()).Times(1).Return(database.AccountHolder{}, sql.ErrNoRows)
+	store.EXPECT().
+		GetActiveAccountGrant(gomock.Any(), gomock.Eq(database.GetActiveAccountGrantParams{AccountID: accountID, Grantee: grantee})).
+		Times(1).
+		Return(grant, nil)
+	return grant
+}
+
+func TestCreateAccountGrant(t *testing.T) {
+	owner, _ := randomUser(t)
+	grantee, _ := randomUser(t)
+	account := randomAccount(owner.Username)
+	expiresAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"grantee": grantee.Username, "transferLimit": 500, "expiresAt": expiresAt},
+			buildStubs: func(store *mock_db.MockStore) {
+				stubAccountHolder(store, account.ID, owner.Username, database.AccountHolderOwner)
+				store.EXPECT().
+					CreateAccountGrantTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg database.CreateAccountGrantParams) (database.AccountGrant, error) {
+						require.Equal(t, account.ID, arg.AccountID)
+						require.Equal(t, owner.Username, arg.Grantor)
+						require.Equal(t, grantee.Username, arg.Grantee)
+						require.Equal(t, float64(500), arg.TransferLimit)
+						require.True(t, expiresAt.Equal(arg.ExpiresAt))
+						return database.AccountGrant{ID: uuid.New()}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "Expired",
+			body: gin.H{"grantee": grantee.Username, "expiresAt": time.Now().Add(-time.Minute)},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().CreateAccountGrantTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyError(t, recorder.Body, errGrantExpired)
+			},
+		},
+		{
+			name: "ToSelf",
+			body: gin.H{"grantee": owner.Username, "expiresAt": expiresAt},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().CreateAccountGrantTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NegativeLimit",
+			body: gin.H{"grantee": grantee.Username, "transferLimit": -1, "expiresAt": expiresAt},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().CreateAccountGrantTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotOwner",
+			body: gin.H{"grantee": grantee.Username, "expiresAt": expiresAt},
+			buildStubs: func(store *mock_db.MockStore) {
+				stubAccountHolder(store, account.ID, owner.Username, database.AccountHolderCoOwner)
+				store.EXPECT().CreateAccountGrantTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/accounts/%s/grants", account.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, owner.Username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestRevokeAccountGrant(t *testing.T) {
+	owner, _ := randomUser(t)
+	accountID := uuid.New()
+	grantID := uuid.New()
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mock_db.MockStore) {
+				stubAccountHolder(store, accountID, owner.Username, database.AccountHolderOwner)
+				store.EXPECT().
+					RevokeAccountGrant(gomock.Any(), gomock.Eq(database.RevokeAccountGrantParams{ID: grantID, AccountID: accountID})).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyRevoked",
+			buildStubs: func(store *mock_db.MockStore) {
+				stubAccountHolder(store, accountID, owner.Username, database.AccountHolderOwner)
+				store.EXPECT().
+					RevokeAccountGrant(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(database.AccountGrant{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%s/grants/%s", accountID, grantID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, owner.Username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestAccountTransferGrant(t *testing.T) {
+	accountID := uuid.New()
+	grantee := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		transferLimit float64
+		transferred   float64
+		amount        float64
+		allowed       bool
+		expectedCode  int
+		expectedError error
+	}{
+		{
+			name:          "WithinLimit",
+			transferLimit: 500,
+			amount:        500,
+			allowed:       true,
+			expectedCode:  http.StatusOK,
+		},
+		{
+			name:          "OverLimit",
+			transferLimit: 500,
+			amount:        501,
+			allowed:       false,
+			expectedCode:  http.StatusForbidden,
+			expectedError: policy.ErrGrantLimitExceeded,
+		},
+		{
+			name:          "WithinWhatIsLeft",
+			transferLimit: 500,
+			transferred:   400,
+			amount:        100,
+			allowed:       true,
+			expectedCode:  http.StatusOK,
+		},
+		{
+			name:          "LimitUsedUp",
+			transferLimit: 500,
+			transferred:   450,
+			amount:        100,
+			allowed:       false,
+			expectedCode:  http.StatusForbidden,
+			expectedError: policy.ErrGrantLimitExceeded,
+		},
+		{
+			name:          "ViewOnly",
+			amount:        1,
+			allowed:       false,
+			expectedCode:  http.StatusForbidden,
+			expectedError: policy.ErrGrantViewOnly,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			grant := stubAccountGrant(store, accountID, grantee, tc.transferLimit, tc.transferred)
+
+			server := newTestServer(t, store)
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/transfers", nil)
+
+			grantID, allowed := server.accountTransferGrant(ctx, accountID, grantee, tc.amount)
+			require.Equal(t, tc.allowed, allowed)
+			require.Equal(t, tc.expectedCode, recorder.Code)
+			if tc.allowed {
+				require.Equal(t, uuid.NullUUID{UUID: grant.ID, Valid: true}, grantID)
+			}
+			if tc.expectedError != nil {
+				require.Contains(t, recorder.Body.String(), tc.expectedError.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/token"
+	"github.com/lib/pq"
+)
+
+var (
+	errAlreadyAccountHolder = errors.New("user already holds or was invited to this account")
+	errNoAccountInvitation  = errors.New("there is no pending invitation to this account")
+	errAccountHolderMissing = errors.New("user does not hold this account")
+	errInviteeNotFound      = errors.New("invited user does not exist")
+)
+
+type accountHolderURI struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+type inviteAccountHolderRequest struct {
+	Username string `json:"username" binding:"required,alphanum"`
+	Role     string `json:"role" binding:"required,oneof=co_owner viewer"`
+}
+
+/*
+Invites another user to hold an account, only the account owner can invite
+*/
+func (s Server) inviteAccountHolder(ctx *gin.Context) {
+	var uri accountHolderURI
+	err := ctx.ShouldBindUri(&uri)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var req inviteAccountHolderRequest
+	err = ctx.ShouldBindJSON(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	accID := uuid.MustParse(uri.ID)
+
+	_, allowed := s.accountHolderAllowed(ctx, accID, authPayload.Username, database.AccountHolderOwner)
+	if !allowed {
+		return
+	}
+
+	holder, err := s.store.CreateAccountHolder(ctx, database.CreateAccountHolderParams{
+		AccountID: accID,
+		Username:  req.Username,
+		Role:      req.Role,
+		InvitedBy: authPayload.Username,
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				respondWithError(ctx, http.StatusConflict, errAlreadyAccountHolder)
+				return
+			case "foreign_key_violation":
+				respondWithError(ctx, http.StatusNotFound, errInviteeNotFound)
+				return
+			}
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, holder)
+}
+
+/*
+Lists everyone holding or invited to an account, visible to any of its holders
+*/
+func (s Server) listAccountHolders(ctx *gin.Context) {
+	var uri accountHolderURI
+	err := ctx.ShouldBindUri(&uri)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	accID := uuid.MustParse(uri.ID)
+
+	_, allowed := s.accountHolderAllowed(ctx, accID, authPayload.Username)
+	if !allowed {
+		return
+	}
+
+	holders, err := s.store.ListAccountHolders(ctx, accID)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, holders)
+}
+
+/*
+Accepts the authenticated user's pending invitation to an account
+*/
+func (s Server) acceptAccountInvitation(ctx *gin.Context) {
+	var uri accountHolderURI
+	err := ctx.ShouldBindUri(&uri)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+
+	holder, err := s.store.AcceptAccountHolder(ctx, database.AcceptAccountHolderParams{
+		AccountID: uuid.MustParse(uri.ID),
+		Username:  authPayload.Username,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(ctx, http.StatusNotFound, errNoAccountInvitation)
+			return
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, holder)
+}
+
+/*
+Lists the authenticated user's pending account invitations
+*/
+func (s Server) listAccountInvitations(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+
+	invitations, err := s.store.ListPendingAccountInvitations(ctx, authPayload.Username)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, invitations)
+}
+
+type removeAccountHolderURI struct {
+	ID       string `uri:"id" binding:"required,uuid"`
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+/*
+Removes a holder from an account. The owner can remove anyone else and holders can remove themselves, which also
+declines a pending invitation. The owner itself can't be removed.
+*/
+func (s Server) removeAccountHolder(ctx *gin.Context) {
+	var uri removeAccountHolderURI
+	err := ctx.ShouldBindUri(&uri)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	accID := uuid.MustParse(uri.ID)
+
+	if uri.Username != authPayload.Username {
+		_, allowed := s.accountHolderAllowed(ctx, accID, authPayload.Username, database.AccountHolderOwner)
+		if !allowed {
+			return
+		}
+	}
+
+	removed, err := s.store.DeleteAccountHolder(ctx, database.DeleteAccountHolderParams{
+		AccountID: accID,
+		Username:  uri.Username,
+	})
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if removed == 0 {
+		respondWithError(ctx, http.StatusNotFound, errAccountHolderMissing)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,356 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+// Stubs an accepted holding of the account by the user
+func stubAccountHolder(store *mock_db.MockStore, accountID uuid.UUID, username, role string) {
+	store.EXPECT().
+		GetAccountHolder(gomock.Any(), gomock.Eq(database.GetAccountHolderParams{AccountID: accountID, Username: username})).
+		Times(1).
+		Return(database.AccountHolder{
+			AccountID:  accountID,
+			Username:   username,
+			Role:       role,
+			AcceptedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		}, nil)
+}
+
+func TestInviteAccountHolder(t *testing.T) {
+	owner, _ := randomUser(t)
+	invitee, _ := randomUser(t)
+	account := randomAccount(owner.Username)
+
+	testCases := []struct {
+		name          string
+		username      string
+		body          gin.H
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: owner.Username,
+			body:     gin.H{"username": invitee.Username, "role": database.AccountHolderCoOwner},
+			buildStubs: func(store *mock_db.MockStore) {
+				stubAccountHolder(store, account.ID, owner.Username, database.AccountHolderOwner)
+				store.EXPECT().
+					CreateAccountHolder(gomock.Any(), gomock.Eq(database.CreateAccountHolderParams{
+						AccountID: account.ID,
+						Username:  invitee.Username,
+						Role:      database.AccountHolderCoOwner,
+						InvitedBy: owner.Username,
+					})).
+					Times(1).
+					Return(database.AccountHolder{AccountID: account.ID, Username: invitee.Username}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:     "CoOwnerCantInvite",
+			username: invitee.Username,
+			body:     gin.H{"username": "someone", "role": database.AccountHolderViewer},
+			buildStubs: func(store *mock_db.MockStore) {
+				stubAccountHolder(store, account.ID, invitee.Username, database.AccountHolderCoOwner)
+				store.EXPECT().CreateAccountHolder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "OwnerRoleNotAllowed",
+			username: owner.Username,
+			body:     gin.H{"username": invitee.Username, "role": database.AccountHolderOwner},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().CreateAccountHolder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "AlreadyHolder",
+			username: owner.Username,
+			body:     gin.H{"username": invitee.Username, "role": database.AccountHolderViewer},
+			buildStubs: func(store *mock_db.MockStore) {
+				stubAccountHolder(store, account.ID, owner.Username, database.AccountHolderOwner)
+				store.EXPECT().
+					CreateAccountHolder(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(database.AccountHolder{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireBodyError(t, recorder.Body, errAlreadyAccountHolder)
+			},
+		},
+		{
+			name:     "UnknownInvitee",
+			username: owner.Username,
+			body:     gin.H{"username": "nobody", "role": database.AccountHolderViewer},
+			buildStubs: func(store *mock_db.MockStore) {
+				stubAccountHolder(store, account.ID, owner.Username, database.AccountHolderOwner)
+				store.EXPECT().
+					CreateAccountHolder(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(database.AccountHolder{}, &pq.Error{Code: "23503"})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/accounts/%s/holders", account.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestAcceptAccountInvitation(t *testing.T) {
+	invitee, _ := randomUser(t)
+	accountID := uuid.New()
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().
+					AcceptAccountHolder(gomock.Any(), gomock.Eq(database.AcceptAccountHolderParams{AccountID: accountID, Username: invitee.Username})).
+					Times(1).
+					Return(database.AccountHolder{AccountID: accountID, Username: invitee.Username}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NoInvitation",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().
+					AcceptAccountHolder(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(database.AccountHolder{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireBodyError(t, recorder.Body, errNoAccountInvitation)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%s/holders/accept", accountID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, invitee.Username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestRemoveAccountHolder(t *testing.T) {
+	owner, _ := randomUser(t)
+	holder, _ := randomUser(t)
+	accountID := uuid.New()
+
+	testCases := []struct {
+		name          string
+		username      string
+		target        string
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OwnerRemovesHolder",
+			username: owner.Username,
+			target:   holder.Username,
+			buildStubs: func(store *mock_db.MockStore) {
+				stubAccountHolder(store, accountID, owner.Username, database.AccountHolderOwner)
+				store.EXPECT().
+					DeleteAccountHolder(gomock.Any(), gomock.Eq(database.DeleteAccountHolderParams{AccountID: accountID, Username: holder.Username})).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		{
+			name:     "HolderLeaves",
+			username: holder.Username,
+			target:   holder.Username,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccountHolder(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().DeleteAccountHolder(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		{
+			name:     "HolderRemovesOwner",
+			username: holder.Username,
+			target:   owner.Username,
+			buildStubs: func(store *mock_db.MockStore) {
+				stubAccountHolder(store, accountID, holder.Username, database.AccountHolderCoOwner)
+				store.EXPECT().DeleteAccountHolder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "OwnerCantLeave",
+			username: owner.Username,
+			target:   owner.Username,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().DeleteAccountHolder(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%s/holders/%s", accountID, tc.target)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestCreateTransferHolderRoles(t *testing.T) {
+	owner, _ := randomUser(t)
+	fromAccount := randomAccount(owner.Username)
+	toAccount := randomAccount(util.RandomOwner())
+	toAccount.Currency = fromAccount.Currency
+
+	testCases := []struct {
+		name          string
+		role          string
+		checkResponse func(recorder *httptest.ResponseRecorder)
+		transfers     int
+	}{
+		{
+			name:      "CoOwner",
+			role:      database.AccountHolderCoOwner,
+			transfers: 1,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "Viewer",
+			role:      database.AccountHolderViewer,
+			transfers: 0,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(tc.transfers).Return(toAccount, nil)
+			stubAccountHolder(store, fromAccount.ID, "holder", tc.role)
+			if tc.transfers == 0 {
+				store.EXPECT().GetActiveAccountGrant(gomock.Any(), gomock.Any()).Times(1).Return(database.AccountGrant{}, sql.ErrNoRows)
+			}
+			store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(tc.transfers)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"FromAccountId": fromAccount.ID,
+				"ToAccountId":   toAccount.ID,
+				"amount":        10,
+				"currency":      fromAccount.Currency,
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "holder", util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -11,12 +11,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
 	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/policy"
 	"github.com/julianinsua/the_simp_bank/token"
 	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 )
 
@@ -35,21 +38,65 @@ func TestGetAccountAPI(t *testing.T) {
 			name:      "OK",
 			accountID: account.ID.String(),
 			setupAuthFunc: func(t *testing.T, request *http.Request, maker token.PASETOMaker) {
-				addAuthorization(t, request, maker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, maker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
 			},
 			buildStubs: func(store *mock_db.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				stubAccountHolder(store, account.ID, user.Username, database.AccountHolderOwner)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 				compareResponse(t, recorder.Body, account)
 			},
 		},
+		{
+			name:      "Viewer",
+			accountID: account.ID.String(),
+			setupAuthFunc: func(t *testing.T, request *http.Request, maker token.PASETOMaker) {
+				addAuthorization(t, request, maker, authorizationTypeBearer, "viewer", util.CustomerRole, time.Minute)
+			},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				stubAccountHolder(store, account.ID, "viewer", database.AccountHolderViewer)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "Grantee",
+			accountID: account.ID.String(),
+			setupAuthFunc: func(t *testing.T, request *http.Request, maker token.PASETOMaker) {
+				addAuthorization(t, request, maker, authorizationTypeBearer, "accountant", util.CustomerRole, time.Minute)
+			},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				stubAccountGrant(store, account.ID, "accountant", 0, 0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "UnauthorizedUser",
+			accountID: account.ID.String(),
+			setupAuthFunc: func(t *testing.T, request *http.Request, maker token.PASETOMaker) {
+				addAuthorization(t, request, maker, authorizationTypeBearer, "stranger", util.CustomerRole, time.Minute)
+			},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountHolder(gomock.Any(), gomock.Any()).Times(1).Return(database.AccountHolder{}, sql.ErrNoRows)
+				store.EXPECT().GetActiveAccountGrant(gomock.Any(), gomock.Any()).Times(1).Return(database.AccountGrant{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
 		{
 			name:      "NotFound",
 			accountID: account.ID.String(),
 			setupAuthFunc: func(t *testing.T, request *http.Request, maker token.PASETOMaker) {
-				addAuthorization(t, request, maker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, maker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
 			},
 			buildStubs: func(store *mock_db.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(database.Account{}, sql.ErrNoRows)
@@ -62,7 +109,7 @@ func TestGetAccountAPI(t *testing.T) {
 			name:      "Internal",
 			accountID: account.ID.String(),
 			setupAuthFunc: func(t *testing.T, request *http.Request, maker token.PASETOMaker) {
-				addAuthorization(t, request, maker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, maker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
 			},
 			buildStubs: func(store *mock_db.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(database.Account{}, sql.ErrConnDone)
@@ -75,7 +122,7 @@ func TestGetAccountAPI(t *testing.T) {
 			name:      "BadRequest",
 			accountID: "asdf",
 			setupAuthFunc: func(t *testing.T, request *http.Request, maker token.PASETOMaker) {
-				addAuthorization(t, request, maker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, maker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
 			},
 			buildStubs: func(store *mock_db.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
@@ -113,6 +160,419 @@ func TestGetAccountAPI(t *testing.T) {
 
 }
 
+func TestCreateAccountAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	parent := randomAccount(user.Username)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "DefaultName",
+			body: gin.H{"Currency": util.USD},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().
+					CreateAccountTx(gomock.Any(), gomock.Eq(database.CreateAccountParams{
+						Owner:    user.Username,
+						Currency: util.USD,
+						Name:     util.USD,
+					})).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Pot",
+			body: gin.H{"Currency": parent.Currency, "name": "Holidays", "parentId": parent.ID},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(parent.ID)).Times(1).Return(parent, nil)
+				store.EXPECT().
+					CreateAccountTx(gomock.Any(), gomock.Eq(database.CreateAccountParams{
+						Owner:    user.Username,
+						Currency: parent.Currency,
+						Name:     "Holidays",
+						ParentID: uuid.NullUUID{UUID: parent.ID, Valid: true},
+					})).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "PotOfPot",
+			body: gin.H{"Currency": parent.Currency, "name": "Holidays", "parentId": parent.ID},
+			buildStubs: func(store *mock_db.MockStore) {
+				pot := parent
+				pot.ParentID = uuid.NullUUID{UUID: uuid.New(), Valid: true}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(parent.ID)).Times(1).Return(pot, nil)
+				store.EXPECT().CreateAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyError(t, recorder.Body, policy.ErrInvalidParent)
+			},
+		},
+		{
+			name: "ParentOfSomeoneElse",
+			body: gin.H{"Currency": parent.Currency, "name": "Holidays", "parentId": parent.ID},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(parent.ID)).
+					Times(1).
+					Return(randomAccount(util.RandomOwner()), nil)
+				store.EXPECT().CreateAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NameTaken",
+			body: gin.H{"Currency": util.USD},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().
+					CreateAccountTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(database.Account{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireBodyError(t, recorder.Body, errAccountNameTaken)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/accounts", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetAccountListAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	accounts := []database.Account{randomAccount(user.Username), randomAccount(user.Username), randomAccount(user.Username)}
+	for i := range accounts {
+		accounts[i].Currency = accounts[0].Currency
+		accounts[i].CreatedAt = time.Now().Add(time.Duration(i) * time.Minute).UTC().Truncate(time.Microsecond)
+	}
+	balances := []database.ListHolderCurrencyBalancesRow{{
+		Currency: accounts[0].Currency,
+		Balance:  accounts[0].Balance + accounts[1].Balance + accounts[2].Balance,
+		Accounts: 3,
+	}}
+	secondCursor := util.PageCursor{CreatedAt: accounts[1].CreatedAt, ID: accounts[1].ID}
+	thirdCursor := util.PageCursor{CreatedAt: accounts[2].CreatedAt, ID: accounts[2].ID, Before: true}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "FirstPage",
+			query: "size=2",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().
+					ListHolderAccountsAfter(gomock.Any(), gomock.Eq(database.ListHolderAccountsAfterParams{Username: user.Username, PageSize: 3})).
+					Times(1).
+					Return(accounts, nil)
+				store.EXPECT().ListHolderCurrencyBalances(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(balances, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res accountListResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Len(t, res.Accounts, 2)
+				require.Equal(t, balances, res.Balances)
+				require.Equal(t, "/accounts?cursor="+secondCursor.Encode()+"&size=2", res.Next)
+				require.Empty(t, res.Prev)
+			},
+		},
+		{
+			name:  "PreviousPage",
+			query: "size=2&cursor=" + thirdCursor.Encode(),
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().
+					ListHolderAccountsBefore(gomock.Any(), gomock.Eq(database.ListHolderAccountsBeforeParams{
+						Username:        user.Username,
+						CursorCreatedAt: accounts[2].CreatedAt,
+						CursorID:        accounts[2].ID,
+						PageSize:        3,
+					})).
+					Times(1).
+					Return([]database.Account{accounts[1], accounts[0]}, nil)
+				store.EXPECT().ListHolderCurrencyBalances(gomock.Any(), gomock.Any()).Times(1).Return(balances, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res accountListResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, []database.Account{accounts[0], accounts[1]}, res.Accounts)
+				require.NotEmpty(t, res.Next)
+				require.Empty(t, res.Prev)
+			},
+		},
+		{
+			name:  "DefaultSize",
+			query: "",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().
+					ListHolderAccountsAfter(gomock.Any(), gomock.Eq(database.ListHolderAccountsAfterParams{Username: user.Username, PageSize: 11})).
+					Times(1).
+					Return(accounts, nil)
+				store.EXPECT().ListHolderCurrencyBalances(gomock.Any(), gomock.Any()).Times(1).Return(balances, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res accountListResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Len(t, res.Accounts, 3)
+				require.Empty(t, res.Next)
+			},
+		},
+		{
+			name:  "InvalidCursor",
+			query: "cursor=not-a-cursor",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().ListHolderAccountsAfter(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyError(t, recorder.Body, util.ErrInvalidCursor)
+			},
+		},
+		{
+			name:  "PageSizeTooLarge",
+			query: "size=51",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().ListHolderAccountsAfter(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/accounts?"+tc.query, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCreateInternalTransferAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	fromAccount := randomAccount(user.Username)
+	toAccount := randomAccount(user.Username)
+	toAccount.Currency = fromAccount.Currency
+	toAccount.ParentID = uuid.NullUUID{UUID: fromAccount.ID, Valid: true}
+
+	// Both accounts are held by the user alone
+	stubUnshared := func(store *mock_db.MockStore, accIDs ...uuid.UUID) {
+		for _, accID := range accIDs {
+			store.EXPECT().ListAccountHolders(gomock.Any(), gomock.Eq(accID)).Times(1).
+				Return([]database.AccountHolder{{AccountID: accID, Username: user.Username, Role: database.AccountHolderOwner}}, nil)
+			store.EXPECT().ListAccountGrants(gomock.Any(), gomock.Eq(accID)).Times(1).
+				Return([]database.AccountGrant{{AccountID: accID, ExpiresAt: time.Now().Add(-time.Hour)}}, nil)
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		amount        float64
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			amount: fromAccount.Balance,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				stubAccountHolder(store, toAccount.ID, user.Username, database.AccountHolderOwner)
+				stubUnshared(store, fromAccount.ID, toAccount.ID)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Eq(database.TransferTxParams{
+						FromAccountID: fromAccount.ID,
+						ToAccountID:   toAccount.ID,
+						Amount:        fromAccount.Balance,
+					})).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "InsufficientFunds",
+			amount: fromAccount.Balance + 1,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				stubAccountHolder(store, toAccount.ID, user.Username, database.AccountHolderOwner)
+				stubUnshared(store, fromAccount.ID, toAccount.ID)
+				// The balance is only checked under lock, by the transfer itself
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(database.TransferTxResult{}, fmt.Errorf("unable to execute transaction: %w", database.ErrInsufficientFunds))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+				requireBodyErrorCode(t, recorder.Body, "insufficient_funds")
+			},
+		},
+		{
+			name:   "NotOwnerOfDestination",
+			amount: 1,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				stubAccountHolder(store, toAccount.ID, user.Username, database.AccountHolderViewer)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "NotAPot",
+			amount: 1,
+			buildStubs: func(store *mock_db.MockStore) {
+				unrelated := toAccount
+				unrelated.ParentID = uuid.NullUUID{}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(unrelated, nil)
+				stubAccountHolder(store, toAccount.ID, user.Username, database.AccountHolderOwner)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+				requireBodyErrorCode(t, recorder.Body, "not_own_pot")
+			},
+		},
+		{
+			name:   "SharedSource",
+			amount: 1,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				stubAccountHolder(store, toAccount.ID, user.Username, database.AccountHolderOwner)
+				store.EXPECT().ListAccountHolders(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).
+					Return([]database.AccountHolder{
+						{AccountID: fromAccount.ID, Username: user.Username, Role: database.AccountHolderOwner},
+						{AccountID: fromAccount.ID, Username: util.RandomOwner(), Role: database.AccountHolderCoOwner},
+					}, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+				requireBodyErrorCode(t, recorder.Body, "account_shared")
+			},
+		},
+		{
+			name:   "GrantedSource",
+			amount: 1,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				stubAccountHolder(store, toAccount.ID, user.Username, database.AccountHolderOwner)
+				store.EXPECT().ListAccountHolders(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(nil, nil)
+				store.EXPECT().ListAccountGrants(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).
+					Return([]database.AccountGrant{{AccountID: fromAccount.ID, Grantee: util.RandomOwner(), ExpiresAt: time.Now().Add(time.Hour)}}, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+				requireBodyErrorCode(t, recorder.Body, "account_shared")
+			},
+		},
+		{
+			name:   "ApprovalThreshold",
+			amount: 1,
+			buildStubs: func(store *mock_db.MockStore) {
+				guarded := toAccount
+				guarded.ApprovalThreshold = 100
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(guarded, nil)
+				stubAccountHolder(store, toAccount.ID, user.Username, database.AccountHolderOwner)
+				stubUnshared(store, fromAccount.ID)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+				requireBodyErrorCode(t, recorder.Body, "account_shared")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+			stubAccountHolder(store, fromAccount.ID, user.Username, database.AccountHolderOwner)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"FromAccountId": fromAccount.ID,
+				"ToAccountId":   toAccount.ID,
+				"amount":        tc.amount,
+				"currency":      fromAccount.Currency,
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers/internal", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
 func compareResponse(t *testing.T, body *bytes.Buffer, account database.Account) {
 	data, err := io.ReadAll(body)
 	require.NoError(t, err)
@@ -132,3 +592,47 @@ func randomAccount(owner string) database.Account {
 		Currency: util.RandomCurrency(),
 	}
 }
+
+func TestListAccountTransfersAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	other := randomAccount(util.RandomOwner())
+	transfers := []database.Transfer{
+		{ID: uuid.New(), FromAccountID: account.ID, ToAccountID: other.ID, Amount: 10, CreatedAt: time.Now().UTC().Truncate(time.Microsecond)},
+		{ID: uuid.New(), FromAccountID: other.ID, ToAccountID: account.ID, Amount: 5, CreatedAt: time.Now().Add(time.Second).UTC().Truncate(time.Microsecond)},
+	}
+	cursor := util.PageCursor{CreatedAt: time.Now().Add(time.Minute).UTC().Truncate(time.Microsecond), ID: uuid.New(), Before: true}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mock_db.NewMockStore(ctrl)
+	stubAccountHolder(store, account.ID, user.Username, database.AccountHolderOwner)
+	store.EXPECT().
+		ListAccountTransfersBefore(gomock.Any(), gomock.Eq(database.ListAccountTransfersBeforeParams{
+			AccountID:       account.ID,
+			CursorCreatedAt: cursor.CreatedAt,
+			CursorID:        cursor.ID,
+			PageSize:        11,
+		})).
+		Times(1).
+		Return([]database.Transfer{transfers[1], transfers[0]}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	url := fmt.Sprintf("/accounts/%s/transfers?cursor=%s", account.ID, cursor.Encode())
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var res transferListResponse
+	err = json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+	require.Equal(t, transfers, res.Transfers)
+	require.NotEmpty(t, res.Next)
+	require.Empty(t, res.Prev)
+}
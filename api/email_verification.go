@@ -0,0 +1,124 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/token"
+	"github.com/julianinsua/the_simp_bank/util"
+)
+
+var (
+	errInvalidVerificationToken = errors.New("email verification token is invalid or expired")
+	errEmailAlreadyVerified     = errors.New("email is already verified")
+	errVerificationResendLimit  = errors.New("too many verification emails")
+)
+
+/*
+Creates a verification token for the user's current email and sends it
+*/
+func (srv *Server) sendEmailVerification(ctx *gin.Context, usr database.User) error {
+	return srv.auth().SendEmailVerification(ctx, usr)
+}
+
+type verifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+/*
+Marks the email a verification token was sent to as verified
+*/
+func (srv *Server) verifyEmail(ctx *gin.Context) {
+	var req verifyEmailRequest
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	verification, err := srv.store.GetEmailVerificationToken(ctx, util.HashSecureToken(req.Token))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(ctx, http.StatusBadRequest, errInvalidVerificationToken)
+			return
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if verification.Used || time.Now().After(verification.ExpiresAt) {
+		respondWithError(ctx, http.StatusBadRequest, errInvalidVerificationToken)
+		return
+	}
+
+	usr, err := srv.store.VerifyEmailTx(ctx, database.VerifyEmailTxParams{
+		TokenID:  verification.ID,
+		Username: verification.Username,
+		Email:    verification.Email,
+	})
+	if err != nil {
+		// Either redeemed concurrently or the user changed its email after the token was sent
+		if errors.Is(err, database.ErrVerificationTokenUnavailable) || errors.Is(err, sql.ErrNoRows) {
+			respondWithError(ctx, http.StatusBadRequest, errInvalidVerificationToken)
+			return
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(usr))
+}
+
+/*
+Sends a new verification email to the authenticated user, limited to a few per resend window
+*/
+func (srv *Server) resendEmailVerification(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+
+	usr, err := srv.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if usr.EmailVerifiedAt.Valid {
+		respondWithError(ctx, http.StatusConflict, errEmailAlreadyVerified)
+		return
+	}
+
+	if !srv.verificationResendAllowed(ctx, usr.Username) {
+		return
+	}
+
+	err = srv.sendEmailVerification(ctx, usr)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusAccepted)
+}
+
+/*
+Responds with 429 when the user was already sent as many verification emails as the resend window allows. Every
+email that sends a verification token goes through here, resends and email changes alike.
+*/
+func (srv *Server) verificationResendAllowed(ctx *gin.Context, username string) bool {
+	sent, err := srv.store.CountRecentEmailVerificationTokens(ctx, database.CountRecentEmailVerificationTokensParams{
+		Username:  username,
+		CreatedAt: time.Now().Add(-srv.config.EmailVerificationResendWindow),
+	})
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return false
+	}
+	if sent >= srv.config.EmailVerificationResendLimit {
+		err = fmt.Errorf("%w: only %d can be sent every %v", errVerificationResendLimit, srv.config.EmailVerificationResendLimit, srv.config.EmailVerificationResendWindow)
+		respondWithError(ctx, http.StatusTooManyRequests, err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,302 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	mock_mail "github.com/julianinsua/the_simp_bank/mail/mock"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyEmail(t *testing.T) {
+	user, _ := randomUser(t)
+	verificationToken, err := util.GenerateSecureToken(32)
+	require.NoError(t, err)
+
+	stored := database.EmailVerificationToken{
+		ID:          uuid.New(),
+		Username:    user.Username,
+		Email:       user.Email,
+		HashedToken: util.HashSecureToken(verificationToken),
+		ExpiresAt:   time.Now().Add(time.Minute),
+	}
+
+	verified := user
+	verified.EmailVerifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetEmailVerificationToken(gomock.Any(), gomock.Eq(stored.HashedToken)).Times(1).Return(stored, nil)
+				store.EXPECT().
+					VerifyEmailTx(gomock.Any(), gomock.Eq(database.VerifyEmailTxParams{
+						TokenID:  stored.ID,
+						Username: user.Username,
+						Email:    user.Email,
+					})).
+					Times(1).
+					Return(verified, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res userResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.True(t, res.EmailVerified)
+			},
+		},
+		{
+			name: "UnknownToken",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetEmailVerificationToken(gomock.Any(), gomock.Any()).Times(1).Return(database.EmailVerificationToken{}, sql.ErrNoRows)
+				store.EXPECT().VerifyEmailTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ExpiredToken",
+			buildStubs: func(store *mock_db.MockStore) {
+				expired := stored
+				expired.ExpiresAt = time.Now().Add(-time.Minute)
+				store.EXPECT().GetEmailVerificationToken(gomock.Any(), gomock.Any()).Times(1).Return(expired, nil)
+				store.EXPECT().VerifyEmailTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "EmailChanged",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetEmailVerificationToken(gomock.Any(), gomock.Any()).Times(1).Return(stored, nil)
+				store.EXPECT().
+					VerifyEmailTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(database.User{}, fmt.Errorf("unable to execute transaction: %w", sql.ErrNoRows))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetEmailVerificationToken(gomock.Any(), gomock.Any()).Times(1).Return(database.EmailVerificationToken{}, sql.ErrConnDone)
+				store.EXPECT().VerifyEmailTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"token": verificationToken})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/verify-email", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestResendEmailVerification(t *testing.T) {
+	user, _ := randomUser(t)
+
+	verified := user
+	verified.EmailVerifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mock_db.MockStore, mailer *mock_mail.MockMailer)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mock_db.MockStore, mailer *mock_mail.MockMailer) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CountRecentEmailVerificationTokens(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+				store.EXPECT().CreateEmailVerificationToken(gomock.Any(), gomock.Any()).Times(1)
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyVerified",
+			buildStubs: func(store *mock_db.MockStore, mailer *mock_mail.MockMailer) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(verified, nil)
+				store.EXPECT().CountRecentEmailVerificationTokens(gomock.Any(), gomock.Any()).Times(0)
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "ResendLimitReached",
+			buildStubs: func(store *mock_db.MockStore, mailer *mock_mail.MockMailer) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CountRecentEmailVerificationTokens(gomock.Any(), gomock.Any()).Times(1).Return(int64(3), nil)
+				store.EXPECT().CreateEmailVerificationToken(gomock.Any(), gomock.Any()).Times(0)
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+			},
+		},
+		{
+			name: "MailerError",
+			buildStubs: func(store *mock_db.MockStore, mailer *mock_mail.MockMailer) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CountRecentEmailVerificationTokens(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+				store.EXPECT().CreateEmailVerificationToken(gomock.Any(), gomock.Any()).Times(1)
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(1).Return(fmt.Errorf("smtp down"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			mailer := mock_mail.NewMockMailer(ctrl)
+			tc.buildStubs(store, mailer)
+
+			server := newTestServerWithMailer(t, store, mailer)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/users/verify-email/resend", nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestTransfersAllowed(t *testing.T) {
+	user, _ := randomUser(t)
+
+	verified := user
+	verified.EmailVerifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	kycVerifiedUser := verified
+	kycVerifiedUser.KycStatus = database.KYCStatusVerified
+
+	testCases := []struct {
+		name         string
+		requireEmail bool
+		requireKYC   bool
+		buildStubs   func(store *mock_db.MockStore)
+		allowed      bool
+		expectedCode int
+	}{
+		{
+			name: "PolicyDisabled",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			allowed:      true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Verified",
+			requireEmail: true,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(verified, nil)
+			},
+			allowed:      true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "NotVerified",
+			requireEmail: true,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+			},
+			allowed:      false,
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "KYCVerified",
+			requireEmail: true,
+			requireKYC:   true,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(kycVerifiedUser, nil)
+			},
+			allowed:      true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:       "KYCPending",
+			requireKYC: true,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(verified, nil)
+			},
+			allowed:      false,
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.config.RequireVerifiedEmailTransfers = tc.requireEmail
+			server.config.KYCRequiredForTransfers = tc.requireKYC
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/transfers", nil)
+
+			require.Equal(t, tc.allowed, server.transfersAllowed(ctx, user.Username))
+			require.Equal(t, tc.expectedCode, recorder.Code)
+		})
+	}
+}
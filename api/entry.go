@@ -0,0 +1,73 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/token"
+	"github.com/julianinsua/the_simp_bank/util"
+)
+
+/*
+A page of the entries of an account
+*/
+type entryListResponse struct {
+	Entries []database.Entry `json:"entries"`
+	pageLinks
+}
+
+func entryCursor(entry database.Entry) util.PageCursor {
+	return util.PageCursor{CreatedAt: entry.CreatedAt, ID: entry.ID}
+}
+
+/*
+Lists the entries of an account oldest first, for anyone who can see the account
+*/
+func (srv *Server) listAccountEntries(ctx *gin.Context) {
+	var uri accountHolderURI
+	err := ctx.ShouldBindUri(&uri)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	cur, size, ok := srv.bindPage(ctx)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	accID := uuid.MustParse(uri.ID)
+	if !srv.accountViewAllowed(ctx, accID, authPayload.Username) {
+		return
+	}
+
+	var entries []database.Entry
+	if cur.Before {
+		entries, err = srv.store.ListAccountEntriesBefore(ctx, database.ListAccountEntriesBeforeParams{
+			AccountID:       accID,
+			CursorCreatedAt: cur.CreatedAt,
+			CursorID:        cur.ID,
+			PageSize:        size + 1,
+		})
+	} else {
+		entries, err = srv.store.ListAccountEntriesAfter(ctx, database.ListAccountEntriesAfterParams{
+			AccountID:       accID,
+			CursorCreatedAt: cur.CreatedAt,
+			CursorID:        cur.ID,
+			PageSize:        size + 1,
+		})
+	}
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	entries, next, prev := util.Paginate(entries, size, cur, entryCursor)
+	ctx.JSON(http.StatusOK, entryListResponse{
+		Entries:   entries,
+		pageLinks: newPageLinks(ctx, size, next, prev),
+	})
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAccountEntriesAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	entries := make([]database.Entry, 3)
+	for i := range entries {
+		entries[i] = database.Entry{
+			ID:        uuid.New(),
+			AccountID: account.ID,
+			Amount:    util.RandomMoney(),
+			CreatedAt: time.Now().Add(time.Duration(i) * time.Second).UTC().Truncate(time.Microsecond),
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "size=2",
+			buildStubs: func(store *mock_db.MockStore) {
+				stubAccountHolder(store, account.ID, user.Username, database.AccountHolderOwner)
+				store.EXPECT().
+					ListAccountEntriesAfter(gomock.Any(), gomock.Eq(database.ListAccountEntriesAfterParams{AccountID: account.ID, PageSize: 3})).
+					Times(1).
+					Return(entries, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res entryListResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, entries[:2], res.Entries)
+				require.NotEmpty(t, res.Next)
+				require.Empty(t, res.Prev)
+			},
+		},
+		{
+			name:  "NotHolder",
+			query: "",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccountHolder(gomock.Any(), gomock.Any()).Times(1).Return(database.AccountHolder{}, database.ErrRecordNotFound)
+				store.EXPECT().GetActiveAccountGrant(gomock.Any(), gomock.Any()).Times(1).Return(database.AccountGrant{}, database.ErrRecordNotFound)
+				store.EXPECT().ListAccountEntriesAfter(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+
+				var res errorResponseBody
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, "account_access_denied", res.Error.Code)
+			},
+		},
+		{
+			name:  "InvalidCursor",
+			query: "cursor=nope",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().ListAccountEntriesAfter(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyError(t, recorder.Body, util.ErrInvalidCursor)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%s/entries?%s", account.ID, tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
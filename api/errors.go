@@ -0,0 +1,240 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/julianinsua/the_simp_bank/auth"
+	"github.com/julianinsua/the_simp_bank/errcode"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/policy"
+	"github.com/julianinsua/the_simp_bank/token"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/julianinsua/the_simp_bank/webhook"
+	"github.com/lib/pq"
+)
+
+// Generic error codes, used when an error has no code of its own
+const (
+	codeInvalidRequest  = "invalid_request"
+	codeUnauthenticated = "unauthenticated"
+	codeForbidden       = "forbidden"
+	codeNotFound        = "not_found"
+	codeConflict        = "conflict"
+	codeUnprocessable   = "unprocessable"
+	codeTooManyRequests = "too_many_requests"
+	codeInternal        = "internal"
+)
+
+/*
+Body of every error response. Code is stable and meant for clients to branch on, message is for humans.
+*/
+type apiError struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Details   any    `json:"details,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+type errorResponseBody struct {
+	Error apiError `json:"error"`
+}
+
+/*
+A request field that failed validation
+*/
+type fieldError struct {
+	Field string `json:"field"`
+	Rule  string `json:"rule"`
+	Param string `json:"param,omitempty"`
+}
+
+/*
+Stable codes of the errors handlers respond with on purpose. Wrapped errors are matched too, in order, so an error
+wrapping several known ones always gets the same code.
+*/
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{errAccountNameTaken, "account_name_taken"},
+	{policy.ErrInvalidParent, "invalid_parent_account"},
+	{policy.ErrNotOwnPot, "not_own_pot"},
+	{policy.ErrAccountShared, "account_shared"},
+	{errGrantExpired, "grant_expired"},
+	{errGrantToSelf, "grant_to_self"},
+	{errGrantNotFound, "grant_not_found"},
+	{policy.ErrGrantViewOnly, "grant_view_only"},
+	{policy.ErrGrantLimitExceeded, "grant_limit_exceeded"},
+	{database.ErrGrantLimitExceeded, "grant_limit_exceeded"},
+	{errGranteeNotFound, "grantee_not_found"},
+	{policy.ErrGrantApproveMissing, "grant_cannot_approve"},
+	{errAlreadyAccountHolder, "already_account_holder"},
+	{errNoAccountInvitation, "no_account_invitation"},
+	{errAccountHolderMissing, "account_holder_not_found"},
+	{errInviteeNotFound, "invitee_not_found"},
+	{policy.ErrAccessDenied, "account_access_denied"},
+	{policy.ErrCurrencyMismatch, "currency_mismatch"},
+	{errInvalidVerificationToken, "invalid_verification_token"},
+	{errEmailAlreadyVerified, "email_already_verified"},
+	{policy.ErrEmailNotVerified, "email_not_verified"},
+	{errVerificationResendLimit, "verification_resend_limit"},
+	{errKYCAlreadyVerified, "kyc_already_verified"},
+	{errKYCPendingReview, "kyc_pending_review"},
+	{errKYCDocumentExpired, "kyc_document_expired"},
+	{errKYCSelfReview, "kyc_self_review"},
+	{policy.ErrKYCRequired, "kyc_required"},
+	{policy.ErrKYCBalanceLimit, "kyc_balance_limit"},
+	{auth.ErrInvalidCredentials, "invalid_credentials"},
+	{auth.ErrLoginThrottled, "login_throttled"},
+	{auth.ErrTokenRevoked, "token_revoked"},
+	{errMissingAuthorization, "missing_authorization"},
+	{errMalformedAuthorization, "malformed_authorization"},
+	{errUnsupportedAuthorization, "unsupported_authorization"},
+	{errMissingScope, "missing_scope"},
+	{errSessionBlocked, "session_blocked"},
+	{errSessionMismatch, "session_mismatch"},
+	{errSessionExpired, "session_expired"},
+	{errInvalidResetToken, "invalid_reset_token"},
+	{errPasswordResetThrottled, "password_reset_throttled"},
+	{errStepUpRequired, "step_up_required"},
+	{errStepUpTokenInvalid, "step_up_token_invalid"},
+	{errStepUpTokenUsed, "step_up_token_used"},
+	{errTOTPAlreadyEnabled, "totp_already_enabled"},
+	{errTOTPNotEnrolled, "totp_not_enrolled"},
+	{errInvalidMFACode, "invalid_mfa_code"},
+	{errMFAChallengeUsed, "mfa_challenge_used"},
+	{errSelfApproval, "self_approval"},
+	{errUsernameTaken, "username_taken"},
+	{errEmailInUse, "email_in_use"},
+	{errEmailChangeReauth, "email_change_reauth_required"},
+	{errEmptyUserUpdate, "empty_update"},
+	{errWebhookNotFound, "webhook_not_found"},
+	{errInvalidEventID, "invalid_event_id"},
+	{webhook.ErrUnsafeURL, "unsafe_webhook_url"},
+	{token.ErrInvalidToken, "invalid_token"},
+	{util.ErrInvalidCursor, "invalid_cursor"},
+	{util.ErrPageSizeTooLarge, "page_size_too_large"},
+
+	{database.ErrRecordNotFound, codeNotFound},
+	{database.ErrInsufficientFunds, "insufficient_funds"},
+	{database.ErrCurrencyMismatch, "currency_mismatch"},
+	{database.ErrAccountClosed, "account_closed"},
+	{database.ErrAccountFrozen, "account_frozen"},
+	{database.ErrForbidden, codeForbidden},
+	{database.ErrNonZeroBalance, "non_zero_balance"},
+	{database.ErrKYCSubmissionReviewed, "kyc_submission_reviewed"},
+	{database.ErrResetTokenUnavailable, "invalid_reset_token"},
+	{database.ErrVerificationTokenUnavailable, "invalid_verification_token"},
+	{database.ErrTransferRequestDecided, "transfer_request_decided"},
+	{database.ErrTransferRequestExpired, "transfer_request_expired"},
+	{database.ErrThresholdLowered, "threshold_reauth_required"},
+	{database.ErrThresholdChangeDecided, "threshold_change_decided"},
+	{database.ErrThresholdChangeExpired, "threshold_change_expired"},
+}
+
+/*
+Aborts the request answering with err as a structured error. Every handler and middleware reports errors through here.
+Server errors and database driver errors never reach the client, they are attached to the gin context to be logged.
+*/
+func respondWithError(ctx *gin.Context, status int, err error) {
+	// Typed store, policy and login errors carry their own status, shared with the gRPC server
+	if known, ok := errcode.Status(err); ok {
+		status = known
+	}
+	_ = ctx.Error(err)
+	ctx.AbortWithStatusJSON(status, errorResponseBody{Error: newAPIError(ctx, status, err)})
+}
+
+/*
+Maps an error to the body clients get
+*/
+func newAPIError(ctx *gin.Context, status int, err error) apiError {
+	res := apiError{
+		Code:      statusErrorCode(status),
+		Message:   err.Error(),
+		RequestID: requestIDFrom(ctx),
+	}
+
+	for _, known := range errorCodes {
+		if errors.Is(err, known.err) {
+			res.Code = known.code
+			break
+		}
+	}
+
+	var validationErrs validator.ValidationErrors
+	var policyErr *util.PasswordPolicyError
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var pqErr *pq.Error
+	switch {
+	case status >= http.StatusInternalServerError:
+		res.Code = codeInternal
+		res.Message = "internal server error"
+	case errors.As(err, &validationErrs):
+		res.Code = codeInvalidRequest
+		res.Message = "request validation failed"
+		details := make([]fieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			details = append(details, fieldError{Field: fe.Field(), Rule: fe.Tag(), Param: fe.Param()})
+		}
+		res.Details = details
+	case errors.As(err, &policyErr):
+		res.Code = "weak_password"
+		res.Details = policyErr.Violations
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		res.Code = codeInvalidRequest
+		res.Message = "request body is not valid JSON for this endpoint"
+	case errors.As(err, &pqErr):
+		res.Message = strings.ToLower(http.StatusText(status))
+	case errors.Is(err, database.ErrRecordNotFound):
+		res.Message = "resource not found"
+	}
+
+	return res
+}
+
+func statusErrorCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return codeInvalidRequest
+	case http.StatusUnauthorized:
+		return codeUnauthenticated
+	case http.StatusForbidden:
+		return codeForbidden
+	case http.StatusNotFound:
+		return codeNotFound
+	case http.StatusConflict:
+		return codeConflict
+	case http.StatusUnprocessableEntity:
+		return codeUnprocessable
+	case http.StatusTooManyRequests:
+		return codeTooManyRequests
+	}
+	if status >= http.StatusInternalServerError {
+		return codeInternal
+	}
+	return codeInvalidRequest
+}
+
+/*
+Names validation errors after the JSON, query or uri field the client sent instead of the Go struct field
+*/
+func requestFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form", "uri"} {
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/policy"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRespondWithError(t *testing.T) {
+	testCases := []struct {
+		name          string
+		status        int
+		err           error
+		checkResponse func(t *testing.T, status int, res apiError)
+	}{
+		{
+			name:   "KnownError",
+			status: http.StatusConflict,
+			err:    errUsernameTaken,
+			checkResponse: func(t *testing.T, status int, res apiError) {
+				require.Equal(t, http.StatusConflict, status)
+				require.Equal(t, "username_taken", res.Code)
+				require.Equal(t, errUsernameTaken.Error(), res.Message)
+			},
+		},
+		{
+			name:   "WrappedError",
+			status: http.StatusForbidden,
+			err:    fmt.Errorf("%w (%v)", policy.ErrGrantLimitExceeded, 10),
+			checkResponse: func(t *testing.T, status int, res apiError) {
+				require.Equal(t, "grant_limit_exceeded", res.Code)
+				require.Equal(t, "transfer exceeds the limit of the grant (10)", res.Message)
+			},
+		},
+		{
+			name:   "DomainErrorStatus",
+			status: http.StatusInternalServerError,
+			err:    fmt.Errorf("unable to execute transaction: %w", database.ErrInsufficientFunds),
+			checkResponse: func(t *testing.T, status int, res apiError) {
+				require.Equal(t, http.StatusUnprocessableEntity, status)
+				require.Equal(t, "insufficient_funds", res.Code)
+			},
+		},
+		{
+			name:   "ClosedAccount",
+			status: http.StatusInternalServerError,
+			err:    fmt.Errorf("unable to execute transaction: %w", database.ErrAccountClosed),
+			checkResponse: func(t *testing.T, status int, res apiError) {
+				require.Equal(t, http.StatusUnprocessableEntity, status)
+				require.Equal(t, "account_closed", res.Code)
+			},
+		},
+		{
+			name:   "PolicyCurrencyMismatch",
+			status: http.StatusBadRequest,
+			err:    fmt.Errorf("Account [1] %w: has USD vs. EUR", policy.ErrCurrencyMismatch),
+			checkResponse: func(t *testing.T, status int, res apiError) {
+				require.Equal(t, http.StatusUnprocessableEntity, status)
+				require.Equal(t, "currency_mismatch", res.Code)
+			},
+		},
+		{
+			name:   "StoreCurrencyMismatch",
+			status: http.StatusInternalServerError,
+			err:    fmt.Errorf("unable to execute transaction: %w", database.ErrCurrencyMismatch),
+			checkResponse: func(t *testing.T, status int, res apiError) {
+				require.Equal(t, http.StatusUnprocessableEntity, status)
+				require.Equal(t, "currency_mismatch", res.Code)
+			},
+		},
+		{
+			name:   "AccessDenied",
+			status: http.StatusUnauthorized,
+			err:    policy.ErrAccessDenied,
+			checkResponse: func(t *testing.T, status int, res apiError) {
+				require.Equal(t, http.StatusForbidden, status)
+				require.Equal(t, "account_access_denied", res.Code)
+			},
+		},
+		{
+			name:   "InternalError",
+			status: http.StatusInternalServerError,
+			err:    &pq.Error{Code: "08006", Message: "connection to server lost"},
+			checkResponse: func(t *testing.T, status int, res apiError) {
+				require.Equal(t, http.StatusInternalServerError, status)
+				require.Equal(t, codeInternal, res.Code)
+				require.NotContains(t, res.Message, "connection")
+			},
+		},
+		{
+			name:   "DriverError",
+			status: http.StatusForbidden,
+			err:    &pq.Error{Code: "23503", Message: "insert violates foreign key constraint"},
+			checkResponse: func(t *testing.T, status int, res apiError) {
+				require.Equal(t, http.StatusForbidden, status)
+				require.Equal(t, codeForbidden, res.Code)
+				require.NotContains(t, res.Message, "constraint")
+			},
+		},
+		{
+			name:   "NotFound",
+			status: http.StatusNotFound,
+			err:    database.ErrRecordNotFound,
+			checkResponse: func(t *testing.T, status int, res apiError) {
+				require.Equal(t, codeNotFound, res.Code)
+				require.Equal(t, "resource not found", res.Message)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			ctx.Set(requestIDKey, "test-request")
+
+			respondWithError(ctx, tc.status, tc.err)
+			require.True(t, ctx.IsAborted())
+
+			var body errorResponseBody
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+			require.Equal(t, "test-request", body.Error.RequestID)
+			tc.checkResponse(t, recorder.Code, body.Error)
+		})
+	}
+}
+
+func TestValidationErrorDetails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mock_db.NewMockStore(ctrl))
+	data, err := json.Marshal(gin.H{"username": "bad name"})
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/users", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	var body struct {
+		Error struct {
+			Code    string       `json:"code"`
+			Details []fieldError `json:"details"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	require.Equal(t, codeInvalidRequest, body.Error.Code)
+	require.Contains(t, body.Error.Details, fieldError{Field: "username", Rule: "alphanum"})
+	require.Contains(t, body.Error.Details, fieldError{Field: "fullName", Rule: "required"})
+}
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/policy"
+	"github.com/julianinsua/the_simp_bank/token"
+	"github.com/lib/pq"
+)
+
+const kycDateLayout = "2006-01-02"
+
+var (
+	errKYCAlreadyVerified = errors.New("identity is already verified")
+	errKYCPendingReview   = errors.New("a kyc submission is already waiting for review")
+	errKYCDocumentExpired = errors.New("identity document has expired")
+	errKYCSelfReview      = errors.New("kyc submissions must be reviewed by someone other than the submitter")
+)
+
+type submitKYCRequest struct {
+	DocumentType      string `json:"documentType" binding:"required,oneof=passport national_id drivers_license"`
+	DocumentNumber    string `json:"documentNumber" binding:"required,alphanum,max=32"`
+	IssuingCountry    string `json:"issuingCountry" binding:"required,len=2,alpha,uppercase"`
+	DocumentExpiresAt string `json:"documentExpiresAt" binding:"required,datetime=2006-01-02"`
+}
+
+/*
+Submits identity document metadata for review
+*/
+func (srv *Server) submitKYC(ctx *gin.Context) {
+	var req submitKYCRequest
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	expiresAt, err := time.Parse(kycDateLayout, req.DocumentExpiresAt)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if expiresAt.Before(time.Now()) {
+		respondWithError(ctx, http.StatusBadRequest, errKYCDocumentExpired)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+
+	usr, err := srv.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if policy.KYCVerified(usr) {
+		respondWithError(ctx, http.StatusConflict, errKYCAlreadyVerified)
+		return
+	}
+
+	submission, err := srv.store.SubmitKYCTx(ctx, database.CreateKYCSubmissionParams{
+		Username:          usr.Username,
+		DocumentType:      req.DocumentType,
+		DocumentNumber:    req.DocumentNumber,
+		IssuingCountry:    req.IssuingCountry,
+		DocumentExpiresAt: expiresAt,
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" {
+			respondWithError(ctx, http.StatusConflict, errKYCPendingReview)
+			return
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, submission)
+}
+
+type kycStatusResponse struct {
+	Status      string                   `json:"status"`
+	Submissions []database.KycSubmission `json:"submissions"`
+}
+
+/*
+Returns the authenticated user's KYC status and its submissions, newest first
+*/
+func (srv *Server) getKYCStatus(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+
+	usr, err := srv.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	submissions, err := srv.store.ListUserKYCSubmissions(ctx, usr.Username)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, kycStatusResponse{
+		Status:      usr.KycStatus,
+		Submissions: submissions,
+	})
+}
+
+type listPendingKYCRequest struct {
+	Page int32 `form:"page" binding:"required,min=1"`
+	Size int32 `form:"size" binding:"required,min=5,max=50"`
+}
+
+/*
+Admin handler listing the submissions waiting for review, oldest first
+*/
+func (srv *Server) listPendingKYC(ctx *gin.Context) {
+	var req listPendingKYCRequest
+	err := ctx.ShouldBindQuery(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	submissions, err := srv.store.ListPendingKYCSubmissions(ctx, database.ListPendingKYCSubmissionsParams{
+		Limit:  req.Size,
+		Offset: (req.Page - 1) * req.Size,
+	})
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, submissions)
+}
+
+type reviewKYCURI struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+type reviewKYCRequest struct {
+	Decision string `json:"decision" binding:"required,oneof=verified rejected"`
+	Note     string `json:"note" binding:"required_if=Decision rejected,max=500"`
+}
+
+/*
+Admin handler approving or rejecting a pending submission. The decision becomes the user's KYC status. Reviewers can't
+decide on their own submissions.
+*/
+func (srv *Server) reviewKYC(ctx *gin.Context) {
+	var uri reviewKYCURI
+	err := ctx.ShouldBindUri(&uri)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var req reviewKYCRequest
+	err = ctx.ShouldBindJSON(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	submissionID := uuid.MustParse(uri.ID)
+	submission, err := srv.store.GetKYCSubmission(ctx, submissionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if submission.Status != database.KYCStatusPending {
+		respondWithError(ctx, http.StatusConflict, database.ErrKYCSubmissionReviewed)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	if submission.Username == authPayload.Username {
+		respondWithError(ctx, http.StatusForbidden, errKYCSelfReview)
+		return
+	}
+
+	result, err := srv.store.ReviewKYCTx(ctx, database.ReviewKYCSubmissionParams{
+		ID:         submission.ID,
+		Status:     req.Decision,
+		ReviewNote: req.Note,
+		ReviewedBy: sql.NullString{String: authPayload.Username, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, database.ErrKYCSubmissionReviewed) {
+			respondWithError(ctx, http.StatusConflict, database.ErrKYCSubmissionReviewed)
+			return
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result.Submission)
+}
@@ -0,0 +1,383 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func randomKYCSubmission(username string) database.KycSubmission {
+	return database.KycSubmission{
+		ID:                uuid.New(),
+		Username:          username,
+		DocumentType:      "passport",
+		DocumentNumber:    util.RandomString(9),
+		IssuingCountry:    "AR",
+		DocumentExpiresAt: time.Now().AddDate(5, 0, 0).Truncate(24 * time.Hour),
+		Status:            database.KYCStatusPending,
+		CreatedAt:         time.Now(),
+	}
+}
+
+func TestSubmitKYC(t *testing.T) {
+	user, _ := randomUser(t)
+	submission := randomKYCSubmission(user.Username)
+
+	verified := user
+	verified.KycStatus = database.KYCStatusVerified
+
+	validBody := gin.H{
+		"documentType":      submission.DocumentType,
+		"documentNumber":    submission.DocumentNumber,
+		"issuingCountry":    submission.IssuingCountry,
+		"documentExpiresAt": submission.DocumentExpiresAt.Format(kycDateLayout),
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: validBody,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					SubmitKYCTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg database.CreateKYCSubmissionParams) (database.KycSubmission, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, submission.DocumentNumber, arg.DocumentNumber)
+						require.True(t, submission.DocumentExpiresAt.Equal(arg.DocumentExpiresAt))
+						return submission, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var res database.KycSubmission
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, submission.ID, res.ID)
+			},
+		},
+		{
+			name: "ExpiredDocument",
+			body: gin.H{
+				"documentType":      "passport",
+				"documentNumber":    submission.DocumentNumber,
+				"issuingCountry":    "AR",
+				"documentExpiresAt": time.Now().AddDate(0, 0, -1).Format(kycDateLayout),
+			},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().SubmitKYCTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyError(t, recorder.Body, errKYCDocumentExpired)
+			},
+		},
+		{
+			name: "InvalidDocumentType",
+			body: gin.H{
+				"documentType":      "library_card",
+				"documentNumber":    submission.DocumentNumber,
+				"issuingCountry":    "AR",
+				"documentExpiresAt": submission.DocumentExpiresAt.Format(kycDateLayout),
+			},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().SubmitKYCTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyVerified",
+			body: validBody,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(verified, nil)
+				store.EXPECT().SubmitKYCTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "PendingSubmission",
+			body: validBody,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					SubmitKYCTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(database.KycSubmission{}, fmt.Errorf("unable to execute transaction: %w", &pq.Error{Code: "23505"}))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireBodyError(t, recorder.Body, errKYCPendingReview)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/me/kyc", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestReviewKYC(t *testing.T) {
+	user, _ := randomUser(t)
+	submission := randomKYCSubmission(user.Username)
+
+	reviewed := submission
+	reviewed.Status = database.KYCStatusVerified
+
+	testCases := []struct {
+		name          string
+		role          string
+		submissionID  string
+		body          gin.H
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:         "Approve",
+			role:         util.SupportRole,
+			submissionID: submission.ID.String(),
+			body:         gin.H{"decision": database.KYCStatusVerified},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetKYCSubmission(gomock.Any(), gomock.Eq(submission.ID)).Times(1).Return(submission, nil)
+				store.EXPECT().
+					ReviewKYCTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg database.ReviewKYCSubmissionParams) (database.ReviewKYCTxResult, error) {
+						require.Equal(t, submission.ID, arg.ID)
+						require.Equal(t, database.KYCStatusVerified, arg.Status)
+						require.Equal(t, "reviewer", arg.ReviewedBy.String)
+						return database.ReviewKYCTxResult{Submission: reviewed}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:         "RejectWithoutNote",
+			role:         util.SupportRole,
+			submissionID: submission.ID.String(),
+			body:         gin.H{"decision": database.KYCStatusRejected},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetKYCSubmission(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:         "AlreadyReviewed",
+			role:         util.AdminRole,
+			submissionID: submission.ID.String(),
+			body:         gin.H{"decision": database.KYCStatusRejected, "note": "blurry picture"},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetKYCSubmission(gomock.Any(), gomock.Eq(submission.ID)).Times(1).Return(reviewed, nil)
+				store.EXPECT().ReviewKYCTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:         "ReviewedConcurrently",
+			role:         util.AdminRole,
+			submissionID: submission.ID.String(),
+			body:         gin.H{"decision": database.KYCStatusVerified},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetKYCSubmission(gomock.Any(), gomock.Eq(submission.ID)).Times(1).Return(submission, nil)
+				store.EXPECT().
+					ReviewKYCTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(database.ReviewKYCTxResult{}, fmt.Errorf("unable to execute transaction: %w", database.ErrKYCSubmissionReviewed))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:         "OwnSubmission",
+			role:         util.AdminRole,
+			submissionID: submission.ID.String(),
+			body:         gin.H{"decision": database.KYCStatusVerified},
+			buildStubs: func(store *mock_db.MockStore) {
+				own := submission
+				own.Username = "reviewer"
+				store.EXPECT().GetKYCSubmission(gomock.Any(), gomock.Eq(submission.ID)).Times(1).Return(own, nil)
+				store.EXPECT().ReviewKYCTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireBodyError(t, recorder.Body, errKYCSelfReview)
+			},
+		},
+		{
+			name:         "NotFound",
+			role:         util.AdminRole,
+			submissionID: submission.ID.String(),
+			body:         gin.H{"decision": database.KYCStatusVerified},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetKYCSubmission(gomock.Any(), gomock.Any()).Times(1).Return(database.KycSubmission{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:         "InvalidID",
+			role:         util.AdminRole,
+			submissionID: "not-a-uuid",
+			body:         gin.H{"decision": database.KYCStatusVerified},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetKYCSubmission(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:         "CustomerForbidden",
+			role:         util.CustomerRole,
+			submissionID: submission.ID.String(),
+			body:         gin.H{"decision": database.KYCStatusVerified},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetKYCSubmission(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			store.EXPECT().GetUserRole(gomock.Any(), gomock.Eq("reviewer")).AnyTimes().Return(tc.role, nil)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/admin/kyc/%s/review", tc.submissionID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "reviewer", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestIncomingTransferAllowed(t *testing.T) {
+	owner, _ := randomUser(t)
+	account := randomAccount(owner.Username)
+	account.Balance = 900
+
+	verified := owner
+	verified.KycStatus = database.KYCStatusVerified
+
+	testCases := []struct {
+		name         string
+		amount       float64
+		buildStubs   func(store *mock_db.MockStore)
+		allowed      bool
+		expectedCode int
+	}{
+		{
+			name:   "UnderLimit",
+			amount: 100,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			allowed:      true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "OverLimitVerifiedOwner",
+			amount: 200,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(owner.Username)).Times(1).Return(verified, nil)
+			},
+			allowed:      true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "OverLimitUnverifiedOwner",
+			amount: 200,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(owner.Username)).Times(1).Return(owner, nil)
+			},
+			allowed:      false,
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.config.KYCUnverifiedMaxBalance = 1000
+
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/transfers", nil)
+
+			require.Equal(t, tc.allowed, server.incomingTransferAllowed(ctx, account, tc.amount))
+			require.Equal(t, tc.expectedCode, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/julianinsua/the_simp_bank/logging"
+	"github.com/julianinsua/the_simp_bank/token"
+)
+
+const requestIDKey = "requestID"
+
+var errInternalPanic = errors.New("internal server error")
+
+/*
+Tags every request with an ID. A well formed X-Request-ID sent by the client is kept, otherwise a new one is generated.
+The ID is echoed back in the response header and carried by the request context, so error responses, logs and store
+calls made with the gin context all see it.
+*/
+func requestIDMiddleware(ctx *gin.Context) {
+	requestID := ctx.GetHeader(logging.RequestIDHeader)
+	if !logging.ValidRequestID(requestID) {
+		requestID = logging.NewRequestID()
+	}
+
+	ctx.Set(requestIDKey, requestID)
+	ctx.Request = ctx.Request.WithContext(logging.WithRequestID(ctx.Request.Context(), requestID))
+	ctx.Header(logging.RequestIDHeader, requestID)
+	ctx.Next()
+}
+
+/*
+Returns the ID the request was tagged with
+*/
+func requestIDFrom(ctx *gin.Context) string {
+	return ctx.GetString(requestIDKey)
+}
+
+/*
+Writes an access log entry once the request is handled. Query parameters holding secrets are redacted and the bodies
+are never logged.
+*/
+func (srv *Server) accessLog(ctx *gin.Context) {
+	start := time.Now()
+	ctx.Next()
+
+	status := ctx.Writer.Status()
+	attrs := []slog.Attr{
+		slog.String("method", ctx.Request.Method),
+		slog.String("route", ctx.FullPath()),
+		slog.String("path", ctx.Request.URL.Path),
+		slog.Int("status", status),
+		slog.Float64("latencyMs", float64(time.Since(start).Microseconds())/1000),
+		slog.Int("size", ctx.Writer.Size()),
+		slog.String("clientIp", ctx.ClientIP()),
+		slog.String("userAgent", ctx.Request.UserAgent()),
+	}
+	if query := logging.RedactQuery(ctx.Request.URL.Query()); query != "" {
+		attrs = append(attrs, slog.String("query", query))
+	}
+	if payload, ok := ctx.Get(authorizationPayloadKey); ok {
+		attrs = append(attrs, slog.String("username", payload.(*token.PASETOPayload).Username))
+	}
+	if err := ctx.Errors.Last(); err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	level := slog.LevelInfo
+	switch {
+	case status >= http.StatusInternalServerError:
+		level = slog.LevelError
+	case status >= http.StatusBadRequest:
+		level = slog.LevelWarn
+	}
+	srv.logger.LogAttrs(ctx, level, "request", attrs...)
+}
+
+/*
+Recovers from panics in the handlers, logging them and responding with an internal error
+*/
+func (srv *Server) recoverPanic(ctx *gin.Context, recovered any) {
+	srv.logger.ErrorContext(ctx, "handler panicked", slog.Any("panic", recovered), slog.String("route", ctx.FullPath()))
+	respondWithError(ctx, http.StatusInternalServerError, errInternalPanic)
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/logging"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+
+	testCases := []struct {
+		name      string
+		requestID string
+		checkID   func(t *testing.T, requestID string)
+	}{
+		{
+			name:      "ClientID",
+			requestID: "client-request.1",
+			checkID: func(t *testing.T, requestID string) {
+				require.Equal(t, "client-request.1", requestID)
+			},
+		},
+		{
+			name: "Generated",
+			checkID: func(t *testing.T, requestID string) {
+				_, err := uuid.Parse(requestID)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:      "InvalidClientID",
+			requestID: "bad id\r\nSet-Cookie: x",
+			checkID: func(t *testing.T, requestID string) {
+				_, err := uuid.Parse(requestID)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:      "TooLong",
+			requestID: strings.Repeat("a", 129),
+			checkID: func(t *testing.T, requestID string) {
+				_, err := uuid.Parse(requestID)
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			var storeRequestID string
+			store.EXPECT().
+				GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+				Times(1).
+				DoAndReturn(func(ctx context.Context, _ uuid.UUID) (database.Account, error) {
+					storeRequestID = logging.RequestID(ctx)
+					return database.Account{}, sql.ErrNoRows
+				})
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/accounts/%s", account.ID), nil)
+			require.NoError(t, err)
+			if tc.requestID != "" {
+				request.Header.Set(logging.RequestIDHeader, tc.requestID)
+			}
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusNotFound, recorder.Code)
+
+			requestID := recorder.Header().Get(logging.RequestIDHeader)
+			tc.checkID(t, requestID)
+			require.Equal(t, requestID, storeRequestID)
+
+			var body errorResponseBody
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+			require.Equal(t, requestID, body.Error.RequestID)
+		})
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user, _ := randomUser(t)
+	server := newTestServer(t, mock_db.NewMockStore(ctrl))
+	var logs bytes.Buffer
+	server.logger = slog.New(logging.NewHandler(&logs, slog.LevelInfo))
+
+	t.Run("Authenticated", func(t *testing.T) {
+		logs.Reset()
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/accounts/not-an-id?token=secret-value&pageSize=5", nil)
+		require.NoError(t, err)
+		request.Header.Set(logging.RequestIDHeader, "access-log-test")
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
+
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+		require.Equal(t, "request", entry["msg"])
+		require.Equal(t, "WARN", entry["level"])
+		require.Equal(t, "access-log-test", entry["requestId"])
+		require.Equal(t, user.Username, entry["username"])
+		require.Equal(t, "/accounts/:id", entry["route"])
+		require.Equal(t, float64(http.StatusBadRequest), entry["status"])
+		require.Equal(t, "pageSize=5&token=%5BREDACTED%5D", entry["query"])
+		require.NotContains(t, logs.String(), "secret-value")
+	})
+
+	t.Run("RequestBodyNotLogged", func(t *testing.T) {
+		logs.Reset()
+		password := util.RandomString(12)
+		data, err := json.Marshal(map[string]any{"username": user.Username, "password": password})
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/users/password", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+		require.Equal(t, "/users/password", entry["route"])
+		require.NotContains(t, entry, "username")
+		require.NotContains(t, logs.String(), password)
+	})
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/julianinsua/the_simp_bank/auth"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/util"
+)
+
+/*
+The login rules, shared with the gRPC server. Built on every call so it sees the current config.
+*/
+func (srv *Server) auth() auth.Authenticator {
+	return auth.New(srv.config, srv.store, srv.mailer, srv.passwordHasher)
+}
+
+/*
+The client of the request as the login audit trail records it
+*/
+func loginClient(ctx *gin.Context) auth.Client {
+	return auth.Client{IP: ctx.ClientIP(), Agent: ctx.Request.UserAgent()}
+}
+
+/*
+Compares the password against a throwaway hash so a login for an unknown user takes as long as one with a wrong password
+*/
+func (srv *Server) checkDummyPassword(password string) {
+	_ = util.CheckPassword(password, srv.dummyHash)
+}
+
+/*
+Responds with 429 and records the throttled attempt when the username or client IP has to wait before trying again.
+Returns whether the handler may go on checking the credentials.
+*/
+func (srv *Server) allowLoginAttempt(ctx *gin.Context, username string) bool {
+	wait, err := srv.auth().AllowLoginAttempt(ctx, username, loginClient(ctx))
+	if err == nil {
+		return true
+	}
+	if !errors.Is(err, auth.ErrLoginThrottled) {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return false
+	}
+	ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	respondWithError(ctx, http.StatusTooManyRequests, err)
+	return false
+}
+
+/*
+Stores the audit record for a login attempt and counts the failed ones in the metrics
+*/
+func (srv *Server) recordLoginAttempt(ctx *gin.Context, username, outcome string) error {
+	return srv.auth().RecordLoginAttempt(ctx, username, outcome, loginClient(ctx))
+}
+
+/*
+Upgrades hashes made with older parameters. Failing only leaves the old hash in place, so the login goes on.
+*/
+func (srv *Server) rehashPassword(ctx *gin.Context, usr database.User, password string) {
+	err := srv.auth().RehashPassword(ctx, usr, password)
+	if err != nil {
+		srv.logger.ErrorContext(ctx, "unable to upgrade the password hash", "username", usr.Username, "error", err)
+	}
+}
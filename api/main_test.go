@@ -1,22 +1,78 @@
 package api
 
 import (
+	"io"
+	"log/slog"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
 	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/mail"
+	"github.com/julianinsua/the_simp_bank/token"
 	"github.com/julianinsua/the_simp_bank/util"
 	"github.com/stretchr/testify/require"
 )
 
+// Cheap argon2id parameters so hashing doesn't dominate the test run
+var testPasswordHasher = util.PasswordHasher{
+	Algorithm:     util.Argon2idAlgorithm,
+	Argon2Time:    1,
+	Argon2Memory:  1024,
+	Argon2Threads: 1,
+	Argon2KeyLen:  32,
+	Argon2SaltLen: 16,
+}
+
 func newTestServer(t *testing.T, store database.Store) *Server {
+	return newTestServerWithMailer(t, store, nil)
+}
+
+func newTestServerWithMailer(t *testing.T, store database.Store, mailer mail.Mailer) *Server {
+	// Tokens are never revoked unless the test stubbed the password change first
+	if mockStore, ok := store.(*mock_db.MockStore); ok {
+		mockStore.EXPECT().GetUserPasswordChangedAt(gomock.Any(), gomock.Any()).AnyTimes().Return(time.Time{}, nil)
+	}
+
 	config := util.Config{
-		SymetricKey:   util.RandomString(33),
-		TokenDuration: time.Minute,
+		SymetricKey:           util.RandomString(33),
+		DocsAssetsPath:        "../assets/swagger-ui",
+		TokenDuration:         time.Minute,
+		LoginAttemptWindow:    time.Hour,
+		LoginMaxAttempts:      5,
+		LoginMaxIPAttempts:    50,
+		LoginBackoffBase:      time.Second,
+		LoginLockoutDuration:  15 * time.Minute,
+		MFAChallengeDuration:  5 * time.Minute,
+		StepUpThreshold:       1000,
+		StepUpTokenDuration:   5 * time.Minute,
+		PasswordResetDuration: 30 * time.Minute,
+		PasswordMinLength:     6,
+		PasswordHashAlgorithm: testPasswordHasher.Algorithm,
+		PasswordArgon2Time:    testPasswordHasher.Argon2Time,
+		PasswordArgon2Memory:  testPasswordHasher.Argon2Memory,
+		PasswordArgon2Threads: testPasswordHasher.Argon2Threads,
+
+		PasswordResetWindow:           time.Hour,
+		PasswordResetMaxRequests:      3,
+		PasswordResetMaxIPRequests:    30,
+		PasswordResetBackoffBase:      2 * time.Second,
+		PasswordResetLockoutDuration:  10 * time.Minute,
+		EmailVerificationDuration:     24 * time.Hour,
+		EmailVerificationResendLimit:  3,
+		EmailVerificationResendWindow: time.Hour,
+		TransferRequestDuration:       time.Hour,
+		PageDefaultSize:               10,
+		PageMaxSize:                   50,
+		StreamPollInterval:            50 * time.Millisecond,
 	}
-	server, err := NewServer(config, store)
+	tokenMaker, err := token.NewPASETOMaker(config.SymetricKey)
+	require.NoError(t, err)
+
+	server, err := NewServer(config, store, tokenMaker, mailer)
 	require.NoError(t, err)
 
 	return server
@@ -24,6 +80,8 @@ func newTestServer(t *testing.T, store database.Store) *Server {
 
 func TestMain(m *testing.M) {
 	gin.SetMode(gin.TestMode)
+	// Access logs would drown the test output, tests that check them use their own logger
+	slog.SetDefault(slog.New(slog.NewJSONHandler(io.Discard, nil)))
 
 	os.Exit(m.Run())
 }
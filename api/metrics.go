@@ -0,0 +1,28 @@
+package api
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/julianinsua/the_simp_bank/metrics"
+)
+
+// Route label of the requests that matched no route, keeps arbitrary paths from becoming label values
+const unmatchedRoute = "unmatched"
+
+/*
+Records the latency and status code of every request under its route template
+*/
+func httpMetrics(ctx *gin.Context) {
+	start := time.Now()
+	ctx.Next()
+
+	route := ctx.FullPath()
+	if route == "" {
+		route = unmatchedRoute
+	}
+	metrics.HTTPRequestDuration.
+		WithLabelValues(ctx.Request.Method, route, strconv.Itoa(ctx.Writer.Status())).
+		Observe(time.Since(start).Seconds())
+}
@@ -1,13 +1,17 @@
 package api
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/julianinsua/the_simp_bank/auth"
 	"github.com/julianinsua/the_simp_bank/token"
+	"github.com/julianinsua/the_simp_bank/util"
 )
 
 const (
@@ -16,34 +20,56 @@ const (
 	authorizationPayloadKey = "authorizationPayload"
 )
 
-/* this is a higher order function that returns the middleware handler function and receives the token maker that checks the token itself */
-func authMiddleware(tokenMaker token.PASETOMaker) gin.HandlerFunc {
+var (
+	errMissingAuthorization     = errors.New("authorization header not provided")
+	errMalformedAuthorization   = errors.New("Access token is malformed")
+	errUnsupportedAuthorization = errors.New("authorization type unsupported")
+	errMissingScope             = errors.New("token lacks a required scope")
+)
+
+/* this is a higher order function that returns the middleware handler function and receives the token maker that checks the token itself and the authenticator to check it hasn't been revoked */
+func authMiddleware(tokenMaker token.PASETOMaker, authenticator auth.Authenticator) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader(authorizationHeaderKey)
 		if len(authHeader) == 0 {
-			err := errors.New("authorization header not provided")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			respondWithError(ctx, http.StatusUnauthorized, errMissingAuthorization)
 			return
 		}
 
 		fields := strings.Fields(authHeader)
 		if len(fields) < 2 {
-			err := errors.New("Access token is malformed")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			respondWithError(ctx, http.StatusUnauthorized, errMalformedAuthorization)
 			return
 		}
 
 		authorizationType := strings.ToLower(fields[0])
 		if authorizationType != authorizationTypeBearer {
-			err := fmt.Errorf("authorization type %s unsupported", authorizationType)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			err := fmt.Errorf("%w: %s", errUnsupportedAuthorization, authorizationType)
+			respondWithError(ctx, http.StatusUnauthorized, err)
 			return
 		}
 
 		accessToken := fields[1]
 		payload, err := tokenMaker.VerifyToken(accessToken)
 		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			respondWithError(ctx, http.StatusUnauthorized, err)
+			return
+		}
+
+		// Purpose tokens (e.g. MFA challenges) are not access tokens
+		if payload.Purpose != "" {
+			respondWithError(ctx, http.StatusUnauthorized, token.ErrInvalidToken)
+			return
+		}
+
+		// Changing the password revokes every token issued before it
+		err = authenticator.CheckNotRevoked(ctx, payload)
+		if err != nil {
+			if errors.Is(err, token.ErrInvalidToken) || errors.Is(err, auth.ErrTokenRevoked) {
+				respondWithError(ctx, http.StatusUnauthorized, err)
+				return
+			}
+			respondWithError(ctx, http.StatusInternalServerError, err)
 			return
 		}
 
@@ -51,3 +77,29 @@ func authMiddleware(tokenMaker token.PASETOMaker) gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+/* Returns a middleware that only lets the request through if the token granted every one of the given scopes and the user's current role still does. Role changes apply on the next request instead of when the token expires. It must run after authMiddleware */
+func (srv *Server) requireScope(scopes ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+		role, err := srv.store.GetUserRole(ctx, payload.Username)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				respondWithError(ctx, http.StatusUnauthorized, token.ErrInvalidToken)
+				return
+			}
+			respondWithError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+
+		for _, scope := range scopes {
+			if !payload.HasScope(scope) || !slices.Contains(util.RoleScopes(role), scope) {
+				err := fmt.Errorf("%w: user %s lacks the %s scope", errMissingScope, payload.Username, scope)
+				respondWithError(ctx, http.StatusForbidden, err)
+				return
+			}
+		}
+
+		ctx.Next()
+	}
+}
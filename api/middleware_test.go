@@ -1,6 +1,7 @@
 package api
 
 import (
+	"database/sql"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -8,7 +9,10 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
 	"github.com/julianinsua/the_simp_bank/token"
+	"github.com/julianinsua/the_simp_bank/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -18,9 +22,10 @@ func addAuthorization(
 	tokenMaker token.PASETOMaker,
 	authType string,
 	username string,
+	role string,
 	duration time.Duration,
 ) {
-	token, payload, err := tokenMaker.CreateToken(username, duration)
+	token, payload, err := tokenMaker.CreateToken(username, role, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
@@ -32,12 +37,13 @@ func TestAuthMiddleware(t *testing.T) {
 	testCases := []struct {
 		name          string
 		setupAuthFunc func(t *testing.T, request *http.Request, maker token.PASETOMaker)
+		buildStubs    func(store *mock_db.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
 			name: "OK",
 			setupAuthFunc: func(t *testing.T, request *http.Request, maker token.PASETOMaker) {
-				addAuthorization(t, request, maker, authorizationTypeBearer, "user", time.Minute)
+				addAuthorization(t, request, maker, authorizationTypeBearer, "user", util.CustomerRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -54,7 +60,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "UnsuportedAuthorization",
 			setupAuthFunc: func(t *testing.T, request *http.Request, maker token.PASETOMaker) {
-				addAuthorization(t, request, maker, "wrongType", "user", time.Minute)
+				addAuthorization(t, request, maker, "wrongType", "user", util.CustomerRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -63,7 +69,7 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "InvalidAuthFormat",
 			setupAuthFunc: func(t *testing.T, request *http.Request, maker token.PASETOMaker) {
-				addAuthorization(t, request, maker, "", "user", time.Minute)
+				addAuthorization(t, request, maker, "", "user", util.CustomerRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -72,7 +78,48 @@ func TestAuthMiddleware(t *testing.T) {
 		{
 			name: "ExpiredAuthorization",
 			setupAuthFunc: func(t *testing.T, request *http.Request, maker token.PASETOMaker) {
-				addAuthorization(t, request, maker, authorizationTypeBearer, "user", -time.Minute)
+				addAuthorization(t, request, maker, authorizationTypeBearer, "user", util.CustomerRole, -time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "RevokedByPasswordChange",
+			setupAuthFunc: func(t *testing.T, request *http.Request, maker token.PASETOMaker) {
+				addAuthorization(t, request, maker, authorizationTypeBearer, "user", util.CustomerRole, time.Minute)
+			},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().
+					GetUserPasswordChangedAt(gomock.Any(), gomock.Eq("user")).
+					Times(1).
+					Return(time.Now().Add(time.Second), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownUser",
+			setupAuthFunc: func(t *testing.T, request *http.Request, maker token.PASETOMaker) {
+				addAuthorization(t, request, maker, authorizationTypeBearer, "user", util.CustomerRole, time.Minute)
+			},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().
+					GetUserPasswordChangedAt(gomock.Any(), gomock.Eq("user")).
+					Times(1).
+					Return(time.Time{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "PurposeToken",
+			setupAuthFunc: func(t *testing.T, request *http.Request, maker token.PASETOMaker) {
+				challenge, _, err := maker.CreatePurposeToken("user", token.PurposeMFAChallenge, "", time.Minute)
+				require.NoError(t, err)
+				request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, challenge))
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -82,10 +129,18 @@ func TestAuthMiddleware(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			srv := newTestServer(t, nil)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			if tc.buildStubs != nil {
+				tc.buildStubs(store)
+			}
+
+			srv := newTestServer(t, store)
 
 			path := "/auth"
-			srv.router.GET(path, authMiddleware(srv.tokenMaker), func(ctx *gin.Context) {
+			srv.router.GET(path, authMiddleware(srv.tokenMaker, srv.auth()), func(ctx *gin.Context) {
 				ctx.JSON(http.StatusOK, gin.H{})
 			})
 
@@ -99,3 +154,98 @@ func TestAuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestRequireScopeMiddleware(t *testing.T) {
+	testCases := []struct {
+		name          string
+		role          string
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			role: util.AdminRole,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUserRole(gomock.Any(), gomock.Eq("user")).Times(1).Return(util.AdminRole, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "MissingScope",
+			role: util.CustomerRole,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUserRole(gomock.Any(), gomock.Eq("user")).Times(1).Return(util.CustomerRole, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownRole",
+			role: "intruder",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUserRole(gomock.Any(), gomock.Eq("user")).Times(1).Return("intruder", nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "RoleDemoted",
+			role: util.AdminRole,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUserRole(gomock.Any(), gomock.Eq("user")).Times(1).Return(util.CustomerRole, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "UserNotFound",
+			role: util.AdminRole,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUserRole(gomock.Any(), gomock.Eq("user")).Times(1).Return("", sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			role: util.AdminRole,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUserRole(gomock.Any(), gomock.Eq("user")).Times(1).Return("", sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			srv := newTestServer(t, store)
+
+			path := "/scoped"
+			srv.router.GET(path, authMiddleware(srv.tokenMaker, srv.auth()), srv.requireScope(util.ScopeUsersRead), func(ctx *gin.Context) {
+				ctx.JSON(http.StatusOK, gin.H{})
+			})
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, path, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, srv.tokenMaker, authorizationTypeBearer, "user", tc.role, time.Minute)
+			srv.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
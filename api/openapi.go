@@ -0,0 +1,401 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/util"
+)
+
+/*
+Documentation of a route. The OpenAPI document is generated from these and the request and response types the
+handlers bind, so field names, casing and validation rules always match what the API accepts.
+*/
+type routeDoc struct {
+	Method    string
+	Path      string // gin path, :param segments become {param}
+	Tag       string
+	Summary   string
+	Auth      bool   // needs a bearer access token
+	Scope     string // token scope required on top of the access token
+	URI       any    // struct bound with ShouldBindUri
+	Query     any    // struct bound with ShouldBindQuery
+	Body      any    // struct bound with ShouldBindJSON
+	Status    int
+	Responses []any // response bodies, more than one means the handler answers with any of them
+}
+
+var (
+	openAPIOnce     sync.Once
+	openAPIDocument map[string]any
+)
+
+/*
+Swagger UI is vendored and served from the docs assets, see make swagger-ui
+*/
+const openAPIDocsPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>The Simp Bank API</title>
+  <link rel="stylesheet" href="/docs/assets/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="/docs/assets/swagger-ui-bundle.js"></script>
+  <script src="/docs/assets/swagger-initializer.js"></script>
+</body>
+</html>`
+
+/*
+Only same origin scripts run on the docs page. Swagger UI sets inline styles and data URI images.
+*/
+const openAPIDocsPolicy = "default-src 'self'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; frame-ancestors 'none'"
+
+/*
+Serves the OpenAPI document
+*/
+func (srv *Server) getOpenAPISpec(ctx *gin.Context) {
+	openAPIOnce.Do(func() {
+		openAPIDocument = newOpenAPIDocument(routeDocs)
+	})
+	ctx.JSON(http.StatusOK, openAPIDocument)
+}
+
+/*
+Serves a Swagger UI page reading the OpenAPI document
+*/
+func (srv *Server) getOpenAPIDocs(ctx *gin.Context) {
+	ctx.Header("Content-Security-Policy", openAPIDocsPolicy)
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(openAPIDocsPage))
+}
+
+var ginPathParam = regexp.MustCompile(`:([A-Za-z]+)`)
+
+/*
+Translates a gin route path into an OpenAPI path
+*/
+func openAPIPath(path string) string {
+	return ginPathParam.ReplaceAllString(path, "{$1}")
+}
+
+/*
+Builds the OpenAPI 3 document for the given routes
+*/
+func newOpenAPIDocument(docs []routeDoc) map[string]any {
+	gen := schemaGenerator{components: map[string]any{}, types: map[string]reflect.Type{}}
+	gen.components["Error"] = gen.inlineSchema(reflect.TypeOf(errorResponseBody{}))
+
+	paths := map[string]any{}
+	for _, doc := range docs {
+		path := openAPIPath(doc.Path)
+		item, ok := paths[path].(map[string]any)
+		if !ok {
+			item = map[string]any{}
+			paths[path] = item
+		}
+		item[strings.ToLower(doc.Method)] = gen.operation(doc)
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "The Simp Bank API",
+			"version": "1.0.0",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": gen.components,
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{
+					"type":         "http",
+					"scheme":       "bearer",
+					"bearerFormat": "PASETO",
+				},
+			},
+		},
+	}
+}
+
+type schemaGenerator struct {
+	components map[string]any
+	types      map[string]reflect.Type
+}
+
+func (gen schemaGenerator) operation(doc routeDoc) map[string]any {
+	op := map[string]any{
+		"tags":        []string{doc.Tag},
+		"summary":     doc.Summary,
+		"operationId": strings.ToLower(doc.Method) + strings.NewReplacer("/", "_", ":", "", "-", "_").Replace(doc.Path),
+	}
+
+	var params []any
+	if doc.URI != nil {
+		params = append(params, gen.parameters(doc.URI, "path", "uri")...)
+	}
+	if doc.Query != nil {
+		params = append(params, gen.parameters(doc.Query, "query", "form")...)
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+
+	if doc.Body != nil {
+		op["requestBody"] = map[string]any{
+			"required": true,
+			"content": map[string]any{
+				"application/json": map[string]any{"schema": gen.inlineSchema(reflect.TypeOf(doc.Body))},
+			},
+		}
+	}
+
+	if doc.Auth {
+		op["security"] = []any{map[string]any{"bearerAuth": []string{}}}
+	}
+	if doc.Scope != "" {
+		op["description"] = fmt.Sprintf("Requires the %s scope.", doc.Scope)
+	}
+
+	success := map[string]any{"description": http.StatusText(doc.Status)}
+	switch len(doc.Responses) {
+	case 0:
+	case 1:
+		success["content"] = jsonContent(gen.schema(reflect.TypeOf(doc.Responses[0])))
+	default:
+		var oneOf []any
+		for _, res := range doc.Responses {
+			oneOf = append(oneOf, gen.schema(reflect.TypeOf(res)))
+		}
+		success["content"] = jsonContent(map[string]any{"oneOf": oneOf})
+	}
+	op["responses"] = map[string]any{
+		strconv.Itoa(doc.Status): success,
+		"default": map[string]any{
+			"description": "Error",
+			"content":     jsonContent(map[string]any{"$ref": "#/components/schemas/Error"}),
+		},
+	}
+
+	return op
+}
+
+func jsonContent(schema any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+/*
+Describes the fields of a uri or query struct as parameters
+*/
+func (gen schemaGenerator) parameters(v any, in, tagKey string) []any {
+	var params []any
+	t := reflect.TypeOf(v)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get(tagKey), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		binding := field.Tag.Get("binding")
+		schema := gen.schema(field.Type)
+		applyBindingRules(schema, binding)
+		params = append(params, map[string]any{
+			"name":     name,
+			"in":       in,
+			"required": in == "path" || hasBindingRule(binding, "required"),
+			"schema":   schema,
+		})
+	}
+	return params
+}
+
+/*
+Schema of a type, structs outside request bodies are stored as components and referenced
+*/
+func (gen schemaGenerator) schema(t reflect.Type) map[string]any {
+	switch t {
+	case reflect.TypeOf(time.Time{}):
+		return map[string]any{"type": "string", "format": "date-time"}
+	case reflect.TypeOf(uuid.UUID{}):
+		return map[string]any{"type": "string", "format": "uuid"}
+	case reflect.TypeOf(uuid.NullUUID{}):
+		return map[string]any{"type": "string", "format": "uuid", "nullable": true}
+	case reflect.TypeOf(sql.NullTime{}):
+		return map[string]any{"type": "string", "format": "date-time", "nullable": true}
+	case reflect.TypeOf(sql.NullString{}):
+		return map[string]any{"type": "string", "nullable": true}
+	case reflect.TypeOf(json.RawMessage{}):
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := gen.schema(t.Elem())
+		if _, ref := schema["$ref"]; ref {
+			return map[string]any{"allOf": []any{schema}, "nullable": true}
+		}
+		schema["nullable"] = true
+		return schema
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int32:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case reflect.Int64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number", "format": "double"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": gen.schema(t.Elem())}
+	case reflect.Interface:
+		return map[string]any{}
+	case reflect.Struct:
+		name := schemaName(t)
+		if seen, ok := gen.types[name]; ok && seen != t {
+			panic(fmt.Sprintf("openapi: %s and %s share the schema name %s", seen, t, name))
+		}
+		if _, ok := gen.types[name]; !ok {
+			// Register the type first so recursive types terminate
+			gen.types[name] = t
+			gen.components[name] = gen.inlineSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	}
+	panic(fmt.Sprintf("openapi: unsupported type %s", t))
+}
+
+func schemaName(t reflect.Type) string {
+	name := t.Name()
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+/*
+Object schema listing the JSON fields of a struct, embedded structs are flattened like encoding/json does
+*/
+func (gen schemaGenerator) inlineSchema(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	gen.addFields(t, properties, &required)
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (gen schemaGenerator) addFields(t reflect.Type, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			gen.addFields(field.Type, properties, required)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		binding := field.Tag.Get("binding")
+		schema := gen.schema(field.Type)
+		applyBindingRules(schema, binding)
+		properties[name] = schema
+		if hasBindingRule(binding, "required") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+func hasBindingRule(binding, rule string) bool {
+	for _, r := range strings.Split(binding, ",") {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Translates the validator rules the handlers bind with into schema constraints. Rules after dive apply to the items of
+an array.
+*/
+func applyBindingRules(schema map[string]any, binding string) {
+	if binding == "" {
+		return
+	}
+	binding, itemBinding, dive := strings.Cut(binding, ",dive")
+	if items, ok := schema["items"].(map[string]any); ok && dive {
+		applyBindingRules(items, strings.TrimPrefix(itemBinding, ","))
+	}
+	array := schema["type"] == "array"
+	numeric := schema["type"] == "number" || schema["type"] == "integer"
+	for _, rule := range strings.Split(binding, ",") {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "email":
+			schema["format"] = "email"
+		case "uuid":
+			schema["format"] = "uuid"
+		case "url", "http_url":
+			schema["format"] = "uri"
+		case "alphanum":
+			schema["pattern"] = "^[a-zA-Z0-9]+$"
+		case "numeric":
+			schema["pattern"] = "^[0-9]+$"
+		case "datetime":
+			schema["format"] = "date"
+		case "currency":
+			schema["enum"] = []string{util.USD, util.EUR, util.CAD}
+		case "role":
+			schema["enum"] = []string{util.CustomerRole, util.SupportRole, util.AdminRole}
+		case "webhook_event":
+			schema["enum"] = database.WebhookEventTypes
+		case "oneof":
+			schema["enum"] = strings.Fields(value)
+		case "len", "min", "max", "gt", "gte":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			switch {
+			case array && (key == "min" || key == "len"):
+				schema["minItems"] = int(n)
+				if key == "len" {
+					schema["maxItems"] = int(n)
+				}
+			case array && key == "max":
+				schema["maxItems"] = int(n)
+			case numeric && (key == "min" || key == "gte"):
+				schema["minimum"] = n
+			case numeric && key == "gt":
+				schema["minimum"] = n
+				schema["exclusiveMinimum"] = true
+			case numeric && key == "max":
+				schema["maximum"] = n
+			case !numeric && (key == "min" || key == "len"):
+				schema["minLength"] = int(n)
+				if key == "len" {
+					schema["maxLength"] = int(n)
+				}
+			case !numeric && key == "max":
+				schema["maxLength"] = int(n)
+			}
+		}
+	}
+}
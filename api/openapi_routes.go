@@ -0,0 +1,260 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/util"
+)
+
+/*
+Every route registered in setupRouter. TestOpenAPIRoutes fails when this table and the router drift apart.
+*/
+var routeDocs = []routeDoc{
+	// Users and sessions
+	{
+		Method: http.MethodPost, Path: "/users", Tag: "users", Summary: "Create a user",
+		Body: createUserRequest{}, Status: http.StatusOK, Responses: []any{userResponse{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/users/login", Tag: "sessions", Summary: "Log in, or get an MFA challenge when TOTP is enabled",
+		Body: loginUserRequest{}, Status: http.StatusOK, Responses: []any{loginUserResponse{}, mfaChallengeResponse{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/users/login/mfa", Tag: "sessions", Summary: "Complete a login with a TOTP or recovery code",
+		Body: verifyLoginMFARequest{}, Status: http.StatusOK, Responses: []any{loginUserResponse{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/users/refresh", Tag: "sessions", Summary: "Renew an access token",
+		Body: refreshTokenRequest{}, Status: http.StatusOK, Responses: []any{refreshTokenResponse{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/users/password-reset", Tag: "users", Summary: "Email a password reset link",
+		Body: requestPasswordResetRequest{}, Status: http.StatusAccepted, Responses: []any{requestPasswordResetResponse{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/users/password-reset/confirm", Tag: "users", Summary: "Set a new password with a reset token",
+		Body: confirmPasswordResetRequest{}, Status: http.StatusOK, Responses: []any{userResponse{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/users/verify-email", Tag: "users", Summary: "Verify an email address",
+		Body: verifyEmailRequest{}, Status: http.StatusOK, Responses: []any{userResponse{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/users/verify-email/resend", Tag: "users", Summary: "Resend the verification email",
+		Auth: true, Status: http.StatusAccepted,
+	},
+	{
+		Method: http.MethodPost, Path: "/users/password", Tag: "users", Summary: "Change the password",
+		Auth: true, Body: changeUserPasswordRequest{}, Status: http.StatusOK, Responses: []any{userResponse{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/users/me", Tag: "users", Summary: "Get the current user",
+		Auth: true, Status: http.StatusOK, Responses: []any{userResponse{}},
+	},
+	{
+		Method: http.MethodPatch, Path: "/users/me", Tag: "users", Summary: "Update the current user",
+		Auth: true, Body: updateCurrentUserRequest{}, Status: http.StatusOK, Responses: []any{userResponse{}},
+	},
+	{
+		Method: http.MethodDelete, Path: "/users/me", Tag: "users", Summary: "Delete the current user",
+		Auth: true, Body: deleteCurrentUserRequest{}, Status: http.StatusNoContent,
+	},
+	{
+		Method: http.MethodGet, Path: "/users/me/export", Tag: "users", Summary: "Export the current user's data",
+		Auth: true, Query: exportUserDataRequest{}, Status: http.StatusOK, Responses: []any{userExport{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/users/me/kyc", Tag: "kyc", Summary: "Submit identity documents",
+		Auth: true, Body: submitKYCRequest{}, Status: http.StatusCreated, Responses: []any{database.KycSubmission{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/users/me/kyc", Tag: "kyc", Summary: "Get the KYC status",
+		Auth: true, Status: http.StatusOK, Responses: []any{kycStatusResponse{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/users/totp", Tag: "users", Summary: "Start TOTP enrollment",
+		Auth: true, Status: http.StatusOK, Responses: []any{enrollTOTPResponse{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/users/totp/confirm", Tag: "users", Summary: "Confirm TOTP enrollment",
+		Auth: true, Body: confirmTOTPRequest{}, Status: http.StatusOK, Responses: []any{confirmTOTPResponse{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/users/me/grants", Tag: "grants", Summary: "List grants received by the current user",
+		Auth: true, Status: http.StatusOK, Responses: []any{[]database.AccountGrant{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/users/me/account-invitations", Tag: "holders", Summary: "List pending account invitations",
+		Auth: true, Status: http.StatusOK, Responses: []any{[]database.AccountHolder{}},
+	},
+
+	// Accounts
+	{
+		Method: http.MethodPost, Path: "/accounts", Tag: "accounts", Summary: "Create an account or a pot",
+		Auth: true, Body: createAccountRequest{}, Status: http.StatusOK, Responses: []any{database.Account{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/accounts", Tag: "accounts", Summary: "List accounts with per currency balances",
+		Auth: true, Query: pageRequest{}, Status: http.StatusOK, Responses: []any{accountListResponse{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/accounts/stream", Tag: "accounts", Summary: "Stream balance changes as server-sent events",
+		Auth: true, Query: streamBalancesRequest{}, Status: http.StatusOK,
+	},
+	{
+		Method: http.MethodGet, Path: "/accounts/:id", Tag: "accounts", Summary: "Get an account",
+		Auth: true, URI: GetAccountRequest{}, Status: http.StatusOK, Responses: []any{database.Account{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/accounts/:id/sub-accounts", Tag: "accounts", Summary: "List the pots of an account",
+		Auth: true, URI: accountHolderURI{}, Status: http.StatusOK, Responses: []any{[]database.Account{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/accounts/:id/entries", Tag: "accounts", Summary: "List the entries of an account",
+		Auth: true, URI: accountHolderURI{}, Query: pageRequest{}, Status: http.StatusOK, Responses: []any{entryListResponse{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/accounts/:id/transfers", Tag: "transfers", Summary: "List the transfers of an account",
+		Auth: true, URI: accountHolderURI{}, Query: pageRequest{}, Status: http.StatusOK,
+		Responses: []any{transferListResponse{}},
+	},
+	{
+		Method: http.MethodPut, Path: "/accounts/:id/approval-threshold", Tag: "accounts", Summary: "Set the transfer approval threshold",
+		Auth: true, URI: accountHolderURI{}, Body: approvalThresholdRequest{}, Status: http.StatusOK,
+		Responses: []any{database.Account{}, database.ApprovalThresholdChange{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/approval-threshold-changes/:id", Tag: "accounts", Summary: "Get an approval threshold change",
+		Auth: true, URI: thresholdChangeURI{}, Status: http.StatusOK, Responses: []any{database.ApprovalThresholdChange{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/approval-threshold-changes/:id/approve", Tag: "accounts", Summary: "Approve lowering an approval threshold",
+		Auth: true, URI: thresholdChangeURI{}, Status: http.StatusOK, Responses: []any{database.DecideThresholdChangeTxResult{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/approval-threshold-changes/:id/reject", Tag: "accounts", Summary: "Reject lowering an approval threshold",
+		Auth: true, URI: thresholdChangeURI{}, Status: http.StatusOK, Responses: []any{database.DecideThresholdChangeTxResult{}},
+	},
+
+	// Holders and grants
+	{
+		Method: http.MethodGet, Path: "/accounts/:id/holders", Tag: "holders", Summary: "List account holders",
+		Auth: true, URI: accountHolderURI{}, Status: http.StatusOK, Responses: []any{[]database.AccountHolder{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/accounts/:id/holders", Tag: "holders", Summary: "Invite an account holder",
+		Auth: true, URI: accountHolderURI{}, Body: inviteAccountHolderRequest{}, Status: http.StatusCreated,
+		Responses: []any{database.AccountHolder{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/accounts/:id/holders/accept", Tag: "holders", Summary: "Accept an account invitation",
+		Auth: true, URI: accountHolderURI{}, Status: http.StatusOK, Responses: []any{database.AccountHolder{}},
+	},
+	{
+		Method: http.MethodDelete, Path: "/accounts/:id/holders/:username", Tag: "holders", Summary: "Remove an account holder",
+		Auth: true, URI: removeAccountHolderURI{}, Status: http.StatusNoContent,
+	},
+	{
+		Method: http.MethodGet, Path: "/accounts/:id/grants", Tag: "grants", Summary: "List account grants",
+		Auth: true, URI: accountHolderURI{}, Status: http.StatusOK, Responses: []any{[]database.AccountGrant{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/accounts/:id/grants", Tag: "grants", Summary: "Grant delegated access to an account",
+		Auth: true, URI: accountHolderURI{}, Body: createAccountGrantRequest{}, Status: http.StatusCreated,
+		Responses: []any{database.AccountGrant{}},
+	},
+	{
+		Method: http.MethodDelete, Path: "/accounts/:id/grants/:grantId", Tag: "grants", Summary: "Revoke an account grant",
+		Auth: true, URI: accountGrantURI{}, Status: http.StatusNoContent,
+	},
+
+	// Transfers
+	{
+		Method: http.MethodPost, Path: "/transfers", Tag: "transfers", Summary: "Transfer money, or request approval above the account threshold",
+		Auth: true, Body: transferRequest{}, Status: http.StatusOK,
+		Responses: []any{database.TransferTxResult{}, database.TransferRequest{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/transfers/step-up", Tag: "transfers", Summary: "Get a step-up token for a large transfer",
+		Auth: true, Body: transferStepUpRequest{}, Status: http.StatusOK, Responses: []any{transferStepUpResponse{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/transfers/internal", Tag: "transfers", Summary: "Move money between an account and its pots",
+		Auth: true, Body: internalTransferRequest{}, Status: http.StatusOK, Responses: []any{database.TransferTxResult{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/accounts/:id/transfer-requests", Tag: "transfers", Summary: "List transfer requests of an account",
+		Auth: true, URI: accountHolderURI{}, Query: pageRequest{}, Status: http.StatusOK,
+		Responses: []any{transferRequestListResponse{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/transfer-requests/:id", Tag: "transfers", Summary: "Get a transfer request and its approvals",
+		Auth: true, URI: transferRequestURI{}, Status: http.StatusOK, Responses: []any{transferRequestResponse{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/transfer-requests/:id/approve", Tag: "transfers", Summary: "Approve a transfer request",
+		Auth: true, URI: transferRequestURI{}, Body: approveTransferRequest{}, Status: http.StatusOK,
+		Responses: []any{database.DecideTransferTxResult{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/transfer-requests/:id/reject", Tag: "transfers", Summary: "Reject a transfer request",
+		Auth: true, URI: transferRequestURI{}, Body: rejectTransferRequest{}, Status: http.StatusOK,
+		Responses: []any{database.DecideTransferTxResult{}},
+	},
+
+	// Webhooks
+	{
+		Method: http.MethodGet, Path: "/webhooks", Tag: "webhooks", Summary: "List your webhooks",
+		Auth: true, Status: http.StatusOK, Responses: []any{webhookListResponse{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/webhooks", Tag: "webhooks", Summary: "Register a webhook",
+		Auth: true, Body: createWebhookRequest{}, Status: http.StatusCreated, Responses: []any{createWebhookResponse{}},
+	},
+	{
+		Method: http.MethodDelete, Path: "/webhooks/:id", Tag: "webhooks", Summary: "Delete a webhook",
+		Auth: true, URI: webhookURI{}, Status: http.StatusNoContent,
+	},
+	{
+		Method: http.MethodGet, Path: "/webhooks/:id/deliveries", Tag: "webhooks", Summary: "List the deliveries of a webhook",
+		Auth: true, URI: webhookURI{}, Query: pageRequest{}, Status: http.StatusOK,
+		Responses: []any{webhookDeliveryListResponse{}},
+	},
+
+	// Administration
+	{
+		Method: http.MethodGet, Path: "/admin/users/:username", Tag: "admin", Summary: "Get a user",
+		Auth: true, Scope: util.ScopeUsersRead, URI: getUserRequest{}, Status: http.StatusOK, Responses: []any{userResponse{}},
+	},
+	{
+		Method: http.MethodPut, Path: "/admin/users/:username/role", Tag: "admin", Summary: "Change the role of a user",
+		Auth: true, Scope: util.ScopeUsersWrite, URI: getUserRequest{}, Body: updateUserRoleRequest{}, Status: http.StatusOK,
+		Responses: []any{userResponse{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/admin/kyc", Tag: "admin", Summary: "List pending KYC submissions",
+		Auth: true, Scope: util.ScopeKYCReview, Query: listPendingKYCRequest{}, Status: http.StatusOK,
+		Responses: []any{[]database.KycSubmission{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/admin/kyc/:id/review", Tag: "admin", Summary: "Review a KYC submission",
+		Auth: true, Scope: util.ScopeKYCReview, URI: reviewKYCURI{}, Body: reviewKYCRequest{}, Status: http.StatusOK,
+		Responses: []any{database.KycSubmission{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/admin/accounts/:id/freeze", Tag: "admin", Summary: "Freeze an account",
+		Auth: true, Scope: util.ScopeAccountsFreeze, URI: GetAccountRequest{}, Status: http.StatusOK,
+		Responses: []any{database.Account{}},
+	},
+	{
+		Method: http.MethodPost, Path: "/admin/accounts/:id/unfreeze", Tag: "admin", Summary: "Unfreeze an account",
+		Auth: true, Scope: util.ScopeAccountsFreeze, URI: GetAccountRequest{}, Status: http.StatusOK,
+		Responses: []any{database.Account{}},
+	},
+	{
+		Method: http.MethodGet, Path: "/admin/reports/ledger", Tag: "admin", Summary: "Ledger totals per currency",
+		Auth: true, Scope: util.ScopeReportsRead, Query: ledgerReportRequest{}, Status: http.StatusOK,
+		Responses: []any{ledgerReportResponse{}},
+	},
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var undocumentedRoutes = map[string]bool{
+	"GET /openapi.json":           true,
+	"GET /docs":                   true,
+	"GET /docs/assets/*filepath":  true,
+	"HEAD /docs/assets/*filepath": true,
+}
+
+func TestOpenAPIRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mock_db.NewMockStore(ctrl))
+
+	registered := map[string]bool{}
+	for _, route := range server.router.Routes() {
+		key := route.Method + " " + route.Path
+		if !undocumentedRoutes[key] {
+			registered[key] = true
+		}
+	}
+
+	documented := map[string]bool{}
+	for _, doc := range routeDocs {
+		key := doc.Method + " " + doc.Path
+		require.False(t, documented[key], "%s is documented twice", key)
+		documented[key] = true
+		require.True(t, registered[key], "%s is documented but not registered in setupRouter", key)
+
+		// Every path segment has to be described by the uri struct the handler binds
+		var uriParams []string
+		if doc.URI != nil {
+			uriType := reflect.TypeOf(doc.URI)
+			for i := 0; i < uriType.NumField(); i++ {
+				uriParams = append(uriParams, uriType.Field(i).Tag.Get("uri"))
+			}
+		}
+		var pathParams []string
+		for _, match := range ginPathParam.FindAllStringSubmatch(doc.Path, -1) {
+			pathParams = append(pathParams, match[1])
+		}
+		require.ElementsMatch(t, pathParams, uriParams, "path parameters of %s", key)
+	}
+
+	for key := range registered {
+		require.True(t, documented[key], "%s is registered in setupRouter but missing from routeDocs", key)
+	}
+}
+
+func TestGetOpenAPISpec(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mock_db.NewMockStore(ctrl))
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/openapi.json", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var spec struct {
+		OpenAPI    string                               `json:"openapi"`
+		Paths      map[string]map[string]map[string]any `json:"paths"`
+		Components struct {
+			Schemas map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	body := recorder.Body.String()
+	require.NoError(t, json.Unmarshal([]byte(body), &spec))
+	require.Equal(t, "3.0.3", spec.OpenAPI)
+
+	operations := 0
+	for path, item := range spec.Paths {
+		require.NotContains(t, path, ":")
+		operations += len(item)
+	}
+	require.Equal(t, len(routeDocs), operations)
+
+	login := spec.Paths["/users/login"]["post"]
+	require.NotContains(t, login, "security")
+	require.Contains(t, spec.Paths["/users/me"]["get"], "security")
+
+	// Every reference points at a generated schema
+	for _, match := range regexp.MustCompile(`"#/components/schemas/([A-Za-z]+)"`).FindAllStringSubmatch(body, -1) {
+		require.Contains(t, spec.Components.Schemas, match[1])
+	}
+	require.Contains(t, body, `"FromAccountId"`)
+}
+
+func TestGetOpenAPIDocs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mock_db.NewMockStore(ctrl))
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/docs", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Header().Get("Content-Security-Policy"), "default-src 'self'")
+
+	// Every script and stylesheet comes from the server itself
+	page := recorder.Body.String()
+	require.NotContains(t, page, "://")
+	for _, match := range regexp.MustCompile(`(?:src|href)="([^"]+)"`).FindAllStringSubmatch(page, -1) {
+		require.True(t, strings.HasPrefix(match[1], "/docs/assets/"), match[1])
+	}
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/docs/assets/swagger-initializer.js", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "/openapi.json")
+}
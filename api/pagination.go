@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/julianinsua/the_simp_bank/util"
+)
+
+/*
+Query parameters of cursor paginated lists. Size defaults to the configured page size.
+*/
+type pageRequest struct {
+	Cursor string `form:"cursor"`
+	Size   int32  `form:"size" binding:"omitempty,min=1"`
+}
+
+/*
+Links to the neighbouring pages, empty when there's no page in that direction
+*/
+type pageLinks struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+/*
+Binds the page query parameters. Responds to the client and returns false when they are invalid.
+*/
+func (srv *Server) bindPage(ctx *gin.Context) (cur util.PageCursor, size int32, ok bool) {
+	var req pageRequest
+	err := ctx.ShouldBindQuery(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	cur, err = util.DecodePageCursor(req.Cursor)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	size, err = util.PageSize(req.Size, srv.config.PageDefaultSize, srv.config.PageMaxSize)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	return cur, size, true
+}
+
+/*
+Builds the links to the neighbouring pages on the URL of the current request
+*/
+func newPageLinks(ctx *gin.Context, size int32, next, prev *util.PageCursor) pageLinks {
+	link := func(cur *util.PageCursor) string {
+		if cur == nil {
+			return ""
+		}
+		u := *ctx.Request.URL
+		query := u.Query()
+		query.Set("cursor", cur.Encode())
+		query.Set("size", strconv.Itoa(int(size)))
+		u.RawQuery = query.Encode()
+		return u.RequestURI()
+	}
+	return pageLinks{Next: link(next), Prev: link(prev)}
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/mail"
+	"github.com/julianinsua/the_simp_bank/util"
+)
+
+const passwordResetTokenSize = 32
+
+var (
+	errInvalidResetToken      = errors.New("password reset token is invalid or expired")
+	errPasswordResetThrottled = errors.New("too many password reset requests")
+)
+
+type requestPasswordResetRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type requestPasswordResetResponse struct {
+	Message string `json:"message"`
+}
+
+/*
+Emails a single use password reset token. The response is the same whether the email belongs to a user or not, and it
+goes out before the user is looked up so its timing doesn't tell either, the email is sent in the background and
+graceful shutdowns wait for it. Requests are throttled per email and client IP.
+*/
+func (srv *Server) requestPasswordReset(ctx *gin.Context) {
+	var req requestPasswordResetRequest
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	if !srv.allowPasswordResetRequest(ctx, req.Email) {
+		return
+	}
+
+	// The request context is done once the response is written, keep its values for the logs
+	sendCtx := context.WithoutCancel(ctx.Request.Context())
+	srv.background.Add(1)
+	go func() {
+		defer srv.background.Done()
+		err := srv.sendPasswordReset(sendCtx, req.Email)
+		if err != nil {
+			srv.logger.ErrorContext(sendCtx, "unable to send password reset email", "error", err)
+		}
+	}()
+
+	ctx.JSON(http.StatusAccepted, requestPasswordResetResponse{
+		Message: "if the email belongs to an account, a password reset link has been sent to it",
+	})
+}
+
+/*
+Responds with 429 when the email or the client IP asked for too many resets lately, otherwise records the request.
+The wait doubles with every request inside the reset window. Emails are counted case-insensitively, so changing the
+case of an address doesn't get around the limit.
+*/
+func (srv *Server) allowPasswordResetRequest(ctx *gin.Context, email string) bool {
+	since := time.Now().Add(-srv.config.PasswordResetWindow)
+	email = strings.ToLower(email)
+
+	byEmail, err := srv.store.GetEmailPasswordResetRequests(ctx, database.GetEmailPasswordResetRequestsParams{
+		Email: email,
+		Since: since,
+	})
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return false
+	}
+
+	byIP, err := srv.store.GetClientIPPasswordResetRequests(ctx, database.GetClientIPPasswordResetRequestsParams{
+		ClientIp: ctx.ClientIP(),
+		Since:    since,
+	})
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return false
+	}
+
+	wait := util.PasswordResetRetryAfter(byEmail.Requests, byEmail.LastRequestAt, srv.config.PasswordResetMaxRequests, srv.config)
+	ipWait := util.PasswordResetRetryAfter(byIP.Requests, byIP.LastRequestAt, srv.config.PasswordResetMaxIPRequests, srv.config)
+	if ipWait > wait {
+		wait = ipWait
+	}
+	if wait > 0 {
+		ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		respondWithError(ctx, http.StatusTooManyRequests, fmt.Errorf("%w, retry in %v", errPasswordResetThrottled, wait.Round(time.Second)))
+		return false
+	}
+
+	err = srv.store.CreatePasswordResetRequest(ctx, database.CreatePasswordResetRequestParams{
+		Email:    email,
+		ClientIp: ctx.ClientIP(),
+	})
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return false
+	}
+	return true
+}
+
+/*
+Creates a reset token for the user the email belongs to and mails it, does nothing for unknown emails
+*/
+func (srv *Server) sendPasswordReset(ctx context.Context, email string) error {
+	usr, err := srv.store.GetUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	resetToken, err := util.GenerateSecureToken(passwordResetTokenSize)
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(srv.config.PasswordResetDuration)
+	_, err = srv.store.CreatePasswordResetToken(ctx, database.CreatePasswordResetTokenParams{
+		Username:    usr.Username,
+		HashedToken: util.HashSecureToken(resetToken),
+		ExpiresAt:   expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	return srv.mailer.Send(ctx, mail.Message{
+		To:      usr.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse this token to choose a new password before %s:\n\n%s\n\nIf you didn't ask for it you can ignore this email.",
+			usr.FullName,
+			expiresAt.Format(time.RFC1123),
+			resetToken,
+		),
+	})
+}
+
+type confirmPasswordResetRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required"`
+}
+
+/*
+Sets a new password using an emailed reset token and blocks every existing session
+*/
+func (srv *Server) confirmPasswordReset(ctx *gin.Context) {
+	var req confirmPasswordResetRequest
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	err = srv.passwordPolicy.Validate(req.NewPassword)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	resetToken, err := srv.store.GetPasswordResetToken(ctx, util.HashSecureToken(req.Token))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(ctx, http.StatusBadRequest, errInvalidResetToken)
+			return
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if resetToken.Used || time.Now().After(resetToken.ExpiresAt) {
+		respondWithError(ctx, http.StatusBadRequest, errInvalidResetToken)
+		return
+	}
+
+	hash, err := srv.passwordHasher.Hash(req.NewPassword)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	usr, err := srv.store.ResetPasswordTx(ctx, database.ResetPasswordTxParams{
+		TokenID:           resetToken.ID,
+		Username:          resetToken.Username,
+		HashedPassword:    hash,
+		PasswordChangedAt: time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, database.ErrResetTokenUnavailable) {
+			respondWithError(ctx, http.StatusBadRequest, errInvalidResetToken)
+			return
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(usr))
+}
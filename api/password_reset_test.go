@@ -0,0 +1,342 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/mail"
+	mock_mail "github.com/julianinsua/the_simp_bank/mail/mock"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+// Stubs the reset requests recently made for the email and from the client IP, both last made right now
+func stubResetRequests(store *mock_db.MockStore, byEmail, byIP int64) {
+	store.EXPECT().
+		GetEmailPasswordResetRequests(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(database.GetEmailPasswordResetRequestsRow{Requests: byEmail, LastRequestAt: time.Now()}, nil)
+	store.EXPECT().
+		GetClientIPPasswordResetRequests(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(database.GetClientIPPasswordResetRequestsRow{Requests: byIP, LastRequestAt: time.Now()}, nil)
+}
+
+type emailResetRequestsMatcher string
+
+func (m emailResetRequestsMatcher) Matches(x interface{}) bool {
+	arg, ok := x.(database.GetEmailPasswordResetRequestsParams)
+	return ok && arg.Email == string(m)
+}
+
+func (m emailResetRequestsMatcher) String() string {
+	return fmt.Sprintf("reset requests for %s", string(m))
+}
+
+func emailResetRequests(email string) gomock.Matcher {
+	return emailResetRequestsMatcher(email)
+}
+
+func TestRequestPasswordReset(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		email         string
+		buildStubs    func(store *mock_db.MockStore, mailer *mock_mail.MockMailer)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			email: user.Email,
+			buildStubs: func(store *mock_db.MockStore, mailer *mock_mail.MockMailer) {
+				var hashedToken string
+				stubResetRequests(store, 0, 0)
+				store.EXPECT().
+					CreatePasswordResetRequest(gomock.Any(), gomock.Eq(database.CreatePasswordResetRequestParams{Email: strings.ToLower(user.Email), ClientIp: "192.0.2.1"})).
+					Times(1).
+					Return(nil)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().
+					CreatePasswordResetToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg database.CreatePasswordResetTokenParams) (database.PasswordResetToken, error) {
+						require.Equal(t, user.Username, arg.Username)
+						hashedToken = arg.HashedToken
+						return database.PasswordResetToken{}, nil
+					})
+				mailer.EXPECT().
+					Send(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, msg mail.Message) error {
+						require.Equal(t, user.Email, msg.To)
+						// The email carries the token whose hash was stored
+						found := false
+						for _, word := range bytes.Fields([]byte(msg.Body)) {
+							if util.HashSecureToken(string(word)) == hashedToken {
+								found = true
+							}
+						}
+						require.True(t, found)
+						return nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name:  "UnknownEmail",
+			email: util.RandomEmail(),
+			buildStubs: func(store *mock_db.MockStore, mailer *mock_mail.MockMailer) {
+				stubResetRequests(store, 0, 0)
+				store.EXPECT().CreatePasswordResetRequest(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Times(1).Return(database.User{}, sql.ErrNoRows)
+				store.EXPECT().CreatePasswordResetToken(gomock.Any(), gomock.Any()).Times(0)
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name:  "MailerError",
+			email: user.Email,
+			buildStubs: func(store *mock_db.MockStore, mailer *mock_mail.MockMailer) {
+				stubResetRequests(store, 0, 0)
+				store.EXPECT().CreatePasswordResetRequest(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().CreatePasswordResetToken(gomock.Any(), gomock.Any()).Times(1)
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(1).Return(fmt.Errorf("smtp down"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name:  "EmailCaseIgnoredByThrottle",
+			email: "Mixed.Case@Example.com",
+			buildStubs: func(store *mock_db.MockStore, mailer *mock_mail.MockMailer) {
+				store.EXPECT().
+					GetEmailPasswordResetRequests(gomock.Any(), emailResetRequests("mixed.case@example.com")).
+					Times(1).
+					Return(database.GetEmailPasswordResetRequestsRow{}, nil)
+				store.EXPECT().GetClientIPPasswordResetRequests(gomock.Any(), gomock.Any()).Times(1)
+				store.EXPECT().
+					CreatePasswordResetRequest(gomock.Any(), gomock.Eq(database.CreatePasswordResetRequestParams{Email: "mixed.case@example.com", ClientIp: "192.0.2.1"})).
+					Times(1).
+					Return(nil)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Times(1).Return(database.User{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name:  "EmailThrottled",
+			email: user.Email,
+			buildStubs: func(store *mock_db.MockStore, mailer *mock_mail.MockMailer) {
+				stubResetRequests(store, 2, 2)
+				store.EXPECT().CreatePasswordResetRequest(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Times(0)
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+				// The reset backoff applies, not the login one
+				require.Equal(t, "4", recorder.Header().Get("Retry-After"))
+				requireBodyErrorCode(t, recorder.Body, "password_reset_throttled")
+			},
+		},
+		{
+			name:  "IPThrottled",
+			email: util.RandomEmail(),
+			buildStubs: func(store *mock_db.MockStore, mailer *mock_mail.MockMailer) {
+				// Spread over many emails, the client IP is locked out
+				stubResetRequests(store, 0, 50)
+				store.EXPECT().CreatePasswordResetRequest(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+				require.Equal(t, "600", recorder.Header().Get("Retry-After"))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			mailer := mock_mail.NewMockMailer(ctrl)
+			tc.buildStubs(store, mailer)
+
+			server := newTestServerWithMailer(t, store, mailer)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"email": tc.email})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/password-reset", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.RemoteAddr = "192.0.2.1:4321"
+
+			server.router.ServeHTTP(recorder, request)
+			// The email is sent after answering
+			server.background.Wait()
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestConfirmPasswordReset(t *testing.T) {
+	user, _ := randomUser(t)
+	resetToken, err := util.GenerateSecureToken(passwordResetTokenSize)
+	require.NoError(t, err)
+
+	stored := database.PasswordResetToken{
+		ID:          uuid.New(),
+		Username:    user.Username,
+		HashedToken: util.HashSecureToken(resetToken),
+		ExpiresAt:   time.Now().Add(time.Minute),
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetPasswordResetToken(gomock.Any(), gomock.Eq(stored.HashedToken)).Times(1).Return(stored, nil)
+				store.EXPECT().ResetPasswordTx(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownToken",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetPasswordResetToken(gomock.Any(), gomock.Any()).Times(1).Return(database.PasswordResetToken{}, sql.ErrNoRows)
+				store.EXPECT().ResetPasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ExpiredToken",
+			buildStubs: func(store *mock_db.MockStore) {
+				expired := stored
+				expired.ExpiresAt = time.Now().Add(-time.Minute)
+				store.EXPECT().GetPasswordResetToken(gomock.Any(), gomock.Any()).Times(1).Return(expired, nil)
+				store.EXPECT().ResetPasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UsedConcurrently",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetPasswordResetToken(gomock.Any(), gomock.Any()).Times(1).Return(stored, nil)
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(database.User{}, fmt.Errorf("unable to execute transaction: %w", database.ErrResetTokenUnavailable))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"token": resetToken, "newPassword": util.RandomString(10)})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/password-reset/confirm", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestShutdownWaitsForResetEmail(t *testing.T) {
+	user, _ := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mock_db.NewMockStore(ctrl)
+	mailer := mock_mail.NewMockMailer(ctrl)
+	stubResetRequests(store, 0, 0)
+	store.EXPECT().CreatePasswordResetRequest(gomock.Any(), gomock.Any()).Times(1).Return(nil)
+	store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+	store.EXPECT().CreatePasswordResetToken(gomock.Any(), gomock.Any()).Times(1)
+
+	// The mailer is slow, the server has answered long before the email goes out
+	release := make(chan struct{})
+	sent := make(chan struct{})
+	mailer.EXPECT().
+		Send(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ interface{}, _ mail.Message) error {
+			<-release
+			close(sent)
+			return nil
+		})
+
+	server := newTestServerWithMailer(t, store, mailer)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{"email": user.Email})
+	require.NoError(t, err)
+	request, err := http.NewRequest(http.MethodPost, "/users/password-reset", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusAccepted, recorder.Code)
+
+	// Gives up once its context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, server.Shutdown(ctx), context.DeadlineExceeded)
+
+	close(release)
+	require.NoError(t, server.Shutdown(context.Background()))
+	select {
+	case <-sent:
+	default:
+		t.Fatal("shutdown returned before the reset email was sent")
+	}
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/policy"
+)
+
+/*
+The account and transfer rules, shared with the gRPC server. Built on every call so it sees the current config.
+*/
+func (s Server) policy() policy.Policy {
+	return policy.New(s.config, s.store)
+}
+
+/*
+Responds to the client when a policy check failed and reports whether the request can go on. Policy errors carry their
+own status through errcode, anything else means the check couldn't run.
+*/
+func policyAllowed(ctx *gin.Context, err error) bool {
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return false
+	}
+	return true
+}
+
+/*
+Checks that a user accepted a holding on an account with one of the given roles, any role when none are given.
+Responds to the client and returns false when the user can't act on the account.
+*/
+func (s Server) accountHolderAllowed(ctx *gin.Context, accID uuid.UUID, username string, roles ...string) (database.AccountHolder, bool) {
+	holder, err := s.policy().HolderAllowed(ctx, accID, username, roles...)
+	return holder, policyAllowed(ctx, err)
+}
+
+/*
+Checks that a user can see an account, as any of its holders or through any active grant
+*/
+func (s Server) accountViewAllowed(ctx *gin.Context, accID uuid.UUID, username string) bool {
+	return policyAllowed(ctx, s.policy().ViewAllowed(ctx, accID, username))
+}
+
+/*
+Checks that a user can move money out of an account, as an owner or co-owner or through a grant with enough of its
+transfer limit left. Returns the grant the transfer is made under, invalid for holders.
+*/
+func (s Server) accountTransferGrant(ctx *gin.Context, accID uuid.UUID, username string, amount float64) (uuid.NullUUID, bool) {
+	grantID, err := s.policy().TransferGrant(ctx, accID, username, amount)
+	return grantID, policyAllowed(ctx, err)
+}
+
+/*
+Checks that a user can approve or reject transfers out of an account
+*/
+func (s Server) accountApproveAllowed(ctx *gin.Context, accID uuid.UUID, username string) bool {
+	return policyAllowed(ctx, s.policy().ApproveAllowed(ctx, accID, username))
+}
+
+/*
+Returns an account a transfer in the given currency can use. Responds to the client and returns false otherwise.
+*/
+func (s Server) validAccount(ctx *gin.Context, accID uuid.UUID, currency string) (database.Account, bool) {
+	acc, err := s.policy().ValidAccount(ctx, accID, currency)
+	return acc, policyAllowed(ctx, err)
+}
+
+/*
+Enforces the sender policies for outgoing transfers, a verified email and a verified identity
+*/
+func (s Server) transfersAllowed(ctx *gin.Context, username string) bool {
+	return policyAllowed(ctx, s.policy().TransfersAllowed(ctx, username))
+}
+
+/*
+Enforces the maximum balance of accounts owned by unverified users
+*/
+func (s Server) incomingTransferAllowed(ctx *gin.Context, toAcc database.Account, amount float64) bool {
+	return policyAllowed(ctx, s.policy().IncomingTransferAllowed(ctx, toAcc, amount))
+}
+
+/*
+Enforces the KYC gate on account creation
+*/
+func (s Server) accountCreationAllowed(ctx *gin.Context, username string) bool {
+	return policyAllowed(ctx, s.policy().AccountCreationAllowed(ctx, username))
+}
+
+/*
+Checks that a pot can be created inside an account the user owns
+*/
+func (s Server) validParentAccount(ctx *gin.Context, parentID uuid.UUID, username, currency string) bool {
+	return policyAllowed(ctx, s.policy().ValidParentAccount(ctx, parentID, username, currency))
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+)
+
+type ledgerReportRequest struct {
+	From time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00" binding:"required"`
+	To   time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00" binding:"required,gtfield=From"`
+}
+
+/*
+Bank wide totals per currency: what every account holds right now and the money transferred during the period
+*/
+type ledgerReportResponse struct {
+	From      time.Time                                 `json:"from"`
+	To        time.Time                                 `json:"to"`
+	Balances  []database.ListCurrencyBalancesRow        `json:"balances"`
+	Transfers []database.ListCurrencyTransferVolumesRow `json:"transfers"`
+}
+
+/*
+Admin handler reporting the ledger totals per currency. Transfers are counted in the currency of the sending account
+from the start of the period up to, but not including, its end
+*/
+func (srv *Server) getLedgerReport(ctx *gin.Context) {
+	var req ledgerReportRequest
+	err := ctx.ShouldBindQuery(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	balances, err := srv.store.ListCurrencyBalances(ctx)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	transfers, err := srv.store.ListCurrencyTransferVolumes(ctx, database.ListCurrencyTransferVolumesParams{
+		PeriodStart: req.From,
+		PeriodEnd:   req.To,
+	})
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ledgerReportResponse{
+		From:      req.From,
+		To:        req.To,
+		Balances:  balances,
+		Transfers: transfers,
+	})
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLedgerReportAPI(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	balances := []database.ListCurrencyBalancesRow{
+		{Currency: util.EUR, Balance: 1500, Accounts: 3, FrozenAccounts: 1},
+		{Currency: util.USD, Balance: 200, Accounts: 1},
+	}
+	transfers := []database.ListCurrencyTransferVolumesRow{
+		{Currency: util.EUR, Transfers: 4, Volume: 320},
+	}
+
+	testCases := []struct {
+		name          string
+		role          string
+		query         url.Values
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			role:  util.AdminRole,
+			query: url.Values{"from": {from.Format(time.RFC3339)}, "to": {to.Format(time.RFC3339)}},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().ListCurrencyBalances(gomock.Any()).Times(1).Return(balances, nil)
+				store.EXPECT().
+					ListCurrencyTransferVolumes(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg database.ListCurrencyTransferVolumesParams) ([]database.ListCurrencyTransferVolumesRow, error) {
+						require.True(t, from.Equal(arg.PeriodStart))
+						require.True(t, to.Equal(arg.PeriodEnd))
+						return transfers, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res ledgerReportResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, balances, res.Balances)
+				require.Equal(t, transfers, res.Transfers)
+			},
+		},
+		{
+			name:  "PeriodEndsBeforeStart",
+			role:  util.AdminRole,
+			query: url.Values{"from": {to.Format(time.RFC3339)}, "to": {from.Format(time.RFC3339)}},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().ListCurrencyBalances(gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "MissingPeriod",
+			role:  util.AdminRole,
+			query: url.Values{},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().ListCurrencyBalances(gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "SupportCantReadReports",
+			role:  util.SupportRole,
+			query: url.Values{"from": {from.Format(time.RFC3339)}, "to": {to.Format(time.RFC3339)}},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().ListCurrencyBalances(gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalError",
+			role:  util.AdminRole,
+			query: url.Values{"from": {from.Format(time.RFC3339)}, "to": {to.Format(time.RFC3339)}},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().ListCurrencyBalances(gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+				store.EXPECT().ListCurrencyTransferVolumes(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			store.EXPECT().GetUserRole(gomock.Any(), gomock.Eq("admin")).AnyTimes().Return(tc.role, nil)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/admin/reports/ledger?"+tc.query.Encode(), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, "admin", tc.role, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -1,10 +1,17 @@
 package api
 
 import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/mail"
 	"github.com/julianinsua/the_simp_bank/token"
 	"github.com/julianinsua/the_simp_bank/util"
 	"github.com/pkg/errors"
@@ -12,19 +19,47 @@ import (
 
 /* Struct to generate a server*/
 type Server struct {
-	store      database.Store
-	tokenMaker token.PASETOMaker
-	router     *gin.Engine
-	config     util.Config
+	store          database.Store
+	tokenMaker     token.PASETOMaker
+	mailer         mail.Mailer
+	passwordPolicy util.PasswordPolicy
+	passwordHasher util.PasswordHasher
+	dummyHash      string
+	router         *gin.Engine
+	config         util.Config
+	balances       *balanceHub
+	background     *sync.WaitGroup // work a handler left running after answering, Shutdown waits for it
+	httpServer     *http.Server
+	logger         *slog.Logger
 }
 
 /* Create a new server struct, add routes andd return the server instance */
-func NewServer(config util.Config, store database.Store) (*Server, error) {
-	tokenMaker, err := token.NewPASETOMaker(config.SymetricKey)
+func NewServer(config util.Config, store database.Store, tokenMaker token.PASETOMaker, mailer mail.Mailer) (*Server, error) {
+	passwordPolicy, err := util.NewPasswordPolicy(config)
+	if err != nil {
+		return nil, errors.Errorf("couldn't load the password policy: %v", err)
+	}
+	passwordHasher, err := util.NewPasswordHasher(config)
+	if err != nil {
+		return nil, errors.Errorf("couldn't initialize the password hasher: %v", err)
+	}
+	// Hashed once so logins for unknown users cost the same as real ones
+	dummyHash, err := passwordHasher.Hash(util.RandomString(16))
 	if err != nil {
-		return nil, errors.Errorf("couldn't initialize JWT token generator: %v", err)
+		return nil, errors.Errorf("couldn't hash the dummy password: %v", err)
+	}
+	server := &Server{
+		store:          store,
+		tokenMaker:     tokenMaker,
+		mailer:         mailer,
+		passwordPolicy: passwordPolicy,
+		passwordHasher: passwordHasher,
+		dummyHash:      dummyHash,
+		config:         config,
+		balances:       newBalanceHub(),
+		background:     &sync.WaitGroup{},
+		logger:         slog.Default(),
 	}
-	server := &Server{store: store, tokenMaker: tokenMaker, config: config}
 
 	// Custom validation bindings
 	v, ok := binding.Validator.Engine().(*validator.Validate)
@@ -33,9 +68,19 @@ func NewServer(config util.Config, store database.Store) (*Server, error) {
 		if err != nil {
 			return nil, errors.Errorf("couldn't register custom currency validator: %v", err)
 		}
+		err = v.RegisterValidation("role", validRole)
+		if err != nil {
+			return nil, errors.Errorf("couldn't register custom role validator: %v", err)
+		}
+		err = v.RegisterValidation("webhook_event", validWebhookEvent)
+		if err != nil {
+			return nil, errors.Errorf("couldn't register custom webhook event validator: %v", err)
+		}
+		v.RegisterTagNameFunc(requestFieldName)
 	}
 
 	server.setupRouter()
+	server.httpServer = &http.Server{Handler: server.router}
 
 	return server, nil
 }
@@ -44,37 +89,123 @@ func NewServer(config util.Config, store database.Store) (*Server, error) {
 Includes all the handlers on their specific routes
 */
 func (srv *Server) setupRouter() {
-	router := gin.Default()
+	router := gin.New()
+	// Lets store calls made with the gin context see the request ID and cancellation of the request
+	router.ContextWithFallback = true
+	router.Use(requestIDMiddleware, srv.accessLog, httpMetrics, gin.CustomRecoveryWithWriter(io.Discard, srv.recoverPanic))
 
 	// Routes go here
 	router.POST("/users", srv.createUser)
 	router.POST("/users/login", srv.loginUser)
+	router.POST("/users/login/mfa", srv.verifyLoginMFA)
 	router.POST("/users/refresh", srv.refreshToken)
+	router.POST("/users/password-reset", srv.requestPasswordReset)
+	router.POST("/users/password-reset/confirm", srv.confirmPasswordReset)
+	router.POST("/users/verify-email", srv.verifyEmail)
+
+	// API documentation
+	router.GET("/openapi.json", srv.getOpenAPISpec)
+	router.GET("/docs", srv.getOpenAPIDocs)
+	if srv.config.DocsAssetsPath != "" {
+		router.Static("/docs/assets", srv.config.DocsAssetsPath)
+	}
 
 	// Authorized routes
-	authRoutes := router.Group("/").Use(authMiddleware(srv.tokenMaker))
+	authRoutes := router.Group("/").Use(authMiddleware(srv.tokenMaker, srv.auth()))
 
 	authRoutes.POST("/accounts", srv.createAccount)
 	authRoutes.GET("/accounts", srv.getAccountList)
+	authRoutes.GET("/accounts/stream", srv.streamBalances)
 	authRoutes.GET("/accounts/:id", srv.getAccount)
+	authRoutes.GET("/accounts/:id/sub-accounts", srv.listSubAccounts)
+	authRoutes.GET("/accounts/:id/entries", srv.listAccountEntries)
+	authRoutes.GET("/accounts/:id/transfers", srv.listAccountTransfers)
+	authRoutes.GET("/accounts/:id/holders", srv.listAccountHolders)
+	authRoutes.POST("/accounts/:id/holders", srv.inviteAccountHolder)
+	authRoutes.POST("/accounts/:id/holders/accept", srv.acceptAccountInvitation)
+	authRoutes.DELETE("/accounts/:id/holders/:username", srv.removeAccountHolder)
+	authRoutes.GET("/accounts/:id/grants", srv.listAccountGrants)
+	authRoutes.POST("/accounts/:id/grants", srv.createAccountGrant)
+	authRoutes.DELETE("/accounts/:id/grants/:grantId", srv.revokeAccountGrant)
+	authRoutes.GET("/users/me/grants", srv.listReceivedGrants)
+	authRoutes.PUT("/accounts/:id/approval-threshold", srv.updateApprovalThreshold)
+	authRoutes.GET("/accounts/:id/transfer-requests", srv.listTransferRequests)
+	authRoutes.GET("/transfer-requests/:id", srv.getTransferRequest)
+	authRoutes.POST("/transfer-requests/:id/approve", srv.approveTransferRequest)
+	authRoutes.POST("/transfer-requests/:id/reject", srv.rejectTransferRequest)
+	authRoutes.GET("/approval-threshold-changes/:id", srv.getThresholdChange)
+	authRoutes.POST("/approval-threshold-changes/:id/approve", srv.approveThresholdChange)
+	authRoutes.POST("/approval-threshold-changes/:id/reject", srv.rejectThresholdChange)
+	authRoutes.GET("/users/me/account-invitations", srv.listAccountInvitations)
 	authRoutes.POST("/transfers", srv.createTransfer)
+	authRoutes.POST("/transfers/step-up", srv.createTransferStepUp)
+	authRoutes.POST("/transfers/internal", srv.createInternalTransfer)
+	authRoutes.POST("/users/password", srv.changeUserPassword)
+	authRoutes.GET("/users/me", srv.getCurrentUser)
+	authRoutes.PATCH("/users/me", srv.updateCurrentUser)
+	authRoutes.DELETE("/users/me", srv.deleteCurrentUser)
+	authRoutes.GET("/users/me/export", srv.exportUserData)
+	authRoutes.POST("/users/me/kyc", srv.submitKYC)
+	authRoutes.GET("/users/me/kyc", srv.getKYCStatus)
+	authRoutes.POST("/users/verify-email/resend", srv.resendEmailVerification)
+	authRoutes.POST("/users/totp", srv.enrollTOTP)
+	authRoutes.POST("/users/totp/confirm", srv.confirmTOTP)
+	authRoutes.GET("/webhooks", srv.listWebhooks)
+	authRoutes.POST("/webhooks", srv.createWebhook)
+	authRoutes.DELETE("/webhooks/:id", srv.deleteWebhook)
+	authRoutes.GET("/webhooks/:id/deliveries", srv.listWebhookDeliveries)
+
+	// Administrative routes, restricted by the scopes of both the token and the current role of the user
+	adminRoutes := router.Group("/admin").Use(authMiddleware(srv.tokenMaker, srv.auth()))
+
+	adminRoutes.GET("/users/:username", srv.requireScope(util.ScopeUsersRead), srv.getUser)
+	adminRoutes.PUT("/users/:username/role", srv.requireScope(util.ScopeUsersWrite), srv.updateUserRole)
+	adminRoutes.GET("/kyc", srv.requireScope(util.ScopeKYCReview), srv.listPendingKYC)
+	adminRoutes.POST("/kyc/:id/review", srv.requireScope(util.ScopeKYCReview), srv.reviewKYC)
+	adminRoutes.POST("/accounts/:id/freeze", srv.requireScope(util.ScopeAccountsFreeze), srv.freezeAccount)
+	adminRoutes.POST("/accounts/:id/unfreeze", srv.requireScope(util.ScopeAccountsFreeze), srv.unfreezeAccount)
+	adminRoutes.GET("/reports/ledger", srv.requireScope(util.ScopeReportsRead), srv.getLedgerReport)
 
 	srv.router = router
 }
 
 /*
-Starts the http server on a specific address
+Starts the http server on a specific address, along with the background jobs. Blocks until the server stops, returns
+nil when it was stopped by Shutdown.
 */
 func (s *Server) Start(addr string) error {
-	return s.router.Run(addr)
+	if s.config.TransferRequestSweepInterval > 0 {
+		go s.expireTransferRequests(s.config.TransferRequestSweepInterval)
+	}
+	go s.listenBalanceChanges()
+
+	s.httpServer.Addr = addr
+	err := s.httpServer.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
 }
 
 /*
-Marshals an error into something gin can return to the user
-returns gin.H: map[string]interface{}
+Stops accepting requests and waits for the ones in flight, then for the work handlers left running after answering,
+like password reset emails. Gives up when ctx is done.
 */
-func errorResponse(err error) gin.H {
-	return gin.H{
-		"error": err.Error(),
+func (s *Server) Shutdown(ctx context.Context) error {
+	err := s.httpServer.Shutdown(ctx)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.background.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
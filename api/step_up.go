@@ -0,0 +1,164 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/julianinsua/the_simp_bank/auth"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/token"
+	"github.com/julianinsua/the_simp_bank/util"
+)
+
+var (
+	errStepUpRequired     = errors.New("step-up authentication required")
+	errStepUpTokenInvalid = errors.New("step-up token is invalid for this transfer")
+	errStepUpTokenUsed    = errors.New("step-up token has already been used")
+)
+
+/*
+Digest of the transfer a step-up token is issued for, so it can't be replayed on a different transfer
+*/
+func transferBinding(username string, req transferRequest) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%s|%s",
+		username,
+		req.FromAccountID,
+		req.ToAccountID,
+		strconv.FormatFloat(req.Amount, 'f', -1, 64),
+		req.Currency,
+	)))
+	return hex.EncodeToString(sum[:])
+}
+
+/*
+Transfer step-up body: the transfer itself plus either the current password or a TOTP code
+*/
+type transferStepUpRequest struct {
+	transferRequest
+	Password string `json:"password" binding:"required_without=Code"`
+	Code     string `json:"code" binding:"omitempty,numeric,len=6"`
+}
+
+type transferStepUpResponse struct {
+	StepUpToken          string    `json:"stepUpToken"`
+	StepUpTokenExpiresAt time.Time `json:"stepUpTokenExpiresAt"`
+}
+
+/*
+Re-authenticates the user for a single high value transfer and returns a one time token bound to its parameters
+*/
+func (s *Server) createTransferStepUp(ctx *gin.Context) {
+	var req transferStepUpRequest
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	if !s.reauthenticate(ctx, authPayload.Username, req.Password, req.Code) {
+		return
+	}
+
+	binding := transferBinding(authPayload.Username, req.transferRequest)
+	stepUpToken, payload, err := s.tokenMaker.CreatePurposeToken(authPayload.Username, token.PurposeStepUp, binding, s.config.StepUpTokenDuration)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, transferStepUpResponse{
+		StepUpToken:          stepUpToken,
+		StepUpTokenExpiresAt: payload.ExpiresAt,
+	})
+}
+
+/*
+Checks the current password or a TOTP code of a user who is already logged in, before an operation that needs a fresh
+proof of identity. Failures count towards the login throttle. Responds to the client and returns false if the user
+couldn't prove their identity.
+*/
+func (s *Server) reauthenticate(ctx *gin.Context, username, password, code string) bool {
+	if !s.allowLoginAttempt(ctx, username) {
+		return false
+	}
+
+	usr, err := s.store.GetUser(ctx, username)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return false
+	}
+
+	var valid bool
+	if code != "" && usr.TotpEnabled {
+		valid, err = s.useTOTPCode(ctx, usr, code)
+		if err != nil {
+			respondWithError(ctx, http.StatusInternalServerError, err)
+			return false
+		}
+	} else if code == "" {
+		valid = util.CheckPassword(password, usr.HashedPassword) == nil
+	}
+	if !valid {
+		err = s.recordLoginAttempt(ctx, usr.Username, auth.OutcomeInvalidCredentials)
+		if err != nil {
+			respondWithError(ctx, http.StatusInternalServerError, err)
+			return false
+		}
+		respondWithError(ctx, http.StatusUnauthorized, auth.ErrInvalidCredentials)
+		return false
+	}
+
+	err = s.recordLoginAttempt(ctx, usr.Username, auth.OutcomeStepUp)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return false
+	}
+	return true
+}
+
+/*
+Verifies the step-up token sent with a transfer. Returns the token to redeem in the same transaction as the transfer, so
+it's only spent if the transfer goes through. Responds to the client and returns false if the token isn't valid.
+*/
+func (s Server) verifyStepUpToken(ctx *gin.Context, username string, req transferRequest) (*database.ConsumeTokenParams, bool) {
+	if req.StepUpToken == "" {
+		err := fmt.Errorf("%w for transfers of %v or more", errStepUpRequired, s.config.StepUpThreshold)
+		respondWithError(ctx, http.StatusForbidden, err)
+		return nil, false
+	}
+
+	payload, err := s.tokenMaker.VerifyPurposeToken(req.StepUpToken, token.PurposeStepUp)
+	if err != nil {
+		respondWithError(ctx, http.StatusForbidden, err)
+		return nil, false
+	}
+	if payload.Username != username || payload.Binding != transferBinding(username, req) {
+		respondWithError(ctx, http.StatusForbidden, errStepUpTokenInvalid)
+		return nil, false
+	}
+
+	return &database.ConsumeTokenParams{
+		ID:        payload.ID,
+		Username:  payload.Username,
+		Purpose:   payload.Purpose,
+		ExpiresAt: payload.ExpiresAt,
+	}, true
+}
+
+/*
+Answers a failed transfer or transfer request, telling apart a step-up token redeemed by a concurrent request
+*/
+func respondWithTransferError(ctx *gin.Context, err error) {
+	if errors.Is(err, database.ErrTokenUsed) {
+		respondWithError(ctx, http.StatusForbidden, errStepUpTokenUsed)
+		return
+	}
+	respondWithError(ctx, http.StatusInternalServerError, err)
+}
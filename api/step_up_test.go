@@ -0,0 +1,346 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/auth"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/token"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTransferStepUp(t *testing.T) {
+	user, password := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		password      string
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			password: password,
+			buildStubs: func(store *mock_db.MockStore) {
+				stubLoginFailures(store, 0, time.Time{})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeStepUp)).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res transferStepUpResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.NotEmpty(t, res.StepUpToken)
+			},
+		},
+		{
+			name:     "WrongPassword",
+			password: "wrongPassword",
+			buildStubs: func(store *mock_db.MockStore) {
+				stubLoginFailures(store, 0, time.Time{})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeInvalidCredentials)).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "NoCredentials",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			body := gin.H{
+				"FromAccountId": "8c4a4b4e-33f4-4a8e-b8a1-7d2f3c6e9a10",
+				"ToAccountId":   "0f6b1c1e-5d2a-4e8b-9c3f-1a2b3c4d5e6f",
+				"amount":        5000,
+				"currency":      util.USD,
+			}
+			if tc.password != "" {
+				body["password"] = tc.password
+			}
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers/step-up", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestCreateTransferStepUpTOTP(t *testing.T) {
+	user, _ := randomTOTPUser(t)
+	code, err := util.TOTPCode(user.TotpSecret, time.Now())
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		lastStep   int64
+		buildStubs func(store *mock_db.MockStore)
+		status     int
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().AdvanceUserTOTPStep(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeStepUp)).Times(1)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:     "ReplayedCode",
+			lastStep: time.Now().Unix() / 30,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().AdvanceUserTOTPStep(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeInvalidCredentials)).Times(1)
+			},
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			usr := user
+			usr.TotpLastStep = tc.lastStep
+			store := mock_db.NewMockStore(ctrl)
+			stubLoginFailures(store, 0, time.Time{})
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(usr, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"FromAccountId": "8c4a4b4e-33f4-4a8e-b8a1-7d2f3c6e9a10",
+				"ToAccountId":   "0f6b1c1e-5d2a-4e8b-9c3f-1a2b3c4d5e6f",
+				"amount":        5000,
+				"currency":      util.USD,
+				"code":          code,
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers/step-up", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.status, recorder.Code)
+		})
+	}
+}
+
+func requireStepUpRedeemed(t *testing.T, stepUp *database.ConsumeTokenParams) {
+	require.NotNil(t, stepUp)
+	require.NotEqual(t, uuid.Nil, stepUp.ID)
+	require.Equal(t, token.PurposeStepUp, stepUp.Purpose)
+}
+
+func TestCreateTransferRequiresStepUp(t *testing.T) {
+	user, _ := randomUser(t)
+	fromAccount := randomAccount(user.Username)
+	toAccount := randomAccount(util.RandomOwner())
+	toAccount.Currency = fromAccount.Currency
+
+	req := transferRequest{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        5000,
+		Currency:      fromAccount.Currency,
+	}
+
+	stepUpToken := func(maker token.PASETOMaker, binding transferRequest) string {
+		tk, _, err := maker.CreatePurposeToken(user.Username, token.PurposeStepUp, transferBinding(user.Username, binding), time.Minute)
+		require.NoError(t, err)
+		return tk
+	}
+
+	testCases := []struct {
+		name          string
+		stepUpToken   func(maker token.PASETOMaker) string
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			stepUpToken: func(maker token.PASETOMaker) string {
+				return stepUpToken(maker, req)
+			},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				stubAccountHolder(store, fromAccount.ID, user.Username, database.AccountHolderOwner)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				// The token is redeemed by the transfer transaction, not ahead of it
+				store.EXPECT().ConsumeToken(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg database.TransferTxParams) (database.TransferTxResult, error) {
+						requireStepUpRedeemed(t, arg.StepUpToken)
+						return database.TransferTxResult{}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NeedsApproval",
+			stepUpToken: func(maker token.PASETOMaker) string {
+				return stepUpToken(maker, req)
+			},
+			buildStubs: func(store *mock_db.MockStore) {
+				guarded := fromAccount
+				guarded.ApprovalThreshold = 1000
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(guarded, nil)
+				stubAccountHolder(store, fromAccount.ID, user.Username, database.AccountHolderOwner)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().
+					CreateTransferRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg database.CreateTransferRequestTxParams) (database.TransferRequest, error) {
+						requireStepUpRedeemed(t, arg.StepUpToken)
+						return database.TransferRequest{ID: uuid.New(), Status: database.TransferRequestPending}, nil
+					})
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name: "TransferFails",
+			stepUpToken: func(maker token.PASETOMaker) string {
+				return stepUpToken(maker, req)
+			},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(2).Return(fromAccount, nil)
+				stubAccountHolder(store, fromAccount.ID, user.Username, database.AccountHolderOwner)
+				// Rolled back along with the transfer, the token can be sent again
+				store.EXPECT().ConsumeToken(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(database.TransferTxResult{}, fmt.Errorf("unable to execute transaction: %w", database.ErrInsufficientFunds))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+				requireBodyErrorCode(t, recorder.Body, "insufficient_funds")
+			},
+		},
+		{
+			name: "MissingStepUp",
+			stepUpToken: func(maker token.PASETOMaker) string {
+				return ""
+			},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(2).Return(fromAccount, nil)
+				stubAccountHolder(store, fromAccount.ID, user.Username, database.AccountHolderOwner)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "DifferentTransfer",
+			stepUpToken: func(maker token.PASETOMaker) string {
+				other := req
+				other.Amount = 1000
+				return stepUpToken(maker, other)
+			},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(2).Return(fromAccount, nil)
+				stubAccountHolder(store, fromAccount.ID, user.Username, database.AccountHolderOwner)
+				store.EXPECT().ConsumeToken(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyUsed",
+			stepUpToken: func(maker token.PASETOMaker) string {
+				return stepUpToken(maker, req)
+			},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(2).Return(fromAccount, nil)
+				stubAccountHolder(store, fromAccount.ID, user.Username, database.AccountHolderOwner)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(database.TransferTxResult{}, fmt.Errorf("unable to execute transaction: %w", database.ErrTokenUsed))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireBodyErrorCode(t, recorder.Body, "step_up_token_used")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"FromAccountId": req.FromAccountID,
+				"ToAccountId":   req.ToAccountID,
+				"amount":        req.Amount,
+				"currency":      req.Currency,
+				"stepUpToken":   tc.stepUpToken(server.tokenMaker),
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/token"
+	"github.com/lib/pq"
+)
+
+const (
+	lastEventIDHeader = "Last-Event-ID"
+	streamBatchSize   = 100
+)
+
+var errInvalidEventID = errors.New("last event id is invalid")
+
+/*
+EventSource sends the last event id as a header when it reconnects, the query parameter is for the first connection
+*/
+type streamBalancesRequest struct {
+	LastEventID string `form:"lastEventId"`
+}
+
+/*
+Position of a balance event in the outbox, sent to clients as the event id so they can resume after it
+*/
+type streamPosition struct {
+	TransactionID int64
+	ID            int64
+}
+
+func (pos streamPosition) String() string {
+	return fmt.Sprintf("%d-%d", pos.TransactionID, pos.ID)
+}
+
+func parseStreamPosition(s string) (pos streamPosition, err error) {
+	txID, id, ok := strings.Cut(s, "-")
+	if !ok {
+		return pos, errInvalidEventID
+	}
+	pos.TransactionID, err = strconv.ParseInt(txID, 10, 64)
+	if err != nil {
+		return pos, errInvalidEventID
+	}
+	pos.ID, err = strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return pos, errInvalidEventID
+	}
+	return
+}
+
+/*
+Wakes the open balance streams watching an account when its balance changes
+*/
+type balanceHub struct {
+	mu      sync.Mutex
+	streams map[chan struct{}]map[uuid.UUID]bool
+}
+
+func newBalanceHub() *balanceHub {
+	return &balanceHub{streams: map[chan struct{}]map[uuid.UUID]bool{}}
+}
+
+/*
+Sets the accounts a stream watches, replacing the previous ones
+*/
+func (hub *balanceHub) watch(wake chan struct{}, accountIDs []uuid.UUID) {
+	accounts := make(map[uuid.UUID]bool, len(accountIDs))
+	for _, id := range accountIDs {
+		accounts[id] = true
+	}
+
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	hub.streams[wake] = accounts
+}
+
+func (hub *balanceHub) unwatch(wake chan struct{}) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	delete(hub.streams, wake)
+}
+
+func (hub *balanceHub) notify(accountID uuid.UUID) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	for wake, accounts := range hub.streams {
+		if accounts[accountID] {
+			wakeUp(wake)
+		}
+	}
+}
+
+func (hub *balanceHub) notifyAll() {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	for wake := range hub.streams {
+		wakeUp(wake)
+	}
+}
+
+/*
+Wakes a stream without blocking, a stream already due to wake up reads every pending event anyway
+*/
+func wakeUp(wake chan struct{}) {
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+/*
+Listens to the balance change notifications sent by committed transfers and wakes the streams watching the accounts
+*/
+func (s *Server) listenBalanceChanges() {
+	listener := pq.NewListener(s.config.DBSource, time.Second, time.Minute, func(_ pq.ListenerEventType, err error) {
+		if err != nil {
+			s.logger.Error("balance change listener failed", "error", err)
+		}
+	})
+	err := listener.Listen(database.BalanceChangedChannel)
+	if err != nil {
+		s.logger.Error("unable to listen for balance changes", "error", err)
+		return
+	}
+
+	for notification := range listener.Notify {
+		if notification == nil {
+			// The connection was reestablished, notifications sent meanwhile are lost
+			s.balances.notifyAll()
+			continue
+		}
+		accountID, err := uuid.Parse(notification.Extra)
+		if err != nil {
+			continue
+		}
+		s.balances.notify(accountID)
+	}
+}
+
+/*
+Streams the balance changes of every account the authenticated user can see as server-sent events. Each event carries
+the account, the entry that changed it and its transfer. Clients resume after the last event they got with the
+Last-Event-ID header, without it the stream starts at the current moment.
+The stream ends when the access token expires, clients reconnect with a fresh token and resume.
+*/
+func (srv *Server) streamBalances(ctx *gin.Context) {
+	var req streamBalancesRequest
+	err := ctx.ShouldBindQuery(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	lastEventID := ctx.GetHeader(lastEventIDHeader)
+	if lastEventID == "" {
+		lastEventID = req.LastEventID
+	}
+
+	var pos streamPosition
+	if lastEventID != "" {
+		pos, err = parseStreamPosition(lastEventID)
+		if err != nil {
+			respondWithError(ctx, http.StatusBadRequest, err)
+			return
+		}
+	} else {
+		// Events of the transactions still running all come after the horizon, even if they committed already
+		horizon, err := srv.store.GetOutboxHorizon(ctx)
+		if err != nil {
+			respondWithError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		pos = streamPosition{TransactionID: horizon - 1, ID: math.MaxInt64}
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	streamCtx, cancel := context.WithDeadline(ctx.Request.Context(), authPayload.ExpiresAt)
+	defer cancel()
+
+	wake := make(chan struct{}, 1)
+	defer srv.balances.unwatch(wake)
+	// Notifications can be lost and events can be held back by older transactions, polling catches up with both
+	ticker := time.NewTicker(srv.config.StreamPollInterval)
+	defer ticker.Stop()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	wakeUp(wake)
+	for {
+		select {
+		case <-streamCtx.Done():
+			return
+		case <-ticker.C:
+			// Keeps proxies from closing an idle connection
+			fmt.Fprint(ctx.Writer, ": keep-alive\n\n")
+		case <-wake:
+		}
+
+		pos, err = srv.sendBalanceEvents(streamCtx, ctx.Writer, wake, authPayload.Username, pos)
+		if err != nil {
+			if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled) {
+				_ = ctx.Error(err)
+			}
+			return
+		}
+		ctx.Writer.Flush()
+	}
+}
+
+/*
+Writes the balance events after pos of the accounts the user can see and returns the position of the last one. The
+accounts are looked up every time so access given or taken away applies to open streams too.
+*/
+func (srv *Server) sendBalanceEvents(ctx context.Context, w io.Writer, wake chan struct{}, username string, pos streamPosition) (streamPosition, error) {
+	accountIDs, err := srv.store.ListAccessibleAccountIDs(ctx, username)
+	if err != nil {
+		return pos, err
+	}
+	// Watch before reading so a change committed in between wakes the stream again
+	srv.balances.watch(wake, accountIDs)
+	if len(accountIDs) == 0 {
+		return pos, nil
+	}
+
+	for {
+		events, err := srv.store.ListAccountOutboxEvents(ctx, database.ListAccountOutboxEventsParams{
+			AccountIds:         accountIDs,
+			EventType:          database.EventBalanceChanged,
+			AfterTransactionID: pos.TransactionID,
+			AfterID:            pos.ID,
+			BatchSize:          streamBatchSize,
+		})
+		if err != nil {
+			return pos, err
+		}
+
+		for _, event := range events {
+			pos = streamPosition{TransactionID: event.TransactionID, ID: event.ID}
+			_, err = fmt.Fprintf(w, "id: %s\nevent: balance\ndata: %s\n\n", pos, event.Payload)
+			if err != nil {
+				return pos, err
+			}
+		}
+		if len(events) < streamBatchSize {
+			return pos, nil
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomBalanceEvent(t *testing.T, account database.Account, transactionID, id int64) database.Outbox {
+	payload, err := json.Marshal(database.TransferEventData{Account: account})
+	require.NoError(t, err)
+	return database.Outbox{
+		ID:            id,
+		EventID:       uuid.New(),
+		EventType:     database.EventBalanceChanged,
+		AggregateType: database.AggregateAccount,
+		AggregateID:   account.ID,
+		Payload:       payload,
+		TransactionID: transactionID,
+	}
+}
+
+func TestStreamBalancesAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	events := []database.Outbox{randomBalanceEvent(t, account, 8, 12), randomBalanceEvent(t, account, 9, 3)}
+
+	testCases := []struct {
+		name          string
+		lastEventID   string
+		query         string
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "Resume",
+			lastEventID: "5-7",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetOutboxHorizon(gomock.Any()).Times(0)
+				store.EXPECT().ListAccessibleAccountIDs(gomock.Any(), gomock.Eq(user.Username)).MinTimes(1).Return([]uuid.UUID{account.ID}, nil)
+				first := store.EXPECT().
+					ListAccountOutboxEvents(gomock.Any(), gomock.Eq(database.ListAccountOutboxEventsParams{
+						AccountIds:         []uuid.UUID{account.ID},
+						EventType:          database.EventBalanceChanged,
+						AfterTransactionID: 5,
+						AfterID:            7,
+						BatchSize:          streamBatchSize,
+					})).
+					Times(1).
+					Return(events, nil)
+				store.EXPECT().
+					ListAccountOutboxEvents(gomock.Any(), gomock.Eq(database.ListAccountOutboxEventsParams{
+						AccountIds:         []uuid.UUID{account.ID},
+						EventType:          database.EventBalanceChanged,
+						AfterTransactionID: 9,
+						AfterID:            3,
+						BatchSize:          streamBatchSize,
+					})).
+					After(first).
+					AnyTimes().
+					Return([]database.Outbox{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "text/event-stream", recorder.Header().Get("Content-Type"))
+				body := recorder.Body.String()
+				require.Contains(t, body, "id: 8-12\nevent: balance\ndata: "+string(events[0].Payload)+"\n\n")
+				require.Contains(t, body, "id: 9-3\nevent: balance\ndata: "+string(events[1].Payload)+"\n\n")
+				require.Contains(t, body, ": keep-alive\n\n")
+			},
+		},
+		{
+			name:  "FromNow",
+			query: "",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetOutboxHorizon(gomock.Any()).Times(1).Return(int64(100), nil)
+				store.EXPECT().ListAccessibleAccountIDs(gomock.Any(), gomock.Any()).MinTimes(1).Return([]uuid.UUID{account.ID}, nil)
+				store.EXPECT().
+					ListAccountOutboxEvents(gomock.Any(), gomock.Eq(database.ListAccountOutboxEventsParams{
+						AccountIds:         []uuid.UUID{account.ID},
+						EventType:          database.EventBalanceChanged,
+						AfterTransactionID: 99,
+						AfterID:            math.MaxInt64,
+						BatchSize:          streamBatchSize,
+					})).
+					MinTimes(1).
+					Return([]database.Outbox{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), "event: balance")
+			},
+		},
+		{
+			name:  "NoAccounts",
+			query: "lastEventId=5-7",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().ListAccessibleAccountIDs(gomock.Any(), gomock.Any()).MinTimes(1).Return([]uuid.UUID{}, nil)
+				store.EXPECT().ListAccountOutboxEvents(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:        "InvalidLastEventID",
+			lastEventID: "not-an-id",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().ListAccessibleAccountIDs(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyError(t, recorder.Body, errInvalidEventID)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/accounts/stream?"+tc.query, nil)
+			require.NoError(t, err)
+			if tc.lastEventID != "" {
+				request.Header.Set(lastEventIDHeader, tc.lastEventID)
+			}
+
+			// The stream ends with the access token
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, 200*time.Millisecond)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestBalanceHub(t *testing.T) {
+	hub := newBalanceHub()
+	watched, other := uuid.New(), uuid.New()
+
+	wake := make(chan struct{}, 1)
+	hub.watch(wake, []uuid.UUID{watched})
+
+	hub.notify(other)
+	require.Len(t, wake, 0)
+
+	// Wake ups coalesce while the stream is busy
+	hub.notify(watched)
+	hub.notify(watched)
+	require.Len(t, wake, 1)
+	<-wake
+
+	hub.notifyAll()
+	require.Len(t, wake, 1)
+	<-wake
+
+	hub.unwatch(wake)
+	hub.notify(watched)
+	require.Len(t, wake, 0)
+}
@@ -2,11 +2,18 @@ package api
 
 import (
 	"database/sql"
-	"fmt"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/julianinsua/the_simp_bank/auth"
+)
+
+var (
+	errSessionBlocked  = errors.New("session blocked")
+	errSessionMismatch = errors.New("refresh token doesn't belong to this session")
+	errSessionExpired  = errors.New("session expired")
 )
 
 type refreshTokenRequest struct {
@@ -22,13 +29,13 @@ func (srv *Server) refreshToken(ctx *gin.Context) {
 	var req refreshTokenRequest
 	err := ctx.ShouldBindJSON(&req)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondWithError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
 	payload, err := srv.tokenMaker.VerifyToken(req.RefreshToken)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		respondWithError(ctx, http.StatusUnauthorized, err)
 		return
 	}
 
@@ -36,41 +43,52 @@ func (srv *Server) refreshToken(ctx *gin.Context) {
 	ssn, err := srv.store.GetSession(ctx, payload.ID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			respondWithError(ctx, http.StatusNotFound, err)
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondWithError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
 	// Check session blocked
 	if ssn.IsBlocked {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(fmt.Errorf("Session blocked")))
+		respondWithError(ctx, http.StatusUnauthorized, errSessionBlocked)
 		return
 	}
 
 	// Check session username to be the same as the token's username
 
 	if ssn.Username != payload.Username {
-		ctx.JSON(http.StatusUnauthorized, fmt.Errorf("incorrect session user"))
+		respondWithError(ctx, http.StatusUnauthorized, errSessionMismatch)
 		return
 	}
 
 	// Check that the request's refresh token is the same as the session's refresh token
 	if req.RefreshToken != ssn.RefreshToken {
-		ctx.JSON(http.StatusUnauthorized, fmt.Errorf("unmatching token"))
+		respondWithError(ctx, http.StatusUnauthorized, errSessionMismatch)
 		return
 	}
 
 	if time.Now().After(ssn.ExpiresAt) {
-		ctx.JSON(http.StatusUnauthorized, fmt.Errorf("expired token"))
+		respondWithError(ctx, http.StatusUnauthorized, errSessionExpired)
+		return
+	}
+
+	// Sessions created before the last password change are revoked. The role is read again in case it changed.
+	usr, err := srv.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if auth.TokenRevoked(payload.IssuedAt, usr.PasswordChangedAt) {
+		respondWithError(ctx, http.StatusUnauthorized, auth.ErrTokenRevoked)
 		return
 	}
 
 	// Create token
-	token, tokenPayload, err := srv.tokenMaker.CreateToken(payload.Username, srv.config.TokenDuration)
+	token, tokenPayload, err := srv.tokenMaker.CreateToken(usr.Username, usr.Role, srv.config.TokenDuration)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondWithError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -0,0 +1,274 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/julianinsua/the_simp_bank/auth"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/token"
+	"github.com/julianinsua/the_simp_bank/util"
+)
+
+const recoveryCodesCount = 10
+
+var (
+	errTOTPAlreadyEnabled = errors.New("two factor authentication is already enabled")
+	errTOTPNotEnrolled    = errors.New("two factor authentication enrollment not started")
+	errInvalidMFACode     = errors.New("invalid authentication code")
+	errMFAChallengeUsed   = errors.New("challenge token has already been used")
+)
+
+type enrollTOTPResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+/*
+Starts two factor enrollment: generates a secret for the authenticated user and returns it with its otpauth URI.
+The secret is not enforced on login until it is confirmed with a valid code.
+*/
+func (srv *Server) enrollTOTP(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+
+	usr, err := srv.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if usr.TotpEnabled {
+		respondWithError(ctx, http.StatusConflict, errTOTPAlreadyEnabled)
+		return
+	}
+
+	secret, err := util.GenerateTOTPSecret()
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	_, err = srv.store.UpdateUserTOTPSecret(ctx, database.UpdateUserTOTPSecretParams{
+		Username:   usr.Username,
+		TotpSecret: secret,
+	})
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, enrollTOTPResponse{
+		Secret: secret,
+		URI:    util.TOTPURI(usr.Username, secret),
+	})
+}
+
+type confirmTOTPRequest struct {
+	Code string `json:"code" binding:"required,numeric,len=6"`
+}
+
+type confirmTOTPResponse struct {
+	User          userResponse `json:"user"`
+	RecoveryCodes []string     `json:"recoveryCodes"`
+}
+
+/*
+Finishes two factor enrollment with a code from the authenticator app and returns the recovery codes. They are only shown once.
+*/
+func (srv *Server) confirmTOTP(ctx *gin.Context) {
+	var req confirmTOTPRequest
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+
+	usr, err := srv.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if usr.TotpEnabled {
+		respondWithError(ctx, http.StatusConflict, errTOTPAlreadyEnabled)
+		return
+	}
+	if usr.TotpSecret == "" {
+		respondWithError(ctx, http.StatusBadRequest, errTOTPNotEnrolled)
+		return
+	}
+	if !util.ValidateTOTP(usr.TotpSecret, req.Code, time.Now()) {
+		respondWithError(ctx, http.StatusBadRequest, errInvalidMFACode)
+		return
+	}
+
+	codes, err := util.GenerateRecoveryCodes(recoveryCodesCount)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i], err = srv.passwordHasher.Hash(code)
+		if err != nil {
+			respondWithError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	usr, err = srv.store.EnableTOTPTx(ctx, database.EnableTOTPTxParams{
+		Username:            usr.Username,
+		HashedRecoveryCodes: hashes,
+	})
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, confirmTOTPResponse{
+		User:          newUserResponse(usr),
+		RecoveryCodes: codes,
+	})
+}
+
+type verifyLoginMFARequest struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+	Code           string `json:"code" binding:"required,min=6,max=11"`
+}
+
+/*
+Second step of the login for users with two factor authentication. Accepts a TOTP code or an unused recovery code
+and exchanges the challenge token for a session. Challenges and TOTP codes are single use, so neither can be
+replayed to open a second session.
+*/
+func (srv *Server) verifyLoginMFA(ctx *gin.Context) {
+	var req verifyLoginMFARequest
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	challenge, err := srv.tokenMaker.VerifyPurposeToken(req.ChallengeToken, token.PurposeMFAChallenge)
+	if err != nil {
+		respondWithError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	// Codes are short, so failures count towards the same throttle as passwords
+	if !srv.allowLoginAttempt(ctx, challenge.Username) {
+		return
+	}
+
+	usr, err := srv.store.GetUser(ctx, challenge.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(ctx, http.StatusUnauthorized, auth.ErrInvalidCredentials)
+			return
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	valid := false
+	if usr.TotpEnabled {
+		valid, err = srv.useTOTPCode(ctx, usr, req.Code)
+		if err != nil {
+			respondWithError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	if !valid && usr.TotpEnabled {
+		valid, err = srv.useRecoveryCode(ctx, usr.Username, req.Code)
+		if err != nil {
+			respondWithError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	if !valid {
+		err = srv.recordLoginAttempt(ctx, usr.Username, auth.OutcomeInvalidCredentials)
+		if err != nil {
+			respondWithError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		respondWithError(ctx, http.StatusUnauthorized, errInvalidMFACode)
+		return
+	}
+
+	rows, err := srv.store.ConsumeToken(ctx, database.ConsumeTokenParams{
+		ID:        challenge.ID,
+		Username:  challenge.Username,
+		Purpose:   challenge.Purpose,
+		ExpiresAt: challenge.ExpiresAt,
+	})
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if rows == 0 {
+		respondWithError(ctx, http.StatusUnauthorized, errMFAChallengeUsed)
+		return
+	}
+
+	err = srv.recordLoginAttempt(ctx, usr.Username, auth.OutcomeSuccess)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res, err := srv.newUserSession(ctx, usr)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+/*
+Checks a TOTP code and records its time step. Codes from the last accepted step or earlier are rejected, so a code
+can't log in twice even while it's still within the allowed clock drift.
+*/
+func (srv *Server) useTOTPCode(ctx *gin.Context, usr database.User, code string) (bool, error) {
+	step, ok := util.MatchTOTP(usr.TotpSecret, code, time.Now())
+	if !ok || step <= usr.TotpLastStep {
+		return false, nil
+	}
+
+	// A concurrent login may have used this step or a later one first
+	rows, err := srv.store.AdvanceUserTOTPStep(ctx, database.AdvanceUserTOTPStepParams{
+		Username:     usr.Username,
+		TotpLastStep: step,
+	})
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+/*
+Checks a code against the user's unused recovery codes and burns the one that matches
+*/
+func (srv *Server) useRecoveryCode(ctx *gin.Context, username, code string) (bool, error) {
+	recoveryCodes, err := srv.store.GetUnusedRecoveryCodes(ctx, username)
+	if err != nil {
+		return false, err
+	}
+
+	for _, rc := range recoveryCodes {
+		if util.CheckPassword(code, rc.HashedCode) != nil {
+			continue
+		}
+		// A concurrent login may have used it first
+		rows, err := srv.store.MarkRecoveryCodeUsed(ctx, rc.ID)
+		if err != nil {
+			return false, err
+		}
+		return rows == 1, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,283 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/auth"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/token"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomTOTPUser(t *testing.T) (user database.User, recoveryCode string) {
+	user, _ = randomUser(t)
+	secret, err := util.GenerateTOTPSecret()
+	require.NoError(t, err)
+	user.TotpSecret = secret
+	user.TotpEnabled = true
+
+	codes, err := util.GenerateRecoveryCodes(1)
+	require.NoError(t, err)
+	return user, codes[0]
+}
+
+func TestConfirmTOTP(t *testing.T) {
+	user, _ := randomTOTPUser(t)
+	user.TotpEnabled = false
+
+	validCode, err := util.TOTPCode(user.TotpSecret, time.Now())
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		code          string
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			code: validCode,
+			buildStubs: func(store *mock_db.MockStore) {
+				enabled := user
+				enabled.TotpEnabled = true
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().EnableTOTPTx(gomock.Any(), gomock.Any()).Times(1).Return(enabled, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res confirmTOTPResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.True(t, res.User.TwoFactorEnabled)
+				require.Len(t, res.RecoveryCodes, recoveryCodesCount)
+			},
+		},
+		{
+			name: "InvalidCode",
+			code: "000000",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().EnableTOTPTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyEnabled",
+			code: validCode,
+			buildStubs: func(store *mock_db.MockStore) {
+				enabled := user
+				enabled.TotpEnabled = true
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(enabled, nil)
+				store.EXPECT().EnableTOTPTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"code": tc.code})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/totp/confirm", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestVerifyLoginMFA(t *testing.T) {
+	user, recoveryCode := randomTOTPUser(t)
+	hashedRecoveryCode, err := testPasswordHasher.Hash(recoveryCode)
+	require.NoError(t, err)
+
+	validCode, err := util.TOTPCode(user.TotpSecret, time.Now())
+	require.NoError(t, err)
+	validStep, _ := util.MatchTOTP(user.TotpSecret, validCode, time.Now())
+
+	testCases := []struct {
+		name          string
+		challenge     func(maker token.PASETOMaker) string
+		code          string
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			code: validCode,
+			buildStubs: func(store *mock_db.MockStore) {
+				stubLoginFailures(store, 0, time.Time{})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					AdvanceUserTOTPStep(gomock.Any(), gomock.Eq(database.AdvanceUserTOTPStepParams{Username: user.Username, TotpLastStep: validStep})).
+					Times(1).
+					Return(int64(1), nil)
+				store.EXPECT().
+					ConsumeToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, params database.ConsumeTokenParams) (int64, error) {
+						require.Equal(t, user.Username, params.Username)
+						require.Equal(t, token.PurposeMFAChallenge, params.Purpose)
+						return 1, nil
+					})
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeSuccess)).Times(1)
+				store.EXPECT().CreateSessionTx(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "ReplayedCode",
+			code: validCode,
+			buildStubs: func(store *mock_db.MockStore) {
+				usedUser := user
+				usedUser.TotpLastStep = validStep
+				stubLoginFailures(store, 0, time.Time{})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(usedUser, nil)
+				store.EXPECT().AdvanceUserTOTPStep(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetUnusedRecoveryCodes(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(nil, nil)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeInvalidCredentials)).Times(1)
+				store.EXPECT().CreateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireBodyErrorCode(t, recorder.Body, "invalid_mfa_code")
+			},
+		},
+		{
+			name: "ConcurrentCode",
+			code: validCode,
+			buildStubs: func(store *mock_db.MockStore) {
+				stubLoginFailures(store, 0, time.Time{})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().AdvanceUserTOTPStep(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+				store.EXPECT().GetUnusedRecoveryCodes(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(nil, nil)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeInvalidCredentials)).Times(1)
+				store.EXPECT().CreateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ChallengeUsed",
+			code: validCode,
+			buildStubs: func(store *mock_db.MockStore) {
+				stubLoginFailures(store, 0, time.Time{})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().AdvanceUserTOTPStep(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+				store.EXPECT().ConsumeToken(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+				store.EXPECT().CreateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireBodyErrorCode(t, recorder.Body, "mfa_challenge_used")
+			},
+		},
+		{
+			name: "RecoveryCode",
+			code: recoveryCode,
+			buildStubs: func(store *mock_db.MockStore) {
+				rc := database.RecoveryCode{ID: uuid.New(), Username: user.Username, HashedCode: hashedRecoveryCode}
+				stubLoginFailures(store, 0, time.Time{})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().GetUnusedRecoveryCodes(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return([]database.RecoveryCode{rc}, nil)
+				store.EXPECT().MarkRecoveryCodeUsed(gomock.Any(), gomock.Eq(rc.ID)).Times(1).Return(int64(1), nil)
+				store.EXPECT().ConsumeToken(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeSuccess)).Times(1)
+				store.EXPECT().CreateSessionTx(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidCode",
+			code: "000000",
+			buildStubs: func(store *mock_db.MockStore) {
+				stubLoginFailures(store, 0, time.Time{})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().GetUnusedRecoveryCodes(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(nil, nil)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeInvalidCredentials)).Times(1)
+				store.EXPECT().CreateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "AccessTokenAsChallenge",
+			challenge: func(maker token.PASETOMaker) string {
+				accessToken, _, err := maker.CreateToken(user.Username, util.CustomerRole, time.Minute)
+				require.NoError(t, err)
+				return accessToken
+			},
+			code: validCode,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			var challenge string
+			if tc.challenge != nil {
+				challenge = tc.challenge(server.tokenMaker)
+			} else {
+				challenge, _, err = server.tokenMaker.CreatePurposeToken(user.Username, token.PurposeMFAChallenge, "", time.Minute)
+				require.NoError(t, err)
+			}
+
+			data, err := json.Marshal(gin.H{"challengeToken": challenge, "code": tc.code})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/login/mfa", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
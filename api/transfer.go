@@ -1,15 +1,14 @@
 package api
 
 import (
-	"database/sql"
-	"errors"
-	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/policy"
 	"github.com/julianinsua/the_simp_bank/token"
+	"github.com/julianinsua/the_simp_bank/util"
 )
 
 /*
@@ -20,6 +19,7 @@ type transferRequest struct {
 	ToAccountID   uuid.UUID `json:"ToAccountId" binding:"required"`
 	Amount        float64   `json:"amount" binding:"required,gt=0"`
 	Currency      string    `json:"currency" binding:"required,currency"`
+	StepUpToken   string    `json:"stepUpToken"` // Required at or above the step-up threshold
 }
 
 /*
@@ -29,7 +29,7 @@ func (s Server) createTransfer(ctx *gin.Context) {
 	var req transferRequest
 	err := ctx.ShouldBindJSON(&req)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondWithError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
@@ -39,46 +39,173 @@ func (s Server) createTransfer(ctx *gin.Context) {
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
-	if authPayload.Username != fromAcc.Owner {
-		err = errors.New("Account selected is not authorized for authenticated user")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+	grantID, allowed := s.accountTransferGrant(ctx, fromAcc.ID, authPayload.Username, req.Amount)
+	if !allowed {
 		return
 	}
 
-	_, valid = s.validAccount(ctx, req.ToAccountID, req.Currency)
+	if !s.transfersAllowed(ctx, authPayload.Username) {
+		return
+	}
+
+	toAcc, valid := s.validAccount(ctx, req.ToAccountID, req.Currency)
 	if !valid {
 		return
 	}
 
+	if !s.incomingTransferAllowed(ctx, toAcc, req.Amount) {
+		return
+	}
+
+	// High value transfers need a fresh re-authentication
+	var stepUp *database.ConsumeTokenParams
+	if s.policy().RequiresStepUp(req.Amount) {
+		stepUp, valid = s.verifyStepUpToken(ctx, authPayload.Username, req)
+		if !valid {
+			return
+		}
+	}
+
+	// Large transfers from accounts with an approval threshold wait for a second authorized user
+	if policy.RequiresApproval(fromAcc, req.Amount) {
+		s.createTransferRequest(ctx, authPayload.Username, req, stepUp)
+		return
+	}
+
 	params := database.TransferTxParams{
 		FromAccountID: req.FromAccountID,
 		ToAccountID:   req.ToAccountID,
 		Amount:        req.Amount,
+		StepUpToken:   stepUp,
+		GrantID:       grantID,
 	}
 
 	result, err := s.store.TransferTx(ctx, params)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondWithTransferError(ctx, err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, result)
 }
 
-func (s Server) validAccount(ctx *gin.Context, accId uuid.UUID, currency string) (database.Account, bool) {
-	acc, err := s.store.GetAccount(ctx, accId)
+/*
+Move between two accounts of the same owner body
+*/
+type internalTransferRequest struct {
+	FromAccountID uuid.UUID `json:"FromAccountId" binding:"required"`
+	ToAccountID   uuid.UUID `json:"ToAccountId" binding:"required"`
+	Amount        float64   `json:"amount" binding:"required,gt=0"`
+	Currency      string    `json:"currency" binding:"required,currency"`
+}
+
+/*
+Moves money between an account and its pots, like topping one up. The move happens instantly, skipping the step-up,
+approval and verification checks of regular transfers, so it's limited to unshared accounts without an approval
+threshold where the money never leaves the user.
+*/
+func (s Server) createInternalTransfer(ctx *gin.Context) {
+	var req internalTransferRequest
+	err := ctx.ShouldBindJSON(&req)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return acc, false
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	fromAcc, valid := s.validAccount(ctx, req.FromAccountID, req.Currency)
+	if !valid {
+		return
+	}
+	_, allowed := s.accountHolderAllowed(ctx, fromAcc.ID, authPayload.Username, database.AccountHolderOwner)
+	if !allowed {
+		return
+	}
+
+	toAcc, valid := s.validAccount(ctx, req.ToAccountID, req.Currency)
+	if !valid {
+		return
 	}
-	if acc.Currency != currency {
-		err = fmt.Errorf("Account [%s] currency mismatch: has %s vs. %s", accId, acc.Currency, currency)
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-		return acc, false
+	_, allowed = s.accountHolderAllowed(ctx, toAcc.ID, authPayload.Username, database.AccountHolderOwner)
+	if !allowed {
+		return
+	}
+
+	if !policyAllowed(ctx, s.policy().InternalTransferAllowed(ctx, fromAcc, toAcc, authPayload.Username)) {
+		return
+	}
+
+	result, err := s.store.TransferTx(ctx, database.TransferTxParams{
+		FromAccountID: fromAcc.ID,
+		ToAccountID:   toAcc.ID,
+		Amount:        req.Amount,
+	})
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+/*
+A page of the transfers in and out of an account
+*/
+type transferListResponse struct {
+	Transfers []database.Transfer `json:"transfers"`
+	pageLinks
+}
+
+func transferCursor(transfer database.Transfer) util.PageCursor {
+	return util.PageCursor{CreatedAt: transfer.CreatedAt, ID: transfer.ID}
+}
+
+/*
+Lists the transfers sent or received by an account oldest first, for anyone who can see the account
+*/
+func (srv *Server) listAccountTransfers(ctx *gin.Context) {
+	var uri accountHolderURI
+	err := ctx.ShouldBindUri(&uri)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	cur, size, ok := srv.bindPage(ctx)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	accID := uuid.MustParse(uri.ID)
+	if !srv.accountViewAllowed(ctx, accID, authPayload.Username) {
+		return
+	}
+
+	var transfers []database.Transfer
+	if cur.Before {
+		transfers, err = srv.store.ListAccountTransfersBefore(ctx, database.ListAccountTransfersBeforeParams{
+			AccountID:       accID,
+			CursorCreatedAt: cur.CreatedAt,
+			CursorID:        cur.ID,
+			PageSize:        size + 1,
+		})
+	} else {
+		transfers, err = srv.store.ListAccountTransfersAfter(ctx, database.ListAccountTransfersAfterParams{
+			AccountID:       accID,
+			CursorCreatedAt: cur.CreatedAt,
+			CursorID:        cur.ID,
+			PageSize:        size + 1,
+		})
+	}
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
 	}
 
-	return acc, true
+	transfers, next, prev := util.Paginate(transfers, size, cur, transferCursor)
+	ctx.JSON(http.StatusOK, transferListResponse{
+		Transfers: transfers,
+		pageLinks: newPageLinks(ctx, size, next, prev),
+	})
 }
@@ -0,0 +1,478 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/token"
+	"github.com/julianinsua/the_simp_bank/util"
+)
+
+var (
+	errSelfApproval = errors.New("transfer requests must be decided by someone other than the requester")
+)
+
+/*
+Stores a transfer that needs a second approval instead of executing it, redeeming the step-up token it came with
+*/
+func (s Server) createTransferRequest(ctx *gin.Context, username string, req transferRequest, stepUp *database.ConsumeTokenParams) {
+	request, err := s.store.CreateTransferRequestTx(ctx, database.CreateTransferRequestTxParams{
+		FromAccountID: req.FromAccountID,
+		ToAccountID:   req.ToAccountID,
+		Amount:        req.Amount,
+		RequestedBy:   username,
+		ExpiresAt:     time.Now().Add(s.config.TransferRequestDuration),
+		StepUpToken:   stepUp,
+	})
+	if err != nil {
+		respondWithTransferError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, request)
+}
+
+/*
+A page of the transfer requests made from an account
+*/
+type transferRequestListResponse struct {
+	TransferRequests []database.TransferRequest `json:"transferRequests"`
+	pageLinks
+}
+
+func transferRequestCursor(request database.TransferRequest) util.PageCursor {
+	return util.PageCursor{CreatedAt: request.CreatedAt, ID: request.ID}
+}
+
+/*
+Lists the transfer requests made from an account oldest first, for anyone who can see the account
+*/
+func (s Server) listTransferRequests(ctx *gin.Context) {
+	var uri accountHolderURI
+	err := ctx.ShouldBindUri(&uri)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	cur, size, ok := s.bindPage(ctx)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	accID := uuid.MustParse(uri.ID)
+
+	if !s.accountViewAllowed(ctx, accID, authPayload.Username) {
+		return
+	}
+
+	var requests []database.TransferRequest
+	if cur.Before {
+		requests, err = s.store.ListAccountTransferRequestsBefore(ctx, database.ListAccountTransferRequestsBeforeParams{
+			FromAccountID:   accID,
+			CursorCreatedAt: cur.CreatedAt,
+			CursorID:        cur.ID,
+			PageSize:        size + 1,
+		})
+	} else {
+		requests, err = s.store.ListAccountTransferRequestsAfter(ctx, database.ListAccountTransferRequestsAfterParams{
+			FromAccountID:   accID,
+			CursorCreatedAt: cur.CreatedAt,
+			CursorID:        cur.ID,
+			PageSize:        size + 1,
+		})
+	}
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	requests, next, prev := util.Paginate(requests, size, cur, transferRequestCursor)
+	ctx.JSON(http.StatusOK, transferRequestListResponse{
+		TransferRequests: requests,
+		pageLinks:        newPageLinks(ctx, size, next, prev),
+	})
+}
+
+type transferRequestURI struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+type transferRequestResponse struct {
+	Request   database.TransferRequest    `json:"request"`
+	Approvals []database.TransferApproval `json:"approvals"`
+}
+
+/*
+Returns a transfer request with its approval trail
+*/
+func (s Server) getTransferRequest(ctx *gin.Context) {
+	var uri transferRequestURI
+	err := ctx.ShouldBindUri(&uri)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	request, err := s.store.GetTransferRequest(ctx, uuid.MustParse(uri.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	if !s.accountViewAllowed(ctx, request.FromAccountID, authPayload.Username) {
+		return
+	}
+
+	approvals, err := s.store.ListTransferApprovals(ctx, request.ID)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, transferRequestResponse{
+		Request:   request,
+		Approvals: approvals,
+	})
+}
+
+/*
+Approval body. Approving a transfer at or above the step-up threshold also needs the approver's current password or a
+TOTP code.
+*/
+type approveTransferRequest struct {
+	Note     string `json:"note" binding:"max=500"`
+	Password string `json:"password"`
+	Code     string `json:"code" binding:"omitempty,numeric,len=6"`
+}
+
+/*
+Approves a pending transfer request, executing the transfer
+*/
+func (s Server) approveTransferRequest(ctx *gin.Context) {
+	var req approveTransferRequest
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	s.decideTransferRequest(ctx, database.TransferDecisionApproved, req.Note, req.Password, req.Code)
+}
+
+type rejectTransferRequest struct {
+	Note string `json:"note" binding:"required,max=500"`
+}
+
+/*
+Rejects a pending transfer request, the reason is kept in the approval trail
+*/
+func (s Server) rejectTransferRequest(ctx *gin.Context) {
+	var req rejectTransferRequest
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	s.decideTransferRequest(ctx, database.TransferDecisionRejected, req.Note, "", "")
+}
+
+/*
+Approving a large transfer moves the money just like making it, so it takes the same fresh proof of identity. Responds
+to the client and returns false when the approver couldn't give it.
+*/
+func (s Server) approvalStepUp(ctx *gin.Context, username string, amount float64, password, code string) bool {
+	if !s.policy().RequiresStepUp(amount) {
+		return true
+	}
+	if password == "" && code == "" {
+		err := fmt.Errorf("%w to approve transfers of %v or more", errStepUpRequired, s.config.StepUpThreshold)
+		respondWithError(ctx, http.StatusForbidden, err)
+		return false
+	}
+	return s.reauthenticate(ctx, username, password, code)
+}
+
+/*
+Records a decision on a transfer request. The password and code are only checked when approving a large transfer.
+*/
+func (s Server) decideTransferRequest(ctx *gin.Context, decision, note, password, code string) {
+	var uri transferRequestURI
+	err := ctx.ShouldBindUri(&uri)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	request, err := s.store.GetTransferRequest(ctx, uuid.MustParse(uri.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	if request.RequestedBy == authPayload.Username {
+		respondWithError(ctx, http.StatusForbidden, errSelfApproval)
+		return
+	}
+	if !s.accountApproveAllowed(ctx, request.FromAccountID, authPayload.Username) {
+		return
+	}
+	var grantID uuid.NullUUID
+	if decision == database.TransferDecisionApproved {
+		grantID, err = s.policy().ApprovedTransferAllowed(ctx, request)
+		if !policyAllowed(ctx, err) {
+			return
+		}
+		if !s.approvalStepUp(ctx, authPayload.Username, request.Amount, password, code) {
+			return
+		}
+	}
+
+	result, err := s.store.DecideTransferTx(ctx, database.DecideTransferTxParams{
+		RequestID: request.ID,
+		Username:  authPayload.Username,
+		Decision:  decision,
+		Note:      note,
+		GrantID:   grantID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrTransferRequestDecided):
+			respondWithError(ctx, http.StatusConflict, database.ErrTransferRequestDecided)
+		case errors.Is(err, database.ErrTransferRequestExpired):
+			respondWithError(ctx, http.StatusConflict, database.ErrTransferRequestExpired)
+		default:
+			respondWithError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+/*
+Threshold change body. Proposing a lowering or turning off the threshold also needs the current password or a TOTP code.
+*/
+type approvalThresholdRequest struct {
+	Threshold float64 `json:"threshold" binding:"gte=0"`
+	Password  string  `json:"password"`
+	Code      string  `json:"code" binding:"omitempty,numeric,len=6"`
+}
+
+/*
+Sets the amount from which transfers out of an account need a second approval, 0 turns approvals off. Owner only.
+Raising the threshold applies right away. Lowering or turning it off lets more transfers through unchecked, so it takes
+a fresh proof of identity and is only proposed: another holder who can approve transfers has to approve it too, the
+same maker-checker rule transfers above the threshold follow. Every change is kept in the account's audit trail.
+*/
+func (s Server) updateApprovalThreshold(ctx *gin.Context) {
+	var uri accountHolderURI
+	err := ctx.ShouldBindUri(&uri)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var req approvalThresholdRequest
+	err = ctx.ShouldBindJSON(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	accID := uuid.MustParse(uri.ID)
+
+	_, allowed := s.accountHolderAllowed(ctx, accID, authPayload.Username, database.AccountHolderOwner)
+	if !allowed {
+		return
+	}
+
+	acc, err := s.store.GetAccount(ctx, accID)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	if database.ThresholdLowered(acc.ApprovalThreshold, req.Threshold) {
+		s.proposeThresholdLowering(ctx, acc, req)
+		return
+	}
+
+	// The transaction checks again in case a concurrent change turned this into a lowering
+	result, err := s.store.UpdateApprovalThresholdTx(ctx, database.UpdateApprovalThresholdTxParams{
+		AccountID: accID,
+		Username:  authPayload.Username,
+		Threshold: req.Threshold,
+	})
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result.Account)
+}
+
+/*
+Stores a lowering of the approval threshold waiting for another holder's approval, once the owner proved their identity
+*/
+func (s Server) proposeThresholdLowering(ctx *gin.Context, acc database.Account, req approvalThresholdRequest) {
+	if req.Password == "" && req.Code == "" {
+		respondWithError(ctx, http.StatusForbidden, database.ErrThresholdLowered)
+		return
+	}
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	if !s.reauthenticate(ctx, authPayload.Username, req.Password, req.Code) {
+		return
+	}
+
+	change, err := s.store.CreateApprovalThresholdChange(ctx, database.CreateApprovalThresholdChangeParams{
+		AccountID:    acc.ID,
+		Username:     authPayload.Username,
+		OldThreshold: acc.ApprovalThreshold,
+		NewThreshold: req.Threshold,
+		Status:       database.ThresholdChangePending,
+		ExpiresAt:    sql.NullTime{Time: time.Now().Add(s.config.TransferRequestDuration), Valid: true},
+	})
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, change)
+}
+
+type thresholdChangeURI struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+/*
+Returns a change of an approval threshold, for anyone who can see the account
+*/
+func (s Server) getThresholdChange(ctx *gin.Context) {
+	var uri thresholdChangeURI
+	err := ctx.ShouldBindUri(&uri)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	change, err := s.store.GetApprovalThresholdChange(ctx, uuid.MustParse(uri.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	if !s.accountViewAllowed(ctx, change.AccountID, authPayload.Username) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, change)
+}
+
+/*
+Approves a pending lowering of an approval threshold, applying it
+*/
+func (s Server) approveThresholdChange(ctx *gin.Context) {
+	s.decideThresholdChange(ctx, database.TransferDecisionApproved)
+}
+
+/*
+Rejects a pending lowering of an approval threshold, the threshold stays as it is
+*/
+func (s Server) rejectThresholdChange(ctx *gin.Context) {
+	s.decideThresholdChange(ctx, database.TransferDecisionRejected)
+}
+
+func (s Server) decideThresholdChange(ctx *gin.Context, decision string) {
+	var uri thresholdChangeURI
+	err := ctx.ShouldBindUri(&uri)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	change, err := s.store.GetApprovalThresholdChange(ctx, uuid.MustParse(uri.ID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	if change.Username == authPayload.Username {
+		respondWithError(ctx, http.StatusForbidden, errSelfApproval)
+		return
+	}
+	if !s.accountApproveAllowed(ctx, change.AccountID, authPayload.Username) {
+		return
+	}
+
+	result, err := s.store.DecideThresholdChangeTx(ctx, database.DecideThresholdChangeTxParams{
+		ChangeID: change.ID,
+		Username: authPayload.Username,
+		Decision: decision,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrThresholdChangeDecided):
+			respondWithError(ctx, http.StatusConflict, database.ErrThresholdChangeDecided)
+		case errors.Is(err, database.ErrThresholdChangeExpired):
+			respondWithError(ctx, http.StatusConflict, database.ErrThresholdChangeExpired)
+		default:
+			respondWithError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+/*
+Periodically marks pending transfer requests and threshold lowerings past their approval window as expired
+*/
+func (s *Server) expireTransferRequests(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		expired, err := s.store.ExpireTransferRequests(context.Background())
+		if err != nil {
+			s.logger.Error("unable to expire transfer requests", "error", err)
+		} else if expired > 0 {
+			s.logger.Info("expired transfer requests", "count", expired)
+		}
+
+		expired, err = s.store.ExpireApprovalThresholdChanges(context.Background())
+		if err != nil {
+			s.logger.Error("unable to expire approval threshold changes", "error", err)
+		} else if expired > 0 {
+			s.logger.Info("expired approval threshold changes", "count", expired)
+		}
+	}
+}
@@ -0,0 +1,700 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/auth"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/policy"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTransferNeedsApproval(t *testing.T) {
+	user, _ := randomUser(t)
+	fromAccount := randomAccount(user.Username)
+	fromAccount.ApprovalThreshold = 100
+	toAccount := randomAccount(util.RandomOwner())
+	toAccount.Currency = fromAccount.Currency
+
+	testCases := []struct {
+		name          string
+		amount        float64
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OverThreshold",
+			amount: 100,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().
+					CreateTransferRequestTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg database.CreateTransferRequestTxParams) (database.TransferRequest, error) {
+						require.Equal(t, fromAccount.ID, arg.FromAccountID)
+						require.Equal(t, toAccount.ID, arg.ToAccountID)
+						require.Equal(t, user.Username, arg.RequestedBy)
+						require.WithinDuration(t, time.Now().Add(time.Hour), arg.ExpiresAt, time.Second)
+						require.Nil(t, arg.StepUpToken)
+						return database.TransferRequest{ID: uuid.New(), Status: database.TransferRequestPending}, nil
+					})
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name:   "UnderThreshold",
+			amount: 99,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().CreateTransferRequestTx(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+			stubAccountHolder(store, fromAccount.ID, user.Username, database.AccountHolderOwner)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"FromAccountId": fromAccount.ID,
+				"ToAccountId":   toAccount.ID,
+				"amount":        tc.amount,
+				"currency":      fromAccount.Currency,
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestDecideTransferRequest(t *testing.T) {
+	maker, _ := randomUser(t)
+	checker, checkerPassword := randomUser(t)
+	transferReq := database.TransferRequest{
+		ID:            uuid.New(),
+		FromAccountID: uuid.New(),
+		ToAccountID:   uuid.New(),
+		Amount:        500,
+		RequestedBy:   maker.Username,
+		Status:        database.TransferRequestPending,
+		ExpiresAt:     time.Now().Add(time.Hour),
+	}
+
+	// The request is checked again on approval, the requester still holds the account
+	stubStillAllowed := func(store *mock_db.MockStore) {
+		stubAccountHolder(store, transferReq.FromAccountID, maker.Username, database.AccountHolderCoOwner)
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(transferReq.ToAccountID)).Times(1).Return(database.Account{ID: transferReq.ToAccountID}, nil)
+	}
+
+	// Approving this one moves more than the step-up threshold
+	largeReq := transferReq
+	largeReq.ID = uuid.New()
+	largeReq.Amount = 5000
+
+	testCases := []struct {
+		name          string
+		username      string
+		requestID     uuid.UUID
+		action        string
+		body          gin.H
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Approve",
+			username: checker.Username,
+			action:   "approve",
+			body:     gin.H{},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(transferReq.ID)).Times(1).Return(transferReq, nil)
+				stubAccountHolder(store, transferReq.FromAccountID, checker.Username, database.AccountHolderCoOwner)
+				stubStillAllowed(store)
+				store.EXPECT().
+					DecideTransferTx(gomock.Any(), gomock.Eq(database.DecideTransferTxParams{
+						RequestID: transferReq.ID,
+						Username:  checker.Username,
+						Decision:  database.TransferDecisionApproved,
+					})).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "ApproveLargeWithoutStepUp",
+			username:  checker.Username,
+			requestID: largeReq.ID,
+			action:    "approve",
+			body:      gin.H{},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(largeReq.ID)).Times(1).Return(largeReq, nil)
+				stubAccountHolder(store, largeReq.FromAccountID, checker.Username, database.AccountHolderCoOwner)
+				stubStillAllowed(store)
+				store.EXPECT().DecideTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireBodyErrorCode(t, recorder.Body, "step_up_required")
+			},
+		},
+		{
+			name:      "ApproveLargeWithPassword",
+			username:  checker.Username,
+			requestID: largeReq.ID,
+			action:    "approve",
+			body:      gin.H{"password": checkerPassword},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(largeReq.ID)).Times(1).Return(largeReq, nil)
+				stubAccountHolder(store, largeReq.FromAccountID, checker.Username, database.AccountHolderCoOwner)
+				stubStillAllowed(store)
+				stubLoginFailures(store, 0, time.Time{})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(checker.Username)).Times(1).Return(checker, nil)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeStepUp)).Times(1)
+				store.EXPECT().DecideTransferTx(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "ApproveLargeWrongPassword",
+			username:  checker.Username,
+			requestID: largeReq.ID,
+			action:    "approve",
+			body:      gin.H{"password": "wrongPassword"},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(largeReq.ID)).Times(1).Return(largeReq, nil)
+				stubAccountHolder(store, largeReq.FromAccountID, checker.Username, database.AccountHolderCoOwner)
+				stubStillAllowed(store)
+				stubLoginFailures(store, 0, time.Time{})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(checker.Username)).Times(1).Return(checker, nil)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeInvalidCredentials)).Times(1)
+				store.EXPECT().DecideTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "RequesterLostAccess",
+			username: checker.Username,
+			action:   "approve",
+			body:     gin.H{},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(transferReq.ID)).Times(1).Return(transferReq, nil)
+				stubAccountHolder(store, transferReq.FromAccountID, checker.Username, database.AccountHolderOwner)
+				store.EXPECT().
+					GetAccountHolder(gomock.Any(), gomock.Eq(database.GetAccountHolderParams{AccountID: transferReq.FromAccountID, Username: maker.Username})).
+					Times(1).
+					Return(database.AccountHolder{}, sql.ErrNoRows)
+				store.EXPECT().GetActiveAccountGrant(gomock.Any(), gomock.Any()).Times(1).Return(database.AccountGrant{}, sql.ErrNoRows)
+				store.EXPECT().DecideTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireBodyErrorCode(t, recorder.Body, "account_access_denied")
+			},
+		},
+		{
+			name:     "ApproveWithGrant",
+			username: checker.Username,
+			action:   "approve",
+			body:     gin.H{},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(transferReq.ID)).Times(1).Return(transferReq, nil)
+				store.EXPECT().
+					GetAccountHolder(gomock.Any(), gomock.Eq(database.GetAccountHolderParams{AccountID: transferReq.FromAccountID, Username: checker.Username})).
+					Times(1).
+					Return(database.AccountHolder{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetActiveAccountGrant(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(database.AccountGrant{Grantee: checker.Username, CanApprove: true}, nil)
+				stubStillAllowed(store)
+				store.EXPECT().DecideTransferTx(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "ApproveChargesRequesterGrant",
+			username: checker.Username,
+			action:   "approve",
+			body:     gin.H{},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(transferReq.ID)).Times(1).Return(transferReq, nil)
+				stubAccountHolder(store, transferReq.FromAccountID, checker.Username, database.AccountHolderCoOwner)
+				grant := stubAccountGrant(store, transferReq.FromAccountID, maker.Username, 1000, 200)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(transferReq.ToAccountID)).Times(1).Return(database.Account{ID: transferReq.ToAccountID}, nil)
+				store.EXPECT().
+					DecideTransferTx(gomock.Any(), gomock.Eq(database.DecideTransferTxParams{
+						RequestID: transferReq.ID,
+						Username:  checker.Username,
+						Decision:  database.TransferDecisionApproved,
+						GrantID:   uuid.NullUUID{UUID: grant.ID, Valid: true},
+					})).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "RequesterGrantUsedUp",
+			username: checker.Username,
+			action:   "approve",
+			body:     gin.H{},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(transferReq.ID)).Times(1).Return(transferReq, nil)
+				stubAccountHolder(store, transferReq.FromAccountID, checker.Username, database.AccountHolderCoOwner)
+				stubAccountGrant(store, transferReq.FromAccountID, maker.Username, 1000, 700)
+				store.EXPECT().DecideTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireBodyErrorCode(t, recorder.Body, "grant_limit_exceeded")
+			},
+		},
+		{
+			name:     "GrantWithoutApproval",
+			username: checker.Username,
+			action:   "approve",
+			body:     gin.H{},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(transferReq.ID)).Times(1).Return(transferReq, nil)
+				stubAccountGrant(store, transferReq.FromAccountID, checker.Username, 10000, 0)
+				store.EXPECT().DecideTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireBodyError(t, recorder.Body, policy.ErrGrantApproveMissing)
+			},
+		},
+		{
+			name:     "SelfApproval",
+			username: maker.Username,
+			action:   "approve",
+			body:     gin.H{},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(transferReq.ID)).Times(1).Return(transferReq, nil)
+				store.EXPECT().DecideTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireBodyError(t, recorder.Body, errSelfApproval)
+			},
+		},
+		{
+			name:     "AlreadyDecided",
+			username: checker.Username,
+			action:   "approve",
+			body:     gin.H{},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(transferReq.ID)).Times(1).Return(transferReq, nil)
+				stubAccountHolder(store, transferReq.FromAccountID, checker.Username, database.AccountHolderOwner)
+				stubStillAllowed(store)
+				store.EXPECT().
+					DecideTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(database.DecideTransferTxResult{}, fmt.Errorf("unable to execute transaction: %w", database.ErrTransferRequestDecided))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "Expired",
+			username: checker.Username,
+			action:   "reject",
+			body:     gin.H{"note": "too late"},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(transferReq.ID)).Times(1).Return(transferReq, nil)
+				stubAccountHolder(store, transferReq.FromAccountID, checker.Username, database.AccountHolderOwner)
+				store.EXPECT().
+					DecideTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(database.DecideTransferTxResult{}, fmt.Errorf("unable to execute transaction: %w", database.ErrTransferRequestExpired))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireBodyError(t, recorder.Body, database.ErrTransferRequestExpired)
+			},
+		},
+		{
+			name:     "Reject",
+			username: checker.Username,
+			action:   "reject",
+			body:     gin.H{"note": "unknown beneficiary"},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Eq(transferReq.ID)).Times(1).Return(transferReq, nil)
+				stubAccountHolder(store, transferReq.FromAccountID, checker.Username, database.AccountHolderOwner)
+				store.EXPECT().
+					DecideTransferTx(gomock.Any(), gomock.Eq(database.DecideTransferTxParams{
+						RequestID: transferReq.ID,
+						Username:  checker.Username,
+						Decision:  database.TransferDecisionRejected,
+						Note:      "unknown beneficiary",
+					})).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "RejectWithoutNote",
+			username: checker.Username,
+			action:   "reject",
+			body:     gin.H{},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "NotFound",
+			username: checker.Username,
+			action:   "approve",
+			body:     gin.H{},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetTransferRequest(gomock.Any(), gomock.Any()).Times(1).Return(database.TransferRequest{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			requestID := tc.requestID
+			if requestID == uuid.Nil {
+				requestID = transferReq.ID
+			}
+			url := fmt.Sprintf("/transfer-requests/%s/%s", requestID, tc.action)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestUpdateApprovalThreshold(t *testing.T) {
+	user, password := randomUser(t)
+	account := randomAccount(user.Username)
+	account.ApprovalThreshold = 100
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Raise",
+			body: gin.H{"threshold": 500},
+			buildStubs: func(store *mock_db.MockStore) {
+				stubAccountHolder(store, account.ID, user.Username, database.AccountHolderOwner)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					UpdateApprovalThresholdTx(gomock.Any(), gomock.Eq(database.UpdateApprovalThresholdTxParams{
+						AccountID: account.ID,
+						Username:  user.Username,
+						Threshold: 500,
+					})).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "RaiseTurnedIntoLowering",
+			body: gin.H{"threshold": 500},
+			buildStubs: func(store *mock_db.MockStore) {
+				stubAccountHolder(store, account.ID, user.Username, database.AccountHolderOwner)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					UpdateApprovalThresholdTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(database.UpdateApprovalThresholdTxResult{}, fmt.Errorf("unable to execute transaction: %w", database.ErrThresholdLowered))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireBodyErrorCode(t, recorder.Body, "threshold_reauth_required")
+			},
+		},
+		{
+			name: "LowerWithoutReauthentication",
+			body: gin.H{"threshold": 0},
+			buildStubs: func(store *mock_db.MockStore) {
+				stubAccountHolder(store, account.ID, user.Username, database.AccountHolderOwner)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().CreateApprovalThresholdChange(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpdateApprovalThresholdTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireBodyErrorCode(t, recorder.Body, "threshold_reauth_required")
+			},
+		},
+		{
+			name: "LowerWithPasswordIsProposed",
+			body: gin.H{"threshold": 0, "password": password},
+			buildStubs: func(store *mock_db.MockStore) {
+				stubAccountHolder(store, account.ID, user.Username, database.AccountHolderOwner)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				stubLoginFailures(store, 0, time.Time{})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeStepUp)).Times(1)
+				store.EXPECT().
+					CreateApprovalThresholdChange(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg database.CreateApprovalThresholdChangeParams) (database.ApprovalThresholdChange, error) {
+						require.Equal(t, account.ID, arg.AccountID)
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, float64(100), arg.OldThreshold)
+						require.Equal(t, float64(0), arg.NewThreshold)
+						require.Equal(t, database.ThresholdChangePending, arg.Status)
+						require.True(t, arg.ExpiresAt.Valid)
+						return database.ApprovalThresholdChange{ID: uuid.New(), Status: arg.Status}, nil
+					})
+				// A single owner can't apply the lowering on their own
+				store.EXPECT().UpdateApprovalThresholdTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name: "WrongPassword",
+			body: gin.H{"threshold": 0, "password": "wrongPassword"},
+			buildStubs: func(store *mock_db.MockStore) {
+				stubAccountHolder(store, account.ID, user.Username, database.AccountHolderOwner)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				stubLoginFailures(store, 0, time.Time{})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeInvalidCredentials)).Times(1)
+				store.EXPECT().CreateApprovalThresholdChange(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "NotOwner",
+			body: gin.H{"threshold": 500},
+			buildStubs: func(store *mock_db.MockStore) {
+				stubAccountHolder(store, account.ID, user.Username, database.AccountHolderCoOwner)
+				store.EXPECT().GetActiveAccountGrant(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpdateApprovalThresholdTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/accounts/%s/approval-threshold", account.ID)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestDecideThresholdChange(t *testing.T) {
+	owner, _ := randomUser(t)
+	coOwner, _ := randomUser(t)
+	change := database.ApprovalThresholdChange{
+		ID:           uuid.New(),
+		AccountID:    uuid.New(),
+		Username:     owner.Username,
+		OldThreshold: 100,
+		NewThreshold: 0,
+		Status:       database.ThresholdChangePending,
+	}
+
+	testCases := []struct {
+		name          string
+		username      string
+		action        string
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Approve",
+			username: coOwner.Username,
+			action:   "approve",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetApprovalThresholdChange(gomock.Any(), gomock.Eq(change.ID)).Times(1).Return(change, nil)
+				stubAccountHolder(store, change.AccountID, coOwner.Username, database.AccountHolderCoOwner)
+				store.EXPECT().
+					DecideThresholdChangeTx(gomock.Any(), gomock.Eq(database.DecideThresholdChangeTxParams{
+						ChangeID: change.ID,
+						Username: coOwner.Username,
+						Decision: database.TransferDecisionApproved,
+					})).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "Reject",
+			username: coOwner.Username,
+			action:   "reject",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetApprovalThresholdChange(gomock.Any(), gomock.Eq(change.ID)).Times(1).Return(change, nil)
+				stubAccountHolder(store, change.AccountID, coOwner.Username, database.AccountHolderCoOwner)
+				store.EXPECT().
+					DecideThresholdChangeTx(gomock.Any(), gomock.Eq(database.DecideThresholdChangeTxParams{
+						ChangeID: change.ID,
+						Username: coOwner.Username,
+						Decision: database.TransferDecisionRejected,
+					})).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "SelfApproval",
+			username: owner.Username,
+			action:   "approve",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetApprovalThresholdChange(gomock.Any(), gomock.Eq(change.ID)).Times(1).Return(change, nil)
+				store.EXPECT().DecideThresholdChangeTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:     "AlreadyDecided",
+			username: coOwner.Username,
+			action:   "approve",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetApprovalThresholdChange(gomock.Any(), gomock.Eq(change.ID)).Times(1).Return(change, nil)
+				stubAccountHolder(store, change.AccountID, coOwner.Username, database.AccountHolderCoOwner)
+				store.EXPECT().
+					DecideThresholdChangeTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(database.DecideThresholdChangeTxResult{}, fmt.Errorf("unable to execute transaction: %w", database.ErrThresholdChangeDecided))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireBodyErrorCode(t, recorder.Body, "threshold_change_decided")
+			},
+		},
+		{
+			name:     "NotFound",
+			username: coOwner.Username,
+			action:   "approve",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().
+					GetApprovalThresholdChange(gomock.Any(), gomock.Eq(change.ID)).
+					Times(1).
+					Return(database.ApprovalThresholdChange{}, sql.ErrNoRows)
+				store.EXPECT().DecideThresholdChangeTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/approval-threshold-changes/%s/%s", change.ID, tc.action)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
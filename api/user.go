@@ -2,19 +2,42 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/auth"
 	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/token"
 	"github.com/julianinsua/the_simp_bank/util"
 	"github.com/lib/pq"
 )
 
+var (
+	errUsernameTaken     = errors.New("username is already taken")
+	errEmailInUse        = errors.New("email is already in use")
+	errEmptyUserUpdate   = errors.New("nothing to update, send a fullName or an email")
+	errEmailChangeReauth = errors.New("changing the email requires re-authentication")
+)
+
+/*
+Translates a unique violation on the users table into the field the client has to change
+*/
+func userConflictError(pqErr *pq.Error) error {
+	switch pqErr.Constraint {
+	case "users_pkey":
+		return errUsernameTaken
+	case "users_email_key":
+		return errEmailInUse
+	}
+	return pqErr
+}
+
 type createUserRequest struct {
 	Username string `json:"username" binding:"required,alphanum"`
-	Password string `json:"password" binding:"required,min=5"`
+	Password string `json:"password" binding:"required"`
 	FullName string `json:"fullName" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
 }
@@ -23,6 +46,10 @@ type userResponse struct {
 	Username          string    `json:"username"`
 	FullName          string    `json:"fullName"`
 	Email             string    `json:"email"`
+	Role              string    `json:"role"`
+	TwoFactorEnabled  bool      `json:"twoFactorEnabled"`
+	EmailVerified     bool      `json:"emailVerified"`
+	KYCStatus         string    `json:"kycStatus"`
 	PasswordChangedAt time.Time `json:"passwordChangedAt"`
 	CreatedAt         time.Time `json:"createdAt"`
 }
@@ -32,6 +59,10 @@ func newUserResponse(user database.User) userResponse {
 		Username:          user.Username,
 		FullName:          user.FullName,
 		Email:             user.Email,
+		Role:              user.Role,
+		TwoFactorEnabled:  user.TotpEnabled,
+		EmailVerified:     user.EmailVerifiedAt.Valid,
+		KYCStatus:         user.KycStatus,
 		PasswordChangedAt: user.PasswordChangedAt,
 		CreatedAt:         user.CreatedAt,
 	}
@@ -41,13 +72,19 @@ func (s *Server) createUser(ctx *gin.Context) {
 	var req createUserRequest
 	err := ctx.ShouldBindJSON(&req)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondWithError(ctx, http.StatusBadRequest, err)
 		return
 	}
 
-	hash, err := util.HashPassword(req.Password)
+	err = s.passwordPolicy.Validate(req.Password)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	hash, err := s.passwordHasher.Hash(req.Password)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -63,20 +100,34 @@ func (s *Server) createUser(ctx *gin.Context) {
 		if pqErr, ok := err.(*pq.Error); ok {
 			switch pqErr.Code.Name() {
 			case "unique_violation":
-				ctx.JSON(http.StatusForbidden, errorResponse(err))
+				respondWithError(ctx, http.StatusConflict, userConflictError(pqErr))
 				return
 			}
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	// The user is created anyway, the email can be sent again later
+	err = s.sendEmailVerification(ctx, usr)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "unable to send verification email", "username", usr.Username, "error", err)
 	}
 
 	rsp := newUserResponse(usr)
 	ctx.JSON(http.StatusOK, rsp)
 }
 
+// The policy only applies to new passwords, so accounts created under an older one can still log in
 type loginUserRequest struct {
 	Username string `json:"username" binding:"required,alphanum"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required"`
+}
+
+type mfaChallengeResponse struct {
+	MFARequired        bool      `json:"mfaRequired"`
+	ChallengeToken     string    `json:"challengeToken"`
+	ChallengeExpiresAt time.Time `json:"challengeExpiresAt"`
 }
 
 type loginUserResponse struct {
@@ -92,39 +143,92 @@ func (srv *Server) loginUser(ctx *gin.Context) {
 	var req loginUserRequest
 	err := ctx.ShouldBindJSON(&req)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	// Throttle repeated failures for this username or client IP
+	if !srv.allowLoginAttempt(ctx, req.Username) {
 		return
 	}
 
 	//get user via username
 	usr, err := srv.store.GetUser(ctx, req.Username)
+	if err != nil && err != sql.ErrNoRows {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	// validate password, deleted users can't log in anymore
+	if err == sql.ErrNoRows || usr.DeletedAt.Valid {
+		srv.checkDummyPassword(req.Password)
+		err = auth.ErrInvalidCredentials
+	} else {
+		err = util.CheckPassword(req.Password, usr.HashedPassword)
+	}
 	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		err = srv.recordLoginAttempt(ctx, req.Username, auth.OutcomeInvalidCredentials)
+		if err != nil {
+			respondWithError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		respondWithError(ctx, http.StatusUnauthorized, auth.ErrInvalidCredentials)
+		return
+	}
+
+	// The plain password is only available now, upgrade hashes made with older parameters
+	srv.rehashPassword(ctx, usr, req.Password)
+
+	// Users with two factor authentication get a challenge instead of a session
+	if usr.TotpEnabled {
+		err = srv.recordLoginAttempt(ctx, req.Username, auth.OutcomeMFAChallenge)
+		if err != nil {
+			respondWithError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+
+		challenge, challengePayload, err := srv.tokenMaker.CreatePurposeToken(usr.Username, token.PurposeMFAChallenge, "", srv.config.MFAChallengeDuration)
+		if err != nil {
+			respondWithError(ctx, http.StatusInternalServerError, err)
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+
+		ctx.JSON(http.StatusOK, mfaChallengeResponse{
+			MFARequired:        true,
+			ChallengeToken:     challenge,
+			ChallengeExpiresAt: challengePayload.ExpiresAt,
+		})
 		return
 	}
-	// validate password
-	err = util.CheckPassword(req.Password, usr.HashedPassword)
+
+	err = srv.recordLoginAttempt(ctx, req.Username, auth.OutcomeSuccess)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		respondWithError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
-	// Create token
-	token, tokenPayload, err := srv.tokenMaker.CreateToken(usr.Username, srv.config.TokenDuration)
+	res, err := srv.newUserSession(ctx, usr)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		respondWithError(ctx, http.StatusInternalServerError, err)
 		return
 	}
 
+	ctx.JSON(http.StatusOK, res)
+}
+
+/*
+Creates the access token, the refresh token and the session for an authenticated user
+*/
+func (srv *Server) newUserSession(ctx *gin.Context, usr database.User) (loginUserResponse, error) {
+	// Create token
+	accessToken, tokenPayload, err := srv.tokenMaker.CreateToken(usr.Username, usr.Role, srv.config.TokenDuration)
+	if err != nil {
+		return loginUserResponse{}, err
+	}
+
 	// Create refresh token
-	refreshToken, refreshTokenPayload, err := srv.tokenMaker.CreateToken(usr.Username, srv.config.RefreshTokenDuration)
+	refreshToken, refreshTokenPayload, err := srv.tokenMaker.CreateToken(usr.Username, usr.Role, srv.config.RefreshTokenDuration)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+		return loginUserResponse{}, err
 	}
 
 	// Create session
@@ -135,21 +239,250 @@ func (srv *Server) loginUser(ctx *gin.Context) {
 		ClientAgent:  ctx.Request.UserAgent(),
 		ClientIp:     ctx.ClientIP(),
 		ExpiresAt:    refreshTokenPayload.ExpiresAt,
+		CreatedAt:    refreshTokenPayload.IssuedAt,
 	}
-	session, err := srv.store.CreateSession(ctx, ssn)
+	session, err := srv.store.CreateSessionTx(ctx, ssn)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return loginUserResponse{}, err
 	}
 
-	// respond
 	res := loginUserResponse{
 		SessionId:             session.ID,
 		User:                  newUserResponse(usr),
-		Token:                 token,
+		Token:                 accessToken,
 		TokenExpiresAt:        tokenPayload.ExpiresAt,
 		RefreshToken:          session.RefreshToken,
 		RefreshTokenExpiresAt: session.ExpiresAt,
 	}
+	return res, nil
+}
 
-	ctx.JSON(http.StatusOK, res)
+type getUserRequest struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+/*
+Admin user lookup handler
+*/
+func (srv *Server) getUser(ctx *gin.Context) {
+	var req getUserRequest
+	err := ctx.ShouldBindUri(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	usr, err := srv.store.GetUser(ctx, req.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(usr))
+}
+
+type updateUserRoleRequest struct {
+	Role string `json:"role" binding:"required,role"`
+}
+
+/*
+Admin handler to change the role, and therefore the scopes, of a user. Admin routes check the stored role on every
+request, so a demoted user loses access right away even with tokens issued before the change
+*/
+func (srv *Server) updateUserRole(ctx *gin.Context) {
+	var uri getUserRequest
+	err := ctx.ShouldBindUri(&uri)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	var req updateUserRoleRequest
+	err = ctx.ShouldBindJSON(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	usr, err := srv.store.UpdateUserRole(ctx, database.UpdateUserRoleParams{
+		Username: uri.Username,
+		Role:     req.Role,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(usr))
+}
+
+type changeUserPasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" binding:"required"`
+	NewPassword     string `json:"newPassword" binding:"required"`
+}
+
+/*
+Changes the authenticated user's password. Every session and token issued before the change stops working.
+*/
+func (srv *Server) changeUserPassword(ctx *gin.Context) {
+	var req changeUserPasswordRequest
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	err = srv.passwordPolicy.Validate(req.NewPassword)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+
+	// Guessing the current password counts towards the login throttle
+	if !srv.allowLoginAttempt(ctx, authPayload.Username) {
+		return
+	}
+
+	usr, err := srv.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = util.CheckPassword(req.CurrentPassword, usr.HashedPassword)
+	if err != nil {
+		err = srv.recordLoginAttempt(ctx, usr.Username, auth.OutcomeInvalidCredentials)
+		if err != nil {
+			respondWithError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		respondWithError(ctx, http.StatusUnauthorized, auth.ErrInvalidCredentials)
+		return
+	}
+
+	hash, err := srv.passwordHasher.Hash(req.NewPassword)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	usr, err = srv.store.ChangePasswordTx(ctx, database.UpdateUserPasswordParams{
+		Username:          usr.Username,
+		HashedPassword:    hash,
+		PasswordChangedAt: time.Now(),
+	})
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(usr))
+}
+
+/*
+Returns the authenticated user's profile
+*/
+func (srv *Server) getCurrentUser(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+
+	usr, err := srv.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(usr))
+}
+
+type updateCurrentUserRequest struct {
+	FullName *string `json:"fullName" binding:"omitempty,min=1"`
+	Email    *string `json:"email" binding:"omitempty,email"`
+	Password string  `json:"password"`                               // Required, or Code, to change the email
+	Code     string  `json:"code" binding:"omitempty,numeric,len=6"` // Authenticator code, instead of the password
+}
+
+/*
+Updates the authenticated user's full name and/or email. Changing the email needs a fresh re-authentication, since
+the email is where password resets go, and the previous address is told about it. A new email has to be verified
+again.
+*/
+func (srv *Server) updateCurrentUser(ctx *gin.Context) {
+	var req updateCurrentUserRequest
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	if req.FullName == nil && req.Email == nil {
+		respondWithError(ctx, http.StatusBadRequest, errEmptyUserUpdate)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+
+	current, err := srv.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	emailChanged := req.Email != nil && *req.Email != current.Email
+	if emailChanged {
+		if req.Password == "" && req.Code == "" {
+			respondWithError(ctx, http.StatusForbidden, errEmailChangeReauth)
+			return
+		}
+		if !srv.reauthenticate(ctx, current.Username, req.Password, req.Code) {
+			return
+		}
+		// The new address gets a verification email, limited like resends
+		if !srv.verificationResendAllowed(ctx, current.Username) {
+			return
+		}
+	}
+
+	params := database.UpdateUserProfileParams{Username: authPayload.Username}
+	if req.FullName != nil {
+		params.FullName = sql.NullString{String: *req.FullName, Valid: true}
+	}
+	if req.Email != nil {
+		params.Email = sql.NullString{String: *req.Email, Valid: true}
+	}
+
+	usr, err := srv.store.UpdateUserProfile(ctx, params)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				respondWithError(ctx, http.StatusConflict, userConflictError(pqErr))
+				return
+			}
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	// The query cleared the verification along with the email
+	if emailChanged {
+		err = srv.sendEmailVerification(ctx, usr)
+		if err != nil {
+			srv.logger.ErrorContext(ctx, "unable to send verification email", "username", usr.Username, "error", err)
+		}
+		err = srv.auth().SendEmailChangedNotice(ctx, usr, current.Email)
+		if err != nil {
+			srv.logger.ErrorContext(ctx, "unable to notify the previous email", "username", usr.Username, "error", err)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, newUserResponse(usr))
 }
@@ -0,0 +1,276 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/auth"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/token"
+	"github.com/julianinsua/the_simp_bank/util"
+)
+
+const (
+	exportFormatJSON = "json"
+	exportFormatZIP  = "zip"
+)
+
+/*
+Session data included in an export, the refresh token is left out on purpose
+*/
+type sessionExport struct {
+	ID          uuid.UUID `json:"id"`
+	ClientAgent string    `json:"clientAgent"`
+	ClientIp    string    `json:"clientIp"`
+	IsBlocked   bool      `json:"isBlocked"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+/*
+Everything the bank stores about a user. Holdings include joint accounts and pending invitations, grants include revoked
+and expired ones and webhooks leave their signing secrets out
+*/
+type userExport struct {
+	ExportedAt     time.Time                `json:"exportedAt"`
+	Profile        userResponse             `json:"profile"`
+	Accounts       []database.Account       `json:"accounts"`
+	Holdings       []database.AccountHolder `json:"accountHoldings"`
+	GrantsGiven    []database.AccountGrant  `json:"grantsGiven"`
+	GrantsReceived []database.AccountGrant  `json:"grantsReceived"`
+	Entries        []database.Entry         `json:"entries"`
+	Transfers      []database.Transfer      `json:"transfers"`
+	Sessions       []sessionExport          `json:"sessions"`
+	LoginAttempts  []database.LoginAttempt  `json:"loginAttempts"`
+	Webhooks       []webhookResponse        `json:"webhooks"`
+	KYC            []database.KycSubmission `json:"kycSubmissions"`
+}
+
+type exportUserDataRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=json zip"`
+}
+
+/*
+Exports the authenticated user's personal data as a single JSON document, or as a ZIP with one JSON file per section
+*/
+func (srv *Server) exportUserData(ctx *gin.Context) {
+	var req exportUserDataRequest
+	err := ctx.ShouldBindQuery(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+
+	export, err := srv.collectUserData(ctx, authPayload.Username)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	filename := fmt.Sprintf("%s-export-%s", authPayload.Username, export.ExportedAt.Format("20060102T150405Z"))
+
+	if req.Format != exportFormatZIP {
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".json"))
+		ctx.JSON(http.StatusOK, export)
+		return
+	}
+
+	archive, err := zipUserData(export)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".zip"))
+	ctx.Data(http.StatusOK, "application/zip", archive)
+}
+
+/*
+Reads every section of the export from the store
+*/
+func (srv *Server) collectUserData(ctx *gin.Context, username string) (export userExport, err error) {
+	usr, err := srv.store.GetUser(ctx, username)
+	if err != nil {
+		return
+	}
+	export.ExportedAt = time.Now().UTC()
+	export.Profile = newUserResponse(usr)
+
+	export.Accounts, err = srv.store.ListOwnerAccounts(ctx, username)
+	if err != nil {
+		return
+	}
+	export.Holdings, err = srv.store.ListUserAccountHolders(ctx, username)
+	if err != nil {
+		return
+	}
+	export.GrantsGiven, err = srv.store.ListGrantorGrantHistory(ctx, username)
+	if err != nil {
+		return
+	}
+	export.GrantsReceived, err = srv.store.ListGranteeGrantHistory(ctx, username)
+	if err != nil {
+		return
+	}
+	export.Entries, err = srv.store.ListOwnerEntries(ctx, username)
+	if err != nil {
+		return
+	}
+	export.Transfers, err = srv.store.ListOwnerTransfers(ctx, username)
+	if err != nil {
+		return
+	}
+
+	export.KYC, err = srv.store.ListUserKYCSubmissions(ctx, username)
+	if err != nil {
+		return
+	}
+
+	export.LoginAttempts, err = srv.store.ListUserLoginAttempts(ctx, username)
+	if err != nil {
+		return
+	}
+
+	hooks, err := srv.store.ListUserWebhooks(ctx, username)
+	if err != nil {
+		return
+	}
+	export.Webhooks = make([]webhookResponse, len(hooks))
+	for i, hook := range hooks {
+		export.Webhooks[i] = newWebhookResponse(hook)
+	}
+
+	sessions, err := srv.store.ListUserSessions(ctx, username)
+	if err != nil {
+		return
+	}
+	export.Sessions = make([]sessionExport, len(sessions))
+	for i, session := range sessions {
+		export.Sessions[i] = sessionExport{
+			ID:          session.ID,
+			ClientAgent: session.ClientAgent,
+			ClientIp:    session.ClientIp,
+			IsBlocked:   session.IsBlocked,
+			ExpiresAt:   session.ExpiresAt,
+			CreatedAt:   session.CreatedAt,
+		}
+	}
+	return
+}
+
+/*
+Writes each section of the export to its own JSON file inside a ZIP archive
+*/
+func zipUserData(export userExport) ([]byte, error) {
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", export.Profile},
+		{"accounts.json", export.Accounts},
+		{"account_holdings.json", export.Holdings},
+		{"grants_given.json", export.GrantsGiven},
+		{"grants_received.json", export.GrantsReceived},
+		{"entries.json", export.Entries},
+		{"transfers.json", export.Transfers},
+		{"sessions.json", export.Sessions},
+		{"login_attempts.json", export.LoginAttempts},
+		{"webhooks.json", export.Webhooks},
+		{"kyc_submissions.json", export.KYC},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(file.data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := archive.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type deleteCurrentUserRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+/*
+Deletes the authenticated user. Personal fields are anonymized, the ledger history is kept and every session is blocked.
+All the user's accounts have to be emptied first.
+*/
+func (srv *Server) deleteCurrentUser(ctx *gin.Context) {
+	var req deleteCurrentUserRequest
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+
+	// Guessing the password counts towards the login throttle
+	if !srv.allowLoginAttempt(ctx, authPayload.Username) {
+		return
+	}
+
+	usr, err := srv.store.GetUser(ctx, authPayload.Username)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = util.CheckPassword(req.Password, usr.HashedPassword)
+	if err != nil {
+		err = srv.recordLoginAttempt(ctx, usr.Username, auth.OutcomeInvalidCredentials)
+		if err != nil {
+			respondWithError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		respondWithError(ctx, http.StatusUnauthorized, auth.ErrInvalidCredentials)
+		return
+	}
+
+	_, err = srv.store.DeleteUserTx(ctx, database.DeleteUserTxParams{
+		Username:  usr.Username,
+		DeletedAt: time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, database.ErrNonZeroBalance) {
+			respondWithError(ctx, http.StatusConflict, database.ErrNonZeroBalance)
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(ctx, http.StatusNotFound, err)
+			return
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,282 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/auth"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportUserData(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.Username)
+	session := database.Session{
+		ID:           uuid.New(),
+		Username:     user.Username,
+		RefreshToken: util.RandomString(32),
+		ClientAgent:  "test-agent",
+		ClientIp:     "127.0.0.1",
+		ExpiresAt:    time.Now().Add(time.Hour),
+		CreatedAt:    time.Now(),
+	}
+
+	submission := database.KycSubmission{
+		ID:             uuid.New(),
+		Username:       user.Username,
+		DocumentType:   "passport",
+		DocumentNumber: util.RandomString(9),
+		IssuingCountry: "AR",
+		Status:         database.KYCStatusPending,
+	}
+
+	joint := randomAccount(util.RandomOwner())
+	holding := database.AccountHolder{
+		AccountID:  joint.ID,
+		Username:   user.Username,
+		Role:       database.AccountHolderCoOwner,
+		InvitedBy:  joint.Owner,
+		AcceptedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}
+	given := database.AccountGrant{ID: uuid.New(), AccountID: account.ID, Grantor: user.Username, Grantee: util.RandomOwner()}
+	received := database.AccountGrant{ID: uuid.New(), AccountID: joint.ID, Grantor: joint.Owner, Grantee: user.Username}
+	attempt := database.LoginAttempt{ID: uuid.New(), Username: user.Username, ClientIp: "127.0.0.1", Outcome: "success"}
+	hook := database.Webhook{
+		ID:         uuid.New(),
+		Username:   user.Username,
+		Url:        "https://example.com/hook",
+		Secret:     util.RandomString(32),
+		EventTypes: []string{database.EventTransferCreated},
+	}
+
+	stubExport := func(store *mock_db.MockStore) {
+		store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+		store.EXPECT().ListOwnerAccounts(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return([]database.Account{account}, nil)
+		store.EXPECT().ListUserAccountHolders(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return([]database.AccountHolder{holding}, nil)
+		store.EXPECT().ListGrantorGrantHistory(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return([]database.AccountGrant{given}, nil)
+		store.EXPECT().ListGranteeGrantHistory(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return([]database.AccountGrant{received}, nil)
+		store.EXPECT().ListOwnerEntries(gomock.Any(), gomock.Eq(user.Username)).Times(1)
+		store.EXPECT().ListOwnerTransfers(gomock.Any(), gomock.Eq(user.Username)).Times(1)
+		store.EXPECT().ListUserLoginAttempts(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return([]database.LoginAttempt{attempt}, nil)
+		store.EXPECT().ListUserWebhooks(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return([]database.Webhook{hook}, nil)
+		store.EXPECT().ListUserSessions(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return([]database.Session{session}, nil)
+		store.EXPECT().ListUserKYCSubmissions(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return([]database.KycSubmission{submission}, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "JSON",
+			buildStubs: stubExport,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Header().Get("Content-Disposition"), ".json")
+				// Refresh tokens and webhook secrets are never exported
+				require.NotContains(t, recorder.Body.String(), session.RefreshToken)
+				require.NotContains(t, recorder.Body.String(), hook.Secret)
+
+				var res userExport
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, user.Username, res.Profile.Username)
+				require.Len(t, res.Accounts, 1)
+				require.Equal(t, account.ID, res.Accounts[0].ID)
+				require.Len(t, res.Holdings, 1)
+				require.Equal(t, joint.ID, res.Holdings[0].AccountID)
+				require.Len(t, res.GrantsGiven, 1)
+				require.Equal(t, given.ID, res.GrantsGiven[0].ID)
+				require.Len(t, res.GrantsReceived, 1)
+				require.Equal(t, received.ID, res.GrantsReceived[0].ID)
+				require.Len(t, res.LoginAttempts, 1)
+				require.Equal(t, attempt.ID, res.LoginAttempts[0].ID)
+				require.Len(t, res.Webhooks, 1)
+				require.Equal(t, hook.ID, res.Webhooks[0].ID)
+				require.Len(t, res.Sessions, 1)
+				require.Equal(t, session.ID, res.Sessions[0].ID)
+				require.Len(t, res.KYC, 1)
+				require.Equal(t, submission.DocumentNumber, res.KYC[0].DocumentNumber)
+			},
+		},
+		{
+			name:       "ZIP",
+			query:      "?format=zip",
+			buildStubs: stubExport,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "application/zip", recorder.Header().Get("Content-Type"))
+
+				body := recorder.Body.Bytes()
+				archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+				require.NoError(t, err)
+
+				names := make([]string, len(archive.File))
+				for i, file := range archive.File {
+					names[i] = file.Name
+				}
+				require.ElementsMatch(t, []string{
+					"profile.json",
+					"accounts.json",
+					"account_holdings.json",
+					"grants_given.json",
+					"grants_received.json",
+					"entries.json",
+					"transfers.json",
+					"sessions.json",
+					"login_attempts.json",
+					"webhooks.json",
+					"kyc_submissions.json",
+				}, names)
+			},
+		},
+		{
+			name:  "InvalidFormat",
+			query: "?format=xml",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().ListOwnerAccounts(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/users/me/export"+tc.query, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestDeleteCurrentUser(t *testing.T) {
+	user, password := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"password": password},
+			buildStubs: func(store *mock_db.MockStore) {
+				stubLoginFailures(store, 0, time.Time{})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					DeleteUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg database.DeleteUserTxParams) (database.User, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.WithinDuration(t, time.Now(), arg.DeletedAt, time.Second)
+						return database.User{}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		{
+			name: "WrongPassword",
+			body: gin.H{"password": "wrongPassword"},
+			buildStubs: func(store *mock_db.MockStore) {
+				stubLoginFailures(store, 0, time.Time{})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeInvalidCredentials)).Times(1)
+				store.EXPECT().DeleteUserTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "NonZeroBalance",
+			body: gin.H{"password": password},
+			buildStubs: func(store *mock_db.MockStore) {
+				stubLoginFailures(store, 0, time.Time{})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					DeleteUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(database.User{}, fmt.Errorf("unable to execute transaction: %w", database.ErrNonZeroBalance))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireBodyError(t, recorder.Body, database.ErrNonZeroBalance)
+			},
+		},
+		{
+			name: "NoPassword",
+			body: gin.H{},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().DeleteUserTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodDelete, "/users/me", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
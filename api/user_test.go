@@ -9,17 +9,22 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
+	"github.com/julianinsua/the_simp_bank/auth"
 	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
 	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/mail"
+	mock_mail "github.com/julianinsua/the_simp_bank/mail/mock"
 	"github.com/julianinsua/the_simp_bank/token"
 	"github.com/julianinsua/the_simp_bank/util"
 	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // Create the necessary structs to match the parameters passed to the mock database for user creation
@@ -70,7 +75,7 @@ func TestCreateUser(t *testing.T) {
 			},
 
 			setupAuthFunc: func(t *testing.T, request *http.Request, maker token.PASETOMaker) {
-				addAuthorization(t, request, maker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, maker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
 			},
 			buildStubs: func(store *mock_db.MockStore) {
 				usrParams := database.CreateUserParams{
@@ -79,6 +84,14 @@ func TestCreateUser(t *testing.T) {
 					Email:    user.Email,
 				}
 				store.EXPECT().CreateUser(gomock.Any(), usrChk(usrParams, password)).Times(1).Return(user, nil)
+				store.EXPECT().
+					CreateEmailVerificationToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg database.CreateEmailVerificationTokenParams) (database.EmailVerificationToken, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, user.Email, arg.Email)
+						return database.EmailVerificationToken{}, nil
+					})
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -94,7 +107,7 @@ func TestCreateUser(t *testing.T) {
 				"email":    user.Email,
 			},
 			setupAuthFunc: func(t *testing.T, request *http.Request, maker token.PASETOMaker) {
-				addAuthorization(t, request, maker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, maker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
 			},
 			buildStubs: func(store *mock_db.MockStore) {
 				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(1).Return(database.User{}, sql.ErrConnDone)
@@ -112,17 +125,18 @@ func TestCreateUser(t *testing.T) {
 				"email":    user.Email,
 			},
 			setupAuthFunc: func(t *testing.T, request *http.Request, maker token.PASETOMaker) {
-				addAuthorization(t, request, maker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, maker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
 			},
 			buildStubs: func(store *mock_db.MockStore) {
 				store.
 					EXPECT().
 					CreateUser(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(database.User{}, &pq.Error{Code: "23505"})
+					Return(database.User{}, &pq.Error{Code: "23505", Constraint: "users_pkey"})
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireBodyError(t, recorder.Body, errUsernameTaken)
 			},
 		},
 		{
@@ -134,7 +148,7 @@ func TestCreateUser(t *testing.T) {
 				"email":    user.Email,
 			},
 			setupAuthFunc: func(t *testing.T, request *http.Request, maker token.PASETOMaker) {
-				addAuthorization(t, request, maker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, maker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
 			},
 			buildStubs: func(store *mock_db.MockStore) {
 				store.
@@ -155,7 +169,7 @@ func TestCreateUser(t *testing.T) {
 				"email":    "invalid-email",
 			},
 			setupAuthFunc: func(t *testing.T, request *http.Request, maker token.PASETOMaker) {
-				addAuthorization(t, request, maker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, maker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
 			},
 			buildStubs: func(store *mock_db.MockStore) {
 				store.
@@ -176,7 +190,7 @@ func TestCreateUser(t *testing.T) {
 				"email":    user.Email,
 			},
 			setupAuthFunc: func(t *testing.T, request *http.Request, maker token.PASETOMaker) {
-				addAuthorization(t, request, maker, authorizationTypeBearer, user.Username, time.Minute)
+				addAuthorization(t, request, maker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
 			},
 			buildStubs: func(store *mock_db.MockStore) {
 				store.
@@ -186,6 +200,17 @@ func TestCreateUser(t *testing.T) {
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+				var res struct {
+					Error struct {
+						Code       string   `json:"code"`
+						Violations []string `json:"details"`
+					} `json:"error"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, "weak_password", res.Error.Code)
+				require.NotEmpty(t, res.Error.Violations)
 			},
 		},
 	}
@@ -198,7 +223,10 @@ func TestCreateUser(t *testing.T) {
 			store := mock_db.NewMockStore(ctrl)
 			tc.buildStubs(store)
 
-			server := newTestServer(t, store)
+			mailer := mock_mail.NewMockMailer(ctrl)
+			mailer.EXPECT().Send(gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+
+			server := newTestServerWithMailer(t, store, mailer)
 			recorder := httptest.NewRecorder()
 
 			// Marshal body data to JSON
@@ -218,7 +246,7 @@ func TestCreateUser(t *testing.T) {
 
 func randomUser(t *testing.T) (user database.User, password string) {
 	password = util.RandomString(8)
-	hashedPassword, err := util.HashPassword(password)
+	hashedPassword, err := testPasswordHasher.Hash(password)
 	require.NoError(t, err)
 
 	user = database.User{
@@ -260,11 +288,103 @@ func TestLoginUser(t *testing.T) {
 				"password": password,
 			},
 			buildStubs: func(store *mock_db.MockStore) {
+				stubLoginFailures(store, 0, time.Time{})
 				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
-				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeSuccess)).Times(1)
+				store.EXPECT().CreateSessionTx(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "rehashLegacyHash",
+			body: gin.H{"username": user.Username, "password": password},
+			buildStubs: func(store *mock_db.MockStore) {
+				legacyHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+				require.NoError(t, err)
+				legacyUser := user
+				legacyUser.HashedPassword = string(legacyHash)
+
+				stubLoginFailures(store, 0, time.Time{})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(legacyUser, nil)
+				store.EXPECT().
+					UpdateUserPasswordHash(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg database.UpdateUserPasswordHashParams) error {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, legacyUser.HashedPassword, arg.OldHash)
+						require.True(t, strings.HasPrefix(arg.NewHash, "$argon2id$"))
+						require.NoError(t, util.CheckPassword(password, arg.NewHash))
+						return nil
+					})
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeSuccess)).Times(1)
+				store.EXPECT().CreateSessionTx(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "rehashFailureDoesNotBlockLogin",
+			body: gin.H{"username": user.Username, "password": password},
+			buildStubs: func(store *mock_db.MockStore) {
+				weakHash, err := util.PasswordHasher{
+					Algorithm:     util.Argon2idAlgorithm,
+					Argon2Time:    1,
+					Argon2Memory:  512,
+					Argon2Threads: 1,
+					Argon2KeyLen:  32,
+					Argon2SaltLen: 16,
+				}.Hash(password)
+				require.NoError(t, err)
+				weakUser := user
+				weakUser.HashedPassword = weakHash
+
+				stubLoginFailures(store, 0, time.Time{})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(weakUser, nil)
+				store.EXPECT().UpdateUserPasswordHash(gomock.Any(), gomock.Any()).Times(1).Return(sql.ErrConnDone)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeSuccess)).Times(1)
+				store.EXPECT().CreateSessionTx(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "mfaRequired",
+			body: gin.H{"username": user.Username, "password": password},
+			buildStubs: func(store *mock_db.MockStore) {
+				mfaUser := user
+				mfaUser.TotpEnabled = true
+				stubLoginFailures(store, 0, time.Time{})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(mfaUser, nil)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeMFAChallenge)).Times(1)
+				store.EXPECT().CreateSessionTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res mfaChallengeResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.True(t, res.MFARequired)
+				require.NotEmpty(t, res.ChallengeToken)
+			},
+		},
+		{
+			name: "deletedUser",
+			body: gin.H{"username": user.Username, "password": password},
+			buildStubs: func(store *mock_db.MockStore) {
+				deleted := user
+				deleted.DeletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+				stubLoginFailures(store, 0, time.Time{})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(deleted, nil)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeInvalidCredentials)).Times(1)
+				store.EXPECT().CreateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
 		{
@@ -281,27 +401,47 @@ func TestLoginUser(t *testing.T) {
 			name: "badPassword",
 			body: gin.H{"username": user.Username, "password": "badPassword"},
 			buildStubs: func(store *mock_db.MockStore) {
+				stubLoginFailures(store, 0, time.Time{})
 				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
-				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeInvalidCredentials)).Times(1)
+				store.EXPECT().CreateSessionTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireBodyError(t, recorder.Body, auth.ErrInvalidCredentials)
 			},
 		},
 		{
 			name: "userNotFound",
 			body: gin.H{"username": user.Username, "password": password},
 			buildStubs: func(store *mock_db.MockStore) {
+				stubLoginFailures(store, 0, time.Time{})
 				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(database.User{}, sql.ErrNoRows)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeInvalidCredentials)).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireBodyError(t, recorder.Body, auth.ErrInvalidCredentials)
+			},
+		},
+		{
+			name: "throttled",
+			body: gin.H{"username": user.Username, "password": password},
+			buildStubs: func(store *mock_db.MockStore) {
+				stubLoginFailures(store, 2, time.Now())
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeThrottled)).Times(1)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+				require.NotEmpty(t, recorder.Header().Get("Retry-After"))
 			},
 		},
 		{
 			name: "internalError",
 			body: gin.H{"username": user.Username, "password": password},
 			buildStubs: func(store *mock_db.MockStore) {
+				stubLoginFailures(store, 0, time.Time{})
 				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(database.User{}, sql.ErrConnDone)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
@@ -334,3 +474,367 @@ func TestLoginUser(t *testing.T) {
 		})
 	}
 }
+
+func stubLoginFailures(store *mock_db.MockStore, failures int64, lastFailureAt time.Time) {
+	store.EXPECT().
+		GetUsernameLoginFailures(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(database.GetUsernameLoginFailuresRow{Failures: failures, LastFailureAt: lastFailureAt}, nil)
+	store.EXPECT().
+		GetClientIPLoginFailures(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(database.GetClientIPLoginFailuresRow{}, nil)
+}
+
+type loginAttemptOutcomeMatcher string
+
+func (m loginAttemptOutcomeMatcher) Matches(x interface{}) bool {
+	arg, ok := x.(database.CreateLoginAttemptParams)
+	return ok && arg.Outcome == string(m)
+}
+
+func (m loginAttemptOutcomeMatcher) String() string {
+	return fmt.Sprintf("login attempt with outcome %s", string(m))
+}
+
+func loginAttemptOutcome(outcome string) gomock.Matcher {
+	return loginAttemptOutcomeMatcher(outcome)
+}
+
+type mailToMatcher string
+
+func (m mailToMatcher) Matches(x interface{}) bool {
+	msg, ok := x.(mail.Message)
+	return ok && msg.To == string(m)
+}
+
+func (m mailToMatcher) String() string {
+	return fmt.Sprintf("message to %s", string(m))
+}
+
+func mailTo(to string) gomock.Matcher {
+	return mailToMatcher(to)
+}
+
+func requireBodyError(t *testing.T, body *bytes.Buffer, expected error) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var got errorResponseBody
+	err = json.Unmarshal(data, &got)
+	require.NoError(t, err)
+	require.Equal(t, expected.Error(), got.Error.Message)
+	require.NotEmpty(t, got.Error.Code)
+}
+
+func requireBodyErrorCode(t *testing.T, body *bytes.Buffer, code string) {
+	var got errorResponseBody
+	err := json.Unmarshal(body.Bytes(), &got)
+	require.NoError(t, err)
+	require.Equal(t, code, got.Error.Code)
+}
+
+func TestChangeUserPassword(t *testing.T) {
+	user, password := randomUser(t)
+	newPassword := util.RandomString(10)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"currentPassword": password, "newPassword": newPassword},
+			buildStubs: func(store *mock_db.MockStore) {
+				stubLoginFailures(store, 0, time.Time{})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					ChangePasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg database.UpdateUserPasswordParams) (database.User, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.NoError(t, util.CheckPassword(newPassword, arg.HashedPassword))
+						require.WithinDuration(t, time.Now(), arg.PasswordChangedAt, time.Second)
+						return user, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "WrongCurrentPassword",
+			body: gin.H{"currentPassword": "wrongPassword", "newPassword": newPassword},
+			buildStubs: func(store *mock_db.MockStore) {
+				stubLoginFailures(store, 0, time.Time{})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeInvalidCredentials)).Times(1)
+				store.EXPECT().ChangePasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ShortNewPassword",
+			body: gin.H{"currentPassword": password, "newPassword": "abc"},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().ChangePasswordTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/password", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetCurrentUser(t *testing.T) {
+	user, _ := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mock_db.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/users/me", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	requireBodyMatchUser(t, recorder.Body, user)
+}
+
+func TestUpdateCurrentUser(t *testing.T) {
+	user, password := randomUser(t)
+	user.EmailVerifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	newEmail := util.RandomEmail()
+
+	renamed := user
+	renamed.FullName = util.RandomOwner()
+
+	moved := user
+	moved.Email = newEmail
+	moved.EmailVerifiedAt = sql.NullTime{}
+
+	// Changing the email re-authenticates the user and checks the verification resend limit first
+	stubReauthenticated := func(store *mock_db.MockStore) {
+		store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(2).Return(user, nil)
+		stubLoginFailures(store, 0, time.Time{})
+		store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeStepUp)).Times(1)
+		store.EXPECT().CountRecentEmailVerificationTokens(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mock_db.MockStore, mailer *mock_mail.MockMailer)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "FullName",
+			body: gin.H{"fullName": renamed.FullName},
+			buildStubs: func(store *mock_db.MockStore, mailer *mock_mail.MockMailer) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					UpdateUserProfile(gomock.Any(), gomock.Eq(database.UpdateUserProfileParams{
+						FullName: sql.NullString{String: renamed.FullName, Valid: true},
+						Username: user.Username,
+					})).
+					Times(1).
+					Return(renamed, nil)
+				store.EXPECT().CreateEmailVerificationToken(gomock.Any(), gomock.Any()).Times(0)
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res userResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, renamed.FullName, res.FullName)
+				require.True(t, res.EmailVerified)
+			},
+		},
+		{
+			name: "EmailRequiresVerification",
+			body: gin.H{"email": newEmail, "password": password},
+			buildStubs: func(store *mock_db.MockStore, mailer *mock_mail.MockMailer) {
+				stubReauthenticated(store)
+				store.EXPECT().
+					UpdateUserProfile(gomock.Any(), gomock.Eq(database.UpdateUserProfileParams{
+						Email:    sql.NullString{String: newEmail, Valid: true},
+						Username: user.Username,
+					})).
+					Times(1).
+					Return(moved, nil)
+				store.EXPECT().
+					CreateEmailVerificationToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg database.CreateEmailVerificationTokenParams) (database.EmailVerificationToken, error) {
+						require.Equal(t, newEmail, arg.Email)
+						return database.EmailVerificationToken{}, nil
+					})
+				// The verification goes to the new address and the notice to the old one
+				mailer.EXPECT().Send(gomock.Any(), mailTo(newEmail)).Times(1).Return(nil)
+				mailer.EXPECT().Send(gomock.Any(), mailTo(user.Email)).Times(1).Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res userResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, newEmail, res.Email)
+				require.False(t, res.EmailVerified)
+			},
+		},
+		{
+			name: "EmailWithoutReauthentication",
+			body: gin.H{"email": newEmail},
+			buildStubs: func(store *mock_db.MockStore, mailer *mock_mail.MockMailer) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().UpdateUserProfile(gomock.Any(), gomock.Any()).Times(0)
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireBodyErrorCode(t, recorder.Body, "email_change_reauth_required")
+			},
+		},
+		{
+			name: "EmailWrongPassword",
+			body: gin.H{"email": newEmail, "password": "wrongPassword"},
+			buildStubs: func(store *mock_db.MockStore, mailer *mock_mail.MockMailer) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(2).Return(user, nil)
+				stubLoginFailures(store, 0, time.Time{})
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeInvalidCredentials)).Times(1)
+				store.EXPECT().UpdateUserProfile(gomock.Any(), gomock.Any()).Times(0)
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "VerificationLimitReached",
+			body: gin.H{"email": newEmail, "password": password},
+			buildStubs: func(store *mock_db.MockStore, mailer *mock_mail.MockMailer) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(2).Return(user, nil)
+				stubLoginFailures(store, 0, time.Time{})
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), loginAttemptOutcome(auth.OutcomeStepUp)).Times(1)
+				store.EXPECT().CountRecentEmailVerificationTokens(gomock.Any(), gomock.Any()).Times(1).Return(int64(3), nil)
+				store.EXPECT().UpdateUserProfile(gomock.Any(), gomock.Any()).Times(0)
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+				requireBodyErrorCode(t, recorder.Body, "verification_resend_limit")
+			},
+		},
+		{
+			name: "SameEmail",
+			body: gin.H{"email": user.Email},
+			buildStubs: func(store *mock_db.MockStore, mailer *mock_mail.MockMailer) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().UpdateUserProfile(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().CountRecentEmailVerificationTokens(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateEmailVerificationToken(gomock.Any(), gomock.Any()).Times(0)
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "EmailInUse",
+			body: gin.H{"email": newEmail, "password": password},
+			buildStubs: func(store *mock_db.MockStore, mailer *mock_mail.MockMailer) {
+				stubReauthenticated(store)
+				store.EXPECT().
+					UpdateUserProfile(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(database.User{}, &pq.Error{Code: "23505", Constraint: "users_email_key"})
+				mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireBodyError(t, recorder.Body, errEmailInUse)
+			},
+		},
+		{
+			name: "EmptyBody",
+			body: gin.H{},
+			buildStubs: func(store *mock_db.MockStore, mailer *mock_mail.MockMailer) {
+				store.EXPECT().UpdateUserProfile(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidEmail",
+			body: gin.H{"email": "invalid-email"},
+			buildStubs: func(store *mock_db.MockStore, mailer *mock_mail.MockMailer) {
+				store.EXPECT().UpdateUserProfile(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			mailer := mock_mail.NewMockMailer(ctrl)
+			tc.buildStubs(store, mailer)
+
+			server := newTestServerWithMailer(t, store, mailer)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPatch, "/users/me", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"github.com/go-playground/validator/v10"
+	"github.com/julianinsua/the_simp_bank/internal/database"
 	"github.com/julianinsua/the_simp_bank/util"
 )
 
@@ -11,3 +12,17 @@ var validCurrency validator.Func = func(fieldLevel validator.FieldLevel) bool {
 	}
 	return false
 }
+
+var validRole validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if role, ok := fieldLevel.Field().Interface().(string); ok {
+		return util.IsSupportedRole(role)
+	}
+	return false
+}
+
+var validWebhookEvent validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if eventType, ok := fieldLevel.Field().Interface().(string); ok {
+		return database.IsWebhookEventType(eventType)
+	}
+	return false
+}
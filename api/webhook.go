@@ -0,0 +1,256 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/token"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/julianinsua/the_simp_bank/webhook"
+)
+
+var errWebhookNotFound = errors.New("there is no webhook with this id")
+
+type webhookURI struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+type createWebhookRequest struct {
+	URL        string   `json:"url" binding:"required,http_url"`
+	EventTypes []string `json:"eventTypes" binding:"required,min=1,dive,webhook_event"`
+}
+
+type webhookResponse struct {
+	ID         uuid.UUID `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"eventTypes"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func newWebhookResponse(hook database.Webhook) webhookResponse {
+	return webhookResponse{
+		ID:         hook.ID,
+		URL:        hook.Url,
+		EventTypes: hook.EventTypes,
+		CreatedAt:  hook.CreatedAt,
+	}
+}
+
+/*
+The secret is only ever returned here, receivers need it to verify the signature of the payloads
+*/
+type createWebhookResponse struct {
+	webhookResponse
+	Secret string `json:"secret"`
+}
+
+type webhookListResponse struct {
+	Webhooks []webhookResponse `json:"webhooks"`
+}
+
+type webhookDeliveryResponse struct {
+	ID            uuid.UUID       `json:"id"`
+	EventID       uuid.UUID       `json:"eventId"`
+	EventType     string          `json:"eventType"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        string          `json:"status"`
+	Attempts      int32           `json:"attempts"`
+	NextAttemptAt *time.Time      `json:"nextAttemptAt"`
+	ResponseCode  *int32          `json:"responseCode"`
+	LastError     *string         `json:"lastError"`
+	DeliveredAt   *time.Time      `json:"deliveredAt"`
+	CreatedAt     time.Time       `json:"createdAt"`
+}
+
+func newWebhookDeliveryResponse(delivery database.WebhookDelivery) webhookDeliveryResponse {
+	res := webhookDeliveryResponse{
+		ID:        delivery.ID,
+		EventID:   delivery.EventID,
+		EventType: delivery.EventType,
+		Payload:   delivery.Payload,
+		Status:    delivery.Status,
+		Attempts:  delivery.Attempts,
+		CreatedAt: delivery.CreatedAt,
+	}
+	if delivery.Status == database.WebhookDeliveryPending {
+		res.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	if delivery.ResponseCode.Valid {
+		res.ResponseCode = &delivery.ResponseCode.Int32
+	}
+	if delivery.LastError.Valid {
+		res.LastError = &delivery.LastError.String
+	}
+	if delivery.DeliveredAt.Valid {
+		res.DeliveredAt = &delivery.DeliveredAt.Time
+	}
+	return res
+}
+
+/*
+A page of the delivery log of a webhook
+*/
+type webhookDeliveryListResponse struct {
+	Deliveries []webhookDeliveryResponse `json:"deliveries"`
+	pageLinks
+}
+
+func webhookDeliveryCursor(delivery database.WebhookDelivery) util.PageCursor {
+	return util.PageCursor{CreatedAt: delivery.CreatedAt, ID: delivery.ID}
+}
+
+/*
+Registers a webhook for the authenticated user. It gets the subscribed events of every account the user holds. The URL
+has to use https and resolve to public addresses only.
+*/
+func (srv *Server) createWebhook(ctx *gin.Context) {
+	var req createWebhookRequest
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+	err = webhook.CheckURL(ctx, req.URL)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	secret, err := util.GenerateSecureToken(32)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	hook, err := srv.store.CreateWebhook(ctx, database.CreateWebhookParams{
+		Username:   authPayload.Username,
+		Url:        req.URL,
+		Secret:     secret,
+		EventTypes: req.EventTypes,
+	})
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, createWebhookResponse{
+		webhookResponse: newWebhookResponse(hook),
+		Secret:          hook.Secret,
+	})
+}
+
+/*
+Lists the webhooks of the authenticated user
+*/
+func (srv *Server) listWebhooks(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+
+	hooks, err := srv.store.ListUserWebhooks(ctx, authPayload.Username)
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	res := webhookListResponse{Webhooks: make([]webhookResponse, 0, len(hooks))}
+	for _, hook := range hooks {
+		res.Webhooks = append(res.Webhooks, newWebhookResponse(hook))
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+/*
+Deletes a webhook of the authenticated user along with its pending deliveries
+*/
+func (srv *Server) deleteWebhook(ctx *gin.Context) {
+	var uri webhookURI
+	err := ctx.ShouldBindUri(&uri)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	_, err = srv.store.DeleteWebhook(ctx, database.DeleteWebhookParams{
+		ID:       uuid.MustParse(uri.ID),
+		Username: authPayload.Username,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(ctx, http.StatusNotFound, errWebhookNotFound)
+			return
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+/*
+Lists the deliveries of a webhook of the authenticated user oldest first, with the outcome of their last attempt
+*/
+func (srv *Server) listWebhookDeliveries(ctx *gin.Context) {
+	var uri webhookURI
+	err := ctx.ShouldBindUri(&uri)
+	if err != nil {
+		respondWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	cur, size, ok := srv.bindPage(ctx)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.PASETOPayload)
+	hook, err := srv.store.GetUserWebhook(ctx, database.GetUserWebhookParams{
+		ID:       uuid.MustParse(uri.ID),
+		Username: authPayload.Username,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			respondWithError(ctx, http.StatusNotFound, errWebhookNotFound)
+			return
+		}
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	var deliveries []database.WebhookDelivery
+	if cur.Before {
+		deliveries, err = srv.store.ListWebhookDeliveriesBefore(ctx, database.ListWebhookDeliveriesBeforeParams{
+			WebhookID:       hook.ID,
+			CursorCreatedAt: cur.CreatedAt,
+			CursorID:        cur.ID,
+			PageSize:        size + 1,
+		})
+	} else {
+		deliveries, err = srv.store.ListWebhookDeliveriesAfter(ctx, database.ListWebhookDeliveriesAfterParams{
+			WebhookID:       hook.ID,
+			CursorCreatedAt: cur.CreatedAt,
+			CursorID:        cur.ID,
+			PageSize:        size + 1,
+		})
+	}
+	if err != nil {
+		respondWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	deliveries, next, prev := util.Paginate(deliveries, size, cur, webhookDeliveryCursor)
+	res := webhookDeliveryListResponse{
+		Deliveries: make([]webhookDeliveryResponse, 0, len(deliveries)),
+		pageLinks:  newPageLinks(ctx, size, next, prev),
+	}
+	for _, delivery := range deliveries {
+		res.Deliveries = append(res.Deliveries, newWebhookDeliveryResponse(delivery))
+	}
+	ctx.JSON(http.StatusOK, res)
+}
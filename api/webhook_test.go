@@ -0,0 +1,278 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomWebhook(username string) database.Webhook {
+	return database.Webhook{
+		ID:         uuid.New(),
+		Username:   username,
+		Url:        "https://93.184.215.14/hooks",
+		Secret:     util.RandomString(43),
+		EventTypes: []string{database.EventTransferCreated, database.EventTransferReceived},
+		CreatedAt:  time.Now(),
+	}
+}
+
+func TestCreateWebhookAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	hook := randomWebhook(user.Username)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"url": hook.Url, "eventTypes": hook.EventTypes},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().
+					CreateWebhook(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, params database.CreateWebhookParams) (database.Webhook, error) {
+						require.Equal(t, user.Username, params.Username)
+						require.Equal(t, hook.EventTypes, params.EventTypes)
+						require.Len(t, params.Secret, 43)
+						return hook, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var res createWebhookResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, hook.ID, res.ID)
+				require.Equal(t, hook.Secret, res.Secret)
+			},
+		},
+		{
+			name: "UnknownEventType",
+			body: gin.H{"url": hook.Url, "eventTypes": []string{"account.frozen"}},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoEventTypes",
+			body: gin.H{"url": hook.Url, "eventTypes": []string{}},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidURL",
+			body: gin.H{"url": "ftp://example.com", "eventTypes": hook.EventTypes},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "PlainHTTP",
+			body: gin.H{"url": "http://93.184.215.14/hooks", "eventTypes": hook.EventTypes},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyErrorCode(t, recorder.Body, "unsafe_webhook_url")
+			},
+		},
+		{
+			name: "PrivateAddress",
+			body: gin.H{"url": "https://169.254.169.254/latest/meta-data", "eventTypes": hook.EventTypes},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyErrorCode(t, recorder.Body, "unsafe_webhook_url")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestDeleteWebhookAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	hook := randomWebhook(user.Username)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().
+					DeleteWebhook(gomock.Any(), gomock.Eq(database.DeleteWebhookParams{ID: hook.ID, Username: user.Username})).
+					Times(1).
+					Return(hook, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNoContent, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().DeleteWebhook(gomock.Any(), gomock.Any()).Times(1).Return(database.Webhook{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireBodyError(t, recorder.Body, errWebhookNotFound)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/webhooks/%s", hook.ID), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListWebhookDeliveriesAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	hook := randomWebhook(user.Username)
+	deliveries := []database.WebhookDelivery{
+		{
+			ID:            uuid.New(),
+			WebhookID:     hook.ID,
+			EventID:       uuid.New(),
+			EventType:     database.EventTransferCreated,
+			Payload:       json.RawMessage(`{"type":"transfer.created"}`),
+			Status:        database.WebhookDeliveryPending,
+			Attempts:      2,
+			NextAttemptAt: time.Now().Add(time.Minute).UTC().Truncate(time.Microsecond),
+			ResponseCode:  sql.NullInt32{Int32: http.StatusServiceUnavailable, Valid: true},
+			LastError:     sql.NullString{String: "receiver answered 503 Service Unavailable", Valid: true},
+			CreatedAt:     time.Now().UTC().Truncate(time.Microsecond),
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().
+					GetUserWebhook(gomock.Any(), gomock.Eq(database.GetUserWebhookParams{ID: hook.ID, Username: user.Username})).
+					Times(1).
+					Return(hook, nil)
+				store.EXPECT().
+					ListWebhookDeliveriesAfter(gomock.Any(), gomock.Eq(database.ListWebhookDeliveriesAfterParams{WebhookID: hook.ID, PageSize: 11})).
+					Times(1).
+					Return(deliveries, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res webhookDeliveryListResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Len(t, res.Deliveries, 1)
+				require.Equal(t, newWebhookDeliveryResponse(deliveries[0]), res.Deliveries[0])
+				require.Empty(t, res.Next)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUserWebhook(gomock.Any(), gomock.Any()).Times(1).Return(database.Webhook{}, sql.ErrNoRows)
+				store.EXPECT().ListWebhookDeliveriesAfter(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireBodyError(t, recorder.Body, errWebhookNotFound)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/webhooks/%s/deliveries", hook.ID), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, util.CustomerRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
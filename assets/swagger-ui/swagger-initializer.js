@@ -0,0 +1 @@
+window.ui = SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"});
@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/mail"
+	"github.com/julianinsua/the_simp_bank/metrics"
+	"github.com/julianinsua/the_simp_bank/token"
+	"github.com/julianinsua/the_simp_bank/util"
+)
+
+// Outcomes stored in the login_attempts audit table
+const (
+	OutcomeSuccess            = "success"
+	OutcomeInvalidCredentials = "invalid_credentials"
+	OutcomeMFAChallenge       = "mfa_challenge"
+	OutcomeStepUp             = "step_up"
+	OutcomeThrottled          = "throttled"
+)
+
+const emailVerificationTokenSize = 32
+
+var (
+	// Same message for unknown users and wrong passwords so usernames can't be enumerated
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrLoginThrottled     = errors.New("too many failed login attempts")
+	ErrTokenRevoked       = errors.New("token was issued before the last password change")
+)
+
+// Client a login attempt comes from, as seen by the transport
+type Client struct {
+	IP    string
+	Agent string
+}
+
+// Logs users in the same way whatever the transport: login throttling, the audit trail, password hash upgrades, token
+// revocation and email verification. Both the HTTP and the gRPC servers go through it.
+type Authenticator struct {
+	config util.Config
+	store  database.Store
+	mailer mail.Mailer
+	hasher util.PasswordHasher
+}
+
+// Creates an authenticator hashing passwords with hasher
+func New(config util.Config, store database.Store, mailer mail.Mailer, hasher util.PasswordHasher) Authenticator {
+	return Authenticator{config: config, store: store, mailer: mailer, hasher: hasher}
+}
+
+// Checks the recent failures for both the username and the client IP and returns the longest wait between them
+func (a Authenticator) LoginRetryAfter(ctx context.Context, username, clientIP string) (time.Duration, error) {
+	since := time.Now().Add(-a.config.LoginAttemptWindow)
+
+	byUser, err := a.store.GetUsernameLoginFailures(ctx, database.GetUsernameLoginFailuresParams{
+		Username: username,
+		Since:    since,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	byIP, err := a.store.GetClientIPLoginFailures(ctx, database.GetClientIPLoginFailuresParams{
+		ClientIp: clientIP,
+		Since:    since,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	wait := util.LoginRetryAfter(byUser.Failures, byUser.LastFailureAt, a.config.LoginMaxAttempts, a.config)
+	ipWait := util.LoginRetryAfter(byIP.Failures, byIP.LastFailureAt, a.config.LoginMaxIPAttempts, a.config)
+	if ipWait > wait {
+		wait = ipWait
+	}
+	return wait, nil
+}
+
+// Records a throttled attempt and returns ErrLoginThrottled when the username or client IP has to wait before trying
+// again, along with the wait. Any other error means the check couldn't run.
+func (a Authenticator) AllowLoginAttempt(ctx context.Context, username string, client Client) (time.Duration, error) {
+	wait, err := a.LoginRetryAfter(ctx, username, client.IP)
+	if err != nil {
+		return 0, err
+	}
+	if wait <= 0 {
+		return 0, nil
+	}
+
+	err = a.RecordLoginAttempt(ctx, username, OutcomeThrottled, client)
+	if err != nil {
+		return 0, err
+	}
+	return wait, fmt.Errorf("%w, retry in %v", ErrLoginThrottled, wait.Round(time.Second))
+}
+
+// Stores the audit record for a login attempt and counts the failed ones in the metrics
+func (a Authenticator) RecordLoginAttempt(ctx context.Context, username, outcome string, client Client) error {
+	if outcome == OutcomeInvalidCredentials || outcome == OutcomeThrottled {
+		metrics.LoginFailures.WithLabelValues(outcome).Inc()
+	}
+	_, err := a.store.CreateLoginAttempt(ctx, database.CreateLoginAttemptParams{
+		Username:    username,
+		ClientIp:    client.IP,
+		ClientAgent: client.Agent,
+		Outcome:     outcome,
+	})
+	return err
+}
+
+// Stores the password hashed with the current parameters when the stored hash used older ones. The plain password is
+// only available on login, and failing only leaves the old hash in place.
+func (a Authenticator) RehashPassword(ctx context.Context, usr database.User, password string) error {
+	if !a.hasher.NeedsRehash(usr.HashedPassword) {
+		return nil
+	}
+	hash, err := a.hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+	// Matching the old hash keeps a concurrent password change from being overwritten
+	return a.store.UpdateUserPasswordHash(ctx, database.UpdateUserPasswordHashParams{
+		NewHash:  hash,
+		Username: usr.Username,
+		OldHash:  usr.HashedPassword,
+	})
+}
+
+// Reports whether a token was issued before the password it was obtained with was changed
+func TokenRevoked(issuedAt, passwordChangedAt time.Time) bool {
+	return issuedAt.Before(passwordChangedAt)
+}
+
+// Checks that an access token wasn't revoked by a password change. Returns token.ErrInvalidToken when the user no
+// longer exists and ErrTokenRevoked when the token is older than the password, any other error means the check
+// couldn't run.
+func (a Authenticator) CheckNotRevoked(ctx context.Context, payload *token.PASETOPayload) error {
+	changedAt, err := a.store.GetUserPasswordChangedAt(ctx, payload.Username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return token.ErrInvalidToken
+		}
+		return err
+	}
+	if TokenRevoked(payload.IssuedAt, changedAt) {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+// Sends a new verification token to the email of a user
+func (a Authenticator) SendEmailVerification(ctx context.Context, usr database.User) error {
+	verificationToken, err := util.GenerateSecureToken(emailVerificationTokenSize)
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(a.config.EmailVerificationDuration)
+	_, err = a.store.CreateEmailVerificationToken(ctx, database.CreateEmailVerificationTokenParams{
+		Username:    usr.Username,
+		Email:       usr.Email,
+		HashedToken: util.HashSecureToken(verificationToken),
+		ExpiresAt:   expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.mailer.Send(ctx, mail.Message{
+		To:      usr.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse this token to verify your email before %s:\n\n%s",
+			usr.FullName,
+			expiresAt.Format(time.RFC1123),
+			verificationToken,
+		),
+	})
+}
+
+// Tells the previous address of a user that their email was changed, so an owner who didn't make the change can react
+func (a Authenticator) SendEmailChangedNotice(ctx context.Context, usr database.User, oldEmail string) error {
+	return a.mailer.Send(ctx, mail.Message{
+		To:      oldEmail,
+		Subject: "Your email was changed",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nThe email of your account was changed to %s. If you didn't make this change, reset your password "+
+				"and contact support right away.",
+			usr.FullName,
+			usr.Email,
+		),
+	})
+}
@@ -0,0 +1,160 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/token"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAuthenticator(store database.Store) Authenticator {
+	config := util.Config{
+		LoginAttemptWindow:   time.Hour,
+		LoginMaxAttempts:     5,
+		LoginMaxIPAttempts:   50,
+		LoginBackoffBase:     time.Second,
+		LoginLockoutDuration: 15 * time.Minute,
+	}
+	return New(config, store, nil, util.DefaultPasswordHasher())
+}
+
+func TestAllowLoginAttempt(t *testing.T) {
+	username := util.RandomOwner()
+	client := Client{IP: "192.0.2.1", Agent: "test"}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mock_db.MockStore)
+		checkErr   func(t *testing.T, wait time.Duration, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUsernameLoginFailures(gomock.Any(), gomock.Any()).Times(1)
+				store.EXPECT().GetClientIPLoginFailures(gomock.Any(), gomock.Any()).Times(1)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkErr: func(t *testing.T, wait time.Duration, err error) {
+				require.NoError(t, err)
+				require.Zero(t, wait)
+			},
+		},
+		{
+			name: "ThrottledByIP",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUsernameLoginFailures(gomock.Any(), gomock.Any()).Times(1)
+				store.EXPECT().
+					GetClientIPLoginFailures(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(database.GetClientIPLoginFailuresRow{Failures: 50, LastFailureAt: time.Now()}, nil)
+				store.EXPECT().
+					CreateLoginAttempt(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg database.CreateLoginAttemptParams) (database.LoginAttempt, error) {
+						require.Equal(t, OutcomeThrottled, arg.Outcome)
+						require.Equal(t, client.IP, arg.ClientIp)
+						require.Equal(t, client.Agent, arg.ClientAgent)
+						return database.LoginAttempt{}, nil
+					})
+			},
+			checkErr: func(t *testing.T, wait time.Duration, err error) {
+				require.ErrorIs(t, err, ErrLoginThrottled)
+				require.Positive(t, wait)
+			},
+		},
+		{
+			name: "StoreError",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().
+					GetUsernameLoginFailures(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(database.GetUsernameLoginFailuresRow{}, sql.ErrConnDone)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkErr: func(t *testing.T, wait time.Duration, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+				require.False(t, errors.Is(err, ErrLoginThrottled))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			wait, err := newTestAuthenticator(store).AllowLoginAttempt(context.Background(), username, client)
+			tc.checkErr(t, wait, err)
+		})
+	}
+}
+
+func TestCheckNotRevoked(t *testing.T) {
+	payload := &token.PASETOPayload{Payload: token.Payload{Username: util.RandomOwner()}, IssuedAt: time.Now()}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mock_db.MockStore)
+		checkErr   func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().
+					GetUserPasswordChangedAt(gomock.Any(), payload.Username).
+					Times(1).
+					Return(payload.IssuedAt.Add(-time.Minute), nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "PasswordChanged",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().
+					GetUserPasswordChangedAt(gomock.Any(), payload.Username).
+					Times(1).
+					Return(payload.IssuedAt.Add(time.Minute), nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrTokenRevoked)
+			},
+		},
+		{
+			name: "UserNotFound",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().
+					GetUserPasswordChangedAt(gomock.Any(), payload.Username).
+					Times(1).
+					Return(time.Time{}, sql.ErrNoRows)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, token.ErrInvalidToken)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			tc.checkErr(t, newTestAuthenticator(store).CheckNotRevoked(context.Background(), payload))
+		})
+	}
+}
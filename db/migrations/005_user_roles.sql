@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE "users" ADD COLUMN "role" varchar NOT NULL DEFAULT 'customer';
+
+ALTER TABLE "users" ADD CONSTRAINT "users_role_check" CHECK ("role" IN ('customer', 'support', 'admin'));
+
+-- +goose Down
+ALTER TABLE IF EXISTS "users" DROP CONSTRAINT IF EXISTS "users_role_check";
+ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "role";
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE "login_attempts" (
+  "id" uuid PRIMARY KEY DEFAULT (gen_random_uuid()),
+  "username" varchar NOT NULL,
+  "client_ip" varchar NOT NULL,
+  "client_agent" varchar NOT NULL,
+  "outcome" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "login_attempts" ("username", "created_at");
+
+CREATE INDEX ON "login_attempts" ("client_ip", "created_at");
+
+COMMENT ON COLUMN "login_attempts"."username" IS 'as submitted, it may not belong to any user';
+
+COMMENT ON COLUMN "login_attempts"."outcome" IS 'success, invalid_credentials or throttled';
+
+-- +goose Down
+DROP TABLE IF EXISTS "login_attempts";
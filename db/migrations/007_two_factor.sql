@@ -0,0 +1,24 @@
+-- +goose Up
+ALTER TABLE "users" ADD COLUMN "totp_secret" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "users" ADD COLUMN "totp_enabled" boolean NOT NULL DEFAULT FALSE;
+
+CREATE TABLE "recovery_codes" (
+  "id" uuid PRIMARY KEY DEFAULT (gen_random_uuid()),
+  "username" varchar NOT NULL,
+  "hashed_code" varchar NOT NULL,
+  "used" boolean NOT NULL DEFAULT FALSE,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "recovery_codes" ("username");
+
+ALTER TABLE "recovery_codes" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "login_attempts"."outcome" IS 'success, invalid_credentials, mfa_challenge or throttled';
+
+-- +goose Down
+DROP TABLE IF EXISTS "recovery_codes";
+
+ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "totp_enabled";
+ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "totp_secret";
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE "consumed_tokens" (
+  "id" uuid PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "purpose" varchar NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "consumed_tokens" ("expires_at");
+
+COMMENT ON TABLE "consumed_tokens" IS 'single use tokens already redeemed, kept until they expire';
+
+COMMENT ON COLUMN "login_attempts"."outcome" IS 'success, invalid_credentials, mfa_challenge, step_up or throttled';
+
+-- +goose Down
+DROP TABLE IF EXISTS "consumed_tokens";
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE "password_reset_tokens" (
+  "id" uuid PRIMARY KEY DEFAULT (gen_random_uuid()),
+  "username" varchar NOT NULL,
+  "hashed_token" varchar UNIQUE NOT NULL,
+  "used" boolean NOT NULL DEFAULT FALSE,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "password_reset_tokens" ("username");
+
+ALTER TABLE "password_reset_tokens" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "password_reset_tokens"."hashed_token" IS 'sha256 of the token sent by email';
+
+-- +goose Down
+DROP TABLE IF EXISTS "password_reset_tokens";
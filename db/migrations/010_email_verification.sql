@@ -0,0 +1,25 @@
+-- +goose Up
+ALTER TABLE "users" ADD COLUMN "email_verified_at" timestamptz;
+
+CREATE TABLE "email_verification_tokens" (
+  "id" uuid PRIMARY KEY DEFAULT (gen_random_uuid()),
+  "username" varchar NOT NULL,
+  "email" varchar NOT NULL,
+  "hashed_token" varchar UNIQUE NOT NULL,
+  "used" boolean NOT NULL DEFAULT FALSE,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "email_verification_tokens" ("username", "created_at");
+
+ALTER TABLE "email_verification_tokens" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "email_verification_tokens"."email" IS 'address the token was sent to, it only verifies that one';
+
+COMMENT ON COLUMN "email_verification_tokens"."hashed_token" IS 'sha256 of the token sent by email';
+
+-- +goose Down
+DROP TABLE IF EXISTS "email_verification_tokens";
+
+ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "email_verified_at";
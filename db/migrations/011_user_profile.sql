@@ -0,0 +1,6 @@
+-- +goose Up
+-- Different people can share a name, only usernames and emails identify a user
+ALTER TABLE "users" DROP CONSTRAINT IF EXISTS "users_full_name_key";
+
+-- +goose Down
+ALTER TABLE "users" ADD CONSTRAINT "users_full_name_key" UNIQUE ("full_name");
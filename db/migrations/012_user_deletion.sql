@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE "users" ADD COLUMN "deleted_at" timestamptz;
+
+COMMENT ON COLUMN "users"."deleted_at" IS 'set when the user deleted its account, personal fields are anonymized';
+
+-- +goose Down
+ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "deleted_at";
@@ -0,0 +1,41 @@
+-- +goose Up
+ALTER TABLE "users" ADD COLUMN "kyc_status" varchar NOT NULL DEFAULT 'pending';
+
+ALTER TABLE "users" ADD CONSTRAINT "users_kyc_status_check" CHECK ("kyc_status" IN ('pending', 'verified', 'rejected'));
+
+CREATE TABLE "kyc_submissions" (
+  "id" uuid PRIMARY KEY DEFAULT (gen_random_uuid()),
+  "username" varchar NOT NULL,
+  "document_type" varchar NOT NULL,
+  "document_number" varchar NOT NULL,
+  "issuing_country" varchar NOT NULL,
+  "document_expires_at" date NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "review_note" varchar NOT NULL DEFAULT '',
+  "reviewed_by" varchar,
+  "reviewed_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "kyc_submissions" ADD CONSTRAINT "kyc_submissions_document_type_check" CHECK ("document_type" IN ('passport', 'national_id', 'drivers_license'));
+
+ALTER TABLE "kyc_submissions" ADD CONSTRAINT "kyc_submissions_status_check" CHECK ("status" IN ('pending', 'verified', 'rejected'));
+
+CREATE INDEX ON "kyc_submissions" ("status", "created_at");
+
+-- A user can only have one submission waiting for review
+CREATE UNIQUE INDEX "kyc_submissions_pending_key" ON "kyc_submissions" ("username") WHERE "status" = 'pending';
+
+ALTER TABLE "kyc_submissions" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "kyc_submissions" ADD FOREIGN KEY ("reviewed_by") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "kyc_submissions"."issuing_country" IS 'ISO 3166-1 alpha-2 code';
+
+COMMENT ON COLUMN "kyc_submissions"."reviewed_by" IS 'admin or support user that took the decision';
+
+-- +goose Down
+DROP TABLE IF EXISTS "kyc_submissions";
+
+ALTER TABLE IF EXISTS "users" DROP CONSTRAINT IF EXISTS "users_kyc_status_check";
+ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "kyc_status";
@@ -0,0 +1,31 @@
+-- +goose Up
+CREATE TABLE "account_holders" (
+  "account_id" uuid NOT NULL,
+  "username" varchar NOT NULL,
+  "role" varchar NOT NULL,
+  "invited_by" varchar NOT NULL,
+  "accepted_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "username")
+);
+
+ALTER TABLE "account_holders" ADD CONSTRAINT "account_holders_role_check" CHECK ("role" IN ('owner', 'co_owner', 'viewer'));
+
+CREATE INDEX ON "account_holders" ("username");
+
+ALTER TABLE "account_holders" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "account_holders" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "account_holders" ADD FOREIGN KEY ("invited_by") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "account_holders"."role" IS 'owner, co_owner or viewer';
+
+COMMENT ON COLUMN "account_holders"."accepted_at" IS 'null while the invitation is pending';
+
+-- Every existing account is held by its owner
+INSERT INTO "account_holders" ("account_id", "username", "role", "invited_by", "accepted_at", "created_at")
+SELECT "id", "owner", 'owner', "owner", "created_at", "created_at" FROM "accounts";
+
+-- +goose Down
+DROP TABLE IF EXISTS "account_holders";
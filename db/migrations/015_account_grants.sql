@@ -0,0 +1,29 @@
+-- +goose Up
+CREATE TABLE "account_grants" (
+  "id" uuid PRIMARY KEY DEFAULT (gen_random_uuid()),
+  "account_id" uuid NOT NULL,
+  "grantor" varchar NOT NULL,
+  "grantee" varchar NOT NULL,
+  "transfer_limit" float NOT NULL DEFAULT 0,
+  "can_approve" boolean NOT NULL DEFAULT false,
+  "expires_at" timestamptz NOT NULL,
+  "revoked_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "account_grants" ADD CONSTRAINT "account_grants_transfer_limit_check" CHECK ("transfer_limit" >= 0);
+
+CREATE INDEX ON "account_grants" ("account_id", "grantee");
+
+CREATE INDEX ON "account_grants" ("grantee");
+
+ALTER TABLE "account_grants" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "account_grants" ADD FOREIGN KEY ("grantor") REFERENCES "users" ("username");
+
+ALTER TABLE "account_grants" ADD FOREIGN KEY ("grantee") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "account_grants"."transfer_limit" IS 'largest transfer the grantee can initiate, 0 for view only access';
+
+-- +goose Down
+DROP TABLE IF EXISTS "account_grants";
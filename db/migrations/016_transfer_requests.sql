@@ -0,0 +1,57 @@
+-- +goose Up
+ALTER TABLE "accounts" ADD COLUMN "approval_threshold" float NOT NULL DEFAULT 0;
+
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_approval_threshold_check" CHECK ("approval_threshold" >= 0);
+
+COMMENT ON COLUMN "accounts"."approval_threshold" IS 'transfers of this amount or more need a second approval, 0 disables approvals';
+
+CREATE TABLE "transfer_requests" (
+  "id" uuid PRIMARY KEY DEFAULT (gen_random_uuid()),
+  "from_account_id" uuid NOT NULL,
+  "to_account_id" uuid NOT NULL,
+  "amount" float NOT NULL,
+  "requested_by" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "transfer_id" uuid,
+  "expires_at" timestamptz NOT NULL,
+  "decided_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "transfer_requests" ADD CONSTRAINT "transfer_requests_status_check" CHECK ("status" IN ('pending', 'executed', 'rejected', 'expired'));
+
+CREATE INDEX ON "transfer_requests" ("from_account_id", "status");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("requested_by") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_requests" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+COMMENT ON COLUMN "transfer_requests"."transfer_id" IS 'transfer made once the request was approved';
+
+CREATE TABLE "transfer_approvals" (
+  "id" uuid PRIMARY KEY DEFAULT (gen_random_uuid()),
+  "transfer_request_id" uuid NOT NULL,
+  "username" varchar NOT NULL,
+  "decision" varchar NOT NULL,
+  "note" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "transfer_approvals" ADD CONSTRAINT "transfer_approvals_decision_check" CHECK ("decision" IN ('approved', 'rejected'));
+
+CREATE UNIQUE INDEX ON "transfer_approvals" ("transfer_request_id", "username");
+
+ALTER TABLE "transfer_approvals" ADD FOREIGN KEY ("transfer_request_id") REFERENCES "transfer_requests" ("id");
+
+ALTER TABLE "transfer_approvals" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+-- +goose Down
+DROP TABLE IF EXISTS "transfer_approvals";
+DROP TABLE IF EXISTS "transfer_requests";
+
+ALTER TABLE IF EXISTS "accounts" DROP CONSTRAINT IF EXISTS "accounts_approval_threshold_check";
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "approval_threshold";
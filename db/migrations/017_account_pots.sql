@@ -0,0 +1,25 @@
+-- +goose Up
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "owner_currency_key";
+
+ALTER TABLE "accounts" ADD COLUMN "name" varchar;
+
+-- Existing accounts were unique per currency, so the currency is a unique name for them
+UPDATE "accounts" SET "name" = "currency";
+
+ALTER TABLE "accounts" ALTER COLUMN "name" SET NOT NULL;
+
+ALTER TABLE "accounts" ADD CONSTRAINT "owner_name_key" UNIQUE ("owner", "name");
+
+ALTER TABLE "accounts" ADD COLUMN "parent_id" uuid;
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("parent_id") REFERENCES "accounts" ("id");
+
+CREATE INDEX ON "accounts" ("parent_id");
+
+COMMENT ON COLUMN "accounts"."parent_id" IS 'account this pot belongs to, pots share the owner and currency of their parent';
+
+-- +goose Down
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "parent_id";
+ALTER TABLE IF EXISTS "accounts" DROP CONSTRAINT IF EXISTS "owner_name_key";
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "name";
+ALTER TABLE "accounts" ADD CONSTRAINT "owner_currency_key" UNIQUE ("owner", "currency");
@@ -0,0 +1,46 @@
+-- +goose Up
+CREATE TABLE "webhooks" (
+  "id" uuid PRIMARY KEY DEFAULT (gen_random_uuid()),
+  "username" varchar NOT NULL,
+  "url" varchar NOT NULL,
+  "secret" varchar NOT NULL,
+  "event_types" varchar[] NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "webhooks" ("username");
+
+ALTER TABLE "webhooks" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "webhooks"."secret" IS 'key the payloads are signed with, HMAC-SHA256';
+
+CREATE TABLE "webhook_deliveries" (
+  "id" uuid PRIMARY KEY DEFAULT (gen_random_uuid()),
+  "webhook_id" uuid NOT NULL,
+  "event_id" uuid NOT NULL,
+  "event_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "attempts" int NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "response_code" int,
+  "last_error" varchar,
+  "delivered_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "webhook_deliveries" ADD CONSTRAINT "webhook_deliveries_status_check" CHECK ("status" IN ('pending', 'delivered', 'failed'));
+
+CREATE INDEX ON "webhook_deliveries" ("next_attempt_at") WHERE "status" = 'pending';
+
+CREATE INDEX ON "webhook_deliveries" ("webhook_id", "created_at", "id");
+
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("webhook_id") REFERENCES "webhooks" ("id") ON DELETE CASCADE;
+
+COMMENT ON COLUMN "webhook_deliveries"."event_id" IS 'shared by the deliveries of the same event, receivers use it to drop duplicates';
+
+COMMENT ON COLUMN "webhook_deliveries"."next_attempt_at" IS 'when the worker picks the delivery up, pushed forward while an attempt is in flight and on every retry';
+
+-- +goose Down
+DROP TABLE IF EXISTS "webhook_deliveries";
+DROP TABLE IF EXISTS "webhooks";
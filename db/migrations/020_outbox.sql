@@ -0,0 +1,30 @@
+-- +goose Up
+CREATE TABLE "outbox" (
+  "id" bigserial PRIMARY KEY,
+  "event_id" uuid NOT NULL,
+  "event_type" varchar NOT NULL,
+  "aggregate_type" varchar NOT NULL,
+  "aggregate_id" uuid NOT NULL,
+  "payload" jsonb NOT NULL,
+  "transaction_id" bigint NOT NULL DEFAULT (pg_current_xact_id()::text::bigint),
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "outbox" ("event_id");
+
+CREATE INDEX ON "outbox" ("transaction_id", "id");
+
+COMMENT ON COLUMN "outbox"."transaction_id" IS 'transaction that appended the event, events are read in (transaction_id, id) order once no older transaction is running so none commits behind a reader';
+
+CREATE TABLE "outbox_offsets" (
+  "consumer" varchar PRIMARY KEY,
+  "transaction_id" bigint NOT NULL,
+  "outbox_id" bigint NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON TABLE "outbox_offsets" IS 'position of the last event each consumer published';
+
+-- +goose Down
+DROP TABLE IF EXISTS "outbox_offsets";
+DROP TABLE IF EXISTS "outbox";
@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX ON "outbox" ("aggregate_id", "transaction_id", "id");
+
+-- +goose Down
+DROP INDEX IF EXISTS "outbox_aggregate_id_transaction_id_id_idx";
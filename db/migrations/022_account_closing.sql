@@ -0,0 +1,12 @@
+-- +goose Up
+ALTER TABLE "accounts" ADD COLUMN "closed_at" timestamptz;
+
+COMMENT ON COLUMN "accounts"."closed_at" IS 'set when the owner deleted its user, closed accounts can no longer receive transfers';
+
+-- Accounts of users deleted before accounts could be closed
+UPDATE "accounts" SET "closed_at" = "users"."deleted_at"
+	FROM "users"
+	WHERE "users"."username" = "accounts"."owner" AND "users"."deleted_at" IS NOT NULL;
+
+-- +goose Down
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "closed_at";
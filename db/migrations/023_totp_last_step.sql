@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE "users" ADD COLUMN "totp_last_step" bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN "users"."totp_last_step" IS 'last TOTP time step accepted at login, codes from this step or earlier are rejected';
+
+-- +goose Down
+ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "totp_last_step";
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE "approval_threshold_changes" (
+  "id" uuid PRIMARY KEY DEFAULT (gen_random_uuid()),
+  "account_id" uuid NOT NULL,
+  "username" varchar NOT NULL,
+  "old_threshold" float NOT NULL,
+  "new_threshold" float NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "approval_threshold_changes" ("account_id", "created_at");
+
+ALTER TABLE "approval_threshold_changes" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "approval_threshold_changes" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+COMMENT ON TABLE "approval_threshold_changes" IS 'audit trail of the approval thresholds of accounts';
+
+-- +goose Down
+DROP TABLE IF EXISTS "approval_threshold_changes";
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE UNIQUE INDEX ON "webhook_deliveries" ("webhook_id", "event_id");
+
+CREATE INDEX ON "outbox" ("created_at");
+
+-- Deliveries used to be queued by the transfers themselves, the webhook fan-out starts after the events already there
+INSERT INTO "outbox_offsets" ("consumer", "transaction_id", "outbox_id")
+  SELECT 'webhooks', "transaction_id", "id" FROM "outbox" ORDER BY "transaction_id" DESC, "id" DESC LIMIT 1
+  ON CONFLICT ("consumer") DO NOTHING;
+
+-- +goose Down
+DELETE FROM "outbox_offsets" WHERE "consumer" = 'webhooks';
+DROP INDEX IF EXISTS "outbox_created_at_idx";
+DROP INDEX IF EXISTS "webhook_deliveries_webhook_id_event_id_idx";
@@ -0,0 +1,9 @@
+-- +goose Up
+-- The column held the wall clock of the application, which runs in UTC. Without a zone the comparison against token
+-- issue times shifted by the offset of whichever host wrote it.
+ALTER TABLE "users" ALTER COLUMN "password_changed_at" TYPE timestamptz USING "password_changed_at" AT TIME ZONE 'UTC';
+ALTER TABLE "users" ALTER COLUMN "password_changed_at" SET DEFAULT ('0001-01-01 00:00:00Z');
+
+-- +goose Down
+ALTER TABLE "users" ALTER COLUMN "password_changed_at" TYPE timestamp USING "password_changed_at" AT TIME ZONE 'UTC';
+ALTER TABLE "users" ALTER COLUMN "password_changed_at" SET DEFAULT ('0001-01-01 00:00:00');
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE "password_reset_requests" (
+  "id" bigserial PRIMARY KEY,
+  "email" varchar NOT NULL,
+  "client_ip" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "password_reset_requests" ("email", "created_at");
+
+CREATE INDEX ON "password_reset_requests" ("client_ip", "created_at");
+
+COMMENT ON TABLE "password_reset_requests" IS 'throttles password reset requests per email and client IP, recorded whether the email belongs to a user or not';
+
+-- +goose Down
+DROP TABLE IF EXISTS "password_reset_requests";
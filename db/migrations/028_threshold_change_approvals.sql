@@ -0,0 +1,29 @@
+-- +goose Up
+-- Lowering an approval threshold is proposed by one holder and only applied once another one approves it
+ALTER TABLE "approval_threshold_changes" ADD COLUMN "status" varchar NOT NULL DEFAULT 'applied';
+
+ALTER TABLE "approval_threshold_changes" ADD CONSTRAINT "approval_threshold_changes_status_check" CHECK ("status" IN ('pending', 'applied', 'rejected', 'expired'));
+
+ALTER TABLE "approval_threshold_changes" ADD COLUMN "decided_by" varchar;
+
+ALTER TABLE "approval_threshold_changes" ADD COLUMN "expires_at" timestamptz;
+
+ALTER TABLE "approval_threshold_changes" ADD COLUMN "decided_at" timestamptz;
+
+ALTER TABLE "approval_threshold_changes" ADD FOREIGN KEY ("decided_by") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "approval_threshold_changes"."decided_by" IS 'holder who approved or rejected a lowering, raises apply right away';
+
+COMMENT ON COLUMN "approval_threshold_changes"."expires_at" IS 'end of the approval window of a lowering';
+
+-- Transfer requests are listed with keyset pagination like the other lists
+CREATE INDEX "transfer_requests_from_account_id_created_at_id_idx" ON "transfer_requests" ("from_account_id", "created_at", "id");
+
+-- +goose Down
+DROP INDEX IF EXISTS "transfer_requests_from_account_id_created_at_id_idx";
+
+ALTER TABLE IF EXISTS "approval_threshold_changes" DROP COLUMN IF EXISTS "decided_at";
+ALTER TABLE IF EXISTS "approval_threshold_changes" DROP COLUMN IF EXISTS "expires_at";
+ALTER TABLE IF EXISTS "approval_threshold_changes" DROP COLUMN IF EXISTS "decided_by";
+ALTER TABLE IF EXISTS "approval_threshold_changes" DROP CONSTRAINT IF EXISTS "approval_threshold_changes_status_check";
+ALTER TABLE IF EXISTS "approval_threshold_changes" DROP COLUMN IF EXISTS "status";
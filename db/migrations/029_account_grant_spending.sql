@@ -0,0 +1,29 @@
+-- +goose Up
+ALTER TABLE "account_grants" ADD COLUMN "transferred" float NOT NULL DEFAULT 0;
+
+ALTER TABLE "account_grants" ADD CONSTRAINT "account_grants_transferred_check" CHECK ("transferred" >= 0 AND "transferred" <= "transfer_limit");
+
+COMMENT ON COLUMN "account_grants"."transfer_limit" IS 'total the grantee can transfer out over the life of the grant, 0 for view only access';
+
+COMMENT ON COLUMN "account_grants"."transferred" IS 'running total transferred out under the grant';
+
+-- Only the newest of several unrevoked grants to the same grantee was ever used, the older ones are revoked so a
+-- grantee holds at most one
+UPDATE "account_grants" AS "older" SET "revoked_at" = now()
+  WHERE "older"."revoked_at" IS NULL AND EXISTS (
+    SELECT 1 FROM "account_grants" AS "newer"
+      WHERE "newer"."account_id" = "older"."account_id"
+        AND "newer"."grantee" = "older"."grantee"
+        AND "newer"."revoked_at" IS NULL
+        AND ("newer"."created_at", "newer"."id") > ("older"."created_at", "older"."id")
+  );
+
+CREATE UNIQUE INDEX "account_grants_account_id_grantee_unrevoked_idx" ON "account_grants" ("account_id", "grantee")
+  WHERE "revoked_at" IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS "account_grants_account_id_grantee_unrevoked_idx";
+
+COMMENT ON COLUMN "account_grants"."transfer_limit" IS 'largest transfer the grantee can initiate, 0 for view only access';
+
+ALTER TABLE "account_grants" DROP COLUMN IF EXISTS "transferred";
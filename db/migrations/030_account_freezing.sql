@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE "accounts" ADD COLUMN "frozen_at" timestamptz;
+
+ALTER TABLE "accounts" ADD COLUMN "frozen_by" varchar;
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("frozen_by") REFERENCES "users" ("username");
+
+COMMENT ON COLUMN "accounts"."frozen_at" IS 'set while an admin holds the account, frozen accounts can neither send nor receive transfers';
+
+COMMENT ON COLUMN "accounts"."frozen_by" IS 'admin that froze the account';
+
+-- +goose Down
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "frozen_by";
+
+ALTER TABLE IF EXISTS "accounts" DROP COLUMN IF EXISTS "frozen_at";
@@ -7,6 +7,7 @@ package mock_db
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -36,6 +37,36 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AcceptAccountHolder mocks base method.
+func (m *MockStore) AcceptAccountHolder(arg0 context.Context, arg1 database.AcceptAccountHolderParams) (database.AccountHolder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptAccountHolder", arg0, arg1)
+	ret0, _ := ret[0].(database.AccountHolder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptAccountHolder indicates an expected call of AcceptAccountHolder.
+func (mr *MockStoreMockRecorder) AcceptAccountHolder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptAccountHolder", reflect.TypeOf((*MockStore)(nil).AcceptAccountHolder), arg0, arg1)
+}
+
+// AddAccountGrantTransferred mocks base method.
+func (m *MockStore) AddAccountGrantTransferred(arg0 context.Context, arg1 database.AddAccountGrantTransferredParams) (database.AccountGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAccountGrantTransferred", arg0, arg1)
+	ret0, _ := ret[0].(database.AccountGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAccountGrantTransferred indicates an expected call of AddAccountGrantTransferred.
+func (mr *MockStoreMockRecorder) AddAccountGrantTransferred(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountGrantTransferred", reflect.TypeOf((*MockStore)(nil).AddAccountGrantTransferred), arg0, arg1)
+}
+
 // AddToAccountBalance mocks base method.
 func (m *MockStore) AddToAccountBalance(arg0 context.Context, arg1 database.AddToAccountBalanceParams) (database.Account, error) {
 	m.ctrl.T.Helper()
@@ -51,6 +82,167 @@ func (mr *MockStoreMockRecorder) AddToAccountBalance(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToAccountBalance", reflect.TypeOf((*MockStore)(nil).AddToAccountBalance), arg0, arg1)
 }
 
+// AdvanceUserTOTPStep mocks base method.
+func (m *MockStore) AdvanceUserTOTPStep(arg0 context.Context, arg1 database.AdvanceUserTOTPStepParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceUserTOTPStep", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdvanceUserTOTPStep indicates an expected call of AdvanceUserTOTPStep.
+func (mr *MockStoreMockRecorder) AdvanceUserTOTPStep(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceUserTOTPStep", reflect.TypeOf((*MockStore)(nil).AdvanceUserTOTPStep), arg0, arg1)
+}
+
+// AnonymizeUser mocks base method.
+func (m *MockStore) AnonymizeUser(arg0 context.Context, arg1 database.AnonymizeUserParams) (database.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizeUser", arg0, arg1)
+	ret0, _ := ret[0].(database.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnonymizeUser indicates an expected call of AnonymizeUser.
+func (mr *MockStoreMockRecorder) AnonymizeUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeUser", reflect.TypeOf((*MockStore)(nil).AnonymizeUser), arg0, arg1)
+}
+
+// AnonymizeUserKYCSubmissions mocks base method.
+func (m *MockStore) AnonymizeUserKYCSubmissions(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizeUserKYCSubmissions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AnonymizeUserKYCSubmissions indicates an expected call of AnonymizeUserKYCSubmissions.
+func (mr *MockStoreMockRecorder) AnonymizeUserKYCSubmissions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeUserKYCSubmissions", reflect.TypeOf((*MockStore)(nil).AnonymizeUserKYCSubmissions), arg0, arg1)
+}
+
+// AnonymizeUserSessions mocks base method.
+func (m *MockStore) AnonymizeUserSessions(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizeUserSessions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AnonymizeUserSessions indicates an expected call of AnonymizeUserSessions.
+func (mr *MockStoreMockRecorder) AnonymizeUserSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeUserSessions", reflect.TypeOf((*MockStore)(nil).AnonymizeUserSessions), arg0, arg1)
+}
+
+// AppendOutboxEvent mocks base method.
+func (m *MockStore) AppendOutboxEvent(arg0 context.Context, arg1 database.AppendOutboxEventParams) (database.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendOutboxEvent", arg0, arg1)
+	ret0, _ := ret[0].(database.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AppendOutboxEvent indicates an expected call of AppendOutboxEvent.
+func (mr *MockStoreMockRecorder) AppendOutboxEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendOutboxEvent", reflect.TypeOf((*MockStore)(nil).AppendOutboxEvent), arg0, arg1)
+}
+
+// BlockUserSessions mocks base method.
+func (m *MockStore) BlockUserSessions(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockUserSessions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockUserSessions indicates an expected call of BlockUserSessions.
+func (mr *MockStoreMockRecorder) BlockUserSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUserSessions", reflect.TypeOf((*MockStore)(nil).BlockUserSessions), arg0, arg1)
+}
+
+// ChangePasswordTx mocks base method.
+func (m *MockStore) ChangePasswordTx(arg0 context.Context, arg1 database.UpdateUserPasswordParams) (database.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePasswordTx", arg0, arg1)
+	ret0, _ := ret[0].(database.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangePasswordTx indicates an expected call of ChangePasswordTx.
+func (mr *MockStoreMockRecorder) ChangePasswordTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePasswordTx", reflect.TypeOf((*MockStore)(nil).ChangePasswordTx), arg0, arg1)
+}
+
+// ClaimWebhookDeliveries mocks base method.
+func (m *MockStore) ClaimWebhookDeliveries(arg0 context.Context, arg1 database.ClaimWebhookDeliveriesParams) ([]database.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]database.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebhookDeliveries indicates an expected call of ClaimWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ClaimWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ClaimWebhookDeliveries), arg0, arg1)
+}
+
+// CloseOwnerAccounts mocks base method.
+func (m *MockStore) CloseOwnerAccounts(arg0 context.Context, arg1 database.CloseOwnerAccountsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseOwnerAccounts", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseOwnerAccounts indicates an expected call of CloseOwnerAccounts.
+func (mr *MockStoreMockRecorder) CloseOwnerAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseOwnerAccounts", reflect.TypeOf((*MockStore)(nil).CloseOwnerAccounts), arg0, arg1)
+}
+
+// ConsumeToken mocks base method.
+func (m *MockStore) ConsumeToken(arg0 context.Context, arg1 database.ConsumeTokenParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeToken", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeToken indicates an expected call of ConsumeToken.
+func (mr *MockStoreMockRecorder) ConsumeToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeToken", reflect.TypeOf((*MockStore)(nil).ConsumeToken), arg0, arg1)
+}
+
+// CountRecentEmailVerificationTokens mocks base method.
+func (m *MockStore) CountRecentEmailVerificationTokens(arg0 context.Context, arg1 database.CountRecentEmailVerificationTokensParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRecentEmailVerificationTokens", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRecentEmailVerificationTokens indicates an expected call of CountRecentEmailVerificationTokens.
+func (mr *MockStoreMockRecorder) CountRecentEmailVerificationTokens(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRecentEmailVerificationTokens", reflect.TypeOf((*MockStore)(nil).CountRecentEmailVerificationTokens), arg0, arg1)
+}
+
 // CreateAccount mocks base method.
 func (m *MockStore) CreateAccount(arg0 context.Context, arg1 database.CreateAccountParams) (database.Account, error) {
 	m.ctrl.T.Helper()
@@ -66,226 +258,1965 @@ func (mr *MockStoreMockRecorder) CreateAccount(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStore)(nil).CreateAccount), arg0, arg1)
 }
 
-// CreateEntry mocks base method.
-func (m *MockStore) CreateEntry(arg0 context.Context, arg1 database.CreateEntryParams) (database.Entry, error) {
+// CreateAccountGrant mocks base method.
+func (m *MockStore) CreateAccountGrant(arg0 context.Context, arg1 database.CreateAccountGrantParams) (database.AccountGrant, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateEntry", arg0, arg1)
-	ret0, _ := ret[0].(database.Entry)
+	ret := m.ctrl.Call(m, "CreateAccountGrant", arg0, arg1)
+	ret0, _ := ret[0].(database.AccountGrant)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateEntry indicates an expected call of CreateEntry.
-func (mr *MockStoreMockRecorder) CreateEntry(arg0, arg1 interface{}) *gomock.Call {
+// CreateAccountGrant indicates an expected call of CreateAccountGrant.
+func (mr *MockStoreMockRecorder) CreateAccountGrant(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockStore)(nil).CreateEntry), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountGrant", reflect.TypeOf((*MockStore)(nil).CreateAccountGrant), arg0, arg1)
 }
 
-// CreateSession mocks base method.
-func (m *MockStore) CreateSession(arg0 context.Context, arg1 database.CreateSessionParams) (database.Session, error) {
+// CreateAccountGrantTx mocks base method.
+func (m *MockStore) CreateAccountGrantTx(arg0 context.Context, arg1 database.CreateAccountGrantParams) (database.AccountGrant, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSession", arg0, arg1)
-	ret0, _ := ret[0].(database.Session)
+	ret := m.ctrl.Call(m, "CreateAccountGrantTx", arg0, arg1)
+	ret0, _ := ret[0].(database.AccountGrant)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateSession indicates an expected call of CreateSession.
-func (mr *MockStoreMockRecorder) CreateSession(arg0, arg1 interface{}) *gomock.Call {
+// CreateAccountGrantTx indicates an expected call of CreateAccountGrantTx.
+func (mr *MockStoreMockRecorder) CreateAccountGrantTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStore)(nil).CreateSession), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountGrantTx", reflect.TypeOf((*MockStore)(nil).CreateAccountGrantTx), arg0, arg1)
 }
 
-// CreateTransfer mocks base method.
-func (m *MockStore) CreateTransfer(arg0 context.Context, arg1 database.CreateTransferParams) (database.Transfer, error) {
+// CreateAccountHolder mocks base method.
+func (m *MockStore) CreateAccountHolder(arg0 context.Context, arg1 database.CreateAccountHolderParams) (database.AccountHolder, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTransfer", arg0, arg1)
-	ret0, _ := ret[0].(database.Transfer)
+	ret := m.ctrl.Call(m, "CreateAccountHolder", arg0, arg1)
+	ret0, _ := ret[0].(database.AccountHolder)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateTransfer indicates an expected call of CreateTransfer.
-func (mr *MockStoreMockRecorder) CreateTransfer(arg0, arg1 interface{}) *gomock.Call {
+// CreateAccountHolder indicates an expected call of CreateAccountHolder.
+func (mr *MockStoreMockRecorder) CreateAccountHolder(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockStore)(nil).CreateTransfer), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountHolder", reflect.TypeOf((*MockStore)(nil).CreateAccountHolder), arg0, arg1)
 }
 
-// CreateUser mocks base method.
-func (m *MockStore) CreateUser(arg0 context.Context, arg1 database.CreateUserParams) (database.User, error) {
+// CreateAccountTx mocks base method.
+func (m *MockStore) CreateAccountTx(arg0 context.Context, arg1 database.CreateAccountParams) (database.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
-	ret0, _ := ret[0].(database.User)
+	ret := m.ctrl.Call(m, "CreateAccountTx", arg0, arg1)
+	ret0, _ := ret[0].(database.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUser indicates an expected call of CreateUser.
-func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+// CreateAccountTx indicates an expected call of CreateAccountTx.
+func (mr *MockStoreMockRecorder) CreateAccountTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountTx", reflect.TypeOf((*MockStore)(nil).CreateAccountTx), arg0, arg1)
 }
 
-// DeleteAccount mocks base method.
-func (m *MockStore) DeleteAccount(arg0 context.Context, arg1 uuid.UUID) error {
+// CreateAccountWebhookDeliveries mocks base method.
+func (m *MockStore) CreateAccountWebhookDeliveries(arg0 context.Context, arg1 database.CreateAccountWebhookDeliveriesParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAccount", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "CreateAccountWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteAccount indicates an expected call of DeleteAccount.
-func (mr *MockStoreMockRecorder) DeleteAccount(arg0, arg1 interface{}) *gomock.Call {
+// CreateAccountWebhookDeliveries indicates an expected call of CreateAccountWebhookDeliveries.
+func (mr *MockStoreMockRecorder) CreateAccountWebhookDeliveries(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockStore)(nil).DeleteAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).CreateAccountWebhookDeliveries), arg0, arg1)
 }
 
-// GetAccount mocks base method.
-func (m *MockStore) GetAccount(arg0 context.Context, arg1 uuid.UUID) (database.Account, error) {
+// CreateApprovalThresholdChange mocks base method.
+func (m *MockStore) CreateApprovalThresholdChange(arg0 context.Context, arg1 database.CreateApprovalThresholdChangeParams) (database.ApprovalThresholdChange, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
-	ret0, _ := ret[0].(database.Account)
+	ret := m.ctrl.Call(m, "CreateApprovalThresholdChange", arg0, arg1)
+	ret0, _ := ret[0].(database.ApprovalThresholdChange)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccount indicates an expected call of GetAccount.
-func (mr *MockStoreMockRecorder) GetAccount(arg0, arg1 interface{}) *gomock.Call {
+// CreateApprovalThresholdChange indicates an expected call of CreateApprovalThresholdChange.
+func (mr *MockStoreMockRecorder) CreateApprovalThresholdChange(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockStore)(nil).GetAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApprovalThresholdChange", reflect.TypeOf((*MockStore)(nil).CreateApprovalThresholdChange), arg0, arg1)
 }
 
-// GetAccountEntries mocks base method.
-func (m *MockStore) GetAccountEntries(arg0 context.Context, arg1 database.GetAccountEntriesParams) ([]database.Entry, error) {
+// CreateEmailVerificationToken mocks base method.
+func (m *MockStore) CreateEmailVerificationToken(arg0 context.Context, arg1 database.CreateEmailVerificationTokenParams) (database.EmailVerificationToken, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountEntries", arg0, arg1)
-	ret0, _ := ret[0].([]database.Entry)
+	ret := m.ctrl.Call(m, "CreateEmailVerificationToken", arg0, arg1)
+	ret0, _ := ret[0].(database.EmailVerificationToken)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccountEntries indicates an expected call of GetAccountEntries.
-func (mr *MockStoreMockRecorder) GetAccountEntries(arg0, arg1 interface{}) *gomock.Call {
+// CreateEmailVerificationToken indicates an expected call of CreateEmailVerificationToken.
+func (mr *MockStoreMockRecorder) CreateEmailVerificationToken(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountEntries", reflect.TypeOf((*MockStore)(nil).GetAccountEntries), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEmailVerificationToken", reflect.TypeOf((*MockStore)(nil).CreateEmailVerificationToken), arg0, arg1)
 }
 
-// GetAccountForUpdate mocks base method.
-func (m *MockStore) GetAccountForUpdate(arg0 context.Context, arg1 uuid.UUID) (database.Account, error) {
+// CreateEntry mocks base method.
+func (m *MockStore) CreateEntry(arg0 context.Context, arg1 database.CreateEntryParams) (database.Entry, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountForUpdate", arg0, arg1)
-	ret0, _ := ret[0].(database.Account)
+	ret := m.ctrl.Call(m, "CreateEntry", arg0, arg1)
+	ret0, _ := ret[0].(database.Entry)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccountForUpdate indicates an expected call of GetAccountForUpdate.
-func (mr *MockStoreMockRecorder) GetAccountForUpdate(arg0, arg1 interface{}) *gomock.Call {
+// CreateEntry indicates an expected call of CreateEntry.
+func (mr *MockStoreMockRecorder) CreateEntry(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountForUpdate", reflect.TypeOf((*MockStore)(nil).GetAccountForUpdate), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockStore)(nil).CreateEntry), arg0, arg1)
 }
 
-// GetAccountsList mocks base method.
-func (m *MockStore) GetAccountsList(arg0 context.Context, arg1 database.GetAccountsListParams) ([]database.Account, error) {
+// CreateKYCSubmission mocks base method.
+func (m *MockStore) CreateKYCSubmission(arg0 context.Context, arg1 database.CreateKYCSubmissionParams) (database.KycSubmission, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountsList", arg0, arg1)
-	ret0, _ := ret[0].([]database.Account)
+	ret := m.ctrl.Call(m, "CreateKYCSubmission", arg0, arg1)
+	ret0, _ := ret[0].(database.KycSubmission)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccountsList indicates an expected call of GetAccountsList.
-func (mr *MockStoreMockRecorder) GetAccountsList(arg0, arg1 interface{}) *gomock.Call {
+// CreateKYCSubmission indicates an expected call of CreateKYCSubmission.
+func (mr *MockStoreMockRecorder) CreateKYCSubmission(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountsList", reflect.TypeOf((*MockStore)(nil).GetAccountsList), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateKYCSubmission", reflect.TypeOf((*MockStore)(nil).CreateKYCSubmission), arg0, arg1)
 }
 
-// GetEntry mocks base method.
-func (m *MockStore) GetEntry(arg0 context.Context, arg1 uuid.UUID) (database.Entry, error) {
+// CreateLoginAttempt mocks base method.
+func (m *MockStore) CreateLoginAttempt(arg0 context.Context, arg1 database.CreateLoginAttemptParams) (database.LoginAttempt, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEntry", arg0, arg1)
-	ret0, _ := ret[0].(database.Entry)
+	ret := m.ctrl.Call(m, "CreateLoginAttempt", arg0, arg1)
+	ret0, _ := ret[0].(database.LoginAttempt)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEntry indicates an expected call of GetEntry.
-func (mr *MockStoreMockRecorder) GetEntry(arg0, arg1 interface{}) *gomock.Call {
+// CreateLoginAttempt indicates an expected call of CreateLoginAttempt.
+func (mr *MockStoreMockRecorder) CreateLoginAttempt(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoginAttempt", reflect.TypeOf((*MockStore)(nil).CreateLoginAttempt), arg0, arg1)
 }
 
-// GetSession mocks base method.
-func (m *MockStore) GetSession(arg0 context.Context, arg1 uuid.UUID) (database.Session, error) {
+// CreatePasswordResetRequest mocks base method.
+func (m *MockStore) CreatePasswordResetRequest(arg0 context.Context, arg1 database.CreatePasswordResetRequestParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSession", arg0, arg1)
+	ret := m.ctrl.Call(m, "CreatePasswordResetRequest", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePasswordResetRequest indicates an expected call of CreatePasswordResetRequest.
+func (mr *MockStoreMockRecorder) CreatePasswordResetRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasswordResetRequest", reflect.TypeOf((*MockStore)(nil).CreatePasswordResetRequest), arg0, arg1)
+}
+
+// CreatePasswordResetToken mocks base method.
+func (m *MockStore) CreatePasswordResetToken(arg0 context.Context, arg1 database.CreatePasswordResetTokenParams) (database.PasswordResetToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePasswordResetToken", arg0, arg1)
+	ret0, _ := ret[0].(database.PasswordResetToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePasswordResetToken indicates an expected call of CreatePasswordResetToken.
+func (mr *MockStoreMockRecorder) CreatePasswordResetToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasswordResetToken", reflect.TypeOf((*MockStore)(nil).CreatePasswordResetToken), arg0, arg1)
+}
+
+// CreateRecoveryCode mocks base method.
+func (m *MockStore) CreateRecoveryCode(arg0 context.Context, arg1 database.CreateRecoveryCodeParams) (database.RecoveryCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecoveryCode", arg0, arg1)
+	ret0, _ := ret[0].(database.RecoveryCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRecoveryCode indicates an expected call of CreateRecoveryCode.
+func (mr *MockStoreMockRecorder) CreateRecoveryCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecoveryCode", reflect.TypeOf((*MockStore)(nil).CreateRecoveryCode), arg0, arg1)
+}
+
+// CreateSession mocks base method.
+func (m *MockStore) CreateSession(arg0 context.Context, arg1 database.CreateSessionParams) (database.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", arg0, arg1)
 	ret0, _ := ret[0].(database.Session)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSession indicates an expected call of GetSession.
-func (mr *MockStoreMockRecorder) GetSession(arg0, arg1 interface{}) *gomock.Call {
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockStoreMockRecorder) CreateSession(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStore)(nil).CreateSession), arg0, arg1)
 }
 
-// GetTransfer mocks base method.
-func (m *MockStore) GetTransfer(arg0 context.Context, arg1 uuid.UUID) (database.Transfer, error) {
+// CreateSessionTx mocks base method.
+func (m *MockStore) CreateSessionTx(arg0 context.Context, arg1 database.CreateSessionParams) (database.Session, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
+	ret := m.ctrl.Call(m, "CreateSessionTx", arg0, arg1)
+	ret0, _ := ret[0].(database.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSessionTx indicates an expected call of CreateSessionTx.
+func (mr *MockStoreMockRecorder) CreateSessionTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSessionTx", reflect.TypeOf((*MockStore)(nil).CreateSessionTx), arg0, arg1)
+}
+
+// CreateTransfer mocks base method.
+func (m *MockStore) CreateTransfer(arg0 context.Context, arg1 database.CreateTransferParams) (database.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransfer", arg0, arg1)
 	ret0, _ := ret[0].(database.Transfer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTransfer indicates an expected call of GetTransfer.
-func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 interface{}) *gomock.Call {
+// CreateTransfer indicates an expected call of CreateTransfer.
+func (mr *MockStoreMockRecorder) CreateTransfer(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockStore)(nil).CreateTransfer), arg0, arg1)
 }
 
-// GetUser mocks base method.
-func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (database.User, error) {
+// CreateTransferApproval mocks base method.
+func (m *MockStore) CreateTransferApproval(arg0 context.Context, arg1 database.CreateTransferApprovalParams) (database.TransferApproval, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
-	ret0, _ := ret[0].(database.User)
+	ret := m.ctrl.Call(m, "CreateTransferApproval", arg0, arg1)
+	ret0, _ := ret[0].(database.TransferApproval)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUser indicates an expected call of GetUser.
-func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+// CreateTransferApproval indicates an expected call of CreateTransferApproval.
+func (mr *MockStoreMockRecorder) CreateTransferApproval(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransferApproval", reflect.TypeOf((*MockStore)(nil).CreateTransferApproval), arg0, arg1)
 }
 
-// TransferTx mocks base method.
-func (m *MockStore) TransferTx(arg0 context.Context, arg1 database.TransferTxParams) (database.TransferTxResult, error) {
+// CreateTransferRequest mocks base method.
+func (m *MockStore) CreateTransferRequest(arg0 context.Context, arg1 database.CreateTransferRequestParams) (database.TransferRequest, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TransferTx", arg0, arg1)
-	ret0, _ := ret[0].(database.TransferTxResult)
+	ret := m.ctrl.Call(m, "CreateTransferRequest", arg0, arg1)
+	ret0, _ := ret[0].(database.TransferRequest)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// TransferTx indicates an expected call of TransferTx.
-func (mr *MockStoreMockRecorder) TransferTx(arg0, arg1 interface{}) *gomock.Call {
+// CreateTransferRequest indicates an expected call of CreateTransferRequest.
+func (mr *MockStoreMockRecorder) CreateTransferRequest(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferTx", reflect.TypeOf((*MockStore)(nil).TransferTx), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransferRequest", reflect.TypeOf((*MockStore)(nil).CreateTransferRequest), arg0, arg1)
 }
 
-// UpdateAccountBalance mocks base method.
-func (m *MockStore) UpdateAccountBalance(arg0 context.Context, arg1 database.UpdateAccountBalanceParams) (database.Account, error) {
+// CreateTransferRequestTx mocks base method.
+func (m *MockStore) CreateTransferRequestTx(arg0 context.Context, arg1 database.CreateTransferRequestTxParams) (database.TransferRequest, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAccountBalance", arg0, arg1)
-	ret0, _ := ret[0].(database.Account)
+	ret := m.ctrl.Call(m, "CreateTransferRequestTx", arg0, arg1)
+	ret0, _ := ret[0].(database.TransferRequest)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateAccountBalance indicates an expected call of UpdateAccountBalance.
-func (mr *MockStoreMockRecorder) UpdateAccountBalance(arg0, arg1 interface{}) *gomock.Call {
+// CreateTransferRequestTx indicates an expected call of CreateTransferRequestTx.
+func (mr *MockStoreMockRecorder) CreateTransferRequestTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountBalance", reflect.TypeOf((*MockStore)(nil).UpdateAccountBalance), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransferRequestTx", reflect.TypeOf((*MockStore)(nil).CreateTransferRequestTx), arg0, arg1)
+}
+
+// CreateUser mocks base method.
+func (m *MockStore) CreateUser(arg0 context.Context, arg1 database.CreateUserParams) (database.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret0, _ := ret[0].(database.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockStore) CreateWebhook(arg0 context.Context, arg1 database.CreateWebhookParams) (database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", arg0, arg1)
+	ret0, _ := ret[0].(database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockStoreMockRecorder) CreateWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockStore)(nil).CreateWebhook), arg0, arg1)
+}
+
+// DecideApprovalThresholdChange mocks base method.
+func (m *MockStore) DecideApprovalThresholdChange(arg0 context.Context, arg1 database.DecideApprovalThresholdChangeParams) (database.ApprovalThresholdChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideApprovalThresholdChange", arg0, arg1)
+	ret0, _ := ret[0].(database.ApprovalThresholdChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideApprovalThresholdChange indicates an expected call of DecideApprovalThresholdChange.
+func (mr *MockStoreMockRecorder) DecideApprovalThresholdChange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideApprovalThresholdChange", reflect.TypeOf((*MockStore)(nil).DecideApprovalThresholdChange), arg0, arg1)
+}
+
+// DecideThresholdChangeTx mocks base method.
+func (m *MockStore) DecideThresholdChangeTx(arg0 context.Context, arg1 database.DecideThresholdChangeTxParams) (database.DecideThresholdChangeTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideThresholdChangeTx", arg0, arg1)
+	ret0, _ := ret[0].(database.DecideThresholdChangeTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideThresholdChangeTx indicates an expected call of DecideThresholdChangeTx.
+func (mr *MockStoreMockRecorder) DecideThresholdChangeTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideThresholdChangeTx", reflect.TypeOf((*MockStore)(nil).DecideThresholdChangeTx), arg0, arg1)
+}
+
+// DecideTransferRequest mocks base method.
+func (m *MockStore) DecideTransferRequest(arg0 context.Context, arg1 database.DecideTransferRequestParams) (database.TransferRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideTransferRequest", arg0, arg1)
+	ret0, _ := ret[0].(database.TransferRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideTransferRequest indicates an expected call of DecideTransferRequest.
+func (mr *MockStoreMockRecorder) DecideTransferRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideTransferRequest", reflect.TypeOf((*MockStore)(nil).DecideTransferRequest), arg0, arg1)
+}
+
+// DecideTransferTx mocks base method.
+func (m *MockStore) DecideTransferTx(arg0 context.Context, arg1 database.DecideTransferTxParams) (database.DecideTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(database.DecideTransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideTransferTx indicates an expected call of DecideTransferTx.
+func (mr *MockStoreMockRecorder) DecideTransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideTransferTx", reflect.TypeOf((*MockStore)(nil).DecideTransferTx), arg0, arg1)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockStore) DeleteAccount(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockStoreMockRecorder) DeleteAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockStore)(nil).DeleteAccount), arg0, arg1)
+}
+
+// DeleteAccountHolder mocks base method.
+func (m *MockStore) DeleteAccountHolder(arg0 context.Context, arg1 database.DeleteAccountHolderParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccountHolder", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAccountHolder indicates an expected call of DeleteAccountHolder.
+func (mr *MockStoreMockRecorder) DeleteAccountHolder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccountHolder", reflect.TypeOf((*MockStore)(nil).DeleteAccountHolder), arg0, arg1)
+}
+
+// DeleteConsumedOutboxEvents mocks base method.
+func (m *MockStore) DeleteConsumedOutboxEvents(arg0 context.Context, arg1 database.DeleteConsumedOutboxEventsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteConsumedOutboxEvents", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteConsumedOutboxEvents indicates an expected call of DeleteConsumedOutboxEvents.
+func (mr *MockStoreMockRecorder) DeleteConsumedOutboxEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConsumedOutboxEvents", reflect.TypeOf((*MockStore)(nil).DeleteConsumedOutboxEvents), arg0, arg1)
+}
+
+// DeleteExpiredConsumedTokens mocks base method.
+func (m *MockStore) DeleteExpiredConsumedTokens(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredConsumedTokens", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredConsumedTokens indicates an expected call of DeleteExpiredConsumedTokens.
+func (mr *MockStoreMockRecorder) DeleteExpiredConsumedTokens(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredConsumedTokens", reflect.TypeOf((*MockStore)(nil).DeleteExpiredConsumedTokens), arg0)
+}
+
+// DeleteUserAccountHolders mocks base method.
+func (m *MockStore) DeleteUserAccountHolders(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserAccountHolders", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserAccountHolders indicates an expected call of DeleteUserAccountHolders.
+func (mr *MockStoreMockRecorder) DeleteUserAccountHolders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserAccountHolders", reflect.TypeOf((*MockStore)(nil).DeleteUserAccountHolders), arg0, arg1)
+}
+
+// DeleteUserEmailVerificationTokens mocks base method.
+func (m *MockStore) DeleteUserEmailVerificationTokens(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserEmailVerificationTokens", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserEmailVerificationTokens indicates an expected call of DeleteUserEmailVerificationTokens.
+func (mr *MockStoreMockRecorder) DeleteUserEmailVerificationTokens(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserEmailVerificationTokens", reflect.TypeOf((*MockStore)(nil).DeleteUserEmailVerificationTokens), arg0, arg1)
+}
+
+// DeleteUserLoginAttempts mocks base method.
+func (m *MockStore) DeleteUserLoginAttempts(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserLoginAttempts", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserLoginAttempts indicates an expected call of DeleteUserLoginAttempts.
+func (mr *MockStoreMockRecorder) DeleteUserLoginAttempts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserLoginAttempts", reflect.TypeOf((*MockStore)(nil).DeleteUserLoginAttempts), arg0, arg1)
+}
+
+// DeleteUserPasswordResetRequests mocks base method.
+func (m *MockStore) DeleteUserPasswordResetRequests(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserPasswordResetRequests", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserPasswordResetRequests indicates an expected call of DeleteUserPasswordResetRequests.
+func (mr *MockStoreMockRecorder) DeleteUserPasswordResetRequests(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserPasswordResetRequests", reflect.TypeOf((*MockStore)(nil).DeleteUserPasswordResetRequests), arg0, arg1)
+}
+
+// DeleteUserPasswordResetTokens mocks base method.
+func (m *MockStore) DeleteUserPasswordResetTokens(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserPasswordResetTokens", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserPasswordResetTokens indicates an expected call of DeleteUserPasswordResetTokens.
+func (mr *MockStoreMockRecorder) DeleteUserPasswordResetTokens(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserPasswordResetTokens", reflect.TypeOf((*MockStore)(nil).DeleteUserPasswordResetTokens), arg0, arg1)
+}
+
+// DeleteUserRecoveryCodes mocks base method.
+func (m *MockStore) DeleteUserRecoveryCodes(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserRecoveryCodes", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserRecoveryCodes indicates an expected call of DeleteUserRecoveryCodes.
+func (mr *MockStoreMockRecorder) DeleteUserRecoveryCodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserRecoveryCodes", reflect.TypeOf((*MockStore)(nil).DeleteUserRecoveryCodes), arg0, arg1)
+}
+
+// DeleteUserTx mocks base method.
+func (m *MockStore) DeleteUserTx(arg0 context.Context, arg1 database.DeleteUserTxParams) (database.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserTx", arg0, arg1)
+	ret0, _ := ret[0].(database.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUserTx indicates an expected call of DeleteUserTx.
+func (mr *MockStoreMockRecorder) DeleteUserTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserTx", reflect.TypeOf((*MockStore)(nil).DeleteUserTx), arg0, arg1)
+}
+
+// DeleteUserWebhooks mocks base method.
+func (m *MockStore) DeleteUserWebhooks(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserWebhooks", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserWebhooks indicates an expected call of DeleteUserWebhooks.
+func (mr *MockStoreMockRecorder) DeleteUserWebhooks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserWebhooks", reflect.TypeOf((*MockStore)(nil).DeleteUserWebhooks), arg0, arg1)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockStore) DeleteWebhook(arg0 context.Context, arg1 database.DeleteWebhookParams) (database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", arg0, arg1)
+	ret0, _ := ret[0].(database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockStoreMockRecorder) DeleteWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockStore)(nil).DeleteWebhook), arg0, arg1)
+}
+
+// EnableTOTPTx mocks base method.
+func (m *MockStore) EnableTOTPTx(arg0 context.Context, arg1 database.EnableTOTPTxParams) (database.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableTOTPTx", arg0, arg1)
+	ret0, _ := ret[0].(database.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableTOTPTx indicates an expected call of EnableTOTPTx.
+func (mr *MockStoreMockRecorder) EnableTOTPTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableTOTPTx", reflect.TypeOf((*MockStore)(nil).EnableTOTPTx), arg0, arg1)
+}
+
+// EnableUserTOTP mocks base method.
+func (m *MockStore) EnableUserTOTP(arg0 context.Context, arg1 string) (database.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableUserTOTP", arg0, arg1)
+	ret0, _ := ret[0].(database.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableUserTOTP indicates an expected call of EnableUserTOTP.
+func (mr *MockStoreMockRecorder) EnableUserTOTP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableUserTOTP", reflect.TypeOf((*MockStore)(nil).EnableUserTOTP), arg0, arg1)
+}
+
+// ExpireApprovalThresholdChanges mocks base method.
+func (m *MockStore) ExpireApprovalThresholdChanges(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireApprovalThresholdChanges", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireApprovalThresholdChanges indicates an expected call of ExpireApprovalThresholdChanges.
+func (mr *MockStoreMockRecorder) ExpireApprovalThresholdChanges(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireApprovalThresholdChanges", reflect.TypeOf((*MockStore)(nil).ExpireApprovalThresholdChanges), arg0)
+}
+
+// ExpireTransferRequests mocks base method.
+func (m *MockStore) ExpireTransferRequests(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireTransferRequests", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireTransferRequests indicates an expected call of ExpireTransferRequests.
+func (mr *MockStoreMockRecorder) ExpireTransferRequests(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireTransferRequests", reflect.TypeOf((*MockStore)(nil).ExpireTransferRequests), arg0)
+}
+
+// FreezeAccount mocks base method.
+func (m *MockStore) FreezeAccount(arg0 context.Context, arg1 database.FreezeAccountParams) (database.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FreezeAccount", arg0, arg1)
+	ret0, _ := ret[0].(database.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FreezeAccount indicates an expected call of FreezeAccount.
+func (mr *MockStoreMockRecorder) FreezeAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreezeAccount", reflect.TypeOf((*MockStore)(nil).FreezeAccount), arg0, arg1)
+}
+
+// GetAccount mocks base method.
+func (m *MockStore) GetAccount(arg0 context.Context, arg1 uuid.UUID) (database.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
+	ret0, _ := ret[0].(database.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockStoreMockRecorder) GetAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockStore)(nil).GetAccount), arg0, arg1)
+}
+
+// GetAccountForUpdate mocks base method.
+func (m *MockStore) GetAccountForUpdate(arg0 context.Context, arg1 uuid.UUID) (database.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(database.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountForUpdate indicates an expected call of GetAccountForUpdate.
+func (mr *MockStoreMockRecorder) GetAccountForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountForUpdate", reflect.TypeOf((*MockStore)(nil).GetAccountForUpdate), arg0, arg1)
+}
+
+// GetAccountGrantForUpdate mocks base method.
+func (m *MockStore) GetAccountGrantForUpdate(arg0 context.Context, arg1 uuid.UUID) (database.AccountGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountGrantForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(database.AccountGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountGrantForUpdate indicates an expected call of GetAccountGrantForUpdate.
+func (mr *MockStoreMockRecorder) GetAccountGrantForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountGrantForUpdate", reflect.TypeOf((*MockStore)(nil).GetAccountGrantForUpdate), arg0, arg1)
+}
+
+// GetAccountHolder mocks base method.
+func (m *MockStore) GetAccountHolder(arg0 context.Context, arg1 database.GetAccountHolderParams) (database.AccountHolder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountHolder", arg0, arg1)
+	ret0, _ := ret[0].(database.AccountHolder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountHolder indicates an expected call of GetAccountHolder.
+func (mr *MockStoreMockRecorder) GetAccountHolder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountHolder", reflect.TypeOf((*MockStore)(nil).GetAccountHolder), arg0, arg1)
+}
+
+// GetActiveAccountGrant mocks base method.
+func (m *MockStore) GetActiveAccountGrant(arg0 context.Context, arg1 database.GetActiveAccountGrantParams) (database.AccountGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveAccountGrant", arg0, arg1)
+	ret0, _ := ret[0].(database.AccountGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveAccountGrant indicates an expected call of GetActiveAccountGrant.
+func (mr *MockStoreMockRecorder) GetActiveAccountGrant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveAccountGrant", reflect.TypeOf((*MockStore)(nil).GetActiveAccountGrant), arg0, arg1)
+}
+
+// GetApprovalThresholdChange mocks base method.
+func (m *MockStore) GetApprovalThresholdChange(arg0 context.Context, arg1 uuid.UUID) (database.ApprovalThresholdChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApprovalThresholdChange", arg0, arg1)
+	ret0, _ := ret[0].(database.ApprovalThresholdChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApprovalThresholdChange indicates an expected call of GetApprovalThresholdChange.
+func (mr *MockStoreMockRecorder) GetApprovalThresholdChange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApprovalThresholdChange", reflect.TypeOf((*MockStore)(nil).GetApprovalThresholdChange), arg0, arg1)
+}
+
+// GetApprovalThresholdChangeForUpdate mocks base method.
+func (m *MockStore) GetApprovalThresholdChangeForUpdate(arg0 context.Context, arg1 uuid.UUID) (database.ApprovalThresholdChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApprovalThresholdChangeForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(database.ApprovalThresholdChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApprovalThresholdChangeForUpdate indicates an expected call of GetApprovalThresholdChangeForUpdate.
+func (mr *MockStoreMockRecorder) GetApprovalThresholdChangeForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApprovalThresholdChangeForUpdate", reflect.TypeOf((*MockStore)(nil).GetApprovalThresholdChangeForUpdate), arg0, arg1)
+}
+
+// GetClientIPLoginFailures mocks base method.
+func (m *MockStore) GetClientIPLoginFailures(arg0 context.Context, arg1 database.GetClientIPLoginFailuresParams) (database.GetClientIPLoginFailuresRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClientIPLoginFailures", arg0, arg1)
+	ret0, _ := ret[0].(database.GetClientIPLoginFailuresRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClientIPLoginFailures indicates an expected call of GetClientIPLoginFailures.
+func (mr *MockStoreMockRecorder) GetClientIPLoginFailures(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClientIPLoginFailures", reflect.TypeOf((*MockStore)(nil).GetClientIPLoginFailures), arg0, arg1)
+}
+
+// GetClientIPPasswordResetRequests mocks base method.
+func (m *MockStore) GetClientIPPasswordResetRequests(arg0 context.Context, arg1 database.GetClientIPPasswordResetRequestsParams) (database.GetClientIPPasswordResetRequestsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClientIPPasswordResetRequests", arg0, arg1)
+	ret0, _ := ret[0].(database.GetClientIPPasswordResetRequestsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClientIPPasswordResetRequests indicates an expected call of GetClientIPPasswordResetRequests.
+func (mr *MockStoreMockRecorder) GetClientIPPasswordResetRequests(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClientIPPasswordResetRequests", reflect.TypeOf((*MockStore)(nil).GetClientIPPasswordResetRequests), arg0, arg1)
+}
+
+// GetEmailPasswordResetRequests mocks base method.
+func (m *MockStore) GetEmailPasswordResetRequests(arg0 context.Context, arg1 database.GetEmailPasswordResetRequestsParams) (database.GetEmailPasswordResetRequestsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmailPasswordResetRequests", arg0, arg1)
+	ret0, _ := ret[0].(database.GetEmailPasswordResetRequestsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEmailPasswordResetRequests indicates an expected call of GetEmailPasswordResetRequests.
+func (mr *MockStoreMockRecorder) GetEmailPasswordResetRequests(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmailPasswordResetRequests", reflect.TypeOf((*MockStore)(nil).GetEmailPasswordResetRequests), arg0, arg1)
+}
+
+// GetEmailVerificationToken mocks base method.
+func (m *MockStore) GetEmailVerificationToken(arg0 context.Context, arg1 string) (database.EmailVerificationToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmailVerificationToken", arg0, arg1)
+	ret0, _ := ret[0].(database.EmailVerificationToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEmailVerificationToken indicates an expected call of GetEmailVerificationToken.
+func (mr *MockStoreMockRecorder) GetEmailVerificationToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmailVerificationToken", reflect.TypeOf((*MockStore)(nil).GetEmailVerificationToken), arg0, arg1)
+}
+
+// GetEntry mocks base method.
+func (m *MockStore) GetEntry(arg0 context.Context, arg1 uuid.UUID) (database.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntry", arg0, arg1)
+	ret0, _ := ret[0].(database.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntry indicates an expected call of GetEntry.
+func (mr *MockStoreMockRecorder) GetEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), arg0, arg1)
+}
+
+// GetKYCSubmission mocks base method.
+func (m *MockStore) GetKYCSubmission(arg0 context.Context, arg1 uuid.UUID) (database.KycSubmission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKYCSubmission", arg0, arg1)
+	ret0, _ := ret[0].(database.KycSubmission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKYCSubmission indicates an expected call of GetKYCSubmission.
+func (mr *MockStoreMockRecorder) GetKYCSubmission(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKYCSubmission", reflect.TypeOf((*MockStore)(nil).GetKYCSubmission), arg0, arg1)
+}
+
+// GetOutboxHorizon mocks base method.
+func (m *MockStore) GetOutboxHorizon(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutboxHorizon", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutboxHorizon indicates an expected call of GetOutboxHorizon.
+func (mr *MockStoreMockRecorder) GetOutboxHorizon(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutboxHorizon", reflect.TypeOf((*MockStore)(nil).GetOutboxHorizon), arg0)
+}
+
+// GetOutboxOffset mocks base method.
+func (m *MockStore) GetOutboxOffset(arg0 context.Context, arg1 string) (database.OutboxOffset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutboxOffset", arg0, arg1)
+	ret0, _ := ret[0].(database.OutboxOffset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutboxOffset indicates an expected call of GetOutboxOffset.
+func (mr *MockStoreMockRecorder) GetOutboxOffset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutboxOffset", reflect.TypeOf((*MockStore)(nil).GetOutboxOffset), arg0, arg1)
+}
+
+// GetPasswordResetToken mocks base method.
+func (m *MockStore) GetPasswordResetToken(arg0 context.Context, arg1 string) (database.PasswordResetToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPasswordResetToken", arg0, arg1)
+	ret0, _ := ret[0].(database.PasswordResetToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPasswordResetToken indicates an expected call of GetPasswordResetToken.
+func (mr *MockStoreMockRecorder) GetPasswordResetToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasswordResetToken", reflect.TypeOf((*MockStore)(nil).GetPasswordResetToken), arg0, arg1)
+}
+
+// GetSession mocks base method.
+func (m *MockStore) GetSession(arg0 context.Context, arg1 uuid.UUID) (database.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", arg0, arg1)
+	ret0, _ := ret[0].(database.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockStoreMockRecorder) GetSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), arg0, arg1)
+}
+
+// GetTransfer mocks base method.
+func (m *MockStore) GetTransfer(arg0 context.Context, arg1 uuid.UUID) (database.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
+	ret0, _ := ret[0].(database.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransfer indicates an expected call of GetTransfer.
+func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
+}
+
+// GetTransferRequest mocks base method.
+func (m *MockStore) GetTransferRequest(arg0 context.Context, arg1 uuid.UUID) (database.TransferRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferRequest", arg0, arg1)
+	ret0, _ := ret[0].(database.TransferRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferRequest indicates an expected call of GetTransferRequest.
+func (mr *MockStoreMockRecorder) GetTransferRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferRequest", reflect.TypeOf((*MockStore)(nil).GetTransferRequest), arg0, arg1)
+}
+
+// GetTransferRequestForUpdate mocks base method.
+func (m *MockStore) GetTransferRequestForUpdate(arg0 context.Context, arg1 uuid.UUID) (database.TransferRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferRequestForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(database.TransferRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferRequestForUpdate indicates an expected call of GetTransferRequestForUpdate.
+func (mr *MockStoreMockRecorder) GetTransferRequestForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferRequestForUpdate", reflect.TypeOf((*MockStore)(nil).GetTransferRequestForUpdate), arg0, arg1)
+}
+
+// GetUnusedRecoveryCodes mocks base method.
+func (m *MockStore) GetUnusedRecoveryCodes(arg0 context.Context, arg1 string) ([]database.RecoveryCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnusedRecoveryCodes", arg0, arg1)
+	ret0, _ := ret[0].([]database.RecoveryCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnusedRecoveryCodes indicates an expected call of GetUnusedRecoveryCodes.
+func (mr *MockStoreMockRecorder) GetUnusedRecoveryCodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnusedRecoveryCodes", reflect.TypeOf((*MockStore)(nil).GetUnusedRecoveryCodes), arg0, arg1)
+}
+
+// GetUser mocks base method.
+func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (database.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
+	ret0, _ := ret[0].(database.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+}
+
+// GetUserByEmail mocks base method.
+func (m *MockStore) GetUserByEmail(arg0 context.Context, arg1 string) (database.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", arg0, arg1)
+	ret0, _ := ret[0].(database.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockStoreMockRecorder) GetUserByEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockStore)(nil).GetUserByEmail), arg0, arg1)
+}
+
+// GetUserPasswordChangedAt mocks base method.
+func (m *MockStore) GetUserPasswordChangedAt(arg0 context.Context, arg1 string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPasswordChangedAt", arg0, arg1)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserPasswordChangedAt indicates an expected call of GetUserPasswordChangedAt.
+func (mr *MockStoreMockRecorder) GetUserPasswordChangedAt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPasswordChangedAt", reflect.TypeOf((*MockStore)(nil).GetUserPasswordChangedAt), arg0, arg1)
+}
+
+// GetUserRole mocks base method.
+func (m *MockStore) GetUserRole(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRole", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRole indicates an expected call of GetUserRole.
+func (mr *MockStoreMockRecorder) GetUserRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRole", reflect.TypeOf((*MockStore)(nil).GetUserRole), arg0, arg1)
+}
+
+// GetUserWebhook mocks base method.
+func (m *MockStore) GetUserWebhook(arg0 context.Context, arg1 database.GetUserWebhookParams) (database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserWebhook", arg0, arg1)
+	ret0, _ := ret[0].(database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserWebhook indicates an expected call of GetUserWebhook.
+func (mr *MockStoreMockRecorder) GetUserWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserWebhook", reflect.TypeOf((*MockStore)(nil).GetUserWebhook), arg0, arg1)
+}
+
+// GetUsernameLoginFailures mocks base method.
+func (m *MockStore) GetUsernameLoginFailures(arg0 context.Context, arg1 database.GetUsernameLoginFailuresParams) (database.GetUsernameLoginFailuresRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsernameLoginFailures", arg0, arg1)
+	ret0, _ := ret[0].(database.GetUsernameLoginFailuresRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsernameLoginFailures indicates an expected call of GetUsernameLoginFailures.
+func (mr *MockStoreMockRecorder) GetUsernameLoginFailures(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsernameLoginFailures", reflect.TypeOf((*MockStore)(nil).GetUsernameLoginFailures), arg0, arg1)
+}
+
+// GetWebhook mocks base method.
+func (m *MockStore) GetWebhook(arg0 context.Context, arg1 uuid.UUID) (database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", arg0, arg1)
+	ret0, _ := ret[0].(database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockStoreMockRecorder) GetWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockStore)(nil).GetWebhook), arg0, arg1)
+}
+
+// ListAccessibleAccountIDs mocks base method.
+func (m *MockStore) ListAccessibleAccountIDs(arg0 context.Context, arg1 string) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccessibleAccountIDs", arg0, arg1)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccessibleAccountIDs indicates an expected call of ListAccessibleAccountIDs.
+func (mr *MockStoreMockRecorder) ListAccessibleAccountIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccessibleAccountIDs", reflect.TypeOf((*MockStore)(nil).ListAccessibleAccountIDs), arg0, arg1)
+}
+
+// ListAccountEntriesAfter mocks base method.
+func (m *MockStore) ListAccountEntriesAfter(arg0 context.Context, arg1 database.ListAccountEntriesAfterParams) ([]database.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountEntriesAfter", arg0, arg1)
+	ret0, _ := ret[0].([]database.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountEntriesAfter indicates an expected call of ListAccountEntriesAfter.
+func (mr *MockStoreMockRecorder) ListAccountEntriesAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountEntriesAfter", reflect.TypeOf((*MockStore)(nil).ListAccountEntriesAfter), arg0, arg1)
+}
+
+// ListAccountEntriesBefore mocks base method.
+func (m *MockStore) ListAccountEntriesBefore(arg0 context.Context, arg1 database.ListAccountEntriesBeforeParams) ([]database.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountEntriesBefore", arg0, arg1)
+	ret0, _ := ret[0].([]database.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountEntriesBefore indicates an expected call of ListAccountEntriesBefore.
+func (mr *MockStoreMockRecorder) ListAccountEntriesBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountEntriesBefore", reflect.TypeOf((*MockStore)(nil).ListAccountEntriesBefore), arg0, arg1)
+}
+
+// ListAccountGrants mocks base method.
+func (m *MockStore) ListAccountGrants(arg0 context.Context, arg1 uuid.UUID) ([]database.AccountGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountGrants", arg0, arg1)
+	ret0, _ := ret[0].([]database.AccountGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountGrants indicates an expected call of ListAccountGrants.
+func (mr *MockStoreMockRecorder) ListAccountGrants(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountGrants", reflect.TypeOf((*MockStore)(nil).ListAccountGrants), arg0, arg1)
+}
+
+// ListAccountHolders mocks base method.
+func (m *MockStore) ListAccountHolders(arg0 context.Context, arg1 uuid.UUID) ([]database.AccountHolder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountHolders", arg0, arg1)
+	ret0, _ := ret[0].([]database.AccountHolder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountHolders indicates an expected call of ListAccountHolders.
+func (mr *MockStoreMockRecorder) ListAccountHolders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountHolders", reflect.TypeOf((*MockStore)(nil).ListAccountHolders), arg0, arg1)
+}
+
+// ListAccountOutboxEvents mocks base method.
+func (m *MockStore) ListAccountOutboxEvents(arg0 context.Context, arg1 database.ListAccountOutboxEventsParams) ([]database.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountOutboxEvents", arg0, arg1)
+	ret0, _ := ret[0].([]database.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountOutboxEvents indicates an expected call of ListAccountOutboxEvents.
+func (mr *MockStoreMockRecorder) ListAccountOutboxEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountOutboxEvents", reflect.TypeOf((*MockStore)(nil).ListAccountOutboxEvents), arg0, arg1)
+}
+
+// ListAccountTransferRequestsAfter mocks base method.
+func (m *MockStore) ListAccountTransferRequestsAfter(arg0 context.Context, arg1 database.ListAccountTransferRequestsAfterParams) ([]database.TransferRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountTransferRequestsAfter", arg0, arg1)
+	ret0, _ := ret[0].([]database.TransferRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountTransferRequestsAfter indicates an expected call of ListAccountTransferRequestsAfter.
+func (mr *MockStoreMockRecorder) ListAccountTransferRequestsAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountTransferRequestsAfter", reflect.TypeOf((*MockStore)(nil).ListAccountTransferRequestsAfter), arg0, arg1)
+}
+
+// ListAccountTransferRequestsBefore mocks base method.
+func (m *MockStore) ListAccountTransferRequestsBefore(arg0 context.Context, arg1 database.ListAccountTransferRequestsBeforeParams) ([]database.TransferRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountTransferRequestsBefore", arg0, arg1)
+	ret0, _ := ret[0].([]database.TransferRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountTransferRequestsBefore indicates an expected call of ListAccountTransferRequestsBefore.
+func (mr *MockStoreMockRecorder) ListAccountTransferRequestsBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountTransferRequestsBefore", reflect.TypeOf((*MockStore)(nil).ListAccountTransferRequestsBefore), arg0, arg1)
+}
+
+// ListAccountTransfersAfter mocks base method.
+func (m *MockStore) ListAccountTransfersAfter(arg0 context.Context, arg1 database.ListAccountTransfersAfterParams) ([]database.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountTransfersAfter", arg0, arg1)
+	ret0, _ := ret[0].([]database.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountTransfersAfter indicates an expected call of ListAccountTransfersAfter.
+func (mr *MockStoreMockRecorder) ListAccountTransfersAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountTransfersAfter", reflect.TypeOf((*MockStore)(nil).ListAccountTransfersAfter), arg0, arg1)
+}
+
+// ListAccountTransfersBefore mocks base method.
+func (m *MockStore) ListAccountTransfersBefore(arg0 context.Context, arg1 database.ListAccountTransfersBeforeParams) ([]database.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountTransfersBefore", arg0, arg1)
+	ret0, _ := ret[0].([]database.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountTransfersBefore indicates an expected call of ListAccountTransfersBefore.
+func (mr *MockStoreMockRecorder) ListAccountTransfersBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountTransfersBefore", reflect.TypeOf((*MockStore)(nil).ListAccountTransfersBefore), arg0, arg1)
+}
+
+// ListCurrencyBalances mocks base method.
+func (m *MockStore) ListCurrencyBalances(arg0 context.Context) ([]database.ListCurrencyBalancesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCurrencyBalances", arg0)
+	ret0, _ := ret[0].([]database.ListCurrencyBalancesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCurrencyBalances indicates an expected call of ListCurrencyBalances.
+func (mr *MockStoreMockRecorder) ListCurrencyBalances(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCurrencyBalances", reflect.TypeOf((*MockStore)(nil).ListCurrencyBalances), arg0)
+}
+
+// ListCurrencyTransferVolumes mocks base method.
+func (m *MockStore) ListCurrencyTransferVolumes(arg0 context.Context, arg1 database.ListCurrencyTransferVolumesParams) ([]database.ListCurrencyTransferVolumesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCurrencyTransferVolumes", arg0, arg1)
+	ret0, _ := ret[0].([]database.ListCurrencyTransferVolumesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCurrencyTransferVolumes indicates an expected call of ListCurrencyTransferVolumes.
+func (mr *MockStoreMockRecorder) ListCurrencyTransferVolumes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCurrencyTransferVolumes", reflect.TypeOf((*MockStore)(nil).ListCurrencyTransferVolumes), arg0, arg1)
+}
+
+// ListGranteeGrantHistory mocks base method.
+func (m *MockStore) ListGranteeGrantHistory(arg0 context.Context, arg1 string) ([]database.AccountGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGranteeGrantHistory", arg0, arg1)
+	ret0, _ := ret[0].([]database.AccountGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGranteeGrantHistory indicates an expected call of ListGranteeGrantHistory.
+func (mr *MockStoreMockRecorder) ListGranteeGrantHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGranteeGrantHistory", reflect.TypeOf((*MockStore)(nil).ListGranteeGrantHistory), arg0, arg1)
+}
+
+// ListGranteeGrants mocks base method.
+func (m *MockStore) ListGranteeGrants(arg0 context.Context, arg1 string) ([]database.AccountGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGranteeGrants", arg0, arg1)
+	ret0, _ := ret[0].([]database.AccountGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGranteeGrants indicates an expected call of ListGranteeGrants.
+func (mr *MockStoreMockRecorder) ListGranteeGrants(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGranteeGrants", reflect.TypeOf((*MockStore)(nil).ListGranteeGrants), arg0, arg1)
+}
+
+// ListGrantorGrantHistory mocks base method.
+func (m *MockStore) ListGrantorGrantHistory(arg0 context.Context, arg1 string) ([]database.AccountGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGrantorGrantHistory", arg0, arg1)
+	ret0, _ := ret[0].([]database.AccountGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGrantorGrantHistory indicates an expected call of ListGrantorGrantHistory.
+func (mr *MockStoreMockRecorder) ListGrantorGrantHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGrantorGrantHistory", reflect.TypeOf((*MockStore)(nil).ListGrantorGrantHistory), arg0, arg1)
+}
+
+// ListHolderAccountsAfter mocks base method.
+func (m *MockStore) ListHolderAccountsAfter(arg0 context.Context, arg1 database.ListHolderAccountsAfterParams) ([]database.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHolderAccountsAfter", arg0, arg1)
+	ret0, _ := ret[0].([]database.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHolderAccountsAfter indicates an expected call of ListHolderAccountsAfter.
+func (mr *MockStoreMockRecorder) ListHolderAccountsAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHolderAccountsAfter", reflect.TypeOf((*MockStore)(nil).ListHolderAccountsAfter), arg0, arg1)
+}
+
+// ListHolderAccountsBefore mocks base method.
+func (m *MockStore) ListHolderAccountsBefore(arg0 context.Context, arg1 database.ListHolderAccountsBeforeParams) ([]database.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHolderAccountsBefore", arg0, arg1)
+	ret0, _ := ret[0].([]database.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHolderAccountsBefore indicates an expected call of ListHolderAccountsBefore.
+func (mr *MockStoreMockRecorder) ListHolderAccountsBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHolderAccountsBefore", reflect.TypeOf((*MockStore)(nil).ListHolderAccountsBefore), arg0, arg1)
+}
+
+// ListHolderCurrencyBalances mocks base method.
+func (m *MockStore) ListHolderCurrencyBalances(arg0 context.Context, arg1 string) ([]database.ListHolderCurrencyBalancesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHolderCurrencyBalances", arg0, arg1)
+	ret0, _ := ret[0].([]database.ListHolderCurrencyBalancesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHolderCurrencyBalances indicates an expected call of ListHolderCurrencyBalances.
+func (mr *MockStoreMockRecorder) ListHolderCurrencyBalances(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHolderCurrencyBalances", reflect.TypeOf((*MockStore)(nil).ListHolderCurrencyBalances), arg0, arg1)
+}
+
+// ListOutboxEvents mocks base method.
+func (m *MockStore) ListOutboxEvents(arg0 context.Context, arg1 database.ListOutboxEventsParams) ([]database.Outbox, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOutboxEvents", arg0, arg1)
+	ret0, _ := ret[0].([]database.Outbox)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOutboxEvents indicates an expected call of ListOutboxEvents.
+func (mr *MockStoreMockRecorder) ListOutboxEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOutboxEvents", reflect.TypeOf((*MockStore)(nil).ListOutboxEvents), arg0, arg1)
+}
+
+// ListOwnerAccounts mocks base method.
+func (m *MockStore) ListOwnerAccounts(arg0 context.Context, arg1 string) ([]database.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOwnerAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]database.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOwnerAccounts indicates an expected call of ListOwnerAccounts.
+func (mr *MockStoreMockRecorder) ListOwnerAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOwnerAccounts", reflect.TypeOf((*MockStore)(nil).ListOwnerAccounts), arg0, arg1)
+}
+
+// ListOwnerAccountsForUpdate mocks base method.
+func (m *MockStore) ListOwnerAccountsForUpdate(arg0 context.Context, arg1 string) ([]database.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOwnerAccountsForUpdate", arg0, arg1)
+	ret0, _ := ret[0].([]database.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOwnerAccountsForUpdate indicates an expected call of ListOwnerAccountsForUpdate.
+func (mr *MockStoreMockRecorder) ListOwnerAccountsForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOwnerAccountsForUpdate", reflect.TypeOf((*MockStore)(nil).ListOwnerAccountsForUpdate), arg0, arg1)
+}
+
+// ListOwnerEntries mocks base method.
+func (m *MockStore) ListOwnerEntries(arg0 context.Context, arg1 string) ([]database.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOwnerEntries", arg0, arg1)
+	ret0, _ := ret[0].([]database.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOwnerEntries indicates an expected call of ListOwnerEntries.
+func (mr *MockStoreMockRecorder) ListOwnerEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOwnerEntries", reflect.TypeOf((*MockStore)(nil).ListOwnerEntries), arg0, arg1)
+}
+
+// ListOwnerTransfers mocks base method.
+func (m *MockStore) ListOwnerTransfers(arg0 context.Context, arg1 string) ([]database.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOwnerTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]database.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOwnerTransfers indicates an expected call of ListOwnerTransfers.
+func (mr *MockStoreMockRecorder) ListOwnerTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOwnerTransfers", reflect.TypeOf((*MockStore)(nil).ListOwnerTransfers), arg0, arg1)
+}
+
+// ListPendingAccountInvitations mocks base method.
+func (m *MockStore) ListPendingAccountInvitations(arg0 context.Context, arg1 string) ([]database.AccountHolder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingAccountInvitations", arg0, arg1)
+	ret0, _ := ret[0].([]database.AccountHolder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingAccountInvitations indicates an expected call of ListPendingAccountInvitations.
+func (mr *MockStoreMockRecorder) ListPendingAccountInvitations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingAccountInvitations", reflect.TypeOf((*MockStore)(nil).ListPendingAccountInvitations), arg0, arg1)
+}
+
+// ListPendingKYCSubmissions mocks base method.
+func (m *MockStore) ListPendingKYCSubmissions(arg0 context.Context, arg1 database.ListPendingKYCSubmissionsParams) ([]database.KycSubmission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingKYCSubmissions", arg0, arg1)
+	ret0, _ := ret[0].([]database.KycSubmission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingKYCSubmissions indicates an expected call of ListPendingKYCSubmissions.
+func (mr *MockStoreMockRecorder) ListPendingKYCSubmissions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingKYCSubmissions", reflect.TypeOf((*MockStore)(nil).ListPendingKYCSubmissions), arg0, arg1)
+}
+
+// ListSubAccounts mocks base method.
+func (m *MockStore) ListSubAccounts(arg0 context.Context, arg1 uuid.NullUUID) ([]database.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]database.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubAccounts indicates an expected call of ListSubAccounts.
+func (mr *MockStoreMockRecorder) ListSubAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubAccounts", reflect.TypeOf((*MockStore)(nil).ListSubAccounts), arg0, arg1)
+}
+
+// ListTransferApprovals mocks base method.
+func (m *MockStore) ListTransferApprovals(arg0 context.Context, arg1 uuid.UUID) ([]database.TransferApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransferApprovals", arg0, arg1)
+	ret0, _ := ret[0].([]database.TransferApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransferApprovals indicates an expected call of ListTransferApprovals.
+func (mr *MockStoreMockRecorder) ListTransferApprovals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransferApprovals", reflect.TypeOf((*MockStore)(nil).ListTransferApprovals), arg0, arg1)
+}
+
+// ListUserAccountHolders mocks base method.
+func (m *MockStore) ListUserAccountHolders(arg0 context.Context, arg1 string) ([]database.AccountHolder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserAccountHolders", arg0, arg1)
+	ret0, _ := ret[0].([]database.AccountHolder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserAccountHolders indicates an expected call of ListUserAccountHolders.
+func (mr *MockStoreMockRecorder) ListUserAccountHolders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserAccountHolders", reflect.TypeOf((*MockStore)(nil).ListUserAccountHolders), arg0, arg1)
+}
+
+// ListUserKYCSubmissions mocks base method.
+func (m *MockStore) ListUserKYCSubmissions(arg0 context.Context, arg1 string) ([]database.KycSubmission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserKYCSubmissions", arg0, arg1)
+	ret0, _ := ret[0].([]database.KycSubmission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserKYCSubmissions indicates an expected call of ListUserKYCSubmissions.
+func (mr *MockStoreMockRecorder) ListUserKYCSubmissions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserKYCSubmissions", reflect.TypeOf((*MockStore)(nil).ListUserKYCSubmissions), arg0, arg1)
+}
+
+// ListUserLoginAttempts mocks base method.
+func (m *MockStore) ListUserLoginAttempts(arg0 context.Context, arg1 string) ([]database.LoginAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserLoginAttempts", arg0, arg1)
+	ret0, _ := ret[0].([]database.LoginAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserLoginAttempts indicates an expected call of ListUserLoginAttempts.
+func (mr *MockStoreMockRecorder) ListUserLoginAttempts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserLoginAttempts", reflect.TypeOf((*MockStore)(nil).ListUserLoginAttempts), arg0, arg1)
+}
+
+// ListUserSessions mocks base method.
+func (m *MockStore) ListUserSessions(arg0 context.Context, arg1 string) ([]database.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserSessions", arg0, arg1)
+	ret0, _ := ret[0].([]database.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserSessions indicates an expected call of ListUserSessions.
+func (mr *MockStoreMockRecorder) ListUserSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserSessions", reflect.TypeOf((*MockStore)(nil).ListUserSessions), arg0, arg1)
+}
+
+// ListUserWebhooks mocks base method.
+func (m *MockStore) ListUserWebhooks(arg0 context.Context, arg1 string) ([]database.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserWebhooks", arg0, arg1)
+	ret0, _ := ret[0].([]database.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserWebhooks indicates an expected call of ListUserWebhooks.
+func (mr *MockStoreMockRecorder) ListUserWebhooks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserWebhooks", reflect.TypeOf((*MockStore)(nil).ListUserWebhooks), arg0, arg1)
+}
+
+// ListWebhookDeliveriesAfter mocks base method.
+func (m *MockStore) ListWebhookDeliveriesAfter(arg0 context.Context, arg1 database.ListWebhookDeliveriesAfterParams) ([]database.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveriesAfter", arg0, arg1)
+	ret0, _ := ret[0].([]database.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveriesAfter indicates an expected call of ListWebhookDeliveriesAfter.
+func (mr *MockStoreMockRecorder) ListWebhookDeliveriesAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveriesAfter", reflect.TypeOf((*MockStore)(nil).ListWebhookDeliveriesAfter), arg0, arg1)
+}
+
+// ListWebhookDeliveriesBefore mocks base method.
+func (m *MockStore) ListWebhookDeliveriesBefore(arg0 context.Context, arg1 database.ListWebhookDeliveriesBeforeParams) ([]database.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveriesBefore", arg0, arg1)
+	ret0, _ := ret[0].([]database.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveriesBefore indicates an expected call of ListWebhookDeliveriesBefore.
+func (mr *MockStoreMockRecorder) ListWebhookDeliveriesBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveriesBefore", reflect.TypeOf((*MockStore)(nil).ListWebhookDeliveriesBefore), arg0, arg1)
+}
+
+// MarkRecoveryCodeUsed mocks base method.
+func (m *MockStore) MarkRecoveryCodeUsed(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRecoveryCodeUsed", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRecoveryCodeUsed indicates an expected call of MarkRecoveryCodeUsed.
+func (mr *MockStoreMockRecorder) MarkRecoveryCodeUsed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRecoveryCodeUsed", reflect.TypeOf((*MockStore)(nil).MarkRecoveryCodeUsed), arg0, arg1)
+}
+
+// MarkWebhookDelivered mocks base method.
+func (m *MockStore) MarkWebhookDelivered(arg0 context.Context, arg1 database.MarkWebhookDeliveredParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWebhookDelivered", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkWebhookDelivered indicates an expected call of MarkWebhookDelivered.
+func (mr *MockStoreMockRecorder) MarkWebhookDelivered(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWebhookDelivered", reflect.TypeOf((*MockStore)(nil).MarkWebhookDelivered), arg0, arg1)
+}
+
+// MarkWebhookDeliveryFailed mocks base method.
+func (m *MockStore) MarkWebhookDeliveryFailed(arg0 context.Context, arg1 database.MarkWebhookDeliveryFailedParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkWebhookDeliveryFailed", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkWebhookDeliveryFailed indicates an expected call of MarkWebhookDeliveryFailed.
+func (mr *MockStoreMockRecorder) MarkWebhookDeliveryFailed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkWebhookDeliveryFailed", reflect.TypeOf((*MockStore)(nil).MarkWebhookDeliveryFailed), arg0, arg1)
+}
+
+// NotifyBalanceChanged mocks base method.
+func (m *MockStore) NotifyBalanceChanged(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NotifyBalanceChanged", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NotifyBalanceChanged indicates an expected call of NotifyBalanceChanged.
+func (mr *MockStoreMockRecorder) NotifyBalanceChanged(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyBalanceChanged", reflect.TypeOf((*MockStore)(nil).NotifyBalanceChanged), arg0, arg1)
+}
+
+// ResetPasswordTx mocks base method.
+func (m *MockStore) ResetPasswordTx(arg0 context.Context, arg1 database.ResetPasswordTxParams) (database.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPasswordTx", arg0, arg1)
+	ret0, _ := ret[0].(database.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetPasswordTx indicates an expected call of ResetPasswordTx.
+func (mr *MockStoreMockRecorder) ResetPasswordTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPasswordTx", reflect.TypeOf((*MockStore)(nil).ResetPasswordTx), arg0, arg1)
+}
+
+// ReviewKYCSubmission mocks base method.
+func (m *MockStore) ReviewKYCSubmission(arg0 context.Context, arg1 database.ReviewKYCSubmissionParams) (database.KycSubmission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReviewKYCSubmission", arg0, arg1)
+	ret0, _ := ret[0].(database.KycSubmission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReviewKYCSubmission indicates an expected call of ReviewKYCSubmission.
+func (mr *MockStoreMockRecorder) ReviewKYCSubmission(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReviewKYCSubmission", reflect.TypeOf((*MockStore)(nil).ReviewKYCSubmission), arg0, arg1)
+}
+
+// ReviewKYCTx mocks base method.
+func (m *MockStore) ReviewKYCTx(arg0 context.Context, arg1 database.ReviewKYCSubmissionParams) (database.ReviewKYCTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReviewKYCTx", arg0, arg1)
+	ret0, _ := ret[0].(database.ReviewKYCTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReviewKYCTx indicates an expected call of ReviewKYCTx.
+func (mr *MockStoreMockRecorder) ReviewKYCTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReviewKYCTx", reflect.TypeOf((*MockStore)(nil).ReviewKYCTx), arg0, arg1)
+}
+
+// RevokeAccountGrant mocks base method.
+func (m *MockStore) RevokeAccountGrant(arg0 context.Context, arg1 database.RevokeAccountGrantParams) (database.AccountGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAccountGrant", arg0, arg1)
+	ret0, _ := ret[0].(database.AccountGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAccountGrant indicates an expected call of RevokeAccountGrant.
+func (mr *MockStoreMockRecorder) RevokeAccountGrant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAccountGrant", reflect.TypeOf((*MockStore)(nil).RevokeAccountGrant), arg0, arg1)
+}
+
+// RevokeGranteeAccountGrants mocks base method.
+func (m *MockStore) RevokeGranteeAccountGrants(arg0 context.Context, arg1 database.RevokeGranteeAccountGrantsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeGranteeAccountGrants", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeGranteeAccountGrants indicates an expected call of RevokeGranteeAccountGrants.
+func (mr *MockStoreMockRecorder) RevokeGranteeAccountGrants(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeGranteeAccountGrants", reflect.TypeOf((*MockStore)(nil).RevokeGranteeAccountGrants), arg0, arg1)
+}
+
+// RevokeUserAccountGrants mocks base method.
+func (m *MockStore) RevokeUserAccountGrants(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserAccountGrants", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserAccountGrants indicates an expected call of RevokeUserAccountGrants.
+func (mr *MockStoreMockRecorder) RevokeUserAccountGrants(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserAccountGrants", reflect.TypeOf((*MockStore)(nil).RevokeUserAccountGrants), arg0, arg1)
+}
+
+// SetOutboxOffset mocks base method.
+func (m *MockStore) SetOutboxOffset(arg0 context.Context, arg1 database.SetOutboxOffsetParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOutboxOffset", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOutboxOffset indicates an expected call of SetOutboxOffset.
+func (mr *MockStoreMockRecorder) SetOutboxOffset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOutboxOffset", reflect.TypeOf((*MockStore)(nil).SetOutboxOffset), arg0, arg1)
+}
+
+// SetUserEmailVerified mocks base method.
+func (m *MockStore) SetUserEmailVerified(arg0 context.Context, arg1 database.SetUserEmailVerifiedParams) (database.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserEmailVerified", arg0, arg1)
+	ret0, _ := ret[0].(database.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserEmailVerified indicates an expected call of SetUserEmailVerified.
+func (mr *MockStoreMockRecorder) SetUserEmailVerified(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserEmailVerified", reflect.TypeOf((*MockStore)(nil).SetUserEmailVerified), arg0, arg1)
+}
+
+// SubmitKYCTx mocks base method.
+func (m *MockStore) SubmitKYCTx(arg0 context.Context, arg1 database.CreateKYCSubmissionParams) (database.KycSubmission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitKYCTx", arg0, arg1)
+	ret0, _ := ret[0].(database.KycSubmission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitKYCTx indicates an expected call of SubmitKYCTx.
+func (mr *MockStoreMockRecorder) SubmitKYCTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitKYCTx", reflect.TypeOf((*MockStore)(nil).SubmitKYCTx), arg0, arg1)
+}
+
+// TransferTx mocks base method.
+func (m *MockStore) TransferTx(arg0 context.Context, arg1 database.TransferTxParams) (database.TransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferTx", arg0, arg1)
+	ret0, _ := ret[0].(database.TransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferTx indicates an expected call of TransferTx.
+func (mr *MockStoreMockRecorder) TransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferTx", reflect.TypeOf((*MockStore)(nil).TransferTx), arg0, arg1)
+}
+
+// UnfreezeAccount mocks base method.
+func (m *MockStore) UnfreezeAccount(arg0 context.Context, arg1 uuid.UUID) (database.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnfreezeAccount", arg0, arg1)
+	ret0, _ := ret[0].(database.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnfreezeAccount indicates an expected call of UnfreezeAccount.
+func (mr *MockStoreMockRecorder) UnfreezeAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfreezeAccount", reflect.TypeOf((*MockStore)(nil).UnfreezeAccount), arg0, arg1)
+}
+
+// UpdateAccountApprovalThreshold mocks base method.
+func (m *MockStore) UpdateAccountApprovalThreshold(arg0 context.Context, arg1 database.UpdateAccountApprovalThresholdParams) (database.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountApprovalThreshold", arg0, arg1)
+	ret0, _ := ret[0].(database.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountApprovalThreshold indicates an expected call of UpdateAccountApprovalThreshold.
+func (mr *MockStoreMockRecorder) UpdateAccountApprovalThreshold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountApprovalThreshold", reflect.TypeOf((*MockStore)(nil).UpdateAccountApprovalThreshold), arg0, arg1)
+}
+
+// UpdateAccountBalance mocks base method.
+func (m *MockStore) UpdateAccountBalance(arg0 context.Context, arg1 database.UpdateAccountBalanceParams) (database.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountBalance", arg0, arg1)
+	ret0, _ := ret[0].(database.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountBalance indicates an expected call of UpdateAccountBalance.
+func (mr *MockStoreMockRecorder) UpdateAccountBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountBalance", reflect.TypeOf((*MockStore)(nil).UpdateAccountBalance), arg0, arg1)
+}
+
+// UpdateApprovalThresholdTx mocks base method.
+func (m *MockStore) UpdateApprovalThresholdTx(arg0 context.Context, arg1 database.UpdateApprovalThresholdTxParams) (database.UpdateApprovalThresholdTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateApprovalThresholdTx", arg0, arg1)
+	ret0, _ := ret[0].(database.UpdateApprovalThresholdTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateApprovalThresholdTx indicates an expected call of UpdateApprovalThresholdTx.
+func (mr *MockStoreMockRecorder) UpdateApprovalThresholdTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApprovalThresholdTx", reflect.TypeOf((*MockStore)(nil).UpdateApprovalThresholdTx), arg0, arg1)
+}
+
+// UpdateUserKYCStatus mocks base method.
+func (m *MockStore) UpdateUserKYCStatus(arg0 context.Context, arg1 database.UpdateUserKYCStatusParams) (database.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserKYCStatus", arg0, arg1)
+	ret0, _ := ret[0].(database.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserKYCStatus indicates an expected call of UpdateUserKYCStatus.
+func (mr *MockStoreMockRecorder) UpdateUserKYCStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserKYCStatus", reflect.TypeOf((*MockStore)(nil).UpdateUserKYCStatus), arg0, arg1)
+}
+
+// UpdateUserPassword mocks base method.
+func (m *MockStore) UpdateUserPassword(arg0 context.Context, arg1 database.UpdateUserPasswordParams) (database.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserPassword", arg0, arg1)
+	ret0, _ := ret[0].(database.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserPassword indicates an expected call of UpdateUserPassword.
+func (mr *MockStoreMockRecorder) UpdateUserPassword(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPassword", reflect.TypeOf((*MockStore)(nil).UpdateUserPassword), arg0, arg1)
+}
+
+// UpdateUserPasswordHash mocks base method.
+func (m *MockStore) UpdateUserPasswordHash(arg0 context.Context, arg1 database.UpdateUserPasswordHashParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserPasswordHash", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserPasswordHash indicates an expected call of UpdateUserPasswordHash.
+func (mr *MockStoreMockRecorder) UpdateUserPasswordHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPasswordHash", reflect.TypeOf((*MockStore)(nil).UpdateUserPasswordHash), arg0, arg1)
+}
+
+// UpdateUserProfile mocks base method.
+func (m *MockStore) UpdateUserProfile(arg0 context.Context, arg1 database.UpdateUserProfileParams) (database.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserProfile", arg0, arg1)
+	ret0, _ := ret[0].(database.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserProfile indicates an expected call of UpdateUserProfile.
+func (mr *MockStoreMockRecorder) UpdateUserProfile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserProfile", reflect.TypeOf((*MockStore)(nil).UpdateUserProfile), arg0, arg1)
+}
+
+// UpdateUserRole mocks base method.
+func (m *MockStore) UpdateUserRole(arg0 context.Context, arg1 database.UpdateUserRoleParams) (database.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRole", arg0, arg1)
+	ret0, _ := ret[0].(database.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserRole indicates an expected call of UpdateUserRole.
+func (mr *MockStoreMockRecorder) UpdateUserRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRole", reflect.TypeOf((*MockStore)(nil).UpdateUserRole), arg0, arg1)
+}
+
+// UpdateUserTOTPSecret mocks base method.
+func (m *MockStore) UpdateUserTOTPSecret(arg0 context.Context, arg1 database.UpdateUserTOTPSecretParams) (database.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserTOTPSecret", arg0, arg1)
+	ret0, _ := ret[0].(database.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserTOTPSecret indicates an expected call of UpdateUserTOTPSecret.
+func (mr *MockStoreMockRecorder) UpdateUserTOTPSecret(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserTOTPSecret", reflect.TypeOf((*MockStore)(nil).UpdateUserTOTPSecret), arg0, arg1)
+}
+
+// UseEmailVerificationToken mocks base method.
+func (m *MockStore) UseEmailVerificationToken(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseEmailVerificationToken", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseEmailVerificationToken indicates an expected call of UseEmailVerificationToken.
+func (mr *MockStoreMockRecorder) UseEmailVerificationToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseEmailVerificationToken", reflect.TypeOf((*MockStore)(nil).UseEmailVerificationToken), arg0, arg1)
+}
+
+// UsePasswordResetToken mocks base method.
+func (m *MockStore) UsePasswordResetToken(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UsePasswordResetToken", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UsePasswordResetToken indicates an expected call of UsePasswordResetToken.
+func (mr *MockStoreMockRecorder) UsePasswordResetToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UsePasswordResetToken", reflect.TypeOf((*MockStore)(nil).UsePasswordResetToken), arg0, arg1)
+}
+
+// VerifyEmailTx mocks base method.
+func (m *MockStore) VerifyEmailTx(arg0 context.Context, arg1 database.VerifyEmailTxParams) (database.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmailTx", arg0, arg1)
+	ret0, _ := ret[0].(database.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyEmailTx indicates an expected call of VerifyEmailTx.
+func (mr *MockStoreMockRecorder) VerifyEmailTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmailTx", reflect.TypeOf((*MockStore)(nil).VerifyEmailTx), arg0, arg1)
 }
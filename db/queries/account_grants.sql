@@ -0,0 +1,62 @@
+-- name: CreateAccountGrant :one
+INSERT INTO account_grants (
+	account_id,
+	grantor,
+	grantee,
+	transfer_limit,
+	can_approve,
+	expires_at
+) VALUES (
+	$1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetActiveAccountGrant :one
+SELECT * FROM account_grants
+	WHERE account_id=$1 AND grantee=$2 AND revoked_at IS NULL AND expires_at > now();
+
+-- name: GetAccountGrantForUpdate :one
+SELECT * FROM account_grants
+	WHERE id=$1
+	FOR NO KEY UPDATE;
+
+-- name: AddAccountGrantTransferred :one
+UPDATE account_grants
+	SET transferred = transferred + sqlc.arg(amount)
+	WHERE id = sqlc.arg(id)
+	RETURNING *;
+
+-- name: ListAccountGrants :many
+SELECT * FROM account_grants
+	WHERE account_id=$1
+	ORDER BY created_at DESC;
+
+-- name: ListGranteeGrants :many
+SELECT * FROM account_grants
+	WHERE grantee=$1 AND revoked_at IS NULL AND expires_at > now()
+	ORDER BY created_at DESC;
+
+-- name: RevokeAccountGrant :one
+UPDATE account_grants
+	SET revoked_at=now()
+	WHERE id=$1 AND account_id=$2 AND revoked_at IS NULL
+	RETURNING *;
+
+-- name: RevokeGranteeAccountGrants :exec
+UPDATE account_grants
+	SET revoked_at=now()
+	WHERE account_id=$1 AND grantee=$2 AND revoked_at IS NULL;
+
+-- name: RevokeUserAccountGrants :exec
+UPDATE account_grants
+	SET revoked_at=now()
+	WHERE grantee=$1 AND revoked_at IS NULL;
+
+-- name: ListGrantorGrantHistory :many
+SELECT * FROM account_grants
+	WHERE grantor=$1
+	ORDER BY created_at;
+
+-- name: ListGranteeGrantHistory :many
+SELECT * FROM account_grants
+	WHERE grantee=$1
+	ORDER BY created_at;
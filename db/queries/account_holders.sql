@@ -0,0 +1,51 @@
+-- name: CreateAccountHolder :one
+INSERT INTO account_holders (
+	account_id,
+	username,
+	role,
+	invited_by,
+	accepted_at
+) VALUES (
+	$1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetAccountHolder :one
+SELECT * FROM account_holders
+	WHERE account_id=$1 AND username=$2
+	LIMIT 1;
+
+-- name: ListAccountHolders :many
+SELECT * FROM account_holders
+	WHERE account_id=$1
+	ORDER BY created_at;
+
+-- name: ListPendingAccountInvitations :many
+SELECT * FROM account_holders
+	WHERE username=$1 AND accepted_at IS NULL
+	ORDER BY created_at;
+
+-- name: AcceptAccountHolder :one
+UPDATE account_holders
+	SET accepted_at=now()
+	WHERE account_id=$1 AND username=$2 AND accepted_at IS NULL
+	RETURNING *;
+
+-- name: DeleteAccountHolder :execrows
+DELETE FROM account_holders
+	WHERE account_id=$1 AND username=$2 AND role <> 'owner';
+
+-- name: DeleteUserAccountHolders :exec
+DELETE FROM account_holders
+	WHERE username=$1 AND role <> 'owner';
+
+-- name: ListAccessibleAccountIDs :many
+SELECT account_id FROM account_holders
+	WHERE username=$1 AND accepted_at IS NOT NULL
+UNION
+SELECT account_id FROM account_grants
+	WHERE grantee=$1 AND revoked_at IS NULL AND expires_at > now();
+
+-- name: ListUserAccountHolders :many
+SELECT * FROM account_holders
+	WHERE username=$1
+	ORDER BY created_at;
@@ -2,9 +2,11 @@
 INSERT INTO accounts (
 	owner,
 	balance,
-	currency
+	currency,
+	name,
+	parent_id
 ) VALUES (
-	$1, $2, $3
+	$1, $2, $3, $4, $5
 ) RETURNING *;
 
 -- name: GetAccount :one
@@ -13,13 +15,6 @@ SELECT * FROM accounts WHERE id=$1 LIMIT 1;
 -- name: GetAccountForUpdate :one
 SELECT * FROM accounts WHERE id=$1 LIMIT 1 FOR NO KEY UPDATE;
 
--- name: GetAccountsList :many
-SELECT * FROM accounts 
-	WHERE owner = $1
-	ORDER BY id 
-	LIMIT $2 
-	OFFSET $3;
-
 -- name: UpdateAccountBalance :one
 UPDATE accounts
 	SET balance=$2
@@ -34,3 +29,73 @@ UPDATE accounts
 
 -- name: DeleteAccount :exec
 DELETE FROM accounts WHERE id=$1;
+
+-- name: ListOwnerAccounts :many
+SELECT * FROM accounts
+	WHERE owner=$1
+	ORDER BY created_at;
+
+-- name: ListOwnerAccountsForUpdate :many
+SELECT * FROM accounts
+	WHERE owner=$1
+	ORDER BY id
+	FOR NO KEY UPDATE;
+
+-- name: CloseOwnerAccounts :exec
+UPDATE accounts
+	SET closed_at=$2
+	WHERE owner=$1 AND closed_at IS NULL;
+
+-- name: FreezeAccount :one
+UPDATE accounts
+	SET frozen_at=now(), frozen_by=$2
+	WHERE id=$1
+	RETURNING *;
+
+-- name: UnfreezeAccount :one
+UPDATE accounts
+	SET frozen_at=NULL, frozen_by=NULL
+	WHERE id=$1
+	RETURNING *;
+
+-- name: UpdateAccountApprovalThreshold :one
+UPDATE accounts
+	SET approval_threshold=$2
+	WHERE id=$1
+	RETURNING *;
+
+-- name: ListSubAccounts :many
+SELECT * FROM accounts
+	WHERE parent_id=$1
+	ORDER BY created_at;
+
+-- name: ListHolderCurrencyBalances :many
+SELECT accounts.currency, SUM(accounts.balance)::float AS balance, COUNT(*) AS accounts FROM accounts
+	JOIN account_holders ON account_holders.account_id = accounts.id
+	WHERE account_holders.username = $1 AND account_holders.accepted_at IS NOT NULL
+	GROUP BY accounts.currency
+	ORDER BY accounts.currency;
+
+-- name: ListCurrencyBalances :many
+SELECT currency, SUM(balance)::float AS balance, COUNT(*) AS accounts, COUNT(*) FILTER (WHERE frozen_at IS NOT NULL) AS frozen_accounts FROM accounts
+	GROUP BY currency
+	ORDER BY currency;
+
+-- name: ListHolderAccountsAfter :many
+SELECT accounts.* FROM accounts
+	JOIN account_holders ON account_holders.account_id = accounts.id
+	WHERE account_holders.username = sqlc.arg(username) AND account_holders.accepted_at IS NOT NULL
+		AND (accounts.created_at, accounts.id) > (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+	ORDER BY accounts.created_at, accounts.id
+	LIMIT sqlc.arg(page_size);
+
+-- name: ListHolderAccountsBefore :many
+SELECT accounts.* FROM accounts
+	JOIN account_holders ON account_holders.account_id = accounts.id
+	WHERE account_holders.username = sqlc.arg(username) AND account_holders.accepted_at IS NOT NULL
+		AND (accounts.created_at, accounts.id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+	ORDER BY accounts.created_at DESC, accounts.id DESC
+	LIMIT sqlc.arg(page_size);
+
+-- name: NotifyBalanceChanged :exec
+SELECT pg_notify('balance_changed', sqlc.arg(account_id)::uuid::text);
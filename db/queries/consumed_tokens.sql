@@ -0,0 +1,11 @@
+-- name: ConsumeToken :execrows
+INSERT INTO consumed_tokens (
+	id,
+	username,
+	purpose,
+	expires_at
+) VALUES ( $1, $2, $3, $4 )
+ON CONFLICT (id) DO NOTHING;
+
+-- name: DeleteExpiredConsumedTokens :exec
+DELETE FROM consumed_tokens WHERE expires_at < now();
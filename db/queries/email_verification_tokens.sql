@@ -0,0 +1,23 @@
+-- name: CreateEmailVerificationToken :one
+INSERT INTO email_verification_tokens (
+	username,
+	email,
+	hashed_token,
+	expires_at
+) VALUES ( $1, $2, $3, $4 )
+RETURNING *;
+
+-- name: GetEmailVerificationToken :one
+SELECT * FROM email_verification_tokens WHERE hashed_token=$1 LIMIT 1;
+
+-- name: CountRecentEmailVerificationTokens :one
+SELECT count(*) FROM email_verification_tokens
+	WHERE username=$1 AND created_at > $2;
+
+-- name: UseEmailVerificationToken :execrows
+UPDATE email_verification_tokens
+	SET used=TRUE
+	WHERE id=$1 AND NOT used AND expires_at > now();
+
+-- name: DeleteUserEmailVerificationTokens :exec
+DELETE FROM email_verification_tokens WHERE username=$1;
@@ -9,10 +9,22 @@ INSERT INTO entries (
 -- name: GetEntry :one
 SELECT * FROM entries WHERE id=$1 LIMIT 1;
 
--- name: GetAccountEntries :many
+-- name: ListOwnerEntries :many
+SELECT entries.* FROM entries
+	JOIN accounts ON accounts.id=entries.account_id
+	WHERE accounts.owner=$1
+	ORDER BY entries.created_at;
+
+-- name: ListAccountEntriesAfter :many
 SELECT * FROM entries
-	WHERE account_id=$1
-	ORDER BY id 
-	LIMIT $1 
-	OFFSET $2;
+	WHERE account_id = sqlc.arg(account_id)
+		AND (created_at, id) > (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+	ORDER BY created_at, id
+	LIMIT sqlc.arg(page_size);
 
+-- name: ListAccountEntriesBefore :many
+SELECT * FROM entries
+	WHERE account_id = sqlc.arg(account_id)
+		AND (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+	ORDER BY created_at DESC, id DESC
+	LIMIT sqlc.arg(page_size);
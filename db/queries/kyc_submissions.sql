@@ -0,0 +1,39 @@
+-- name: CreateKYCSubmission :one
+INSERT INTO kyc_submissions (
+	username,
+	document_type,
+	document_number,
+	issuing_country,
+	document_expires_at
+) VALUES ( $1, $2, $3, $4, $5 )
+RETURNING *;
+
+-- name: GetKYCSubmission :one
+SELECT * FROM kyc_submissions WHERE id=$1 LIMIT 1;
+
+-- name: ListUserKYCSubmissions :many
+SELECT * FROM kyc_submissions
+	WHERE username=$1
+	ORDER BY created_at DESC;
+
+-- name: ListPendingKYCSubmissions :many
+SELECT * FROM kyc_submissions
+	WHERE status='pending'
+	ORDER BY created_at
+	LIMIT $1
+	OFFSET $2;
+
+-- name: ReviewKYCSubmission :one
+UPDATE kyc_submissions
+	SET status=$2, review_note=$3, reviewed_by=$4, reviewed_at=now()
+	WHERE id=$1 AND status='pending'
+	RETURNING *;
+
+-- name: AnonymizeUserKYCSubmissions :exec
+UPDATE kyc_submissions
+	SET document_number='',
+		issuing_country='',
+		status=CASE WHEN status='pending' THEN 'rejected' ELSE status END,
+		review_note=CASE WHEN status='pending' THEN 'user deleted' ELSE review_note END,
+		reviewed_at=CASE WHEN status='pending' THEN now() ELSE reviewed_at END
+	WHERE username=$1;
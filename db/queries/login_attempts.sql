@@ -0,0 +1,38 @@
+-- name: CreateLoginAttempt :one
+INSERT INTO login_attempts (
+	username,
+	client_ip,
+	client_agent,
+	outcome
+) VALUES ( $1, $2, $3, $4 )
+RETURNING *;
+
+-- name: GetUsernameLoginFailures :one
+SELECT
+	count(*) AS failures,
+	COALESCE(max(created_at), '0001-01-01')::timestamptz AS last_failure_at
+FROM login_attempts
+WHERE username = sqlc.arg(username)
+	AND outcome = 'invalid_credentials'
+	AND created_at > sqlc.arg(since)
+	AND created_at > COALESCE((
+		SELECT max(created_at) FROM login_attempts
+		WHERE username = sqlc.arg(username) AND outcome = 'success'
+	), '-infinity');
+
+-- name: GetClientIPLoginFailures :one
+SELECT
+	count(*) AS failures,
+	COALESCE(max(created_at), '0001-01-01')::timestamptz AS last_failure_at
+FROM login_attempts
+WHERE client_ip = sqlc.arg(client_ip)
+	AND outcome = 'invalid_credentials'
+	AND created_at > sqlc.arg(since);
+
+-- name: DeleteUserLoginAttempts :exec
+DELETE FROM login_attempts WHERE username=$1;
+
+-- name: ListUserLoginAttempts :many
+SELECT * FROM login_attempts
+	WHERE username=$1
+	ORDER BY created_at;
@@ -0,0 +1,55 @@
+-- name: AppendOutboxEvent :one
+INSERT INTO outbox (
+	event_id,
+	event_type,
+	aggregate_type,
+	aggregate_id,
+	payload
+) VALUES (
+	$1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: ListOutboxEvents :many
+SELECT * FROM outbox
+	WHERE (transaction_id, id) > (sqlc.arg(after_transaction_id)::bigint, sqlc.arg(after_id)::bigint)
+		AND transaction_id < pg_snapshot_xmin(pg_current_snapshot())::text::bigint
+	ORDER BY transaction_id, id
+	LIMIT sqlc.arg(batch_size);
+
+-- name: GetOutboxOffset :one
+SELECT * FROM outbox_offsets WHERE consumer=$1 LIMIT 1;
+
+-- name: SetOutboxOffset :exec
+INSERT INTO outbox_offsets (
+	consumer,
+	transaction_id,
+	outbox_id
+) VALUES (
+	$1, $2, $3
+) ON CONFLICT (consumer) DO UPDATE
+	SET transaction_id=EXCLUDED.transaction_id, outbox_id=EXCLUDED.outbox_id, updated_at=now();
+
+-- name: GetOutboxHorizon :one
+SELECT pg_snapshot_xmin(pg_current_snapshot())::text::bigint AS horizon;
+
+-- name: ListAccountOutboxEvents :many
+SELECT * FROM outbox
+	WHERE aggregate_type='account'
+		AND aggregate_id = ANY(sqlc.arg(account_ids)::uuid[])
+		AND event_type = sqlc.arg(event_type)
+		AND (transaction_id, id) > (sqlc.arg(after_transaction_id)::bigint, sqlc.arg(after_id)::bigint)
+		AND transaction_id < pg_snapshot_xmin(pg_current_snapshot())::text::bigint
+	ORDER BY transaction_id, id
+	LIMIT sqlc.arg(batch_size);
+
+-- name: DeleteConsumedOutboxEvents :execrows
+DELETE FROM outbox
+	WHERE id IN (
+		SELECT id FROM outbox
+			WHERE (transaction_id, id) <= (
+				SELECT transaction_id, outbox_id FROM outbox_offsets ORDER BY transaction_id, outbox_id LIMIT 1
+			)
+				AND created_at < sqlc.arg(created_before)
+			ORDER BY transaction_id, id
+			LIMIT sqlc.arg(batch_size)
+	);
@@ -0,0 +1,24 @@
+-- name: CreatePasswordResetRequest :exec
+INSERT INTO password_reset_requests (
+	email,
+	client_ip
+) VALUES ( $1, $2 );
+
+-- name: GetEmailPasswordResetRequests :one
+SELECT
+	count(*) AS requests,
+	COALESCE(max(created_at), '0001-01-01')::timestamptz AS last_request_at
+FROM password_reset_requests
+WHERE email = sqlc.arg(email)
+	AND created_at > sqlc.arg(since);
+
+-- name: GetClientIPPasswordResetRequests :one
+SELECT
+	count(*) AS requests,
+	COALESCE(max(created_at), '0001-01-01')::timestamptz AS last_request_at
+FROM password_reset_requests
+WHERE client_ip = sqlc.arg(client_ip)
+	AND created_at > sqlc.arg(since);
+
+-- name: DeleteUserPasswordResetRequests :exec
+DELETE FROM password_reset_requests WHERE email = (SELECT email FROM users WHERE username=$1);
@@ -0,0 +1,18 @@
+-- name: CreatePasswordResetToken :one
+INSERT INTO password_reset_tokens (
+	username,
+	hashed_token,
+	expires_at
+) VALUES ( $1, $2, $3 )
+RETURNING *;
+
+-- name: GetPasswordResetToken :one
+SELECT * FROM password_reset_tokens WHERE hashed_token=$1 LIMIT 1;
+
+-- name: UsePasswordResetToken :execrows
+UPDATE password_reset_tokens
+	SET used=TRUE
+	WHERE id=$1 AND NOT used AND expires_at > now();
+
+-- name: DeleteUserPasswordResetTokens :exec
+DELETE FROM password_reset_tokens WHERE username=$1;
@@ -0,0 +1,19 @@
+-- name: CreateRecoveryCode :one
+INSERT INTO recovery_codes (
+	username,
+	hashed_code
+) VALUES ( $1, $2 )
+RETURNING *;
+
+-- name: GetUnusedRecoveryCodes :many
+SELECT * FROM recovery_codes
+	WHERE username=$1 AND NOT used
+	ORDER BY created_at;
+
+-- name: MarkRecoveryCodeUsed :execrows
+UPDATE recovery_codes
+	SET used=TRUE
+	WHERE id=$1 AND NOT used;
+
+-- name: DeleteUserRecoveryCodes :exec
+DELETE FROM recovery_codes WHERE username=$1;
@@ -12,3 +12,16 @@ RETURNING *;
 
 -- name: GetSession :one
 SELECT * FROM "sessions" WHERE id=$1 LIMIT 1;
+
+-- name: BlockUserSessions :exec
+UPDATE "sessions" SET is_blocked=TRUE WHERE username=$1;
+
+-- name: ListUserSessions :many
+SELECT * FROM "sessions"
+	WHERE username=$1
+	ORDER BY created_at;
+
+-- name: AnonymizeUserSessions :exec
+UPDATE "sessions"
+	SET is_blocked=TRUE, client_agent='', client_ip=''
+	WHERE username=$1;
@@ -0,0 +1,85 @@
+-- name: CreateTransferRequest :one
+INSERT INTO transfer_requests (
+	from_account_id,
+	to_account_id,
+	amount,
+	requested_by,
+	expires_at
+) VALUES (
+	$1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetTransferRequest :one
+SELECT * FROM transfer_requests WHERE id=$1 LIMIT 1;
+
+-- name: ListAccountTransferRequestsAfter :many
+SELECT * FROM transfer_requests
+	WHERE from_account_id = sqlc.arg(from_account_id)
+		AND (created_at, id) > (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+	ORDER BY created_at, id
+	LIMIT sqlc.arg(page_size);
+
+-- name: ListAccountTransferRequestsBefore :many
+SELECT * FROM transfer_requests
+	WHERE from_account_id = sqlc.arg(from_account_id)
+		AND (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+	ORDER BY created_at DESC, id DESC
+	LIMIT sqlc.arg(page_size);
+
+-- name: DecideTransferRequest :one
+UPDATE transfer_requests
+	SET status=sqlc.arg(status), transfer_id=sqlc.narg(transfer_id), decided_at=now()
+	WHERE id=sqlc.arg(id) AND status='pending'
+	RETURNING *;
+
+-- name: ExpireTransferRequests :execrows
+UPDATE transfer_requests
+	SET status='expired', decided_at=now()
+	WHERE status='pending' AND expires_at <= now();
+
+-- name: CreateTransferApproval :one
+INSERT INTO transfer_approvals (
+	transfer_request_id,
+	username,
+	decision,
+	note
+) VALUES (
+	$1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListTransferApprovals :many
+SELECT * FROM transfer_approvals
+	WHERE transfer_request_id=$1
+	ORDER BY created_at;
+
+-- name: GetTransferRequestForUpdate :one
+SELECT * FROM transfer_requests WHERE id=$1 LIMIT 1 FOR NO KEY UPDATE;
+
+-- name: CreateApprovalThresholdChange :one
+INSERT INTO approval_threshold_changes (
+	account_id,
+	username,
+	old_threshold,
+	new_threshold,
+	status,
+	expires_at
+) VALUES (
+	$1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetApprovalThresholdChange :one
+SELECT * FROM approval_threshold_changes WHERE id=$1 LIMIT 1;
+
+-- name: GetApprovalThresholdChangeForUpdate :one
+SELECT * FROM approval_threshold_changes WHERE id=$1 LIMIT 1 FOR NO KEY UPDATE;
+
+-- name: DecideApprovalThresholdChange :one
+UPDATE approval_threshold_changes
+	SET status=sqlc.arg(status), decided_by=sqlc.arg(decided_by), decided_at=now()
+	WHERE id=sqlc.arg(id) AND status='pending'
+	RETURNING *;
+
+-- name: ExpireApprovalThresholdChanges :execrows
+UPDATE approval_threshold_changes
+	SET status='expired', decided_at=now()
+	WHERE status='pending' AND expires_at <= now();
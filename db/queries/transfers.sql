@@ -10,3 +10,30 @@ RETURNING *;
 SELECT * FROM transfers
 	WHERE id=$1
 	LIMIT 1;
+
+-- name: ListOwnerTransfers :many
+SELECT * FROM transfers
+	WHERE from_account_id IN (SELECT id FROM accounts WHERE owner=$1)
+		OR to_account_id IN (SELECT id FROM accounts WHERE owner=$1)
+	ORDER BY created_at;
+
+-- name: ListAccountTransfersAfter :many
+SELECT * FROM transfers
+	WHERE (from_account_id = sqlc.arg(account_id) OR to_account_id = sqlc.arg(account_id))
+		AND (created_at, id) > (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+	ORDER BY created_at, id
+	LIMIT sqlc.arg(page_size);
+
+-- name: ListAccountTransfersBefore :many
+SELECT * FROM transfers
+	WHERE (from_account_id = sqlc.arg(account_id) OR to_account_id = sqlc.arg(account_id))
+		AND (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+	ORDER BY created_at DESC, id DESC
+	LIMIT sqlc.arg(page_size);
+
+-- name: ListCurrencyTransferVolumes :many
+SELECT accounts.currency, COUNT(*) AS transfers, SUM(transfers.amount)::float AS volume FROM transfers
+	JOIN accounts ON accounts.id = transfers.from_account_id
+	WHERE transfers.created_at >= sqlc.arg(period_start)::timestamptz AND transfers.created_at < sqlc.arg(period_end)::timestamptz
+	GROUP BY accounts.currency
+	ORDER BY accounts.currency;
@@ -9,3 +9,84 @@ RETURNING *;
 
 -- name: GetUser :one
 SELECT * FROM "users" WHERE username=$1 LIMIT 1;
+
+-- name: UpdateUserRole :one
+UPDATE "users"
+	SET role=$2
+	WHERE username=$1
+	RETURNING *;
+
+-- name: UpdateUserTOTPSecret :one
+UPDATE "users"
+	SET totp_secret=$2, totp_enabled=FALSE
+	WHERE username=$1
+	RETURNING *;
+
+-- name: EnableUserTOTP :one
+UPDATE "users"
+	SET totp_enabled=TRUE
+	WHERE username=$1
+	RETURNING *;
+
+-- name: AdvanceUserTOTPStep :execrows
+UPDATE "users"
+	SET totp_last_step=$2
+	WHERE username=$1 AND totp_last_step < $2;
+
+-- name: GetUserRole :one
+SELECT role FROM "users" WHERE username=$1 LIMIT 1;
+
+-- name: GetUserPasswordChangedAt :one
+SELECT password_changed_at FROM "users" WHERE username=$1 LIMIT 1;
+
+-- name: UpdateUserPassword :one
+UPDATE "users"
+	SET hashed_password=$2, password_changed_at=$3
+	WHERE username=$1
+	RETURNING *;
+
+-- name: GetUserByEmail :one
+SELECT * FROM "users" WHERE email=$1 LIMIT 1;
+
+-- name: SetUserEmailVerified :one
+UPDATE "users"
+	SET email_verified_at=now()
+	WHERE username=$1 AND email=$2
+	RETURNING *;
+
+-- name: UpdateUserProfile :one
+UPDATE "users"
+	SET
+		full_name=COALESCE(sqlc.narg(full_name), full_name),
+		email=COALESCE(sqlc.narg(email), email),
+		email_verified_at=CASE
+			WHEN sqlc.narg(email) IS NULL OR sqlc.narg(email)=email THEN email_verified_at
+			ELSE NULL
+		END
+	WHERE username=sqlc.arg(username)
+	RETURNING *;
+
+-- name: UpdateUserPasswordHash :exec
+UPDATE "users"
+	SET hashed_password=sqlc.arg(new_hash)
+	WHERE username=sqlc.arg(username) AND hashed_password=sqlc.arg(old_hash);
+
+-- name: AnonymizeUser :one
+UPDATE "users"
+	SET
+		full_name='Deleted user',
+		email=username || '@deleted.invalid',
+		hashed_password='',
+		password_changed_at=sqlc.arg(deleted_at),
+		totp_secret='',
+		totp_enabled=FALSE,
+		email_verified_at=NULL,
+		deleted_at=sqlc.arg(deleted_at)
+	WHERE username=sqlc.arg(username) AND deleted_at IS NULL
+	RETURNING *;
+
+-- name: UpdateUserKYCStatus :one
+UPDATE "users"
+	SET kyc_status=$2
+	WHERE username=$1
+	RETURNING *;
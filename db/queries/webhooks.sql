@@ -0,0 +1,78 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks (
+	username,
+	url,
+	secret,
+	event_types
+) VALUES (
+	$1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetWebhook :one
+SELECT * FROM webhooks WHERE id=$1 LIMIT 1;
+
+-- name: GetUserWebhook :one
+SELECT * FROM webhooks WHERE id=$1 AND username=$2 LIMIT 1;
+
+-- name: ListUserWebhooks :many
+SELECT * FROM webhooks
+	WHERE username=$1
+	ORDER BY created_at;
+
+-- name: DeleteWebhook :one
+DELETE FROM webhooks
+	WHERE id=$1 AND username=$2
+	RETURNING *;
+
+-- name: DeleteUserWebhooks :exec
+DELETE FROM webhooks WHERE username=$1;
+
+-- name: CreateAccountWebhookDeliveries :execrows
+INSERT INTO webhook_deliveries (
+	webhook_id,
+	event_id,
+	event_type,
+	payload
+) SELECT webhooks.id, sqlc.arg(event_id)::uuid, sqlc.arg(event_type)::varchar, sqlc.arg(payload)::jsonb
+	FROM webhooks
+	JOIN account_holders ON account_holders.username=webhooks.username
+	WHERE account_holders.account_id = sqlc.arg(account_id)
+		AND account_holders.accepted_at IS NOT NULL
+		AND sqlc.arg(event_type)::varchar = ANY(webhooks.event_types)
+	ON CONFLICT (webhook_id, event_id) DO NOTHING;
+
+-- name: ClaimWebhookDeliveries :many
+UPDATE webhook_deliveries
+	SET attempts=attempts+1, next_attempt_at=sqlc.arg(lease_until)
+	WHERE id IN (
+		SELECT id FROM webhook_deliveries
+			WHERE status='pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at
+			LIMIT sqlc.arg(batch_size)
+			FOR UPDATE SKIP LOCKED
+	)
+	RETURNING *;
+
+-- name: MarkWebhookDelivered :exec
+UPDATE webhook_deliveries
+	SET status='delivered', response_code=$2, last_error=NULL, delivered_at=now()
+	WHERE id=$1;
+
+-- name: MarkWebhookDeliveryFailed :exec
+UPDATE webhook_deliveries
+	SET status=$2, response_code=$3, last_error=$4, next_attempt_at=$5
+	WHERE id=$1;
+
+-- name: ListWebhookDeliveriesAfter :many
+SELECT * FROM webhook_deliveries
+	WHERE webhook_id = sqlc.arg(webhook_id)
+		AND (created_at, id) > (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+	ORDER BY created_at, id
+	LIMIT sqlc.arg(page_size);
+
+-- name: ListWebhookDeliveriesBefore :many
+SELECT * FROM webhook_deliveries
+	WHERE webhook_id = sqlc.arg(webhook_id)
+		AND (created_at, id) < (sqlc.arg(cursor_created_at)::timestamptz, sqlc.arg(cursor_id)::uuid)
+	ORDER BY created_at DESC, id DESC
+	LIMIT sqlc.arg(page_size);
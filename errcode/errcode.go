@@ -0,0 +1,54 @@
+package errcode
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/julianinsua/the_simp_bank/auth"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/policy"
+)
+
+// Typed store, policy and login errors with the status they are answered with, whatever the handler fell back to.
+// Both the HTTP and the gRPC servers map errors through here, gRPC translating the HTTP status to its own code.
+// Checked in order and wrapped errors are matched too, so an error wrapping several known ones always gets the same
+// status.
+var statuses = []struct {
+	err    error
+	status int
+}{
+	{policy.ErrAccountNotFound, http.StatusNotFound},
+	{policy.ErrCurrencyMismatch, http.StatusUnprocessableEntity},
+	{policy.ErrAccessDenied, http.StatusForbidden},
+	{policy.ErrGrantViewOnly, http.StatusForbidden},
+	{policy.ErrGrantLimitExceeded, http.StatusForbidden},
+	{policy.ErrGrantApproveMissing, http.StatusForbidden},
+	{policy.ErrEmailNotVerified, http.StatusForbidden},
+	{policy.ErrKYCRequired, http.StatusForbidden},
+	{policy.ErrKYCBalanceLimit, http.StatusForbidden},
+	{policy.ErrInvalidParent, http.StatusBadRequest},
+	{policy.ErrNotOwnPot, http.StatusUnprocessableEntity},
+	{policy.ErrAccountShared, http.StatusUnprocessableEntity},
+
+	{auth.ErrInvalidCredentials, http.StatusUnauthorized},
+	{auth.ErrLoginThrottled, http.StatusTooManyRequests},
+	{auth.ErrTokenRevoked, http.StatusUnauthorized},
+
+	{database.ErrInsufficientFunds, http.StatusUnprocessableEntity},
+	{database.ErrCurrencyMismatch, http.StatusUnprocessableEntity},
+	{database.ErrAccountClosed, http.StatusUnprocessableEntity},
+	{database.ErrAccountFrozen, http.StatusUnprocessableEntity},
+	{database.ErrForbidden, http.StatusForbidden},
+	{database.ErrGrantLimitExceeded, http.StatusForbidden},
+	{database.ErrThresholdLowered, http.StatusForbidden},
+}
+
+// Returns the status a typed error is answered with, ok is false for errors of no known kind
+func Status(err error) (status int, ok bool) {
+	for _, known := range statuses {
+		if errors.Is(err, known.err) {
+			return known.status, true
+		}
+	}
+	return 0, false
+}
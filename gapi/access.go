@@ -0,0 +1,12 @@
+package gapi
+
+import (
+	"github.com/julianinsua/the_simp_bank/policy"
+)
+
+/*
+The account and transfer rules, shared with the HTTP API
+*/
+func (s *Server) policy() policy.Policy {
+	return policy.New(s.config, s.store)
+}
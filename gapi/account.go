@@ -0,0 +1,139 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/pb"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errAccountNameTaken = errors.New("an account with this name already exists")
+
+func (s *Server) CreateAccount(ctx context.Context, req *pb.CreateAccountRequest) (*pb.CreateAccountResponse, error) {
+	if err := validateCurrency(req.GetCurrency()); err != nil {
+		return nil, invalidArgumentError("currency", err)
+	}
+	if len(req.GetName()) > 64 {
+		return nil, invalidArgumentError("name", fmt.Errorf("must be at most 64 characters"))
+	}
+
+	username := authPayload(ctx).Username
+	err := s.policy().AccountCreationAllowed(ctx, username)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	params := database.CreateAccountParams{
+		Owner:    username,
+		Currency: req.GetCurrency(),
+		Name:     req.GetName(),
+	}
+	if params.Name == "" {
+		params.Name = req.GetCurrency()
+	}
+
+	if req.GetParentId() != "" {
+		parentID, err := parseUUID("parent_id", req.GetParentId())
+		if err != nil {
+			return nil, err
+		}
+		err = s.policy().ValidParentAccount(ctx, parentID, username, params.Currency)
+		if err != nil {
+			return nil, statusError(err)
+		}
+		params.ParentID = uuid.NullUUID{UUID: parentID, Valid: true}
+	}
+
+	acc, err := s.store.CreateAccountTx(ctx, params)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				return nil, status.Error(codes.AlreadyExists, errAccountNameTaken.Error())
+			case "foreign_key_violation":
+				return nil, status.Error(codes.PermissionDenied, "forbidden")
+			}
+		}
+		return nil, statusError(err)
+	}
+
+	return &pb.CreateAccountResponse{Account: convertAccount(acc)}, nil
+}
+
+func (s *Server) GetAccount(ctx context.Context, req *pb.GetAccountRequest) (*pb.GetAccountResponse, error) {
+	accID, err := parseUUID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	acc, err := s.store.GetAccount(ctx, accID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Error(codes.NotFound, "account not found")
+		}
+		return nil, statusError(err)
+	}
+
+	err = statusError(s.policy().ViewAllowed(ctx, acc.ID, authPayload(ctx).Username))
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetAccountResponse{Account: convertAccount(acc)}, nil
+}
+
+func (s *Server) ListAccounts(ctx context.Context, req *pb.ListAccountsRequest) (*pb.ListAccountsResponse, error) {
+	cur, err := util.DecodePageCursor(req.GetCursor())
+	if err != nil {
+		return nil, invalidArgumentError("cursor", err)
+	}
+	size, err := util.PageSize(req.GetSize(), s.config.PageDefaultSize, s.config.PageMaxSize)
+	if err != nil {
+		return nil, invalidArgumentError("size", err)
+	}
+
+	username := authPayload(ctx).Username
+	var accs []database.Account
+	if cur.Before {
+		accs, err = s.store.ListHolderAccountsBefore(ctx, database.ListHolderAccountsBeforeParams{
+			Username:        username,
+			CursorCreatedAt: cur.CreatedAt,
+			CursorID:        cur.ID,
+			PageSize:        size + 1,
+		})
+	} else {
+		accs, err = s.store.ListHolderAccountsAfter(ctx, database.ListHolderAccountsAfterParams{
+			Username:        username,
+			CursorCreatedAt: cur.CreatedAt,
+			CursorID:        cur.ID,
+			PageSize:        size + 1,
+		})
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	accs, next, prev := util.Paginate(accs, size, cur, func(acc database.Account) util.PageCursor {
+		return util.PageCursor{CreatedAt: acc.CreatedAt, ID: acc.ID}
+	})
+	res := &pb.ListAccountsResponse{}
+	for _, acc := range accs {
+		res.Accounts = append(res.Accounts, convertAccount(acc))
+	}
+	if next != nil {
+		res.NextCursor = next.Encode()
+	}
+	if prev != nil {
+		res.PrevCursor = prev.Encode()
+	}
+	return res, nil
+}
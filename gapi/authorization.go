@@ -0,0 +1,97 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/julianinsua/the_simp_bank/auth"
+	"github.com/julianinsua/the_simp_bank/pb"
+	"github.com/julianinsua/the_simp_bank/token"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	authorizationHeaderKey  = "authorization"
+	authorizationTypeBearer = "bearer"
+)
+
+type authorizationPayloadKey struct{}
+
+// RPCs that can be called without an access token
+var publicMethods = map[string]bool{
+	pb.SimpleBank_CreateUser_FullMethodName:       true,
+	pb.SimpleBank_LoginUser_FullMethodName:        true,
+	pb.SimpleBank_RenewAccessToken_FullMethodName: true,
+}
+
+/*
+Interceptor equivalent to the HTTP authMiddleware: checks the access token sent in the authorization metadata and
+stores its payload in the context of every RPC but the public ones
+*/
+func (s *Server) authInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if publicMethods[info.FullMethod] {
+		return handler(ctx, req)
+	}
+
+	payload, err := s.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	logUsername(ctx, payload.Username)
+
+	return handler(context.WithValue(ctx, authorizationPayloadKey{}, payload), req)
+}
+
+func (s *Server) authorizeUser(ctx context.Context) (*token.PASETOPayload, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing metadata")
+	}
+
+	values := md.Get(authorizationHeaderKey)
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "authorization header not provided")
+	}
+
+	fields := strings.Fields(values[0])
+	if len(fields) < 2 {
+		return nil, status.Error(codes.Unauthenticated, "Access token is malformed")
+	}
+
+	authorizationType := strings.ToLower(fields[0])
+	if authorizationType != authorizationTypeBearer {
+		return nil, status.Errorf(codes.Unauthenticated, "authorization type %s unsupported", authorizationType)
+	}
+
+	payload, err := s.tokenMaker.VerifyToken(fields[1])
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	// Purpose tokens (e.g. MFA challenges) are not access tokens
+	if payload.Purpose != "" {
+		return nil, status.Error(codes.Unauthenticated, token.ErrInvalidToken.Error())
+	}
+
+	// Changing the password revokes every token issued before it
+	err = s.auth().CheckNotRevoked(ctx, payload)
+	if err != nil {
+		if errors.Is(err, token.ErrInvalidToken) || errors.Is(err, auth.ErrTokenRevoked) {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return nil, statusError(err)
+	}
+
+	return payload, nil
+}
+
+/*
+Returns the payload the interceptor stored for an authenticated RPC
+*/
+func authPayload(ctx context.Context) *token.PASETOPayload {
+	return ctx.Value(authorizationPayloadKey{}).(*token.PASETOPayload)
+}
@@ -0,0 +1,128 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/pb"
+	"github.com/julianinsua/the_simp_bank/token"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthInterceptor(t *testing.T) {
+	username := util.RandomOwner()
+
+	testCases := []struct {
+		name       string
+		method     string
+		buildCtx   func(t *testing.T, tokenMaker token.PASETOMaker) context.Context
+		buildStubs func(store *mock_db.MockStore)
+		code       codes.Code
+	}{
+		{
+			name:   "OK",
+			method: pb.SimpleBank_GetCurrentUser_FullMethodName,
+			buildCtx: func(t *testing.T, tokenMaker token.PASETOMaker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, username)
+			},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUserPasswordChangedAt(gomock.Any(), gomock.Eq(username)).Times(1).Return(time.Time{}, nil)
+			},
+			code: codes.OK,
+		},
+		{
+			name:   "PublicMethod",
+			method: pb.SimpleBank_LoginUser_FullMethodName,
+			buildCtx: func(t *testing.T, tokenMaker token.PASETOMaker) context.Context {
+				return context.Background()
+			},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUserPasswordChangedAt(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codes.OK,
+		},
+		{
+			name:   "NoAuthorization",
+			method: pb.SimpleBank_GetCurrentUser_FullMethodName,
+			buildCtx: func(t *testing.T, tokenMaker token.PASETOMaker) context.Context {
+				return metadata.NewIncomingContext(context.Background(), metadata.MD{})
+			},
+			buildStubs: func(store *mock_db.MockStore) {},
+			code:       codes.Unauthenticated,
+		},
+		{
+			name:   "UnsupportedAuthorization",
+			method: pb.SimpleBank_GetCurrentUser_FullMethodName,
+			buildCtx: func(t *testing.T, tokenMaker token.PASETOMaker) context.Context {
+				md := metadata.Pairs(authorizationHeaderKey, "basic abc")
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			buildStubs: func(store *mock_db.MockStore) {},
+			code:       codes.Unauthenticated,
+		},
+		{
+			name:   "PurposeToken",
+			method: pb.SimpleBank_GetCurrentUser_FullMethodName,
+			buildCtx: func(t *testing.T, tokenMaker token.PASETOMaker) context.Context {
+				challenge, _, err := tokenMaker.CreatePurposeToken(username, token.PurposeMFAChallenge, "", time.Minute)
+				require.NoError(t, err)
+				md := metadata.Pairs(authorizationHeaderKey, authorizationTypeBearer+" "+challenge)
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			buildStubs: func(store *mock_db.MockStore) {},
+			code:       codes.Unauthenticated,
+		},
+		{
+			name:   "RevokedByPasswordChange",
+			method: pb.SimpleBank_GetCurrentUser_FullMethodName,
+			buildCtx: func(t *testing.T, tokenMaker token.PASETOMaker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, username)
+			},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUserPasswordChangedAt(gomock.Any(), gomock.Any()).Times(1).Return(time.Now().Add(time.Minute), nil)
+			},
+			code: codes.Unauthenticated,
+		},
+		{
+			name:   "DeletedUser",
+			method: pb.SimpleBank_GetCurrentUser_FullMethodName,
+			buildCtx: func(t *testing.T, tokenMaker token.PASETOMaker) context.Context {
+				return newContextWithBearerToken(t, tokenMaker, username)
+			},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUserPasswordChangedAt(gomock.Any(), gomock.Any()).Times(1).Return(time.Time{}, sql.ErrNoRows)
+			},
+			code: codes.Unauthenticated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			info := &grpc.UnaryServerInfo{FullMethod: tc.method}
+
+			_, err := server.authInterceptor(tc.buildCtx(t, server.tokenMaker), nil, info, func(ctx context.Context, _ any) (any, error) {
+				if !publicMethods[tc.method] {
+					require.Equal(t, username, authPayload(ctx).Username)
+				}
+				return nil, nil
+			})
+			require.Equal(t, tc.code, status.Code(err))
+		})
+	}
+}
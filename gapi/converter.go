@@ -0,0 +1,57 @@
+package gapi
+
+import (
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Same fields as the HTTP userResponse, credentials never leave the server
+func convertUser(user database.User) *pb.User {
+	return &pb.User{
+		Username:          user.Username,
+		FullName:          user.FullName,
+		Email:             user.Email,
+		Role:              user.Role,
+		TwoFactorEnabled:  user.TotpEnabled,
+		EmailVerified:     user.EmailVerifiedAt.Valid,
+		KycStatus:         user.KycStatus,
+		PasswordChangedAt: timestamppb.New(user.PasswordChangedAt),
+		CreatedAt:         timestamppb.New(user.CreatedAt),
+	}
+}
+
+func convertAccount(acc database.Account) *pb.Account {
+	res := &pb.Account{
+		Id:                acc.ID.String(),
+		Owner:             acc.Owner,
+		Name:              acc.Name,
+		Balance:           acc.Balance,
+		Currency:          acc.Currency,
+		ApprovalThreshold: acc.ApprovalThreshold,
+		CreatedAt:         timestamppb.New(acc.CreatedAt),
+	}
+	if acc.ParentID.Valid {
+		res.ParentId = acc.ParentID.UUID.String()
+	}
+	return res
+}
+
+func convertTransfer(transfer database.Transfer) *pb.Transfer {
+	return &pb.Transfer{
+		Id:            transfer.ID.String(),
+		FromAccountId: transfer.FromAccountID.String(),
+		ToAccountId:   transfer.ToAccountID.String(),
+		Amount:        transfer.Amount,
+		CreatedAt:     timestamppb.New(transfer.CreatedAt),
+	}
+}
+
+func convertEntry(entry database.Entry) *pb.Entry {
+	return &pb.Entry{
+		Id:        entry.ID.String(),
+		AccountId: entry.AccountID.String(),
+		Amount:    entry.Amount,
+		CreatedAt: timestamppb.New(entry.CreatedAt),
+	}
+}
@@ -0,0 +1,84 @@
+package gapi
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/julianinsua/the_simp_bank/errcode"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const internalErrorMessage = "internal server error"
+
+/*
+Error of an RPC that failed on the server side. Clients only get a generic message, the cause is kept for the access log
+like the HTTP API does.
+*/
+type internalError struct {
+	cause error
+}
+
+func (e internalError) Error() string {
+	return e.cause.Error()
+}
+
+func (e internalError) Unwrap() error {
+	return e.cause
+}
+
+func (e internalError) GRPCStatus() *status.Status {
+	return status.New(codes.Internal, internalErrorMessage)
+}
+
+/*
+Maps an error to a gRPC status through the same table the HTTP API answers with. Errors of no known kind mean the RPC
+couldn't run, so their message never reaches the client.
+*/
+func statusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	httpStatus, ok := errcode.Status(err)
+	if !ok {
+		return internalError{cause: err}
+	}
+	return status.Error(grpcCode(httpStatus), err.Error())
+}
+
+/*
+The gRPC code equivalent to an HTTP status
+*/
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusUnprocessableEntity:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	}
+	if httpStatus >= http.StatusInternalServerError {
+		return codes.Internal
+	}
+	return codes.InvalidArgument
+}
+
+/*
+The message the access log records for an RPC error, the cause for internal errors
+*/
+func logErrorMessage(err error) string {
+	var internal internalError
+	if errors.As(err, &internal) {
+		return internal.cause.Error()
+	}
+	return status.Convert(err).Message()
+}
@@ -0,0 +1,62 @@
+package gapi
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/julianinsua/the_simp_bank/auth"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/policy"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatusError(t *testing.T) {
+	testCases := []struct {
+		name    string
+		err     error
+		code    codes.Code
+		message string
+	}{
+		{
+			name:    "PolicyError",
+			err:     fmt.Errorf("%w (%v)", policy.ErrGrantLimitExceeded, 10),
+			code:    codes.PermissionDenied,
+			message: "transfer exceeds the limit of the grant (10)",
+		},
+		{
+			name:    "StoreError",
+			err:     fmt.Errorf("unable to execute transaction: %w", database.ErrInsufficientFunds),
+			code:    codes.FailedPrecondition,
+			message: "unable to execute transaction: insufficient funds",
+		},
+		{
+			name:    "LoginThrottled",
+			err:     fmt.Errorf("%w, retry in 1s", auth.ErrLoginThrottled),
+			code:    codes.ResourceExhausted,
+			message: "too many failed login attempts, retry in 1s",
+		},
+		{
+			name:    "DriverError",
+			err:     &pq.Error{Code: "08006", Message: "connection to server lost"},
+			code:    codes.Internal,
+			message: internalErrorMessage,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := statusError(tc.err)
+			st, ok := status.FromError(err)
+			require.True(t, ok)
+			require.Equal(t, tc.code, st.Code())
+			require.Equal(t, tc.message, st.Message())
+			// The access log still gets the cause
+			require.Contains(t, logErrorMessage(err), tc.err.Error())
+		})
+	}
+
+	require.NoError(t, statusError(nil))
+}
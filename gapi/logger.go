@@ -0,0 +1,80 @@
+package gapi
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/julianinsua/the_simp_bank/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type accessLogKey struct{}
+
+/*
+Details of an RPC only known further down the interceptor chain, filled in as they become available
+*/
+type accessLogEntry struct {
+	username string
+}
+
+/*
+Interceptor equivalent to the HTTP request ID and access log middlewares: tags the RPC with the x-request-id sent by the
+client, or a generated one, sends it back in the response headers and logs the RPC once it's handled
+*/
+func (s *Server) loggerInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+
+	var requestID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(strings.ToLower(logging.RequestIDHeader)); len(values) > 0 {
+			requestID = values[0]
+		}
+	}
+	if !logging.ValidRequestID(requestID) {
+		requestID = logging.NewRequestID()
+	}
+	ctx = logging.WithRequestID(ctx, requestID)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(logging.RequestIDHeader, requestID))
+
+	entry := &accessLogEntry{}
+	res, err := handler(context.WithValue(ctx, accessLogKey{}, entry), req)
+
+	code := status.Code(err)
+	attrs := []slog.Attr{
+		slog.String("method", info.FullMethod),
+		slog.String("status", code.String()),
+		slog.Float64("latencyMs", float64(time.Since(start).Microseconds())/1000),
+		slog.String("clientIp", extractMetadata(ctx).ClientIP),
+	}
+	if entry.username != "" {
+		attrs = append(attrs, slog.String("username", entry.username))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", logErrorMessage(err)))
+	}
+
+	level := slog.LevelWarn
+	switch code {
+	case codes.OK:
+		level = slog.LevelInfo
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		level = slog.LevelError
+	}
+	slog.Default().LogAttrs(ctx, level, "rpc", attrs...)
+
+	return res, err
+}
+
+/*
+Records the authenticated user on the access log entry of the RPC
+*/
+func logUsername(ctx context.Context, username string) {
+	if entry, ok := ctx.Value(accessLogKey{}).(*accessLogEntry); ok {
+		entry.username = username
+	}
+}
@@ -0,0 +1,55 @@
+package gapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/logging"
+	"github.com/julianinsua/the_simp_bank/pb"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestLoggerInterceptor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	username := util.RandomOwner()
+	store := mock_db.NewMockStore(ctrl)
+	store.EXPECT().GetUserPasswordChangedAt(gomock.Any(), gomock.Eq(username)).Times(1).Return(time.Time{}, nil)
+	server := newTestServer(t, store)
+
+	var logs bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(logging.NewHandler(&logs, slog.LevelInfo)))
+	defer slog.SetDefault(defaultLogger)
+
+	ctx := newContextWithBearerToken(t, server.tokenMaker, username)
+	md, _ := metadata.FromIncomingContext(ctx)
+	md.Set(logging.RequestIDHeader, "rpc-request-1")
+	info := &grpc.UnaryServerInfo{FullMethod: pb.SimpleBank_GetCurrentUser_FullMethodName}
+
+	_, err := server.loggerInterceptor(metadata.NewIncomingContext(ctx, md), nil, info, func(ctx context.Context, req any) (any, error) {
+		return server.authInterceptor(ctx, req, info, func(ctx context.Context, _ any) (any, error) {
+			require.Equal(t, "rpc-request-1", logging.RequestID(ctx))
+			return nil, nil
+		})
+	})
+	require.NoError(t, err)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+	require.Equal(t, "rpc", entry["msg"])
+	require.Equal(t, "rpc-request-1", entry["requestId"])
+	require.Equal(t, username, entry["username"])
+	require.Equal(t, pb.SimpleBank_GetCurrentUser_FullMethodName, entry["method"])
+	require.Equal(t, "OK", entry["status"])
+}
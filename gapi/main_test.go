@@ -0,0 +1,100 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/token"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+// Cheap argon2id parameters so hashing doesn't dominate the test run
+var testPasswordHasher = util.PasswordHasher{
+	Algorithm:     util.Argon2idAlgorithm,
+	Argon2Time:    1,
+	Argon2Memory:  1024,
+	Argon2Threads: 1,
+	Argon2KeyLen:  32,
+	Argon2SaltLen: 16,
+}
+
+func newTestServer(t *testing.T, store database.Store) *Server {
+	config := util.Config{
+		SymetricKey:           util.RandomString(33),
+		TokenDuration:         time.Minute,
+		RefreshTokenDuration:  time.Hour,
+		LoginAttemptWindow:    time.Hour,
+		LoginMaxAttempts:      5,
+		LoginMaxIPAttempts:    50,
+		LoginBackoffBase:      time.Second,
+		LoginLockoutDuration:  15 * time.Minute,
+		StepUpThreshold:       1000,
+		PageDefaultSize:       10,
+		PageMaxSize:           50,
+		PasswordMinLength:     6,
+		PasswordHashAlgorithm: testPasswordHasher.Algorithm,
+		PasswordArgon2Time:    testPasswordHasher.Argon2Time,
+		PasswordArgon2Memory:  testPasswordHasher.Argon2Memory,
+		PasswordArgon2Threads: testPasswordHasher.Argon2Threads,
+	}
+
+	tokenMaker, err := token.NewPASETOMaker(config.SymetricKey)
+	require.NoError(t, err)
+
+	server, err := NewServer(config, store, tokenMaker, nil)
+	require.NoError(t, err)
+
+	return server
+}
+
+func randomUser(t *testing.T) (user database.User, password string) {
+	password = util.RandomString(8)
+	hashedPassword, err := testPasswordHasher.Hash(password)
+	require.NoError(t, err)
+
+	user = database.User{
+		Username:       util.RandomOwner(),
+		HashedPassword: hashedPassword,
+		FullName:       util.RandomOwner(),
+		Email:          util.RandomEmail(),
+	}
+	return
+}
+
+func randomAccount(owner string) database.Account {
+	return database.Account{
+		ID:       uuid.New(),
+		Owner:    owner,
+		Balance:  util.RandomMoney(),
+		Currency: util.RandomCurrency(),
+	}
+}
+
+/*
+Builds the incoming context of an RPC sent with an access token for the user
+*/
+func newContextWithBearerToken(t *testing.T, tokenMaker token.PASETOMaker, username string) context.Context {
+	accessToken, _, err := tokenMaker.CreateToken(username, util.CustomerRole, time.Minute)
+	require.NoError(t, err)
+
+	md := metadata.MD{
+		authorizationHeaderKey: []string{fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken)},
+	}
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+/*
+Builds the context an authenticated RPC handler gets from the auth interceptor
+*/
+func newAuthorizedContext(t *testing.T, tokenMaker token.PASETOMaker, username string) context.Context {
+	_, payload, err := tokenMaker.CreateToken(username, util.CustomerRole, time.Minute)
+	require.NoError(t, err)
+
+	return context.WithValue(context.Background(), authorizationPayloadKey{}, payload)
+}
@@ -0,0 +1,47 @@
+package gapi
+
+import (
+	"context"
+	"net"
+
+	"github.com/julianinsua/the_simp_bank/auth"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const userAgentHeaderKey = "user-agent"
+
+/*
+Client information the HTTP handlers read from the request, stored with sessions and login attempts
+*/
+type clientMetadata struct {
+	UserAgent string
+	ClientIP  string
+}
+
+func extractMetadata(ctx context.Context) clientMetadata {
+	var mtdt clientMetadata
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if userAgents := md.Get(userAgentHeaderKey); len(userAgents) > 0 {
+			mtdt.UserAgent = userAgents[0]
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+		mtdt.ClientIP = host
+	}
+
+	return mtdt
+}
+
+/*
+The client as the login audit trail records it
+*/
+func (mtdt clientMetadata) client() auth.Client {
+	return auth.Client{IP: mtdt.ClientIP, Agent: mtdt.UserAgent}
+}
@@ -0,0 +1,70 @@
+package gapi
+
+import (
+	"net"
+
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/mail"
+	"github.com/julianinsua/the_simp_bank/pb"
+	"github.com/julianinsua/the_simp_bank/token"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+/* Struct to generate the gRPC server, it shares the store and the token maker with the HTTP server */
+type Server struct {
+	pb.UnimplementedSimpleBankServer
+	store          database.Store
+	tokenMaker     token.PASETOMaker
+	mailer         mail.Mailer
+	passwordPolicy util.PasswordPolicy
+	passwordHasher util.PasswordHasher
+	dummyHash      string
+	config         util.Config
+}
+
+/* Create a new gRPC server struct */
+func NewServer(config util.Config, store database.Store, tokenMaker token.PASETOMaker, mailer mail.Mailer) (*Server, error) {
+	passwordPolicy, err := util.NewPasswordPolicy(config)
+	if err != nil {
+		return nil, errors.Errorf("couldn't load the password policy: %v", err)
+	}
+	passwordHasher, err := util.NewPasswordHasher(config)
+	if err != nil {
+		return nil, errors.Errorf("couldn't initialize the password hasher: %v", err)
+	}
+	// Hashed once so logins for unknown users cost the same as real ones
+	dummyHash, err := passwordHasher.Hash(util.RandomString(16))
+	if err != nil {
+		return nil, errors.Errorf("couldn't hash the dummy password: %v", err)
+	}
+
+	return &Server{
+		store:          store,
+		tokenMaker:     tokenMaker,
+		mailer:         mailer,
+		passwordPolicy: passwordPolicy,
+		passwordHasher: passwordHasher,
+		dummyHash:      dummyHash,
+		config:         config,
+	}, nil
+}
+
+/*
+Starts the gRPC server on a specific address
+*/
+func (s *Server) Start(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.Errorf("couldn't listen on %s: %v", addr, err)
+	}
+
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(s.loggerInterceptor, s.authInterceptor))
+	pb.RegisterSimpleBankServer(grpcServer, s)
+	// Lets tools like grpcurl discover the service
+	reflection.Register(grpcServer)
+
+	return grpcServer.Serve(listener)
+}
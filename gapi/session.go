@@ -0,0 +1,170 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/julianinsua/the_simp_bank/auth"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/pb"
+	"github.com/julianinsua/the_simp_bank/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var errMFARequired = errors.New("two factor authentication is enabled, log in through the HTTP API")
+
+func (s *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (*pb.LoginUserResponse, error) {
+	if err := validateUsername(req.GetUsername()); err != nil {
+		return nil, invalidArgumentError("username", err)
+	}
+	if err := validateRequired(req.GetPassword()); err != nil {
+		return nil, invalidArgumentError("password", err)
+	}
+
+	mtdt := extractMetadata(ctx)
+
+	// Throttle repeated failures for this username or client IP
+	err := s.allowLoginAttempt(ctx, req.GetUsername(), mtdt)
+	if err != nil {
+		return nil, err
+	}
+
+	usr, err := s.store.GetUser(ctx, req.GetUsername())
+	if err != nil && err != sql.ErrNoRows {
+		return nil, statusError(err)
+	}
+	// validate password, deleted users can't log in anymore
+	if err == sql.ErrNoRows || usr.DeletedAt.Valid {
+		_ = util.CheckPassword(req.GetPassword(), s.dummyHash)
+		err = auth.ErrInvalidCredentials
+	} else {
+		err = util.CheckPassword(req.GetPassword(), usr.HashedPassword)
+	}
+	if err != nil {
+		err = s.auth().RecordLoginAttempt(ctx, req.GetUsername(), auth.OutcomeInvalidCredentials, mtdt.client())
+		if err != nil {
+			return nil, statusError(err)
+		}
+		return nil, status.Error(codes.Unauthenticated, auth.ErrInvalidCredentials.Error())
+	}
+
+	// There is no MFA challenge over gRPC, the password alone must not be enough
+	if usr.TotpEnabled {
+		return nil, status.Error(codes.FailedPrecondition, errMFARequired.Error())
+	}
+
+	// The plain password is only available now, upgrade hashes made with older parameters
+	err = s.auth().RehashPassword(ctx, usr, req.GetPassword())
+	if err != nil {
+		slog.ErrorContext(ctx, "unable to upgrade the password hash", "username", usr.Username, "error", err)
+	}
+
+	err = s.auth().RecordLoginAttempt(ctx, req.GetUsername(), auth.OutcomeSuccess, mtdt.client())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	accessToken, accessPayload, err := s.tokenMaker.CreateToken(usr.Username, usr.Role, s.config.TokenDuration)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	refreshToken, refreshPayload, err := s.tokenMaker.CreateToken(usr.Username, usr.Role, s.config.RefreshTokenDuration)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	session, err := s.store.CreateSessionTx(ctx, database.CreateSessionParams{
+		ID:           refreshPayload.ID,
+		Username:     usr.Username,
+		RefreshToken: refreshToken,
+		ClientAgent:  mtdt.UserAgent,
+		ClientIp:     mtdt.ClientIP,
+		ExpiresAt:    refreshPayload.ExpiresAt,
+		CreatedAt:    refreshPayload.IssuedAt,
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &pb.LoginUserResponse{
+		SessionId:             session.ID.String(),
+		User:                  convertUser(usr),
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  timestamppb.New(accessPayload.ExpiresAt),
+		RefreshToken:          session.RefreshToken,
+		RefreshTokenExpiresAt: timestamppb.New(session.ExpiresAt),
+	}, nil
+}
+
+/*
+The login rules, shared with the HTTP API
+*/
+func (s *Server) auth() auth.Authenticator {
+	return auth.New(s.config, s.store, s.mailer, s.passwordHasher)
+}
+
+/*
+Returns a resource exhausted error and records the throttled attempt when the username or client IP has to wait before
+trying again, the same backoff the HTTP login applies
+*/
+func (s *Server) allowLoginAttempt(ctx context.Context, username string, mtdt clientMetadata) error {
+	_, err := s.auth().AllowLoginAttempt(ctx, username, mtdt.client())
+	return statusError(err)
+}
+
+func (s *Server) RenewAccessToken(ctx context.Context, req *pb.RenewAccessTokenRequest) (*pb.RenewAccessTokenResponse, error) {
+	if err := validateRequired(req.GetRefreshToken()); err != nil {
+		return nil, invalidArgumentError("refresh_token", err)
+	}
+
+	payload, err := s.tokenMaker.VerifyToken(req.GetRefreshToken())
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	ssn, err := s.store.GetSession(ctx, payload.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Error(codes.NotFound, "session not found")
+		}
+		return nil, statusError(err)
+	}
+
+	if ssn.IsBlocked {
+		return nil, status.Error(codes.Unauthenticated, "Session blocked")
+	}
+	if ssn.Username != payload.Username {
+		return nil, status.Error(codes.Unauthenticated, "incorrect session user")
+	}
+	if req.GetRefreshToken() != ssn.RefreshToken {
+		return nil, status.Error(codes.Unauthenticated, "unmatching token")
+	}
+	if time.Now().After(ssn.ExpiresAt) {
+		return nil, status.Error(codes.Unauthenticated, "expired token")
+	}
+
+	// Sessions created before the last password change are revoked. The role is read again in case it changed.
+	usr, err := s.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	if auth.TokenRevoked(payload.IssuedAt, usr.PasswordChangedAt) {
+		return nil, status.Error(codes.Unauthenticated, auth.ErrTokenRevoked.Error())
+	}
+
+	accessToken, accessPayload, err := s.tokenMaker.CreateToken(usr.Username, usr.Role, s.config.TokenDuration)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &pb.RenewAccessTokenResponse{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: timestamppb.New(accessPayload.ExpiresAt),
+	}, nil
+}
@@ -0,0 +1,143 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/julianinsua/the_simp_bank/auth"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/pb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLoginUser(t *testing.T) {
+	user, password := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		req           *pb.LoginUserRequest
+		buildStubs    func(store *mock_db.MockStore)
+		checkResponse func(t *testing.T, res *pb.LoginUserResponse, err error)
+	}{
+		{
+			name: "OK",
+			req:  &pb.LoginUserRequest{Username: user.Username, Password: password},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUsernameLoginFailures(gomock.Any(), gomock.Any()).Times(1)
+				store.EXPECT().GetClientIPLoginFailures(gomock.Any(), gomock.Any()).Times(1)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					CreateLoginAttempt(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg database.CreateLoginAttemptParams) (database.LoginAttempt, error) {
+						require.Equal(t, auth.OutcomeSuccess, arg.Outcome)
+						return database.LoginAttempt{}, nil
+					})
+				store.EXPECT().
+					CreateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg database.CreateSessionParams) (database.Session, error) {
+						return database.Session{ID: arg.ID, RefreshToken: arg.RefreshToken, ExpiresAt: arg.ExpiresAt}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, user.Username, res.GetUser().GetUsername())
+				require.NotEmpty(t, res.GetAccessToken())
+				require.NotEmpty(t, res.GetRefreshToken())
+			},
+		},
+		{
+			name: "WrongPassword",
+			req:  &pb.LoginUserRequest{Username: user.Username, Password: "wrong" + password},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUsernameLoginFailures(gomock.Any(), gomock.Any()).Times(1)
+				store.EXPECT().GetClientIPLoginFailures(gomock.Any(), gomock.Any()).Times(1)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), gomock.Any()).Times(1)
+				store.EXPECT().CreateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "UserNotFound",
+			req:  &pb.LoginUserRequest{Username: user.Username, Password: password},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetUsernameLoginFailures(gomock.Any(), gomock.Any()).Times(1)
+				store.EXPECT().GetClientIPLoginFailures(gomock.Any(), gomock.Any()).Times(1)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(database.User{}, sql.ErrNoRows)
+				store.EXPECT().CreateLoginAttempt(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Equal(t, codes.Unauthenticated, status.Code(err))
+			},
+		},
+		{
+			name: "Throttled",
+			req:  &pb.LoginUserRequest{Username: user.Username, Password: password},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().
+					GetUsernameLoginFailures(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(database.GetUsernameLoginFailuresRow{Failures: 5, LastFailureAt: time.Now()}, nil)
+				store.EXPECT().GetClientIPLoginFailures(gomock.Any(), gomock.Any()).Times(1)
+				store.EXPECT().
+					CreateLoginAttempt(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg database.CreateLoginAttemptParams) (database.LoginAttempt, error) {
+						require.Equal(t, auth.OutcomeThrottled, arg.Outcome)
+						return database.LoginAttempt{}, nil
+					})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Equal(t, codes.ResourceExhausted, status.Code(err))
+			},
+		},
+		{
+			name: "TwoFactorEnabled",
+			req:  &pb.LoginUserRequest{Username: user.Username, Password: password},
+			buildStubs: func(store *mock_db.MockStore) {
+				totpUser := user
+				totpUser.TotpEnabled = true
+				store.EXPECT().GetUsernameLoginFailures(gomock.Any(), gomock.Any()).Times(1)
+				store.EXPECT().GetClientIPLoginFailures(gomock.Any(), gomock.Any()).Times(1)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(totpUser, nil)
+				store.EXPECT().CreateSessionTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Equal(t, codes.FailedPrecondition, status.Code(err))
+			},
+		},
+		{
+			name:       "InvalidUsername",
+			req:        &pb.LoginUserRequest{Username: "not a username", Password: password},
+			buildStubs: func(store *mock_db.MockStore) {},
+			checkResponse: func(t *testing.T, res *pb.LoginUserResponse, err error) {
+				require.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			res, err := server.LoginUser(context.Background(), tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/pb"
+	"github.com/julianinsua/the_simp_bank/policy"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errStepUpRequired   = errors.New("transfer needs a step-up authentication, make it through the HTTP API")
+	errApprovalRequired = errors.New("transfer needs a second approval, make it through the HTTP API")
+)
+
+func (s *Server) CreateTransfer(ctx context.Context, req *pb.CreateTransferRequest) (*pb.CreateTransferResponse, error) {
+	fromID, err := parseUUID("from_account_id", req.GetFromAccountId())
+	if err != nil {
+		return nil, err
+	}
+	toID, err := parseUUID("to_account_id", req.GetToAccountId())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetAmount() <= 0 {
+		return nil, invalidArgumentError("amount", fmt.Errorf("must be positive"))
+	}
+	if err := validateCurrency(req.GetCurrency()); err != nil {
+		return nil, invalidArgumentError("currency", err)
+	}
+
+	username := authPayload(ctx).Username
+	checks := s.policy()
+	fromAcc, err := checks.ValidAccount(ctx, fromID, req.GetCurrency())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	grantID, err := checks.TransferGrant(ctx, fromAcc.ID, username, req.GetAmount())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	err = checks.TransfersAllowed(ctx, username)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	toAcc, err := checks.ValidAccount(ctx, toID, req.GetCurrency())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	err = checks.IncomingTransferAllowed(ctx, toAcc, req.GetAmount())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	// Step-ups and approvals are interactive, only the HTTP API supports them
+	if checks.RequiresStepUp(req.GetAmount()) {
+		return nil, status.Error(codes.FailedPrecondition, errStepUpRequired.Error())
+	}
+	if policy.RequiresApproval(fromAcc, req.GetAmount()) {
+		return nil, status.Error(codes.FailedPrecondition, errApprovalRequired.Error())
+	}
+
+	result, err := s.store.TransferTx(ctx, database.TransferTxParams{
+		FromAccountID: fromAcc.ID,
+		ToAccountID:   toAcc.ID,
+		Amount:        req.GetAmount(),
+		GrantID:       grantID,
+	})
+	if err != nil {
+		// Insufficient funds, closed accounts and the like map to their own code
+		return nil, statusError(err)
+	}
+
+	return &pb.CreateTransferResponse{
+		Transfer:    convertTransfer(result.Transfer),
+		FromAccount: convertAccount(result.FromAccount),
+		ToAccount:   convertAccount(result.ToAccount),
+		FromEntry:   convertEntry(result.FromEntry),
+		ToEntry:     convertEntry(result.ToEntry),
+	}, nil
+}
@@ -0,0 +1,126 @@
+package gapi
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/pb"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateTransfer(t *testing.T) {
+	user, _ := randomUser(t)
+	fromAccount := randomAccount(user.Username)
+	toAccount := randomAccount(util.RandomOwner())
+	toAccount.Currency = fromAccount.Currency
+
+	ownerHolding := database.AccountHolder{
+		AccountID:  fromAccount.ID,
+		Username:   user.Username,
+		Role:       database.AccountHolderOwner,
+		AcceptedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}
+
+	testCases := []struct {
+		name       string
+		amount     float64
+		buildStubs func(store *mock_db.MockStore)
+		code       codes.Code
+	}{
+		{
+			name:   "OK",
+			amount: 10,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccountHolder(gomock.Any(), gomock.Any()).Times(1).Return(ownerHolding, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Eq(database.TransferTxParams{
+						FromAccountID: fromAccount.ID,
+						ToAccountID:   toAccount.ID,
+						Amount:        10,
+					})).
+					Times(1)
+			},
+			code: codes.OK,
+		},
+		{
+			name:   "NotHolder",
+			amount: 10,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccountHolder(gomock.Any(), gomock.Any()).Times(1).Return(database.AccountHolder{}, sql.ErrNoRows)
+				store.EXPECT().GetActiveAccountGrant(gomock.Any(), gomock.Any()).Times(1).Return(database.AccountGrant{}, sql.ErrNoRows)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codes.PermissionDenied,
+		},
+		{
+			name:   "NeedsStepUp",
+			amount: 1000,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccountHolder(gomock.Any(), gomock.Any()).Times(1).Return(ownerHolding, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			name:   "NeedsApproval",
+			amount: 100,
+			buildStubs: func(store *mock_db.MockStore) {
+				acc := fromAccount
+				acc.ApprovalThreshold = 50
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(acc, nil)
+				store.EXPECT().GetAccountHolder(gomock.Any(), gomock.Any()).Times(1).Return(ownerHolding, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().CreateTransferRequestTx(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			name:   "AccountNotFound",
+			amount: 10,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(1).Return(database.Account{}, sql.ErrNoRows)
+			},
+			code: codes.NotFound,
+		},
+		{
+			name:       "NegativeAmount",
+			amount:     -1,
+			buildStubs: func(store *mock_db.MockStore) {},
+			code:       codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := newAuthorizedContext(t, server.tokenMaker, user.Username)
+
+			_, err := server.CreateTransfer(ctx, &pb.CreateTransferRequest{
+				FromAccountId: fromAccount.ID.String(),
+				ToAccountId:   toAccount.ID.String(),
+				Amount:        tc.amount,
+				Currency:      fromAccount.Currency,
+			})
+			require.Equal(t, tc.code, status.Code(err))
+		})
+	}
+}
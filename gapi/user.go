@@ -0,0 +1,77 @@
+package gapi
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/pb"
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func validateCreateUserRequest(req *pb.CreateUserRequest) error {
+	if err := validateUsername(req.GetUsername()); err != nil {
+		return invalidArgumentError("username", err)
+	}
+	if err := validateRequired(req.GetFullName()); err != nil {
+		return invalidArgumentError("full_name", err)
+	}
+	if err := validateEmail(req.GetEmail()); err != nil {
+		return invalidArgumentError("email", err)
+	}
+	return nil
+}
+
+func (s *Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
+	err := validateCreateUserRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.passwordPolicy.Validate(req.GetPassword())
+	if err != nil {
+		return nil, invalidArgumentError("password", err)
+	}
+
+	hash, err := s.passwordHasher.Hash(req.GetPassword())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	usr, err := s.store.CreateUser(ctx, database.CreateUserParams{
+		Username:       req.GetUsername(),
+		HashedPassword: hash,
+		FullName:       req.GetFullName(),
+		Email:          req.GetEmail(),
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			switch pqErr.Constraint {
+			case "users_pkey":
+				return nil, status.Error(codes.AlreadyExists, "username is already taken")
+			case "users_email_key":
+				return nil, status.Error(codes.AlreadyExists, "email is already in use")
+			}
+		}
+		return nil, statusError(err)
+	}
+
+	// The user is created anyway, the email can be sent again later
+	err = s.auth().SendEmailVerification(ctx, usr)
+	if err != nil {
+		slog.ErrorContext(ctx, "unable to send verification email", "username", usr.Username, "error", err)
+	}
+
+	return &pb.CreateUserResponse{User: convertUser(usr)}, nil
+}
+
+func (s *Server) GetCurrentUser(ctx context.Context, req *pb.GetCurrentUserRequest) (*pb.GetCurrentUserResponse, error) {
+	usr, err := s.store.GetUser(ctx, authPayload(ctx).Username)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &pb.GetCurrentUserResponse{User: convertUser(usr)}, nil
+}
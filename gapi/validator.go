@@ -0,0 +1,58 @@
+package gapi
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Same rules the gin binding tags enforce on the HTTP API
+var isAlphanumeric = regexp.MustCompile(`^[a-zA-Z0-9]+$`).MatchString
+
+func validateUsername(value string) error {
+	if !isAlphanumeric(value) {
+		return fmt.Errorf("must contain only letters and digits")
+	}
+	return nil
+}
+
+func validateEmail(value string) error {
+	if _, err := mail.ParseAddress(value); err != nil {
+		return fmt.Errorf("is not a valid email address")
+	}
+	return nil
+}
+
+func validateCurrency(value string) error {
+	if !util.IsSupportedCurrency(value) {
+		return fmt.Errorf("%s is not supported", value)
+	}
+	return nil
+}
+
+func validateRequired(value string) error {
+	if value == "" {
+		return fmt.Errorf("is required")
+	}
+	return nil
+}
+
+/*
+Parses a uuid sent as a string, responding with an invalid argument error naming the field
+*/
+func parseUUID(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return id, invalidArgumentError(field, fmt.Errorf("is not a valid uuid"))
+	}
+	return id, nil
+}
+
+func invalidArgumentError(field string, err error) error {
+	return status.Errorf(codes.InvalidArgument, "invalid %s: %v", field, err)
+}
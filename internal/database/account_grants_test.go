@@ -0,0 +1,181 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountGrants(t *testing.T) {
+	store := NewStore(testDB)
+	owner := createRandomUser(t)
+	grantee := createRandomUser(t)
+
+	acc, err := store.CreateAccountTx(context.Background(), CreateAccountParams{
+		Owner:    owner.Username,
+		Currency: util.RandomCurrency(),
+	})
+	require.NoError(t, err)
+
+	// Expired grants are never active
+	_, err = store.CreateAccountGrant(context.Background(), CreateAccountGrantParams{
+		AccountID: acc.ID,
+		Grantor:   owner.Username,
+		Grantee:   grantee.Username,
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+
+	active := GetActiveAccountGrantParams{AccountID: acc.ID, Grantee: grantee.Username}
+	_, err = store.GetActiveAccountGrant(context.Background(), active)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	grant, err := store.CreateAccountGrant(context.Background(), CreateAccountGrantParams{
+		AccountID:     acc.ID,
+		Grantor:       owner.Username,
+		Grantee:       grantee.Username,
+		TransferLimit: 100,
+		ExpiresAt:     time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	got, err := store.GetActiveAccountGrant(context.Background(), active)
+	require.NoError(t, err)
+	require.Equal(t, grant.ID, got.ID)
+	require.Equal(t, float64(100), got.TransferLimit)
+
+	received, err := store.ListGranteeGrants(context.Background(), grantee.Username)
+	require.NoError(t, err)
+	require.Len(t, received, 1)
+
+	revoked, err := store.RevokeAccountGrant(context.Background(), RevokeAccountGrantParams{ID: grant.ID, AccountID: acc.ID})
+	require.NoError(t, err)
+	require.True(t, revoked.RevokedAt.Valid)
+
+	_, err = store.GetActiveAccountGrant(context.Background(), active)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	all, err := store.ListAccountGrants(context.Background(), acc.ID)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+
+	// The history of both sides keeps expired and revoked grants
+	given, err := store.ListGrantorGrantHistory(context.Background(), owner.Username)
+	require.NoError(t, err)
+	require.Len(t, given, 2)
+
+	received, err = store.ListGranteeGrantHistory(context.Background(), grantee.Username)
+	require.NoError(t, err)
+	require.Len(t, received, 2)
+	require.Equal(t, grant.ID, received[1].ID)
+}
+
+func TestCreateAccountGrantTxReplaces(t *testing.T) {
+	store := NewStore(testDB)
+	owner := createRandomUser(t)
+	grantee := createRandomUser(t)
+
+	acc, err := store.CreateAccountTx(context.Background(), CreateAccountParams{
+		Owner:    owner.Username,
+		Currency: util.RandomCurrency(),
+	})
+	require.NoError(t, err)
+
+	params := CreateAccountGrantParams{
+		AccountID:     acc.ID,
+		Grantor:       owner.Username,
+		Grantee:       grantee.Username,
+		TransferLimit: 100,
+		ExpiresAt:     time.Now().Add(time.Hour),
+	}
+	first, err := store.CreateAccountGrantTx(context.Background(), params)
+	require.NoError(t, err)
+
+	params.TransferLimit = 50
+	second, err := store.CreateAccountGrantTx(context.Background(), params)
+	require.NoError(t, err)
+
+	got, err := store.GetActiveAccountGrant(context.Background(), GetActiveAccountGrantParams{AccountID: acc.ID, Grantee: grantee.Username})
+	require.NoError(t, err)
+	require.Equal(t, second.ID, got.ID)
+
+	all, err := store.ListAccountGrants(context.Background(), acc.ID)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	for _, grant := range all {
+		require.Equal(t, grant.ID == first.ID, grant.RevokedAt.Valid)
+	}
+
+	// Two unrevoked grants to the same grantee are rejected by the database
+	_, err = store.CreateAccountGrant(context.Background(), params)
+	require.Error(t, err)
+}
+
+func TestTransferTxChargesGrant(t *testing.T) {
+	store := NewStore(testDB)
+	owner := createRandomUser(t)
+	grantee := createRandomUser(t)
+
+	from, err := store.CreateAccount(context.Background(), CreateAccountParams{Owner: owner.Username, Balance: 1000, Currency: util.USD, Name: "from"})
+	require.NoError(t, err)
+	to, err := store.CreateAccount(context.Background(), CreateAccountParams{Owner: owner.Username, Currency: util.USD, Name: "to"})
+	require.NoError(t, err)
+
+	grant, err := store.CreateAccountGrantTx(context.Background(), CreateAccountGrantParams{
+		AccountID:     from.ID,
+		Grantor:       owner.Username,
+		Grantee:       grantee.Username,
+		TransferLimit: 100,
+		ExpiresAt:     time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	params := TransferTxParams{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        30,
+		GrantID:       uuid.NullUUID{UUID: grant.ID, Valid: true},
+	}
+
+	// Each transfer is within the limit but together they would go over it, only three of them fit
+	errs := make(chan error)
+	n := 5
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.TransferTx(context.Background(), params)
+			errs <- err
+		}()
+	}
+
+	succeeded := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			succeeded++
+			continue
+		}
+		require.ErrorIs(t, err, ErrGrantLimitExceeded)
+	}
+	require.Equal(t, 3, succeeded)
+
+	got, err := store.GetAccountGrantForUpdate(context.Background(), grant.ID)
+	require.NoError(t, err)
+	require.Equal(t, float64(90), got.Transferred)
+
+	acc, err := store.GetAccount(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, float64(910), acc.Balance)
+
+	// Revoked grants can't be charged even if the caller checked them before
+	_, err = store.RevokeAccountGrant(context.Background(), RevokeAccountGrantParams{ID: grant.ID, AccountID: from.ID})
+	require.NoError(t, err)
+
+	params.Amount = 5
+	_, err = store.TransferTx(context.Background(), params)
+	require.ErrorIs(t, err, ErrForbidden)
+}
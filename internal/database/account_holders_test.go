@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountHolders(t *testing.T) {
+	store := NewStore(testDB)
+	owner := createRandomUser(t)
+	invitee := createRandomUser(t)
+
+	acc, err := store.CreateAccountTx(context.Background(), CreateAccountParams{
+		Owner:    owner.Username,
+		Currency: util.RandomCurrency(),
+	})
+	require.NoError(t, err)
+
+	holder, err := store.GetAccountHolder(context.Background(), GetAccountHolderParams{AccountID: acc.ID, Username: owner.Username})
+	require.NoError(t, err)
+	require.Equal(t, AccountHolderOwner, holder.Role)
+	require.True(t, holder.AcceptedAt.Valid)
+
+	invitation, err := store.CreateAccountHolder(context.Background(), CreateAccountHolderParams{
+		AccountID: acc.ID,
+		Username:  invitee.Username,
+		Role:      AccountHolderCoOwner,
+		InvitedBy: owner.Username,
+	})
+	require.NoError(t, err)
+	require.False(t, invitation.AcceptedAt.Valid)
+
+	// Pending invitations don't give access yet
+	accs, err := store.ListHolderAccountsAfter(context.Background(), ListHolderAccountsAfterParams{Username: invitee.Username, PageSize: 5})
+	require.NoError(t, err)
+	require.Empty(t, accs)
+
+	pending, err := store.ListPendingAccountInvitations(context.Background(), invitee.Username)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+
+	accepted, err := store.AcceptAccountHolder(context.Background(), AcceptAccountHolderParams{AccountID: acc.ID, Username: invitee.Username})
+	require.NoError(t, err)
+	require.True(t, accepted.AcceptedAt.Valid)
+
+	accs, err = store.ListHolderAccountsAfter(context.Background(), ListHolderAccountsAfterParams{Username: invitee.Username, PageSize: 5})
+	require.NoError(t, err)
+	require.Len(t, accs, 1)
+	require.Equal(t, acc.ID, accs[0].ID)
+
+	holdings, err := store.ListUserAccountHolders(context.Background(), invitee.Username)
+	require.NoError(t, err)
+	require.Len(t, holdings, 1)
+	require.Equal(t, AccountHolderCoOwner, holdings[0].Role)
+
+	// The owner can't be removed
+	removed, err := store.DeleteAccountHolder(context.Background(), DeleteAccountHolderParams{AccountID: acc.ID, Username: owner.Username})
+	require.NoError(t, err)
+	require.Zero(t, removed)
+
+	removed, err = store.DeleteAccountHolder(context.Background(), DeleteAccountHolderParams{AccountID: acc.ID, Username: invitee.Username})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), removed)
+}
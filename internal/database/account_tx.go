@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Roles a user can have on an account
+const (
+	AccountHolderOwner   = "owner"
+	AccountHolderCoOwner = "co_owner"
+	AccountHolderViewer  = "viewer"
+)
+
+// Creates an account, registers its owner as the first holder and records the account.created event within a single
+// database transaction
+func (st *SQLStore) CreateAccountTx(ctx context.Context, params CreateAccountParams) (account Account, err error) {
+	err = st.execTx(ctx, func(q *Queries) error {
+		account, err = q.CreateAccount(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateAccountHolder(ctx, CreateAccountHolderParams{
+			AccountID:  account.ID,
+			Username:   params.Owner,
+			Role:       AccountHolderOwner,
+			InvitedBy:  params.Owner,
+			AcceptedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		return appendEvent(ctx, q, EventAccountCreated, AggregateAccount, account.ID, account)
+	})
+
+	if err != nil {
+		return account, fmt.Errorf("unable to execute transaction: %w", err)
+	}
+	return
+}
+
+// Gives a user a grant on an account, revoking whatever grant they held on it within the same database transaction. A
+// grantee holds at most one unrevoked grant per account, so the new grant replaces the old one, running total included.
+func (st *SQLStore) CreateAccountGrantTx(ctx context.Context, params CreateAccountGrantParams) (grant AccountGrant, err error) {
+	err = st.execTx(ctx, func(q *Queries) error {
+		err = q.RevokeGranteeAccountGrants(ctx, RevokeGranteeAccountGrantsParams{
+			AccountID: params.AccountID,
+			Grantee:   params.Grantee,
+		})
+		if err != nil {
+			return err
+		}
+
+		grant, err = q.CreateAccountGrant(ctx, params)
+		return err
+	})
+
+	if err != nil {
+		return grant, fmt.Errorf("unable to execute transaction: %w", err)
+	}
+	return
+}
@@ -7,6 +7,8 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -15,7 +17,7 @@ const addToAccountBalance = `-- name: AddToAccountBalance :one
 UPDATE accounts
 	SET balance=balance + $1
 	WHERE id= $2
-	RETURNING id, owner, balance, currency, created_at
+	RETURNING id, owner, balance, currency, created_at, approval_threshold, name, parent_id, closed_at, frozen_at, frozen_by
 `
 
 type AddToAccountBalanceParams struct {
@@ -32,28 +34,60 @@ func (q *Queries) AddToAccountBalance(ctx context.Context, arg AddToAccountBalan
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.ApprovalThreshold,
+		&i.Name,
+		&i.ParentID,
+		&i.ClosedAt,
+		&i.FrozenAt,
+		&i.FrozenBy,
 	)
 	return i, err
 }
 
+const closeOwnerAccounts = `-- name: CloseOwnerAccounts :exec
+UPDATE accounts
+	SET closed_at=$2
+	WHERE owner=$1 AND closed_at IS NULL
+`
+
+type CloseOwnerAccountsParams struct {
+	Owner    string       `json:"owner"`
+	ClosedAt sql.NullTime `json:"closedAt"`
+}
+
+func (q *Queries) CloseOwnerAccounts(ctx context.Context, arg CloseOwnerAccountsParams) error {
+	_, err := q.db.ExecContext(ctx, closeOwnerAccounts, arg.Owner, arg.ClosedAt)
+	return err
+}
+
 const createAccount = `-- name: CreateAccount :one
 INSERT INTO accounts (
 	owner,
 	balance,
-	currency
+	currency,
+	name,
+	parent_id
 ) VALUES (
-	$1, $2, $3
-) RETURNING id, owner, balance, currency, created_at
+	$1, $2, $3, $4, $5
+) RETURNING id, owner, balance, currency, created_at, approval_threshold, name, parent_id, closed_at, frozen_at, frozen_by
 `
 
 type CreateAccountParams struct {
-	Owner    string  `json:"owner"`
-	Balance  float64 `json:"balance"`
-	Currency string  `json:"currency"`
+	Owner    string        `json:"owner"`
+	Balance  float64       `json:"balance"`
+	Currency string        `json:"currency"`
+	Name     string        `json:"name"`
+	ParentID uuid.NullUUID `json:"parentId"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, createAccount, arg.Owner, arg.Balance, arg.Currency)
+	row := q.db.QueryRowContext(ctx, createAccount,
+		arg.Owner,
+		arg.Balance,
+		arg.Currency,
+		arg.Name,
+		arg.ParentID,
+	)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -61,6 +95,12 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.ApprovalThreshold,
+		&i.Name,
+		&i.ParentID,
+		&i.ClosedAt,
+		&i.FrozenAt,
+		&i.FrozenBy,
 	)
 	return i, err
 }
@@ -74,8 +114,39 @@ func (q *Queries) DeleteAccount(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const freezeAccount = `-- name: FreezeAccount :one
+UPDATE accounts
+	SET frozen_at=now(), frozen_by=$2
+	WHERE id=$1
+	RETURNING id, owner, balance, currency, created_at, approval_threshold, name, parent_id, closed_at, frozen_at, frozen_by
+`
+
+type FreezeAccountParams struct {
+	ID       uuid.UUID      `json:"id"`
+	FrozenBy sql.NullString `json:"frozenBy"`
+}
+
+func (q *Queries) FreezeAccount(ctx context.Context, arg FreezeAccountParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, freezeAccount, arg.ID, arg.FrozenBy)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.ApprovalThreshold,
+		&i.Name,
+		&i.ParentID,
+		&i.ClosedAt,
+		&i.FrozenAt,
+		&i.FrozenBy,
+	)
+	return i, err
+}
+
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency, created_at FROM accounts WHERE id=$1 LIMIT 1
+SELECT id, owner, balance, currency, created_at, approval_threshold, name, parent_id, closed_at, frozen_at, frozen_by FROM accounts WHERE id=$1 LIMIT 1
 `
 
 func (q *Queries) GetAccount(ctx context.Context, id uuid.UUID) (Account, error) {
@@ -87,12 +158,18 @@ func (q *Queries) GetAccount(ctx context.Context, id uuid.UUID) (Account, error)
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.ApprovalThreshold,
+		&i.Name,
+		&i.ParentID,
+		&i.ClosedAt,
+		&i.FrozenAt,
+		&i.FrozenBy,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-SELECT id, owner, balance, currency, created_at FROM accounts WHERE id=$1 LIMIT 1 FOR NO KEY UPDATE
+SELECT id, owner, balance, currency, created_at, approval_threshold, name, parent_id, closed_at, frozen_at, frozen_by FROM accounts WHERE id=$1 LIMIT 1 FOR NO KEY UPDATE
 `
 
 func (q *Queries) GetAccountForUpdate(ctx context.Context, id uuid.UUID) (Account, error) {
@@ -104,26 +181,75 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id uuid.UUID) (Accoun
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.ApprovalThreshold,
+		&i.Name,
+		&i.ParentID,
+		&i.ClosedAt,
+		&i.FrozenAt,
+		&i.FrozenBy,
 	)
 	return i, err
 }
 
-const getAccountsList = `-- name: GetAccountsList :many
-SELECT id, owner, balance, currency, created_at FROM accounts 
-	WHERE owner = $1
-	ORDER BY id 
-	LIMIT $2 
-	OFFSET $3
+const listCurrencyBalances = `-- name: ListCurrencyBalances :many
+SELECT currency, SUM(balance)::float AS balance, COUNT(*) AS accounts, COUNT(*) FILTER (WHERE frozen_at IS NOT NULL) AS frozen_accounts FROM accounts
+	GROUP BY currency
+	ORDER BY currency
 `
 
-type GetAccountsListParams struct {
-	Owner  string `json:"owner"`
-	Limit  int32  `json:"limit"`
-	Offset int32  `json:"offset"`
+type ListCurrencyBalancesRow struct {
+	Currency       string  `json:"currency"`
+	Balance        float64 `json:"balance"`
+	Accounts       int64   `json:"accounts"`
+	FrozenAccounts int64   `json:"frozenAccounts"`
 }
 
-func (q *Queries) GetAccountsList(ctx context.Context, arg GetAccountsListParams) ([]Account, error) {
-	rows, err := q.db.QueryContext(ctx, getAccountsList, arg.Owner, arg.Limit, arg.Offset)
+func (q *Queries) ListCurrencyBalances(ctx context.Context) ([]ListCurrencyBalancesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCurrencyBalances)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCurrencyBalancesRow
+	for rows.Next() {
+		var i ListCurrencyBalancesRow
+		if err := rows.Scan(
+			&i.Currency,
+			&i.Balance,
+			&i.Accounts,
+			&i.FrozenAccounts,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listHolderAccountsAfter = `-- name: ListHolderAccountsAfter :many
+SELECT accounts.id, accounts.owner, accounts.balance, accounts.currency, accounts.created_at, accounts.approval_threshold, accounts.name, accounts.parent_id, accounts.closed_at, accounts.frozen_at, accounts.frozen_by FROM accounts
+	JOIN account_holders ON account_holders.account_id = accounts.id
+	WHERE account_holders.username = $1 AND account_holders.accepted_at IS NOT NULL
+		AND (accounts.created_at, accounts.id) > ($2::timestamptz, $3::uuid)
+	ORDER BY accounts.created_at, accounts.id
+	LIMIT $4
+`
+
+type ListHolderAccountsAfterParams struct {
+	Username        string    `json:"username"`
+	CursorCreatedAt time.Time `json:"cursorCreatedAt"`
+	CursorID        uuid.UUID `json:"cursorId"`
+	PageSize        int32     `json:"pageSize"`
+}
+
+func (q *Queries) ListHolderAccountsAfter(ctx context.Context, arg ListHolderAccountsAfterParams) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, listHolderAccountsAfter, arg.Username, arg.CursorCreatedAt, arg.CursorID, arg.PageSize)
 	if err != nil {
 		return nil, err
 	}
@@ -137,6 +263,12 @@ func (q *Queries) GetAccountsList(ctx context.Context, arg GetAccountsListParams
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.ApprovalThreshold,
+			&i.Name,
+			&i.ParentID,
+			&i.ClosedAt,
+			&i.FrozenAt,
+			&i.FrozenBy,
 		); err != nil {
 			return nil, err
 		}
@@ -151,11 +283,289 @@ func (q *Queries) GetAccountsList(ctx context.Context, arg GetAccountsListParams
 	return items, nil
 }
 
+const listHolderAccountsBefore = `-- name: ListHolderAccountsBefore :many
+SELECT accounts.id, accounts.owner, accounts.balance, accounts.currency, accounts.created_at, accounts.approval_threshold, accounts.name, accounts.parent_id, accounts.closed_at, accounts.frozen_at, accounts.frozen_by FROM accounts
+	JOIN account_holders ON account_holders.account_id = accounts.id
+	WHERE account_holders.username = $1 AND account_holders.accepted_at IS NOT NULL
+		AND (accounts.created_at, accounts.id) < ($2::timestamptz, $3::uuid)
+	ORDER BY accounts.created_at DESC, accounts.id DESC
+	LIMIT $4
+`
+
+type ListHolderAccountsBeforeParams struct {
+	Username        string    `json:"username"`
+	CursorCreatedAt time.Time `json:"cursorCreatedAt"`
+	CursorID        uuid.UUID `json:"cursorId"`
+	PageSize        int32     `json:"pageSize"`
+}
+
+func (q *Queries) ListHolderAccountsBefore(ctx context.Context, arg ListHolderAccountsBeforeParams) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, listHolderAccountsBefore, arg.Username, arg.CursorCreatedAt, arg.CursorID, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Account
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.ApprovalThreshold,
+			&i.Name,
+			&i.ParentID,
+			&i.ClosedAt,
+			&i.FrozenAt,
+			&i.FrozenBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listHolderCurrencyBalances = `-- name: ListHolderCurrencyBalances :many
+SELECT accounts.currency, SUM(accounts.balance)::float AS balance, COUNT(*) AS accounts FROM accounts
+	JOIN account_holders ON account_holders.account_id = accounts.id
+	WHERE account_holders.username = $1 AND account_holders.accepted_at IS NOT NULL
+	GROUP BY accounts.currency
+	ORDER BY accounts.currency
+`
+
+type ListHolderCurrencyBalancesRow struct {
+	Currency string  `json:"currency"`
+	Balance  float64 `json:"balance"`
+	Accounts int64   `json:"accounts"`
+}
+
+func (q *Queries) ListHolderCurrencyBalances(ctx context.Context, username string) ([]ListHolderCurrencyBalancesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listHolderCurrencyBalances, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListHolderCurrencyBalancesRow
+	for rows.Next() {
+		var i ListHolderCurrencyBalancesRow
+		if err := rows.Scan(&i.Currency, &i.Balance, &i.Accounts); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOwnerAccounts = `-- name: ListOwnerAccounts :many
+SELECT id, owner, balance, currency, created_at, approval_threshold, name, parent_id, closed_at, frozen_at, frozen_by FROM accounts
+	WHERE owner=$1
+	ORDER BY created_at
+`
+
+func (q *Queries) ListOwnerAccounts(ctx context.Context, owner string) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, listOwnerAccounts, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Account
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.ApprovalThreshold,
+			&i.Name,
+			&i.ParentID,
+			&i.ClosedAt,
+			&i.FrozenAt,
+			&i.FrozenBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOwnerAccountsForUpdate = `-- name: ListOwnerAccountsForUpdate :many
+SELECT id, owner, balance, currency, created_at, approval_threshold, name, parent_id, closed_at, frozen_at, frozen_by FROM accounts
+	WHERE owner=$1
+	ORDER BY id
+	FOR NO KEY UPDATE
+`
+
+func (q *Queries) ListOwnerAccountsForUpdate(ctx context.Context, owner string) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, listOwnerAccountsForUpdate, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Account
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.ApprovalThreshold,
+			&i.Name,
+			&i.ParentID,
+			&i.ClosedAt,
+			&i.FrozenAt,
+			&i.FrozenBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubAccounts = `-- name: ListSubAccounts :many
+SELECT id, owner, balance, currency, created_at, approval_threshold, name, parent_id, closed_at, frozen_at, frozen_by FROM accounts
+	WHERE parent_id=$1
+	ORDER BY created_at
+`
+
+func (q *Queries) ListSubAccounts(ctx context.Context, parentID uuid.NullUUID) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, listSubAccounts, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Account
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.ApprovalThreshold,
+			&i.Name,
+			&i.ParentID,
+			&i.ClosedAt,
+			&i.FrozenAt,
+			&i.FrozenBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const notifyBalanceChanged = `-- name: NotifyBalanceChanged :exec
+SELECT pg_notify('balance_changed', $1::uuid::text)
+`
+
+func (q *Queries) NotifyBalanceChanged(ctx context.Context, accountID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, notifyBalanceChanged, accountID)
+	return err
+}
+
+const unfreezeAccount = `-- name: UnfreezeAccount :one
+UPDATE accounts
+	SET frozen_at=NULL, frozen_by=NULL
+	WHERE id=$1
+	RETURNING id, owner, balance, currency, created_at, approval_threshold, name, parent_id, closed_at, frozen_at, frozen_by
+`
+
+func (q *Queries) UnfreezeAccount(ctx context.Context, id uuid.UUID) (Account, error) {
+	row := q.db.QueryRowContext(ctx, unfreezeAccount, id)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.ApprovalThreshold,
+		&i.Name,
+		&i.ParentID,
+		&i.ClosedAt,
+		&i.FrozenAt,
+		&i.FrozenBy,
+	)
+	return i, err
+}
+
+const updateAccountApprovalThreshold = `-- name: UpdateAccountApprovalThreshold :one
+UPDATE accounts
+	SET approval_threshold=$2
+	WHERE id=$1
+	RETURNING id, owner, balance, currency, created_at, approval_threshold, name, parent_id, closed_at, frozen_at, frozen_by
+`
+
+type UpdateAccountApprovalThresholdParams struct {
+	ID                uuid.UUID `json:"id"`
+	ApprovalThreshold float64   `json:"approvalThreshold"`
+}
+
+func (q *Queries) UpdateAccountApprovalThreshold(ctx context.Context, arg UpdateAccountApprovalThresholdParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, updateAccountApprovalThreshold, arg.ID, arg.ApprovalThreshold)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.ApprovalThreshold,
+		&i.Name,
+		&i.ParentID,
+		&i.ClosedAt,
+		&i.FrozenAt,
+		&i.FrozenBy,
+	)
+	return i, err
+}
+
 const updateAccountBalance = `-- name: UpdateAccountBalance :one
 UPDATE accounts
 	SET balance=$2
 	WHERE id=$1
-	RETURNING id, owner, balance, currency, created_at
+	RETURNING id, owner, balance, currency, created_at, approval_threshold, name, parent_id, closed_at, frozen_at, frozen_by
 `
 
 type UpdateAccountBalanceParams struct {
@@ -172,6 +582,12 @@ func (q *Queries) UpdateAccountBalance(ctx context.Context, arg UpdateAccountBal
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.ApprovalThreshold,
+		&i.Name,
+		&i.ParentID,
+		&i.ClosedAt,
+		&i.FrozenAt,
+		&i.FrozenBy,
 	)
 	return i, err
 }
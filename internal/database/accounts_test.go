@@ -2,8 +2,10 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/julianinsua/the_simp_bank/util"
 	"github.com/stretchr/testify/require"
 )
@@ -15,6 +17,7 @@ func TestAccountCreation(t *testing.T) {
 		Balance:  util.RandomMoney(),
 		Currency: util.RandomCurrency(),
 	}
+	params.Name = params.Currency
 
 	acc, err := testQueries.CreateAccount(context.Background(), params)
 
@@ -23,7 +26,91 @@ func TestAccountCreation(t *testing.T) {
 	require.Equal(t, acc.Owner, params.Owner)
 	require.Equal(t, acc.Balance, params.Balance)
 	require.Equal(t, acc.Currency, params.Currency)
+	require.Equal(t, acc.Name, params.Name)
+	require.False(t, acc.ParentID.Valid)
 
 	require.NotEmpty(t, acc.ID)
 	require.NotEmpty(t, acc.CreatedAt)
 }
+
+func TestAccountPots(t *testing.T) {
+	user := createRandomUser(t)
+	store := NewStore(testDB)
+
+	parent, err := store.CreateAccountTx(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Balance:  100,
+		Currency: util.USD,
+		Name:     util.USD,
+	})
+	require.NoError(t, err)
+
+	// A second account in the same currency needs its own name
+	_, err = store.CreateAccountTx(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Currency: util.USD,
+		Name:     util.USD,
+	})
+	require.Error(t, err)
+
+	pot, err := store.CreateAccountTx(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Balance:  50,
+		Currency: util.USD,
+		Name:     "Holidays",
+		ParentID: uuid.NullUUID{UUID: parent.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, parent.ID, pot.ParentID.UUID)
+
+	pots, err := testQueries.ListSubAccounts(context.Background(), uuid.NullUUID{UUID: parent.ID, Valid: true})
+	require.NoError(t, err)
+	require.Len(t, pots, 1)
+	require.Equal(t, pot.ID, pots[0].ID)
+
+	balances, err := testQueries.ListHolderCurrencyBalances(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, balances, 1)
+	require.Equal(t, util.USD, balances[0].Currency)
+	require.Equal(t, float64(150), balances[0].Balance)
+	require.Equal(t, int64(2), balances[0].Accounts)
+}
+
+func TestFreezeAccount(t *testing.T) {
+	admin := createRandomUser(t)
+	store := NewStore(testDB)
+
+	accounts := make([]Account, 2)
+	for i := range accounts {
+		acc, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+			Owner:    createRandomUser(t).Username,
+			Balance:  100,
+			Currency: util.USD,
+			Name:     util.USD,
+		})
+		require.NoError(t, err)
+		accounts[i] = acc
+	}
+
+	frozen, err := testQueries.FreezeAccount(context.Background(), FreezeAccountParams{
+		ID:       accounts[0].ID,
+		FrozenBy: sql.NullString{String: admin.Username, Valid: true},
+	})
+	require.NoError(t, err)
+	require.True(t, frozen.FrozenAt.Valid)
+	require.Equal(t, admin.Username, frozen.FrozenBy.String)
+
+	// Money can neither leave nor reach a frozen account
+	_, err = store.TransferTx(context.Background(), TransferTxParams{FromAccountID: accounts[0].ID, ToAccountID: accounts[1].ID, Amount: 1})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+	_, err = store.TransferTx(context.Background(), TransferTxParams{FromAccountID: accounts[1].ID, ToAccountID: accounts[0].ID, Amount: 1})
+	require.ErrorIs(t, err, ErrAccountFrozen)
+
+	unfrozen, err := testQueries.UnfreezeAccount(context.Background(), accounts[0].ID)
+	require.NoError(t, err)
+	require.False(t, unfrozen.FrozenAt.Valid)
+	require.False(t, unfrozen.FrozenBy.Valid)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{FromAccountID: accounts[0].ID, ToAccountID: accounts[1].ID, Amount: 1})
+	require.NoError(t, err)
+}
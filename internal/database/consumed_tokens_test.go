@@ -0,0 +1,29 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumeToken(t *testing.T) {
+	params := ConsumeTokenParams{
+		ID:        uuid.New(),
+		Username:  util.RandomOwner(),
+		Purpose:   "step_up",
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+
+	rows, err := testQueries.ConsumeToken(context.Background(), params)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rows)
+
+	// A token can only be consumed once
+	rows, err = testQueries.ConsumeToken(context.Background(), params)
+	require.NoError(t, err)
+	require.Zero(t, rows)
+}
@@ -0,0 +1,44 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Returned when a verification token was used or expired between being read and redeemed
+var ErrVerificationTokenUnavailable = errors.New("email verification token already used or expired")
+
+// Contains the input parameters to redeem an email verification token
+type VerifyEmailTxParams struct {
+	TokenID  uuid.UUID `json:"tokenId"`
+	Username string    `json:"username"`
+	Email    string    `json:"email"`
+}
+
+// Marks the verification token as used and the user's email as verified within a single database transaction.
+// It fails with sql.ErrNoRows if the user changed its email after the token was sent.
+func (st *SQLStore) VerifyEmailTx(ctx context.Context, params VerifyEmailTxParams) (user User, err error) {
+	err = st.execTx(ctx, func(q *Queries) error {
+		rows, err := q.UseEmailVerificationToken(ctx, params.TokenID)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return ErrVerificationTokenUnavailable
+		}
+
+		user, err = q.SetUserEmailVerified(ctx, SetUserEmailVerifiedParams{
+			Username: params.Username,
+			Email:    params.Email,
+		})
+		return err
+	})
+
+	if err != nil {
+		return user, fmt.Errorf("unable to execute transaction: %w", err)
+	}
+	return
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyEmailTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	require.False(t, user.EmailVerifiedAt.Valid)
+
+	hashedToken := util.HashSecureToken(util.RandomString(32))
+	verification, err := store.CreateEmailVerificationToken(context.Background(), CreateEmailVerificationTokenParams{
+		Username:    user.Username,
+		Email:       user.Email,
+		HashedToken: hashedToken,
+		ExpiresAt:   time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.False(t, verification.Used)
+
+	sent, err := store.CountRecentEmailVerificationTokens(context.Background(), CountRecentEmailVerificationTokensParams{
+		Username:  user.Username,
+		CreatedAt: time.Now().Add(-time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), sent)
+
+	params := VerifyEmailTxParams{
+		TokenID:  verification.ID,
+		Username: user.Username,
+		Email:    user.Email,
+	}
+
+	verified, err := store.VerifyEmailTx(context.Background(), params)
+	require.NoError(t, err)
+	require.True(t, verified.EmailVerifiedAt.Valid)
+
+	// The token is single use
+	_, err = store.VerifyEmailTx(context.Background(), params)
+	require.True(t, errors.Is(err, ErrVerificationTokenUnavailable))
+}
@@ -7,6 +7,7 @@ package database
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -37,21 +38,39 @@ func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry
 	return i, err
 }
 
-const getAccountEntries = `-- name: GetAccountEntries :many
+const getEntry = `-- name: GetEntry :one
+SELECT id, account_id, amount, created_at FROM entries WHERE id=$1 LIMIT 1
+`
+
+func (q *Queries) GetEntry(ctx context.Context, id uuid.UUID) (Entry, error) {
+	row := q.db.QueryRowContext(ctx, getEntry, id)
+	var i Entry
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Amount,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listAccountEntriesAfter = `-- name: ListAccountEntriesAfter :many
 SELECT id, account_id, amount, created_at FROM entries
-	WHERE account_id=$1
-	ORDER BY id 
-	LIMIT $1 
-	OFFSET $2
+	WHERE account_id = $1
+		AND (created_at, id) > ($2::timestamptz, $3::uuid)
+	ORDER BY created_at, id
+	LIMIT $4
 `
 
-type GetAccountEntriesParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+type ListAccountEntriesAfterParams struct {
+	AccountID       uuid.UUID `json:"accountId"`
+	CursorCreatedAt time.Time `json:"cursorCreatedAt"`
+	CursorID        uuid.UUID `json:"cursorId"`
+	PageSize        int32     `json:"pageSize"`
 }
 
-func (q *Queries) GetAccountEntries(ctx context.Context, arg GetAccountEntriesParams) ([]Entry, error) {
-	rows, err := q.db.QueryContext(ctx, getAccountEntries, arg.Limit, arg.Offset)
+func (q *Queries) ListAccountEntriesAfter(ctx context.Context, arg ListAccountEntriesAfterParams) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountEntriesAfter, arg.AccountID, arg.CursorCreatedAt, arg.CursorID, arg.PageSize)
 	if err != nil {
 		return nil, err
 	}
@@ -78,18 +97,80 @@ func (q *Queries) GetAccountEntries(ctx context.Context, arg GetAccountEntriesPa
 	return items, nil
 }
 
-const getEntry = `-- name: GetEntry :one
-SELECT id, account_id, amount, created_at FROM entries WHERE id=$1 LIMIT 1
+const listAccountEntriesBefore = `-- name: ListAccountEntriesBefore :many
+SELECT id, account_id, amount, created_at FROM entries
+	WHERE account_id = $1
+		AND (created_at, id) < ($2::timestamptz, $3::uuid)
+	ORDER BY created_at DESC, id DESC
+	LIMIT $4
 `
 
-func (q *Queries) GetEntry(ctx context.Context, id uuid.UUID) (Entry, error) {
-	row := q.db.QueryRowContext(ctx, getEntry, id)
-	var i Entry
-	err := row.Scan(
-		&i.ID,
-		&i.AccountID,
-		&i.Amount,
-		&i.CreatedAt,
-	)
-	return i, err
+type ListAccountEntriesBeforeParams struct {
+	AccountID       uuid.UUID `json:"accountId"`
+	CursorCreatedAt time.Time `json:"cursorCreatedAt"`
+	CursorID        uuid.UUID `json:"cursorId"`
+	PageSize        int32     `json:"pageSize"`
+}
+
+func (q *Queries) ListAccountEntriesBefore(ctx context.Context, arg ListAccountEntriesBeforeParams) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountEntriesBefore, arg.AccountID, arg.CursorCreatedAt, arg.CursorID, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Entry
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOwnerEntries = `-- name: ListOwnerEntries :many
+SELECT entries.id, entries.account_id, entries.amount, entries.created_at FROM entries
+	JOIN accounts ON accounts.id=entries.account_id
+	WHERE accounts.owner=$1
+	ORDER BY entries.created_at
+`
+
+func (q *Queries) ListOwnerEntries(ctx context.Context, owner string) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, listOwnerEntries, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Entry
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
@@ -0,0 +1,26 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// Domain errors returned by the store, callers match them with errors.Is
+var (
+	// Returned by queries and transactions when the row they need doesn't exist
+	ErrRecordNotFound = sql.ErrNoRows
+	// Returned when a transfer would leave the sending account with a negative balance
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// Returned when a transfer moves money between accounts of different currencies
+	ErrCurrencyMismatch = errors.New("accounts have different currencies")
+	// Returned when a transfer sends money to an account whose owner deleted its user
+	ErrAccountClosed = errors.New("account is closed")
+	// Returned when a transfer moves money from or to an account an admin froze
+	ErrAccountFrozen = errors.New("account is frozen")
+	// Returned when a single use token sent along with an operation was already redeemed
+	ErrTokenUsed = errors.New("token has already been used")
+	// Returned when the user is not allowed to perform the operation
+	ErrForbidden = errors.New("operation not allowed")
+	// Returned when a transfer made under a grant would take its running total past the grant's transfer limit
+	ErrGrantLimitExceeded = errors.New("transfer exceeds what is left of the grant's transfer limit")
+)
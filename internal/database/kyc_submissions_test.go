@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewKYCTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	reviewer := createRandomUser(t)
+	require.Equal(t, KYCStatusPending, user.KycStatus)
+
+	params := CreateKYCSubmissionParams{
+		Username:          user.Username,
+		DocumentType:      "passport",
+		DocumentNumber:    util.RandomString(9),
+		IssuingCountry:    "AR",
+		DocumentExpiresAt: time.Now().AddDate(5, 0, 0),
+	}
+	submission, err := store.SubmitKYCTx(context.Background(), params)
+	require.NoError(t, err)
+	require.Equal(t, KYCStatusPending, submission.Status)
+
+	// Only one submission can wait for review at a time
+	_, err = store.SubmitKYCTx(context.Background(), params)
+	require.Error(t, err)
+
+	review := ReviewKYCSubmissionParams{
+		ID:         submission.ID,
+		Status:     KYCStatusVerified,
+		ReviewedBy: sql.NullString{String: reviewer.Username, Valid: true},
+	}
+	result, err := store.ReviewKYCTx(context.Background(), review)
+	require.NoError(t, err)
+	require.Equal(t, KYCStatusVerified, result.Submission.Status)
+	require.True(t, result.Submission.ReviewedAt.Valid)
+	require.Equal(t, KYCStatusVerified, result.User.KycStatus)
+
+	_, err = store.ReviewKYCTx(context.Background(), review)
+	require.True(t, errors.Is(err, ErrKYCSubmissionReviewed))
+
+	submissions, err := store.ListUserKYCSubmissions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, submissions, 1)
+}
+
+func TestDeleteUserTxKYCSubmissions(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	submission, err := store.SubmitKYCTx(context.Background(), CreateKYCSubmissionParams{
+		Username:          user.Username,
+		DocumentType:      "passport",
+		DocumentNumber:    util.RandomString(9),
+		IssuingCountry:    "AR",
+		DocumentExpiresAt: time.Now().AddDate(5, 0, 0),
+	})
+	require.NoError(t, err)
+
+	_, err = store.DeleteUserTx(context.Background(), DeleteUserTxParams{Username: user.Username, DeletedAt: time.Now()})
+	require.NoError(t, err)
+
+	// The document details are gone and the submission left the review queue
+	got, err := store.GetKYCSubmission(context.Background(), submission.ID)
+	require.NoError(t, err)
+	require.Empty(t, got.DocumentNumber)
+	require.Empty(t, got.IssuingCountry)
+	require.Equal(t, KYCStatusRejected, got.Status)
+	require.True(t, got.ReviewedAt.Valid)
+
+	pending, err := store.ListPendingKYCSubmissions(context.Background(), ListPendingKYCSubmissionsParams{Limit: 100000})
+	require.NoError(t, err)
+	for _, p := range pending {
+		require.NotEqual(t, submission.ID, p.ID)
+	}
+}
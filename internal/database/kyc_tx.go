@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// KYC statuses shared by users and their submissions
+const (
+	KYCStatusPending  = "pending"
+	KYCStatusVerified = "verified"
+	KYCStatusRejected = "rejected"
+)
+
+// Returned when a submission was already reviewed, possibly by a concurrent request
+var ErrKYCSubmissionReviewed = errors.New("kyc submission was already reviewed")
+
+// Stores new KYC documents and puts the user back in the pending status within a single database transaction
+func (st *SQLStore) SubmitKYCTx(ctx context.Context, params CreateKYCSubmissionParams) (submission KycSubmission, err error) {
+	err = st.execTx(ctx, func(q *Queries) error {
+		submission, err = q.CreateKYCSubmission(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.UpdateUserKYCStatus(ctx, UpdateUserKYCStatusParams{
+			Username:  params.Username,
+			KycStatus: KYCStatusPending,
+		})
+		return err
+	})
+
+	if err != nil {
+		return submission, fmt.Errorf("unable to execute transaction: %w", err)
+	}
+	return
+}
+
+// Contains the result of reviewing a KYC submission
+type ReviewKYCTxResult struct {
+	Submission KycSubmission `json:"submission"`
+	User       User          `json:"user"`
+}
+
+// Records the decision on a pending submission and copies it to the user's KYC status within a single database transaction
+func (st *SQLStore) ReviewKYCTx(ctx context.Context, params ReviewKYCSubmissionParams) (result ReviewKYCTxResult, err error) {
+	err = st.execTx(ctx, func(q *Queries) error {
+		result.Submission, err = q.ReviewKYCSubmission(ctx, params)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return ErrKYCSubmissionReviewed
+			}
+			return err
+		}
+
+		result.User, err = q.UpdateUserKYCStatus(ctx, UpdateUserKYCStatusParams{
+			Username:  result.Submission.Username,
+			KycStatus: result.Submission.Status,
+		})
+		return err
+	})
+
+	if err != nil {
+		return result, fmt.Errorf("unable to execute transaction: %w", err)
+	}
+	return
+}
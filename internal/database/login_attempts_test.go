@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomLoginAttempt(t *testing.T, username, ip, outcome string) LoginAttempt {
+	attempt, err := testQueries.CreateLoginAttempt(context.Background(), CreateLoginAttemptParams{
+		Username:    username,
+		ClientIp:    ip,
+		ClientAgent: "test-agent",
+		Outcome:     outcome,
+	})
+	require.NoError(t, err)
+	require.Equal(t, username, attempt.Username)
+	require.Equal(t, outcome, attempt.Outcome)
+	require.NotZero(t, attempt.CreatedAt)
+	return attempt
+}
+
+func TestGetUsernameLoginFailures(t *testing.T) {
+	username := util.RandomOwner()
+	ip := util.RandomString(12)
+	since := time.Now().Add(-time.Hour)
+
+	createRandomLoginAttempt(t, username, ip, "invalid_credentials")
+	createRandomLoginAttempt(t, username, ip, "success")
+	last := createRandomLoginAttempt(t, username, ip, "invalid_credentials")
+
+	// failures before the last success are not counted against the username
+	byUser, err := testQueries.GetUsernameLoginFailures(context.Background(), GetUsernameLoginFailuresParams{
+		Username: username,
+		Since:    since,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), byUser.Failures)
+	require.WithinDuration(t, last.CreatedAt, byUser.LastFailureAt, time.Second)
+
+	byIP, err := testQueries.GetClientIPLoginFailures(context.Background(), GetClientIPLoginFailuresParams{
+		ClientIp: ip,
+		Since:    since,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), byIP.Failures)
+
+	attempts, err := testQueries.ListUserLoginAttempts(context.Background(), username)
+	require.NoError(t, err)
+	require.Len(t, attempts, 3)
+	require.Equal(t, last.ID, attempts[2].ID)
+}
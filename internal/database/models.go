@@ -5,6 +5,8 @@
 package database
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,6 +18,80 @@ type Account struct {
 	Balance   float64   `json:"balance"`
 	Currency  string    `json:"currency"`
 	CreatedAt time.Time `json:"createdAt"`
+	// transfers of this amount or more need a second approval, 0 disables approvals
+	ApprovalThreshold float64 `json:"approvalThreshold"`
+	Name              string  `json:"name"`
+	// account this pot belongs to, pots share the owner and currency of their parent
+	ParentID uuid.NullUUID `json:"parentId"`
+	// set when the owner deleted its user, closed accounts can no longer receive transfers
+	ClosedAt sql.NullTime `json:"closedAt"`
+	// set while an admin holds the account, frozen accounts can neither send nor receive transfers
+	FrozenAt sql.NullTime `json:"frozenAt"`
+	// admin that froze the account
+	FrozenBy sql.NullString `json:"frozenBy"`
+}
+
+type AccountGrant struct {
+	ID        uuid.UUID `json:"id"`
+	AccountID uuid.UUID `json:"accountId"`
+	Grantor   string    `json:"grantor"`
+	Grantee   string    `json:"grantee"`
+	// total the grantee can transfer out over the life of the grant, 0 for view only access
+	TransferLimit float64      `json:"transferLimit"`
+	CanApprove    bool         `json:"canApprove"`
+	ExpiresAt     time.Time    `json:"expiresAt"`
+	RevokedAt     sql.NullTime `json:"revokedAt"`
+	CreatedAt     time.Time    `json:"createdAt"`
+	// running total transferred out under the grant
+	Transferred float64 `json:"transferred"`
+}
+
+type AccountHolder struct {
+	AccountID uuid.UUID `json:"accountId"`
+	Username  string    `json:"username"`
+	// owner, co_owner or viewer
+	Role      string `json:"role"`
+	InvitedBy string `json:"invitedBy"`
+	// null while the invitation is pending
+	AcceptedAt sql.NullTime `json:"acceptedAt"`
+	CreatedAt  time.Time    `json:"createdAt"`
+}
+
+// audit trail of the approval thresholds of accounts
+type ApprovalThresholdChange struct {
+	ID           uuid.UUID `json:"id"`
+	AccountID    uuid.UUID `json:"accountId"`
+	Username     string    `json:"username"`
+	OldThreshold float64   `json:"oldThreshold"`
+	NewThreshold float64   `json:"newThreshold"`
+	CreatedAt    time.Time `json:"createdAt"`
+	Status       string    `json:"status"`
+	// holder who approved or rejected a lowering, raises apply right away
+	DecidedBy sql.NullString `json:"decidedBy"`
+	// end of the approval window of a lowering
+	ExpiresAt sql.NullTime `json:"expiresAt"`
+	DecidedAt sql.NullTime `json:"decidedAt"`
+}
+
+// single use tokens already redeemed, kept until they expire
+type ConsumedToken struct {
+	ID        uuid.UUID `json:"id"`
+	Username  string    `json:"username"`
+	Purpose   string    `json:"purpose"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type EmailVerificationToken struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+	// address the token was sent to, it only verifies that one
+	Email string `json:"email"`
+	// sha256 of the token sent by email
+	HashedToken string    `json:"hashedToken"`
+	Used        bool      `json:"used"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+	CreatedAt   time.Time `json:"createdAt"`
 }
 
 type Entry struct {
@@ -25,6 +101,79 @@ type Entry struct {
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+type KycSubmission struct {
+	ID             uuid.UUID `json:"id"`
+	Username       string    `json:"username"`
+	DocumentType   string    `json:"documentType"`
+	DocumentNumber string    `json:"documentNumber"`
+	// ISO 3166-1 alpha-2 code
+	IssuingCountry    string    `json:"issuingCountry"`
+	DocumentExpiresAt time.Time `json:"documentExpiresAt"`
+	Status            string    `json:"status"`
+	ReviewNote        string    `json:"reviewNote"`
+	// admin or support user that took the decision
+	ReviewedBy sql.NullString `json:"reviewedBy"`
+	ReviewedAt sql.NullTime   `json:"reviewedAt"`
+	CreatedAt  time.Time      `json:"createdAt"`
+}
+
+type LoginAttempt struct {
+	ID uuid.UUID `json:"id"`
+	// as submitted, it may not belong to any user
+	Username    string `json:"username"`
+	ClientIp    string `json:"clientIp"`
+	ClientAgent string `json:"clientAgent"`
+	// success, invalid_credentials, mfa_challenge, step_up or throttled
+	Outcome   string    `json:"outcome"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type Outbox struct {
+	ID            int64           `json:"id"`
+	EventID       uuid.UUID       `json:"eventId"`
+	EventType     string          `json:"eventType"`
+	AggregateType string          `json:"aggregateType"`
+	AggregateID   uuid.UUID       `json:"aggregateId"`
+	Payload       json.RawMessage `json:"payload"`
+	// transaction that appended the event, events are read in (transaction_id, id) order once no older transaction is running so none commits behind a reader
+	TransactionID int64     `json:"transactionId"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// position of the last event each consumer published
+type OutboxOffset struct {
+	Consumer      string    `json:"consumer"`
+	TransactionID int64     `json:"transactionId"`
+	OutboxID      int64     `json:"outboxId"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// throttles password reset requests per email and client IP, recorded whether the email belongs to a user or not
+type PasswordResetRequest struct {
+	ID        int64     `json:"id"`
+	Email     string    `json:"email"`
+	ClientIp  string    `json:"clientIp"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type PasswordResetToken struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+	// sha256 of the token sent by email
+	HashedToken string    `json:"hashedToken"`
+	Used        bool      `json:"used"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type RecoveryCode struct {
+	ID         uuid.UUID `json:"id"`
+	Username   string    `json:"username"`
+	HashedCode string    `json:"hashedCode"`
+	Used       bool      `json:"used"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
 type Session struct {
 	ID           uuid.UUID `json:"id"`
 	Username     string    `json:"username"`
@@ -45,11 +194,70 @@ type Transfer struct {
 	CreatedAt time.Time `json:"createdAt"`
 }
 
-type User struct {
+type TransferApproval struct {
+	ID                uuid.UUID `json:"id"`
+	TransferRequestID uuid.UUID `json:"transferRequestId"`
 	Username          string    `json:"username"`
-	HashedPassword    string    `json:"hashedPassword"`
-	FullName          string    `json:"fullName"`
-	Email             string    `json:"email"`
-	PasswordChangedAt time.Time `json:"passwordChangedAt"`
+	Decision          string    `json:"decision"`
+	Note              string    `json:"note"`
 	CreatedAt         time.Time `json:"createdAt"`
 }
+
+type TransferRequest struct {
+	ID            uuid.UUID `json:"id"`
+	FromAccountID uuid.UUID `json:"fromAccountId"`
+	ToAccountID   uuid.UUID `json:"toAccountId"`
+	Amount        float64   `json:"amount"`
+	RequestedBy   string    `json:"requestedBy"`
+	Status        string    `json:"status"`
+	// transfer made once the request was approved
+	TransferID uuid.NullUUID `json:"transferId"`
+	ExpiresAt  time.Time     `json:"expiresAt"`
+	DecidedAt  sql.NullTime  `json:"decidedAt"`
+	CreatedAt  time.Time     `json:"createdAt"`
+}
+
+type User struct {
+	Username          string       `json:"username"`
+	HashedPassword    string       `json:"hashedPassword"`
+	FullName          string       `json:"fullName"`
+	Email             string       `json:"email"`
+	PasswordChangedAt time.Time    `json:"passwordChangedAt"`
+	CreatedAt         time.Time    `json:"createdAt"`
+	Role              string       `json:"role"`
+	TotpSecret        string       `json:"totpSecret"`
+	TotpEnabled       bool         `json:"totpEnabled"`
+	EmailVerifiedAt   sql.NullTime `json:"emailVerifiedAt"`
+	// set when the user deleted its account, personal fields are anonymized
+	DeletedAt sql.NullTime `json:"deletedAt"`
+	KycStatus string       `json:"kycStatus"`
+	// last TOTP time step accepted at login, codes from this step or earlier are rejected
+	TotpLastStep int64 `json:"totpLastStep"`
+}
+
+type Webhook struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+	Url      string    `json:"url"`
+	// key the payloads are signed with, HMAC-SHA256
+	Secret     string    `json:"secret"`
+	EventTypes []string  `json:"eventTypes"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type WebhookDelivery struct {
+	ID        uuid.UUID `json:"id"`
+	WebhookID uuid.UUID `json:"webhookId"`
+	// shared by the deliveries of the same event, receivers use it to drop duplicates
+	EventID   uuid.UUID       `json:"eventId"`
+	EventType string          `json:"eventType"`
+	Payload   json.RawMessage `json:"payload"`
+	Status    string          `json:"status"`
+	Attempts  int32           `json:"attempts"`
+	// when the worker picks the delivery up, pushed forward while an attempt is in flight and on every retry
+	NextAttemptAt time.Time      `json:"nextAttemptAt"`
+	ResponseCode  sql.NullInt32  `json:"responseCode"`
+	LastError     sql.NullString `json:"lastError"`
+	DeliveredAt   sql.NullTime   `json:"deliveredAt"`
+	CreatedAt     time.Time      `json:"createdAt"`
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Events not offered to webhooks, they only go to internal consumers
+const (
+	EventSessionCreated = "session.created"
+	EventBalanceChanged = "account.balance_changed" // an entry changed the balance, carries TransferEventData
+)
+
+// Channel notified with the account ID whenever a committed transaction changes its balance
+const BalanceChangedChannel = "balance_changed"
+
+// What the events in the outbox are about
+const (
+	AggregateTransfer = "transfer"
+	AggregateAccount  = "account"
+	AggregateSession  = "session"
+)
+
+// Data of the session events, the refresh token never leaves the sessions table
+type SessionEventData struct {
+	ID          uuid.UUID `json:"id"`
+	Username    string    `json:"username"`
+	ClientAgent string    `json:"clientAgent"`
+	ClientIp    string    `json:"clientIp"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// Appends a domain event to the outbox. Meant to run inside the transaction that produced the event, so the event is
+// recorded if and only if the change is committed.
+func appendEvent(ctx context.Context, q *Queries, eventType, aggregateType string, aggregateID uuid.UUID, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.AppendOutboxEvent(ctx, AppendOutboxEventParams{
+		EventID:       uuid.New(),
+		EventType:     eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       payload,
+	})
+	return err
+}
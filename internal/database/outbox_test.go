@@ -0,0 +1,215 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+// Events appended so far, in the order relays read them
+func listOutbox(t *testing.T, store *SQLStore) []Outbox {
+	events, err := store.ListOutboxEvents(context.Background(), ListOutboxEventsParams{BatchSize: 100000})
+	require.NoError(t, err)
+	return events
+}
+
+func findOutboxEvent(events []Outbox, eventType string, aggregateID uuid.UUID) (Outbox, bool) {
+	for _, event := range events {
+		if event.EventType == eventType && event.AggregateID == aggregateID {
+			return event, true
+		}
+	}
+	return Outbox{}, false
+}
+
+func TestOutboxEvents(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	from, err := store.CreateAccountTx(context.Background(), CreateAccountParams{Owner: user.Username, Balance: 10, Currency: util.USD, Name: "from"})
+	require.NoError(t, err)
+	to, err := store.CreateAccountTx(context.Background(), CreateAccountParams{Owner: user.Username, Currency: util.USD, Name: "to"})
+	require.NoError(t, err)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 3})
+	require.NoError(t, err)
+
+	session, err := store.CreateSessionTx(context.Background(), CreateSessionParams{
+		ID:           uuid.New(),
+		Username:     user.Username,
+		RefreshToken: util.RandomString(32),
+		ClientAgent:  "test",
+		ClientIp:     "127.0.0.1",
+		ExpiresAt:    time.Now().Add(time.Hour),
+		CreatedAt:    time.Now(),
+	})
+	require.NoError(t, err)
+
+	events := listOutbox(t, store)
+	created, ok := findOutboxEvent(events, EventAccountCreated, from.ID)
+	require.True(t, ok)
+	require.Equal(t, AggregateAccount, created.AggregateType)
+
+	transferred, ok := findOutboxEvent(events, EventTransferCreated, result.Transfer.ID)
+	require.True(t, ok)
+	var data TransferTxResult
+	err = json.Unmarshal(transferred.Payload, &data)
+	require.NoError(t, err)
+	require.Equal(t, float64(7), data.FromAccount.Balance)
+
+	logged, ok := findOutboxEvent(events, EventSessionCreated, session.ID)
+	require.True(t, ok)
+	require.NotContains(t, string(logged.Payload), session.RefreshToken)
+
+	// Events come in commit order
+	require.Less(t, created.TransactionID, transferred.TransactionID)
+	require.Less(t, transferred.TransactionID, logged.TransactionID)
+
+	// Rolled back transfers leave no event
+	_, err = store.TransferTx(context.Background(), TransferTxParams{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 100})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+	require.Len(t, listOutbox(t, store), len(events))
+}
+
+func TestOutboxOffsets(t *testing.T) {
+	store := NewStore(testDB)
+	consumer := util.RandomString(12)
+
+	_, err := store.GetOutboxOffset(context.Background(), consumer)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	err = store.SetOutboxOffset(context.Background(), SetOutboxOffsetParams{Consumer: consumer, TransactionID: 10, OutboxID: 3})
+	require.NoError(t, err)
+	err = store.SetOutboxOffset(context.Background(), SetOutboxOffsetParams{Consumer: consumer, TransactionID: 12, OutboxID: 2})
+	require.NoError(t, err)
+
+	offset, err := store.GetOutboxOffset(context.Background(), consumer)
+	require.NoError(t, err)
+	require.Equal(t, int64(12), offset.TransactionID)
+	require.Equal(t, int64(2), offset.OutboxID)
+
+	// Only events past the offset are listed
+	events, err := store.ListOutboxEvents(context.Background(), ListOutboxEventsParams{
+		AfterTransactionID: offset.TransactionID,
+		AfterID:            offset.OutboxID,
+		BatchSize:          100000,
+	})
+	require.NoError(t, err)
+	for _, event := range events {
+		require.True(t, event.TransactionID > 12 || (event.TransactionID == 12 && event.ID > 2))
+	}
+}
+
+func TestBalanceEvents(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	horizon, err := store.GetOutboxHorizon(context.Background())
+	require.NoError(t, err)
+
+	from, err := store.CreateAccountTx(context.Background(), CreateAccountParams{Owner: user.Username, Balance: 10, Currency: util.USD, Name: "from"})
+	require.NoError(t, err)
+	to, err := store.CreateAccountTx(context.Background(), CreateAccountParams{Owner: user.Username, Currency: util.USD, Name: "to"})
+	require.NoError(t, err)
+
+	accountIDs, err := store.ListAccessibleAccountIDs(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []uuid.UUID{from.ID, to.ID}, accountIDs)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 3})
+	require.NoError(t, err)
+	_, err = store.TransferTx(context.Background(), TransferTxParams{FromAccountID: to.ID, ToAccountID: from.ID, Amount: 1})
+	require.NoError(t, err)
+
+	params := ListAccountOutboxEventsParams{
+		AccountIds:         []uuid.UUID{from.ID},
+		EventType:          EventBalanceChanged,
+		AfterTransactionID: horizon - 1,
+		AfterID:            math.MaxInt64,
+		BatchSize:          10,
+	}
+	events, err := store.ListAccountOutboxEvents(context.Background(), params)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	balances := make([]float64, 0, len(events))
+	for _, event := range events {
+		var data TransferEventData
+		err = json.Unmarshal(event.Payload, &data)
+		require.NoError(t, err)
+		require.Equal(t, from.ID, data.Account.ID)
+		balances = append(balances, data.Account.Balance)
+	}
+	require.Equal(t, []float64{7, 8}, balances)
+
+	// Resuming after the first event only returns the second
+	params.AfterTransactionID, params.AfterID = events[0].TransactionID, events[0].ID
+	resumed, err := store.ListAccountOutboxEvents(context.Background(), params)
+	require.NoError(t, err)
+	require.Len(t, resumed, 1)
+	require.Equal(t, events[1].EventID, resumed[0].EventID)
+}
+
+func TestDeleteConsumedOutboxEvents(t *testing.T) {
+	// The sweep is global, run it in a transaction that is rolled back so other tests keep their events
+	tx, err := testDB.BeginTx(context.Background(), nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	q := New(tx)
+
+	_, err = tx.ExecContext(context.Background(), "DELETE FROM outbox_offsets")
+	require.NoError(t, err)
+
+	var events []Outbox
+	for i := 0; i < 3; i++ {
+		event, err := q.AppendOutboxEvent(context.Background(), AppendOutboxEventParams{
+			EventID:       uuid.New(),
+			EventType:     EventAccountCreated,
+			AggregateType: AggregateAccount,
+			AggregateID:   uuid.New(),
+			Payload:       json.RawMessage(`{}`),
+		})
+		require.NoError(t, err)
+		events = append(events, event)
+	}
+
+	// No consumer saved an offset yet, nothing is known to be published
+	sweep := DeleteConsumedOutboxEventsParams{CreatedBefore: time.Now().Add(time.Hour), BatchSize: math.MaxInt32}
+	n, err := q.DeleteConsumedOutboxEvents(context.Background(), sweep)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	for consumer, event := range map[string]Outbox{"slow": events[0], "fast": events[2]} {
+		err = q.SetOutboxOffset(context.Background(), SetOutboxOffsetParams{
+			Consumer:      consumer,
+			TransactionID: event.TransactionID,
+			OutboxID:      event.ID,
+		})
+		require.NoError(t, err)
+	}
+
+	// Events within the retention stay
+	n, err = q.DeleteConsumedOutboxEvents(context.Background(), DeleteConsumedOutboxEventsParams{
+		CreatedBefore: time.Now().Add(-time.Hour),
+		BatchSize:     math.MaxInt32,
+	})
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	// Only what the slowest consumer published goes
+	_, err = q.DeleteConsumedOutboxEvents(context.Background(), sweep)
+	require.NoError(t, err)
+	// Relays don't list events of a transaction still running, look the rows up directly
+	for i, kept := range []bool{false, true, true} {
+		var count int
+		err = tx.QueryRowContext(context.Background(), "SELECT count(*) FROM outbox WHERE id = $1", events[i].ID).Scan(&count)
+		require.NoError(t, err)
+		require.Equal(t, kept, count == 1)
+	}
+}
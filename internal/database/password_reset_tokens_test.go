@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResetPasswordTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	hashedToken := util.HashSecureToken(util.RandomString(32))
+	resetToken, err := store.CreatePasswordResetToken(context.Background(), CreatePasswordResetTokenParams{
+		Username:    user.Username,
+		HashedToken: hashedToken,
+		ExpiresAt:   time.Now().Add(time.Minute),
+	})
+	require.NoError(t, err)
+	require.False(t, resetToken.Used)
+
+	got, err := store.GetPasswordResetToken(context.Background(), hashedToken)
+	require.NoError(t, err)
+	require.Equal(t, resetToken.ID, got.ID)
+
+	hash, err := util.HashPassword(util.RandomString(8))
+	require.NoError(t, err)
+	params := ResetPasswordTxParams{
+		TokenID:           resetToken.ID,
+		Username:          user.Username,
+		HashedPassword:    hash,
+		PasswordChangedAt: time.Now().UTC(),
+	}
+
+	updated, err := store.ResetPasswordTx(context.Background(), params)
+	require.NoError(t, err)
+	require.Equal(t, hash, updated.HashedPassword)
+
+	// The token is single use
+	_, err = store.ResetPasswordTx(context.Background(), params)
+	require.True(t, errors.Is(err, ErrResetTokenUnavailable))
+}
+
+func TestPasswordResetRequests(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	clientIP := util.RandomString(12)
+	since := time.Now().Add(-time.Minute)
+
+	for _, email := range []string{user.Email, user.Email, util.RandomEmail()} {
+		err := store.CreatePasswordResetRequest(context.Background(), CreatePasswordResetRequestParams{Email: email, ClientIp: clientIP})
+		require.NoError(t, err)
+	}
+
+	byEmail, err := store.GetEmailPasswordResetRequests(context.Background(), GetEmailPasswordResetRequestsParams{Email: user.Email, Since: since})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), byEmail.Requests)
+	require.WithinDuration(t, time.Now(), byEmail.LastRequestAt, time.Minute)
+
+	byIP, err := store.GetClientIPPasswordResetRequests(context.Background(), GetClientIPPasswordResetRequestsParams{ClientIp: clientIP, Since: since})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), byIP.Requests)
+
+	// Deleting the user drops the requests made for its email
+	_, err = store.DeleteUserTx(context.Background(), DeleteUserTxParams{Username: user.Username, DeletedAt: time.Now()})
+	require.NoError(t, err)
+	byEmail, err = store.GetEmailPasswordResetRequests(context.Background(), GetEmailPasswordResetRequestsParams{Email: user.Email, Since: since})
+	require.NoError(t, err)
+	require.Zero(t, byEmail.Requests)
+}
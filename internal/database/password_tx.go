@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Updates a user's password hash and blocks all of its sessions within a single database transaction.
+// Tokens issued before PasswordChangedAt must be rejected by the caller.
+func (st *SQLStore) ChangePasswordTx(ctx context.Context, params UpdateUserPasswordParams) (user User, err error) {
+	err = st.execTx(ctx, func(q *Queries) error {
+		user, err = q.UpdateUserPassword(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		return q.BlockUserSessions(ctx, params.Username)
+	})
+
+	if err != nil {
+		return user, fmt.Errorf("unable to execute transaction: %w", err)
+	}
+	return
+}
+
+// Returned when a password reset token was used or expired between being read and redeemed
+var ErrResetTokenUnavailable = errors.New("password reset token already used or expired")
+
+// Contains the input parameters to redeem a password reset token
+type ResetPasswordTxParams struct {
+	TokenID           uuid.UUID `json:"tokenId"`
+	Username          string    `json:"username"`
+	HashedPassword    string    `json:"hashedPassword"`
+	PasswordChangedAt time.Time `json:"passwordChangedAt"`
+}
+
+// Marks the reset token as used, sets the new password and blocks all the user's sessions within a single database transaction
+func (st *SQLStore) ResetPasswordTx(ctx context.Context, params ResetPasswordTxParams) (user User, err error) {
+	err = st.execTx(ctx, func(q *Queries) error {
+		rows, err := q.UsePasswordResetToken(ctx, params.TokenID)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return ErrResetTokenUnavailable
+		}
+
+		user, err = q.UpdateUserPassword(ctx, UpdateUserPasswordParams{
+			Username:          params.Username,
+			HashedPassword:    params.HashedPassword,
+			PasswordChangedAt: params.PasswordChangedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.BlockUserSessions(ctx, params.Username)
+	})
+
+	if err != nil {
+		return user, fmt.Errorf("unable to execute transaction: %w", err)
+	}
+	return
+}
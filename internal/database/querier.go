@@ -6,27 +6,144 @@ package database
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	AcceptAccountHolder(ctx context.Context, arg AcceptAccountHolderParams) (AccountHolder, error)
+	AddAccountGrantTransferred(ctx context.Context, arg AddAccountGrantTransferredParams) (AccountGrant, error)
 	AddToAccountBalance(ctx context.Context, arg AddToAccountBalanceParams) (Account, error)
+	AdvanceUserTOTPStep(ctx context.Context, arg AdvanceUserTOTPStepParams) (int64, error)
+	AnonymizeUser(ctx context.Context, arg AnonymizeUserParams) (User, error)
+	AnonymizeUserKYCSubmissions(ctx context.Context, username string) error
+	AnonymizeUserSessions(ctx context.Context, username string) error
+	AppendOutboxEvent(ctx context.Context, arg AppendOutboxEventParams) (Outbox, error)
+	BlockUserSessions(ctx context.Context, username string) error
+	ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	CloseOwnerAccounts(ctx context.Context, arg CloseOwnerAccountsParams) error
+	ConsumeToken(ctx context.Context, arg ConsumeTokenParams) (int64, error)
+	CountRecentEmailVerificationTokens(ctx context.Context, arg CountRecentEmailVerificationTokensParams) (int64, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateAccountGrant(ctx context.Context, arg CreateAccountGrantParams) (AccountGrant, error)
+	CreateAccountHolder(ctx context.Context, arg CreateAccountHolderParams) (AccountHolder, error)
+	CreateAccountWebhookDeliveries(ctx context.Context, arg CreateAccountWebhookDeliveriesParams) (int64, error)
+	CreateApprovalThresholdChange(ctx context.Context, arg CreateApprovalThresholdChangeParams) (ApprovalThresholdChange, error)
+	CreateEmailVerificationToken(ctx context.Context, arg CreateEmailVerificationTokenParams) (EmailVerificationToken, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateKYCSubmission(ctx context.Context, arg CreateKYCSubmissionParams) (KycSubmission, error)
+	CreateLoginAttempt(ctx context.Context, arg CreateLoginAttemptParams) (LoginAttempt, error)
+	CreatePasswordResetRequest(ctx context.Context, arg CreatePasswordResetRequestParams) error
+	CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) (PasswordResetToken, error)
+	CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) (RecoveryCode, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
+	CreateTransferApproval(ctx context.Context, arg CreateTransferApprovalParams) (TransferApproval, error)
+	CreateTransferRequest(ctx context.Context, arg CreateTransferRequestParams) (TransferRequest, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error)
+	DecideApprovalThresholdChange(ctx context.Context, arg DecideApprovalThresholdChangeParams) (ApprovalThresholdChange, error)
+	DecideTransferRequest(ctx context.Context, arg DecideTransferRequestParams) (TransferRequest, error)
 	DeleteAccount(ctx context.Context, id uuid.UUID) error
+	DeleteAccountHolder(ctx context.Context, arg DeleteAccountHolderParams) (int64, error)
+	DeleteConsumedOutboxEvents(ctx context.Context, arg DeleteConsumedOutboxEventsParams) (int64, error)
+	DeleteExpiredConsumedTokens(ctx context.Context) error
+	DeleteUserAccountHolders(ctx context.Context, username string) error
+	DeleteUserEmailVerificationTokens(ctx context.Context, username string) error
+	DeleteUserLoginAttempts(ctx context.Context, username string) error
+	DeleteUserPasswordResetRequests(ctx context.Context, username string) error
+	DeleteUserPasswordResetTokens(ctx context.Context, username string) error
+	DeleteUserRecoveryCodes(ctx context.Context, username string) error
+	DeleteUserWebhooks(ctx context.Context, username string) error
+	DeleteWebhook(ctx context.Context, arg DeleteWebhookParams) (Webhook, error)
+	EnableUserTOTP(ctx context.Context, username string) (User, error)
+	ExpireApprovalThresholdChanges(ctx context.Context) (int64, error)
+	ExpireTransferRequests(ctx context.Context) (int64, error)
+	FreezeAccount(ctx context.Context, arg FreezeAccountParams) (Account, error)
 	GetAccount(ctx context.Context, id uuid.UUID) (Account, error)
-	GetAccountEntries(ctx context.Context, arg GetAccountEntriesParams) ([]Entry, error)
 	GetAccountForUpdate(ctx context.Context, id uuid.UUID) (Account, error)
-	GetAccountsList(ctx context.Context, arg GetAccountsListParams) ([]Account, error)
+	GetAccountGrantForUpdate(ctx context.Context, id uuid.UUID) (AccountGrant, error)
+	GetAccountHolder(ctx context.Context, arg GetAccountHolderParams) (AccountHolder, error)
+	GetActiveAccountGrant(ctx context.Context, arg GetActiveAccountGrantParams) (AccountGrant, error)
+	GetApprovalThresholdChange(ctx context.Context, id uuid.UUID) (ApprovalThresholdChange, error)
+	GetApprovalThresholdChangeForUpdate(ctx context.Context, id uuid.UUID) (ApprovalThresholdChange, error)
+	GetClientIPLoginFailures(ctx context.Context, arg GetClientIPLoginFailuresParams) (GetClientIPLoginFailuresRow, error)
+	GetClientIPPasswordResetRequests(ctx context.Context, arg GetClientIPPasswordResetRequestsParams) (GetClientIPPasswordResetRequestsRow, error)
+	GetEmailPasswordResetRequests(ctx context.Context, arg GetEmailPasswordResetRequestsParams) (GetEmailPasswordResetRequestsRow, error)
+	GetEmailVerificationToken(ctx context.Context, hashedToken string) (EmailVerificationToken, error)
 	GetEntry(ctx context.Context, id uuid.UUID) (Entry, error)
+	GetKYCSubmission(ctx context.Context, id uuid.UUID) (KycSubmission, error)
+	GetOutboxHorizon(ctx context.Context) (int64, error)
+	GetOutboxOffset(ctx context.Context, consumer string) (OutboxOffset, error)
+	GetPasswordResetToken(ctx context.Context, hashedToken string) (PasswordResetToken, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
 	GetTransfer(ctx context.Context, id uuid.UUID) (Transfer, error)
+	GetTransferRequest(ctx context.Context, id uuid.UUID) (TransferRequest, error)
+	GetTransferRequestForUpdate(ctx context.Context, id uuid.UUID) (TransferRequest, error)
+	GetUnusedRecoveryCodes(ctx context.Context, username string) ([]RecoveryCode, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserPasswordChangedAt(ctx context.Context, username string) (time.Time, error)
+	GetUserRole(ctx context.Context, username string) (string, error)
+	GetUserWebhook(ctx context.Context, arg GetUserWebhookParams) (Webhook, error)
+	GetUsernameLoginFailures(ctx context.Context, arg GetUsernameLoginFailuresParams) (GetUsernameLoginFailuresRow, error)
+	GetWebhook(ctx context.Context, id uuid.UUID) (Webhook, error)
+	ListAccessibleAccountIDs(ctx context.Context, username string) ([]uuid.UUID, error)
+	ListAccountEntriesAfter(ctx context.Context, arg ListAccountEntriesAfterParams) ([]Entry, error)
+	ListAccountEntriesBefore(ctx context.Context, arg ListAccountEntriesBeforeParams) ([]Entry, error)
+	ListAccountGrants(ctx context.Context, accountID uuid.UUID) ([]AccountGrant, error)
+	ListAccountHolders(ctx context.Context, accountID uuid.UUID) ([]AccountHolder, error)
+	ListAccountOutboxEvents(ctx context.Context, arg ListAccountOutboxEventsParams) ([]Outbox, error)
+	ListAccountTransferRequestsAfter(ctx context.Context, arg ListAccountTransferRequestsAfterParams) ([]TransferRequest, error)
+	ListAccountTransferRequestsBefore(ctx context.Context, arg ListAccountTransferRequestsBeforeParams) ([]TransferRequest, error)
+	ListAccountTransfersAfter(ctx context.Context, arg ListAccountTransfersAfterParams) ([]Transfer, error)
+	ListAccountTransfersBefore(ctx context.Context, arg ListAccountTransfersBeforeParams) ([]Transfer, error)
+	ListCurrencyBalances(ctx context.Context) ([]ListCurrencyBalancesRow, error)
+	ListCurrencyTransferVolumes(ctx context.Context, arg ListCurrencyTransferVolumesParams) ([]ListCurrencyTransferVolumesRow, error)
+	ListGranteeGrantHistory(ctx context.Context, grantee string) ([]AccountGrant, error)
+	ListGranteeGrants(ctx context.Context, grantee string) ([]AccountGrant, error)
+	ListGrantorGrantHistory(ctx context.Context, grantor string) ([]AccountGrant, error)
+	ListHolderAccountsAfter(ctx context.Context, arg ListHolderAccountsAfterParams) ([]Account, error)
+	ListHolderAccountsBefore(ctx context.Context, arg ListHolderAccountsBeforeParams) ([]Account, error)
+	ListHolderCurrencyBalances(ctx context.Context, username string) ([]ListHolderCurrencyBalancesRow, error)
+	ListOutboxEvents(ctx context.Context, arg ListOutboxEventsParams) ([]Outbox, error)
+	ListOwnerAccounts(ctx context.Context, owner string) ([]Account, error)
+	ListOwnerAccountsForUpdate(ctx context.Context, owner string) ([]Account, error)
+	ListOwnerEntries(ctx context.Context, owner string) ([]Entry, error)
+	ListOwnerTransfers(ctx context.Context, owner string) ([]Transfer, error)
+	ListPendingAccountInvitations(ctx context.Context, username string) ([]AccountHolder, error)
+	ListPendingKYCSubmissions(ctx context.Context, arg ListPendingKYCSubmissionsParams) ([]KycSubmission, error)
+	ListSubAccounts(ctx context.Context, parentID uuid.NullUUID) ([]Account, error)
+	ListTransferApprovals(ctx context.Context, transferRequestID uuid.UUID) ([]TransferApproval, error)
+	ListUserAccountHolders(ctx context.Context, username string) ([]AccountHolder, error)
+	ListUserKYCSubmissions(ctx context.Context, username string) ([]KycSubmission, error)
+	ListUserLoginAttempts(ctx context.Context, username string) ([]LoginAttempt, error)
+	ListUserSessions(ctx context.Context, username string) ([]Session, error)
+	ListUserWebhooks(ctx context.Context, username string) ([]Webhook, error)
+	ListWebhookDeliveriesAfter(ctx context.Context, arg ListWebhookDeliveriesAfterParams) ([]WebhookDelivery, error)
+	ListWebhookDeliveriesBefore(ctx context.Context, arg ListWebhookDeliveriesBeforeParams) ([]WebhookDelivery, error)
+	MarkRecoveryCodeUsed(ctx context.Context, id uuid.UUID) (int64, error)
+	MarkWebhookDelivered(ctx context.Context, arg MarkWebhookDeliveredParams) error
+	MarkWebhookDeliveryFailed(ctx context.Context, arg MarkWebhookDeliveryFailedParams) error
+	NotifyBalanceChanged(ctx context.Context, accountID uuid.UUID) error
+	ReviewKYCSubmission(ctx context.Context, arg ReviewKYCSubmissionParams) (KycSubmission, error)
+	RevokeAccountGrant(ctx context.Context, arg RevokeAccountGrantParams) (AccountGrant, error)
+	RevokeGranteeAccountGrants(ctx context.Context, arg RevokeGranteeAccountGrantsParams) error
+	RevokeUserAccountGrants(ctx context.Context, grantee string) error
+	SetOutboxOffset(ctx context.Context, arg SetOutboxOffsetParams) error
+	SetUserEmailVerified(ctx context.Context, arg SetUserEmailVerifiedParams) (User, error)
+	UnfreezeAccount(ctx context.Context, id uuid.UUID) (Account, error)
+	UpdateAccountApprovalThreshold(ctx context.Context, arg UpdateAccountApprovalThresholdParams) (Account, error)
 	UpdateAccountBalance(ctx context.Context, arg UpdateAccountBalanceParams) (Account, error)
+	UpdateUserKYCStatus(ctx context.Context, arg UpdateUserKYCStatusParams) (User, error)
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error)
+	UpdateUserPasswordHash(ctx context.Context, arg UpdateUserPasswordHashParams) error
+	UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error)
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
+	UpdateUserTOTPSecret(ctx context.Context, arg UpdateUserTOTPSecretParams) (User, error)
+	UseEmailVerificationToken(ctx context.Context, id uuid.UUID) (int64, error)
+	UsePasswordResetToken(ctx context.Context, id uuid.UUID) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnableTOTPTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	secret, err := util.GenerateTOTPSecret()
+	require.NoError(t, err)
+	user, err = store.UpdateUserTOTPSecret(context.Background(), UpdateUserTOTPSecretParams{
+		Username:   user.Username,
+		TotpSecret: secret,
+	})
+	require.NoError(t, err)
+	require.Equal(t, secret, user.TotpSecret)
+	require.False(t, user.TotpEnabled)
+
+	user, err = store.EnableTOTPTx(context.Background(), EnableTOTPTxParams{
+		Username:            user.Username,
+		HashedRecoveryCodes: []string{"hash1", "hash2"},
+	})
+	require.NoError(t, err)
+	require.True(t, user.TotpEnabled)
+
+	codes, err := store.GetUnusedRecoveryCodes(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, codes, 2)
+
+	rows, err := store.MarkRecoveryCodeUsed(context.Background(), codes[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rows)
+
+	// Recovery codes are single use
+	rows, err = store.MarkRecoveryCodeUsed(context.Background(), codes[0].ID)
+	require.NoError(t, err)
+	require.Zero(t, rows)
+
+	codes, err = store.GetUnusedRecoveryCodes(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, codes, 1)
+}
@@ -0,0 +1,33 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/julianinsua/the_simp_bank/metrics"
+)
+
+// Creates a session and records the session.created event within a single database transaction
+func (st *SQLStore) CreateSessionTx(ctx context.Context, params CreateSessionParams) (session Session, err error) {
+	err = st.execTx(ctx, func(q *Queries) error {
+		session, err = q.CreateSession(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		return appendEvent(ctx, q, EventSessionCreated, AggregateSession, session.ID, SessionEventData{
+			ID:          session.ID,
+			Username:    session.Username,
+			ClientAgent: session.ClientAgent,
+			ClientIp:    session.ClientIp,
+			ExpiresAt:   session.ExpiresAt,
+			CreatedAt:   session.CreatedAt,
+		})
+	})
+
+	if err != nil {
+		return session, fmt.Errorf("unable to execute transaction: %w", err)
+	}
+	metrics.SessionsCreated.Inc()
+	return
+}
@@ -12,6 +12,26 @@ import (
 	"github.com/google/uuid"
 )
 
+const anonymizeUserSessions = `-- name: AnonymizeUserSessions :exec
+UPDATE "sessions"
+	SET is_blocked=TRUE, client_agent='', client_ip=''
+	WHERE username=$1
+`
+
+func (q *Queries) AnonymizeUserSessions(ctx context.Context, username string) error {
+	_, err := q.db.ExecContext(ctx, anonymizeUserSessions, username)
+	return err
+}
+
+const blockUserSessions = `-- name: BlockUserSessions :exec
+UPDATE "sessions" SET is_blocked=TRUE WHERE username=$1
+`
+
+func (q *Queries) BlockUserSessions(ctx context.Context, username string) error {
+	_, err := q.db.ExecContext(ctx, blockUserSessions, username)
+	return err
+}
+
 const createSession = `-- name: CreateSession :one
 INSERT INTO sessions (
 	id,
@@ -78,3 +98,41 @@ func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (Session, error)
 	)
 	return i, err
 }
+
+const listUserSessions = `-- name: ListUserSessions :many
+SELECT id, username, refresh_token, client_agent, client_ip, is_blocked, expires_at, created_at FROM "sessions"
+	WHERE username=$1
+	ORDER BY created_at
+`
+
+func (q *Queries) ListUserSessions(ctx context.Context, username string) ([]Session, error) {
+	rows, err := q.db.QueryContext(ctx, listUserSessions, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Session
+	for rows.Next() {
+		var i Session
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.RefreshToken,
+			&i.ClientAgent,
+			&i.ClientIp,
+			&i.IsBlocked,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -3,16 +3,35 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log/slog"
+	"time"
 
 	_ "github.com/golang/mock/mockgen/model"
 	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/metrics"
+	"github.com/lib/pq"
 )
 
 // Provides all functions to run individual operations and Transactions
 type Store interface {
 	Querier
 	TransferTx(ctx context.Context, params TransferTxParams) (result TransferTxResult, err error)
+	CreateAccountTx(ctx context.Context, params CreateAccountParams) (account Account, err error)
+	CreateSessionTx(ctx context.Context, params CreateSessionParams) (session Session, err error)
+	EnableTOTPTx(ctx context.Context, params EnableTOTPTxParams) (user User, err error)
+	ChangePasswordTx(ctx context.Context, params UpdateUserPasswordParams) (user User, err error)
+	ResetPasswordTx(ctx context.Context, params ResetPasswordTxParams) (user User, err error)
+	VerifyEmailTx(ctx context.Context, params VerifyEmailTxParams) (user User, err error)
+	DeleteUserTx(ctx context.Context, params DeleteUserTxParams) (user User, err error)
+	SubmitKYCTx(ctx context.Context, params CreateKYCSubmissionParams) (submission KycSubmission, err error)
+	ReviewKYCTx(ctx context.Context, params ReviewKYCSubmissionParams) (result ReviewKYCTxResult, err error)
+	CreateTransferRequestTx(ctx context.Context, params CreateTransferRequestTxParams) (request TransferRequest, err error)
+	DecideTransferTx(ctx context.Context, params DecideTransferTxParams) (result DecideTransferTxResult, err error)
+	UpdateApprovalThresholdTx(ctx context.Context, params UpdateApprovalThresholdTxParams) (result UpdateApprovalThresholdTxResult, err error)
+	DecideThresholdChangeTx(ctx context.Context, params DecideThresholdChangeTxParams) (result DecideThresholdChangeTxResult, err error)
+	CreateAccountGrantTx(ctx context.Context, params CreateAccountGrantParams) (grant AccountGrant, err error)
 }
 
 // Provides all functions to run individual operations and Transactions
@@ -41,8 +60,10 @@ func (st *SQLStore) execTx(ctx context.Context, fn func(q *Queries) error) error
 	if err != nil {
 		rbErr := tx.Rollback()
 		if rbErr != nil {
-			return fmt.Errorf("tx error: %v, rollback error: %v", err, rbErr)
+			slog.ErrorContext(ctx, "unable to roll back transaction", "error", rbErr, "cause", err)
+			return fmt.Errorf("tx error: %w, rollback error: %v", err, rbErr)
 		}
+		slog.DebugContext(ctx, "transaction rolled back", "error", err)
 		return err
 	}
 	return tx.Commit()
@@ -50,9 +71,11 @@ func (st *SQLStore) execTx(ctx context.Context, fn func(q *Queries) error) error
 
 // Contains the input parameters for all the operations inside a Transfer transaction
 type TransferTxParams struct {
-	FromAccountID uuid.UUID `json:"fromAccountId"`
-	ToAccountID   uuid.UUID `json:"toAccountId"`
-	Amount        float64   `json:"amount"`
+	FromAccountID uuid.UUID           `json:"fromAccountId"`
+	ToAccountID   uuid.UUID           `json:"toAccountId"`
+	Amount        float64             `json:"amount"`
+	StepUpToken   *ConsumeTokenParams `json:"-"` // Redeemed along with the transfer when set
+	GrantID       uuid.NullUUID       `json:"-"` // Charged with the amount when the transfer is made under a grant
 }
 
 // Contains all the results out of a transfer transaction
@@ -65,55 +88,188 @@ type TransferTxResult struct {
 }
 
 // Performs all the necessary operations for a transfer from one account to another.
-// It creates a transfer record, adds account entries and updates balances within a single database transaction.
+// It creates a transfer record, adds account entries, updates balances and records the transfer events within a single
+// database transaction.
 func (st *SQLStore) TransferTx(ctx context.Context, params TransferTxParams) (result TransferTxResult, err error) {
+	start := time.Now()
 	err = st.execTx(ctx, func(q *Queries) error {
-		// Create the transfer record
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
-			FromAccountID: params.FromAccountID,
-			ToAccountID:   params.ToAccountID,
-			Amount:        params.Amount,
-		})
+		err = redeemToken(ctx, q, params.StepUpToken)
 		if err != nil {
 			return err
 		}
+		result, err = transfer(ctx, q, params)
+		return err
+	})
 
-		// Add From Account entry
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: params.FromAccountID,
-			Amount:    -params.Amount,
-		})
-		if err != nil {
-			return err
-		}
+	if err != nil {
+		metrics.ObserveTransferTx(start, transferFailureReason(err))
+		return result, fmt.Errorf("unable to execute transaction: %w", err)
+	}
+	metrics.ObserveTransferTx(start, "")
+	metrics.ObserveTransfer(result.FromAccount.Currency, result.Transfer.Amount)
+	return
+}
 
-		// Add From Account entry
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: params.ToAccountID,
-			Amount:    params.Amount,
-		})
-		if err != nil {
-			return err
-		}
+// Labels a failed transfer for the metrics. Database errors are labelled with their condition name, so deadlocks and
+// serialization failures show up as deadlock_detected and serialization_failure.
+func transferFailureReason(err error) string {
+	var pqErr *pq.Error
+	switch {
+	case errors.Is(err, ErrInsufficientFunds):
+		return "insufficient_funds"
+	case errors.Is(err, ErrCurrencyMismatch):
+		return "currency_mismatch"
+	case errors.Is(err, ErrAccountClosed):
+		return "account_closed"
+	case errors.Is(err, ErrAccountFrozen):
+		return "account_frozen"
+	case errors.Is(err, ErrTokenUsed):
+		return "token_used"
+	case errors.Is(err, ErrGrantLimitExceeded):
+		return "grant_limit_exceeded"
+	case errors.Is(err, ErrRecordNotFound):
+		return "account_not_found"
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "canceled"
+	case errors.As(err, &pqErr):
+		return pqErr.Code.Name()
+	}
+	return "unknown"
+}
 
-		if params.FromAccountID.String() < params.ToAccountID.String() {
-			result.FromAccount, result.ToAccount, err = modAccountsBalance(ctx, q, params.FromAccountID, -params.Amount, params.ToAccountID, params.Amount)
-		} else {
-			result.ToAccount, result.FromAccount, err = modAccountsBalance(ctx, q, params.ToAccountID, params.Amount, params.FromAccountID, -params.Amount)
-		}
-		if err != nil {
-			return err
-		}
+// Records a single use token as consumed, meant to run inside the transaction of the operation it authorizes so the
+// token is only spent if the operation commits. Concurrent redemptions wait for each other, the loser gets
+// ErrTokenUsed. Does nothing without a token.
+func redeemToken(ctx context.Context, q *Queries, params *ConsumeTokenParams) error {
+	if params == nil {
+		return nil
+	}
+	rows, err := q.ConsumeToken(ctx, *params)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrTokenUsed
+	}
+	return nil
+}
 
+// Adds the amount of a transfer to the running total of the grant it's made under. The grant is locked first, so
+// concurrent transfers under it are serialized and can't add up past its limit. Does nothing without a grant.
+func chargeGrant(ctx context.Context, q *Queries, grantID uuid.NullUUID, accountID uuid.UUID, amount float64) error {
+	if !grantID.Valid {
 		return nil
+	}
+	grant, err := q.GetAccountGrantForUpdate(ctx, grantID.UUID)
+	if err != nil {
+		return err
+	}
+	// The grant may have been revoked or have expired since the caller checked it
+	if grant.AccountID != accountID || grant.RevokedAt.Valid || !grant.ExpiresAt.After(time.Now()) {
+		return ErrForbidden
+	}
+	if grant.Transferred+amount > grant.TransferLimit {
+		return fmt.Errorf("%w (%v left)", ErrGrantLimitExceeded, grant.TransferLimit-grant.Transferred)
+	}
+	_, err = q.AddAccountGrantTransferred(ctx, AddAccountGrantTransferredParams{
+		Amount: amount,
+		ID:     grant.ID,
 	})
+	return err
+}
 
+// Creates the transfer record and entries and updates both balances, meant to run inside a transaction
+func transfer(ctx context.Context, q *Queries, params TransferTxParams) (result TransferTxResult, err error) {
+	err = chargeGrant(ctx, q, params.GrantID, params.FromAccountID, params.Amount)
 	if err != nil {
-		return result, fmt.Errorf("unable to execute transaction: %v", err)
+		return
 	}
+
+	// Create the transfer record
+	result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+		FromAccountID: params.FromAccountID,
+		ToAccountID:   params.ToAccountID,
+		Amount:        params.Amount,
+	})
+	if err != nil {
+		return
+	}
+
+	// Add From Account entry
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: params.FromAccountID,
+		Amount:    -params.Amount,
+	})
+	if err != nil {
+		return
+	}
+
+	// Add From Account entry
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: params.ToAccountID,
+		Amount:    params.Amount,
+	})
+	if err != nil {
+		return
+	}
+
+	if params.FromAccountID.String() < params.ToAccountID.String() {
+		result.FromAccount, result.ToAccount, err = modAccountsBalance(ctx, q, params.FromAccountID, -params.Amount, params.ToAccountID, params.Amount)
+	} else {
+		result.ToAccount, result.FromAccount, err = modAccountsBalance(ctx, q, params.ToAccountID, params.Amount, params.FromAccountID, -params.Amount)
+	}
+	if err != nil {
+		return
+	}
+
+	// Both rows are locked by now, returning an error rolls the whole transfer back
+	if result.FromAccount.Currency != result.ToAccount.Currency {
+		err = ErrCurrencyMismatch
+		return
+	}
+	if result.FromAccount.Balance < 0 {
+		err = ErrInsufficientFunds
+		return
+	}
+	if result.ToAccount.ClosedAt.Valid {
+		err = ErrAccountClosed
+		return
+	}
+	if result.FromAccount.FrozenAt.Valid || result.ToAccount.FrozenAt.Valid {
+		err = ErrAccountFrozen
+		return
+	}
+
+	// Events are written with the transfer so they're published if and only if it commits
+	err = recordTransferEvents(ctx, q, result)
 	return
 }
 
+// Records a transfer in the outbox and wakes the balance streams of both accounts. Webhook deliveries are fanned out
+// from the outbox later. Postgres holds notifications until the transaction commits and drops them on rollback.
+func recordTransferEvents(ctx context.Context, q *Queries, result TransferTxResult) error {
+	err := appendEvent(ctx, q, EventTransferCreated, AggregateTransfer, result.Transfer.ID, result)
+	if err != nil {
+		return err
+	}
+
+	sides := []TransferEventData{
+		{Transfer: result.Transfer, Account: result.FromAccount, Entry: result.FromEntry},
+		{Transfer: result.Transfer, Account: result.ToAccount, Entry: result.ToEntry},
+	}
+	for _, side := range sides {
+		err = appendEvent(ctx, q, EventBalanceChanged, AggregateAccount, side.Account.ID, side)
+		if err != nil {
+			return err
+		}
+		err = q.NotifyBalanceChanged(ctx, side.Account.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func modAccountsBalance(ctx context.Context, q *Queries, acc1ID uuid.UUID, acc1Amount float64, acc2ID uuid.UUID, acc2Amount float64) (acc1 Account, acc2 Account, err error) {
 	acc1, err = q.AddToAccountBalance(ctx, AddToAccountBalanceParams{
 		ID:     acc1ID,
@@ -2,9 +2,17 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/metrics"
 	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -25,7 +33,7 @@ func TestTransferTx(t *testing.T) {
 	acc2, err := store.CreateAccount(context.Background(), CreateAccountParams{
 		Owner:    user2.Username,
 		Balance:  util.RandomMoney(),
-		Currency: util.RandomCurrency(),
+		Currency: acc1.Currency,
 	})
 	require.NoError(t, err)
 
@@ -140,7 +148,7 @@ func TestTransferTxDeadlock(t *testing.T) {
 	acc2, err := store.CreateAccount(context.Background(), CreateAccountParams{
 		Owner:    user2.Username,
 		Balance:  util.RandomMoney(),
-		Currency: util.RandomCurrency(),
+		Currency: acc1.Currency,
 	})
 	require.NoError(t, err)
 
@@ -183,3 +191,92 @@ func TestTransferTxDeadlock(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, acc2.Balance, updatedAcc2.Balance)
 }
+
+func TestTransferTxDomainErrors(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	from, err := store.CreateAccount(context.Background(), CreateAccountParams{Owner: user.Username, Balance: 10, Currency: util.USD, Name: "from"})
+	require.NoError(t, err)
+	to, err := store.CreateAccount(context.Background(), CreateAccountParams{Owner: user.Username, Currency: util.USD, Name: "to"})
+	require.NoError(t, err)
+	other, err := store.CreateAccount(context.Background(), CreateAccountParams{Owner: user.Username, Currency: util.EUR, Name: "other"})
+	require.NoError(t, err)
+
+	insufficientFunds := testutil.ToFloat64(metrics.TransferTxFailures.WithLabelValues("insufficient_funds"))
+	_, err = store.TransferTx(context.Background(), TransferTxParams{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 11})
+	require.True(t, errors.Is(err, ErrInsufficientFunds))
+	require.Equal(t, insufficientFunds+1, testutil.ToFloat64(metrics.TransferTxFailures.WithLabelValues("insufficient_funds")))
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{FromAccountID: from.ID, ToAccountID: other.ID, Amount: 1})
+	require.True(t, errors.Is(err, ErrCurrencyMismatch))
+
+	// Failed transfers are rolled back
+	got, err := store.GetAccount(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, float64(10), got.Balance)
+}
+
+func TestTransferTxStepUpToken(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	from, err := store.CreateAccount(context.Background(), CreateAccountParams{Owner: user.Username, Balance: 10, Currency: util.USD, Name: "from"})
+	require.NoError(t, err)
+	to, err := store.CreateAccount(context.Background(), CreateAccountParams{Owner: user.Username, Currency: util.USD, Name: "to"})
+	require.NoError(t, err)
+
+	stepUp := &ConsumeTokenParams{
+		ID:        uuid.New(),
+		Username:  user.Username,
+		Purpose:   "step_up",
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+
+	// A failed transfer gives the token back
+	_, err = store.TransferTx(context.Background(), TransferTxParams{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 11, StepUpToken: stepUp})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 4, StepUpToken: stepUp})
+	require.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 4, StepUpToken: stepUp})
+	require.ErrorIs(t, err, ErrTokenUsed)
+	require.Equal(t, "token_used", transferFailureReason(err))
+
+	got, err := store.GetAccount(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, float64(6), got.Balance)
+
+	// Requests redeem the token the same way
+	request := CreateTransferRequestTxParams{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        4,
+		RequestedBy:   user.Username,
+		ExpiresAt:     time.Now().Add(time.Hour),
+		StepUpToken:   stepUp,
+	}
+	_, err = store.CreateTransferRequestTx(context.Background(), request)
+	require.ErrorIs(t, err, ErrTokenUsed)
+
+	request.StepUpToken = &ConsumeTokenParams{ID: uuid.New(), Username: user.Username, Purpose: "step_up", ExpiresAt: time.Now().Add(time.Minute)}
+	created, err := store.CreateTransferRequestTx(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, TransferRequestPending, created.Status)
+}
+
+func TestTransferFailureReason(t *testing.T) {
+	deadlock := &pq.Error{Code: "40P01"}
+
+	require.Equal(t, "insufficient_funds", transferFailureReason(fmt.Errorf("unable to execute transaction: %w", ErrInsufficientFunds)))
+	require.Equal(t, "currency_mismatch", transferFailureReason(ErrCurrencyMismatch))
+	require.Equal(t, "account_closed", transferFailureReason(ErrAccountClosed))
+	require.Equal(t, "account_frozen", transferFailureReason(ErrAccountFrozen))
+	require.Equal(t, "grant_limit_exceeded", transferFailureReason(ErrGrantLimitExceeded))
+	require.Equal(t, "account_not_found", transferFailureReason(sql.ErrNoRows))
+	require.Equal(t, "canceled", transferFailureReason(context.Canceled))
+	require.Equal(t, "deadlock_detected", transferFailureReason(fmt.Errorf("wrapped: %w", deadlock)))
+	require.Equal(t, "serialization_failure", transferFailureReason(&pq.Error{Code: "40001"}))
+	require.Equal(t, "unknown", transferFailureReason(errors.New("boom")))
+}
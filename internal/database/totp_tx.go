@@ -0,0 +1,44 @@
+package database
+
+import (
+	"context"
+	"fmt"
+)
+
+// Contains the input parameters to turn on two factor authentication for a user
+type EnableTOTPTxParams struct {
+	Username            string   `json:"username"`
+	HashedRecoveryCodes []string `json:"hashedRecoveryCodes"`
+}
+
+// Enables TOTP for a user and replaces its recovery codes within a single database transaction
+func (st *SQLStore) EnableTOTPTx(ctx context.Context, params EnableTOTPTxParams) (user User, err error) {
+	err = st.execTx(ctx, func(q *Queries) error {
+		user, err = q.EnableUserTOTP(ctx, params.Username)
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteUserRecoveryCodes(ctx, params.Username)
+		if err != nil {
+			return err
+		}
+
+		for _, code := range params.HashedRecoveryCodes {
+			_, err = q.CreateRecoveryCode(ctx, CreateRecoveryCodeParams{
+				Username:   params.Username,
+				HashedCode: code,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return user, fmt.Errorf("unable to execute transaction: %w", err)
+	}
+	return
+}
@@ -0,0 +1,283 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/metrics"
+)
+
+// Transfer request statuses
+const (
+	TransferRequestPending  = "pending"
+	TransferRequestExecuted = "executed"
+	TransferRequestRejected = "rejected"
+	TransferRequestExpired  = "expired"
+)
+
+// Approval threshold change statuses, only lowerings ever wait in pending
+const (
+	ThresholdChangePending  = "pending"
+	ThresholdChangeApplied  = "applied"
+	ThresholdChangeRejected = "rejected"
+	ThresholdChangeExpired  = "expired"
+)
+
+// Decisions an approver can take on a transfer request or a threshold lowering
+const (
+	TransferDecisionApproved = "approved"
+	TransferDecisionRejected = "rejected"
+)
+
+var (
+	// Returned when the request was already executed, rejected or expired, possibly by a concurrent request
+	ErrTransferRequestDecided = errors.New("transfer request was already decided")
+	// Returned when the request is still pending but its approval window is over
+	ErrTransferRequestExpired = errors.New("transfer request has expired")
+	// Returned when an approval threshold would be lowered or turned off directly, lowerings take a fresh proof of
+	// identity and another holder's approval
+	ErrThresholdLowered = errors.New("lowering or turning off the approval threshold requires re-authentication and another holder's approval")
+	// Returned when the threshold change was already applied, rejected or expired, possibly by a concurrent request
+	ErrThresholdChangeDecided = errors.New("threshold change was already decided")
+	// Returned when the threshold change is still pending but its approval window is over
+	ErrThresholdChangeExpired = errors.New("threshold change has expired")
+)
+
+// Contains the input parameters to create a transfer request
+type CreateTransferRequestTxParams struct {
+	FromAccountID uuid.UUID           `json:"fromAccountId"`
+	ToAccountID   uuid.UUID           `json:"toAccountId"`
+	Amount        float64             `json:"amount"`
+	RequestedBy   string              `json:"requestedBy"`
+	ExpiresAt     time.Time           `json:"expiresAt"`
+	StepUpToken   *ConsumeTokenParams `json:"-"` // Redeemed along with the request when set
+}
+
+// Creates a pending transfer request, redeeming the step-up token it was authorized with in the same database
+// transaction
+func (st *SQLStore) CreateTransferRequestTx(ctx context.Context, params CreateTransferRequestTxParams) (request TransferRequest, err error) {
+	err = st.execTx(ctx, func(q *Queries) error {
+		err = redeemToken(ctx, q, params.StepUpToken)
+		if err != nil {
+			return err
+		}
+		request, err = q.CreateTransferRequest(ctx, CreateTransferRequestParams{
+			FromAccountID: params.FromAccountID,
+			ToAccountID:   params.ToAccountID,
+			Amount:        params.Amount,
+			RequestedBy:   params.RequestedBy,
+			ExpiresAt:     params.ExpiresAt,
+		})
+		return err
+	})
+
+	if err != nil {
+		return request, fmt.Errorf("unable to execute transaction: %w", err)
+	}
+	return
+}
+
+// Contains the input parameters to approve or reject a transfer request
+type DecideTransferTxParams struct {
+	RequestID uuid.UUID `json:"requestId"`
+	Username  string    `json:"username"`
+	Decision  string    `json:"decision"`
+	Note      string    `json:"note"`
+	// Grant the requester asked for the transfer under, charged with the amount when it's approved
+	GrantID uuid.NullUUID `json:"-"`
+}
+
+// Contains the result of deciding on a transfer request, Transfer is only set when the request was approved
+type DecideTransferTxResult struct {
+	Request  TransferRequest   `json:"request"`
+	Approval TransferApproval  `json:"approval"`
+	Transfer *TransferTxResult `json:"transfer,omitempty"`
+}
+
+// Records an approver's decision on a pending transfer request within a single database transaction.
+// Approving executes the transfer in the same transaction so a request can never be approved without moving the money.
+// Access, verification and balance limit policies aren't enforced here, a request can wait for the whole approval
+// window so callers check them again before approving.
+func (st *SQLStore) DecideTransferTx(ctx context.Context, params DecideTransferTxParams) (result DecideTransferTxResult, err error) {
+	err = st.execTx(ctx, func(q *Queries) error {
+		// Locks the request so concurrent decisions are serialized
+		request, err := q.GetTransferRequestForUpdate(ctx, params.RequestID)
+		if err != nil {
+			return err
+		}
+		if request.Status != TransferRequestPending {
+			return ErrTransferRequestDecided
+		}
+		if !request.ExpiresAt.After(time.Now()) {
+			return ErrTransferRequestExpired
+		}
+		// Maker-checker, whoever asked for the transfer can't be the one deciding it
+		if request.RequestedBy == params.Username {
+			return ErrForbidden
+		}
+
+		result.Approval, err = q.CreateTransferApproval(ctx, CreateTransferApprovalParams{
+			TransferRequestID: request.ID,
+			Username:          params.Username,
+			Decision:          params.Decision,
+			Note:              params.Note,
+		})
+		if err != nil {
+			return err
+		}
+
+		decided := DecideTransferRequestParams{
+			ID:     request.ID,
+			Status: TransferRequestRejected,
+		}
+		if params.Decision == TransferDecisionApproved {
+			transferResult, err := transfer(ctx, q, TransferTxParams{
+				FromAccountID: request.FromAccountID,
+				ToAccountID:   request.ToAccountID,
+				Amount:        request.Amount,
+				GrantID:       params.GrantID,
+			})
+			if err != nil {
+				return err
+			}
+			result.Transfer = &transferResult
+			decided.Status = TransferRequestExecuted
+			decided.TransferID = uuid.NullUUID{UUID: transferResult.Transfer.ID, Valid: true}
+		}
+
+		result.Request, err = q.DecideTransferRequest(ctx, decided)
+		return err
+	})
+
+	if err != nil {
+		return result, fmt.Errorf("unable to execute transaction: %w", err)
+	}
+	if result.Transfer != nil {
+		metrics.ObserveTransfer(result.Transfer.FromAccount.Currency, result.Transfer.Transfer.Amount)
+	}
+	return
+}
+
+// Contains the input parameters to change the approval threshold of an account
+type UpdateApprovalThresholdTxParams struct {
+	AccountID uuid.UUID `json:"accountId"`
+	Username  string    `json:"username"`
+	Threshold float64   `json:"threshold"`
+}
+
+// Contains the result of changing an approval threshold
+type UpdateApprovalThresholdTxResult struct {
+	Account Account                 `json:"account"`
+	Change  ApprovalThresholdChange `json:"change"`
+}
+
+// Raises the approval threshold of an account and records the change in its audit trail within a single database
+// transaction. The account is locked while the current threshold is compared, so a concurrent change can't turn a
+// raise into an unchecked lowering. Lowerings go through DecideThresholdChangeTx instead.
+func (st *SQLStore) UpdateApprovalThresholdTx(ctx context.Context, params UpdateApprovalThresholdTxParams) (result UpdateApprovalThresholdTxResult, err error) {
+	err = st.execTx(ctx, func(q *Queries) error {
+		acc, err := q.GetAccountForUpdate(ctx, params.AccountID)
+		if err != nil {
+			return err
+		}
+		if ThresholdLowered(acc.ApprovalThreshold, params.Threshold) {
+			return ErrThresholdLowered
+		}
+
+		result.Account, err = q.UpdateAccountApprovalThreshold(ctx, UpdateAccountApprovalThresholdParams{
+			ID:                params.AccountID,
+			ApprovalThreshold: params.Threshold,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Change, err = q.CreateApprovalThresholdChange(ctx, CreateApprovalThresholdChangeParams{
+			AccountID:    params.AccountID,
+			Username:     params.Username,
+			OldThreshold: acc.ApprovalThreshold,
+			NewThreshold: params.Threshold,
+			Status:       ThresholdChangeApplied,
+		})
+		return err
+	})
+
+	if err != nil {
+		return result, fmt.Errorf("unable to execute transaction: %w", err)
+	}
+	return
+}
+
+// Reports whether a threshold change lets more transfers through without approval. 0 turns approvals off, so any
+// change from an active threshold to 0 or to a smaller amount counts.
+func ThresholdLowered(current, next float64) bool {
+	return current > 0 && (next == 0 || next < current)
+}
+
+// Contains the input parameters to approve or reject a pending threshold lowering
+type DecideThresholdChangeTxParams struct {
+	ChangeID uuid.UUID `json:"changeId"`
+	Username string    `json:"username"`
+	Decision string    `json:"decision"`
+}
+
+// Contains the result of deciding on a threshold lowering, Account has the threshold in force afterwards
+type DecideThresholdChangeTxResult struct {
+	Change  ApprovalThresholdChange `json:"change"`
+	Account Account                 `json:"account"`
+}
+
+// Records a holder's decision on a pending threshold lowering within a single database transaction, approving it sets
+// the new threshold. Like transfer requests, whoever proposed the lowering can't be the one deciding it. Access
+// policies aren't enforced here, callers check them before deciding.
+func (st *SQLStore) DecideThresholdChangeTx(ctx context.Context, params DecideThresholdChangeTxParams) (result DecideThresholdChangeTxResult, err error) {
+	err = st.execTx(ctx, func(q *Queries) error {
+		// Locks the change so concurrent decisions are serialized
+		change, err := q.GetApprovalThresholdChangeForUpdate(ctx, params.ChangeID)
+		if err != nil {
+			return err
+		}
+		if change.Status != ThresholdChangePending {
+			return ErrThresholdChangeDecided
+		}
+		if !change.ExpiresAt.Valid || !change.ExpiresAt.Time.After(time.Now()) {
+			return ErrThresholdChangeExpired
+		}
+		if change.Username == params.Username {
+			return ErrForbidden
+		}
+
+		result.Account, err = q.GetAccountForUpdate(ctx, change.AccountID)
+		if err != nil {
+			return err
+		}
+
+		decided := DecideApprovalThresholdChangeParams{
+			ID:        change.ID,
+			Status:    ThresholdChangeRejected,
+			DecidedBy: sql.NullString{String: params.Username, Valid: true},
+		}
+		if params.Decision == TransferDecisionApproved {
+			result.Account, err = q.UpdateAccountApprovalThreshold(ctx, UpdateAccountApprovalThresholdParams{
+				ID:                change.AccountID,
+				ApprovalThreshold: change.NewThreshold,
+			})
+			if err != nil {
+				return err
+			}
+			decided.Status = ThresholdChangeApplied
+		}
+
+		result.Change, err = q.DecideApprovalThresholdChange(ctx, decided)
+		return err
+	})
+
+	if err != nil {
+		return result, fmt.Errorf("unable to execute transaction: %w", err)
+	}
+	return
+}
@@ -0,0 +1,163 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecideTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+	maker := createRandomUser(t)
+	checker := createRandomUser(t)
+	currency := util.RandomCurrency()
+
+	from, err := store.CreateAccountTx(context.Background(), CreateAccountParams{Owner: maker.Username, Balance: 100, Currency: currency})
+	require.NoError(t, err)
+	to, err := store.CreateAccountTx(context.Background(), CreateAccountParams{Owner: checker.Username, Currency: currency})
+	require.NoError(t, err)
+
+	request, err := store.CreateTransferRequest(context.Background(), CreateTransferRequestParams{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        40,
+		RequestedBy:   maker.Username,
+		ExpiresAt:     time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferRequestPending, request.Status)
+
+	params := DecideTransferTxParams{
+		RequestID: request.ID,
+		Username:  checker.Username,
+		Decision:  TransferDecisionApproved,
+	}
+	result, err := store.DecideTransferTx(context.Background(), params)
+	require.NoError(t, err)
+	require.Equal(t, TransferRequestExecuted, result.Request.Status)
+	require.NotNil(t, result.Transfer)
+	require.Equal(t, result.Transfer.Transfer.ID, result.Request.TransferID.UUID)
+	require.Equal(t, float64(60), result.Transfer.FromAccount.Balance)
+	require.Equal(t, float64(40), result.Transfer.ToAccount.Balance)
+
+	// A request can only be decided once
+	_, err = store.DecideTransferTx(context.Background(), params)
+	require.True(t, errors.Is(err, ErrTransferRequestDecided))
+
+	approvals, err := store.ListTransferApprovals(context.Background(), request.ID)
+	require.NoError(t, err)
+	require.Len(t, approvals, 1)
+	require.Equal(t, checker.Username, approvals[0].Username)
+
+	expired, err := store.CreateTransferRequest(context.Background(), CreateTransferRequestParams{
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        10,
+		RequestedBy:   maker.Username,
+		ExpiresAt:     time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+
+	params.RequestID = expired.ID
+	_, err = store.DecideTransferTx(context.Background(), params)
+	require.True(t, errors.Is(err, ErrTransferRequestExpired))
+
+	n, err := store.ExpireTransferRequests(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, n, int64(1))
+
+	got, err := store.GetTransferRequest(context.Background(), expired.ID)
+	require.NoError(t, err)
+	require.Equal(t, TransferRequestExpired, got.Status)
+}
+
+func TestUpdateApprovalThresholdTx(t *testing.T) {
+	store := NewStore(testDB)
+	owner := createRandomUser(t)
+
+	acc, err := store.CreateAccountTx(context.Background(), CreateAccountParams{Owner: owner.Username, Currency: util.RandomCurrency()})
+	require.NoError(t, err)
+
+	params := UpdateApprovalThresholdTxParams{AccountID: acc.ID, Username: owner.Username, Threshold: 100}
+	result, err := store.UpdateApprovalThresholdTx(context.Background(), params)
+	require.NoError(t, err)
+	require.Equal(t, float64(100), result.Account.ApprovalThreshold)
+	require.Equal(t, float64(0), result.Change.OldThreshold)
+	require.Equal(t, float64(100), result.Change.NewThreshold)
+	require.Equal(t, owner.Username, result.Change.Username)
+
+	// Lowering and turning off need another holder's approval
+	params.Threshold = 50
+	_, err = store.UpdateApprovalThresholdTx(context.Background(), params)
+	require.ErrorIs(t, err, ErrThresholdLowered)
+	params.Threshold = 0
+	_, err = store.UpdateApprovalThresholdTx(context.Background(), params)
+	require.ErrorIs(t, err, ErrThresholdLowered)
+
+	require.Equal(t, ThresholdChangeApplied, result.Change.Status)
+}
+
+func TestDecideThresholdChangeTx(t *testing.T) {
+	store := NewStore(testDB)
+	owner := createRandomUser(t)
+	coOwner := createRandomUser(t)
+
+	acc, err := store.CreateAccountTx(context.Background(), CreateAccountParams{Owner: owner.Username, Currency: util.RandomCurrency()})
+	require.NoError(t, err)
+	_, err = store.UpdateApprovalThresholdTx(context.Background(), UpdateApprovalThresholdTxParams{AccountID: acc.ID, Username: owner.Username, Threshold: 100})
+	require.NoError(t, err)
+
+	propose := func(threshold float64, expiresAt time.Time) ApprovalThresholdChange {
+		change, err := store.CreateApprovalThresholdChange(context.Background(), CreateApprovalThresholdChangeParams{
+			AccountID:    acc.ID,
+			Username:     owner.Username,
+			OldThreshold: 100,
+			NewThreshold: threshold,
+			Status:       ThresholdChangePending,
+			ExpiresAt:    sql.NullTime{Time: expiresAt, Valid: true},
+		})
+		require.NoError(t, err)
+		return change
+	}
+
+	// The proposer can't approve their own lowering
+	change := propose(50, time.Now().Add(time.Hour))
+	params := DecideThresholdChangeTxParams{ChangeID: change.ID, Username: owner.Username, Decision: TransferDecisionApproved}
+	_, err = store.DecideThresholdChangeTx(context.Background(), params)
+	require.ErrorIs(t, err, ErrForbidden)
+
+	params.Username = coOwner.Username
+	params.Decision = TransferDecisionRejected
+	result, err := store.DecideThresholdChangeTx(context.Background(), params)
+	require.NoError(t, err)
+	require.Equal(t, ThresholdChangeRejected, result.Change.Status)
+	require.Equal(t, float64(100), result.Account.ApprovalThreshold)
+
+	_, err = store.DecideThresholdChangeTx(context.Background(), params)
+	require.ErrorIs(t, err, ErrThresholdChangeDecided)
+
+	expired := propose(0, time.Now().Add(-time.Minute))
+	_, err = store.DecideThresholdChangeTx(context.Background(), DecideThresholdChangeTxParams{ChangeID: expired.ID, Username: coOwner.Username, Decision: TransferDecisionApproved})
+	require.ErrorIs(t, err, ErrThresholdChangeExpired)
+
+	change = propose(0, time.Now().Add(time.Hour))
+	result, err = store.DecideThresholdChangeTx(context.Background(), DecideThresholdChangeTxParams{ChangeID: change.ID, Username: coOwner.Username, Decision: TransferDecisionApproved})
+	require.NoError(t, err)
+	require.Equal(t, ThresholdChangeApplied, result.Change.Status)
+	require.Equal(t, coOwner.Username, result.Change.DecidedBy.String)
+	require.Equal(t, float64(0), result.Account.ApprovalThreshold)
+}
+
+func TestThresholdLowered(t *testing.T) {
+	require.False(t, ThresholdLowered(0, 100))
+	require.False(t, ThresholdLowered(100, 200))
+	require.False(t, ThresholdLowered(100, 100))
+	require.False(t, ThresholdLowered(0, 0))
+	require.True(t, ThresholdLowered(100, 50))
+	require.True(t, ThresholdLowered(100, 0))
+}
@@ -7,6 +7,7 @@ package database
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -57,3 +58,169 @@ func (q *Queries) GetTransfer(ctx context.Context, id uuid.UUID) (Transfer, erro
 	)
 	return i, err
 }
+
+const listAccountTransfersAfter = `-- name: ListAccountTransfersAfter :many
+SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+	WHERE (from_account_id = $1 OR to_account_id = $1)
+		AND (created_at, id) > ($2::timestamptz, $3::uuid)
+	ORDER BY created_at, id
+	LIMIT $4
+`
+
+type ListAccountTransfersAfterParams struct {
+	AccountID       uuid.UUID `json:"accountId"`
+	CursorCreatedAt time.Time `json:"cursorCreatedAt"`
+	CursorID        uuid.UUID `json:"cursorId"`
+	PageSize        int32     `json:"pageSize"`
+}
+
+func (q *Queries) ListAccountTransfersAfter(ctx context.Context, arg ListAccountTransfersAfterParams) ([]Transfer, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountTransfersAfter, arg.AccountID, arg.CursorCreatedAt, arg.CursorID, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transfer
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAccountTransfersBefore = `-- name: ListAccountTransfersBefore :many
+SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+	WHERE (from_account_id = $1 OR to_account_id = $1)
+		AND (created_at, id) < ($2::timestamptz, $3::uuid)
+	ORDER BY created_at DESC, id DESC
+	LIMIT $4
+`
+
+type ListAccountTransfersBeforeParams struct {
+	AccountID       uuid.UUID `json:"accountId"`
+	CursorCreatedAt time.Time `json:"cursorCreatedAt"`
+	CursorID        uuid.UUID `json:"cursorId"`
+	PageSize        int32     `json:"pageSize"`
+}
+
+func (q *Queries) ListAccountTransfersBefore(ctx context.Context, arg ListAccountTransfersBeforeParams) ([]Transfer, error) {
+	rows, err := q.db.QueryContext(ctx, listAccountTransfersBefore, arg.AccountID, arg.CursorCreatedAt, arg.CursorID, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transfer
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCurrencyTransferVolumes = `-- name: ListCurrencyTransferVolumes :many
+SELECT accounts.currency, COUNT(*) AS transfers, SUM(transfers.amount)::float AS volume FROM transfers
+	JOIN accounts ON accounts.id = transfers.from_account_id
+	WHERE transfers.created_at >= $1::timestamptz AND transfers.created_at < $2::timestamptz
+	GROUP BY accounts.currency
+	ORDER BY accounts.currency
+`
+
+type ListCurrencyTransferVolumesParams struct {
+	PeriodStart time.Time `json:"periodStart"`
+	PeriodEnd   time.Time `json:"periodEnd"`
+}
+
+type ListCurrencyTransferVolumesRow struct {
+	Currency  string  `json:"currency"`
+	Transfers int64   `json:"transfers"`
+	Volume    float64 `json:"volume"`
+}
+
+func (q *Queries) ListCurrencyTransferVolumes(ctx context.Context, arg ListCurrencyTransferVolumesParams) ([]ListCurrencyTransferVolumesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listCurrencyTransferVolumes, arg.PeriodStart, arg.PeriodEnd)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCurrencyTransferVolumesRow
+	for rows.Next() {
+		var i ListCurrencyTransferVolumesRow
+		if err := rows.Scan(&i.Currency, &i.Transfers, &i.Volume); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOwnerTransfers = `-- name: ListOwnerTransfers :many
+SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+	WHERE from_account_id IN (SELECT id FROM accounts WHERE owner=$1)
+		OR to_account_id IN (SELECT id FROM accounts WHERE owner=$1)
+	ORDER BY created_at
+`
+
+func (q *Queries) ListOwnerTransfers(ctx context.Context, owner string) ([]Transfer, error) {
+	rows, err := q.db.QueryContext(ctx, listOwnerTransfers, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transfer
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Returned when a user tries to delete its account while some of its accounts still hold money
+var ErrNonZeroBalance = errors.New("all accounts must have a zero balance")
+
+// Contains the input parameters to delete a user
+type DeleteUserTxParams struct {
+	Username  string    `json:"username"`
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+// Anonymizes a user's personal fields and KYC documents, blocks its sessions and removes its credentials, audit data,
+// webhooks and access to other users' accounts within a single database transaction. Accounts, entries and transfers
+// are kept so the ledger stays consistent, the accounts are closed so they can't receive money nobody can take out.
+// The accounts are locked while their balances are checked so no transfer can land in them mid deletion.
+func (st *SQLStore) DeleteUserTx(ctx context.Context, params DeleteUserTxParams) (user User, err error) {
+	err = st.execTx(ctx, func(q *Queries) error {
+		accounts, err := q.ListOwnerAccountsForUpdate(ctx, params.Username)
+		if err != nil {
+			return err
+		}
+		for _, acc := range accounts {
+			if acc.Balance != 0 {
+				return ErrNonZeroBalance
+			}
+		}
+
+		err = q.CloseOwnerAccounts(ctx, CloseOwnerAccountsParams{
+			Owner:    params.Username,
+			ClosedAt: sql.NullTime{Time: params.DeletedAt, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		// Looked up by email, so it goes before the email is anonymized
+		err = q.DeleteUserPasswordResetRequests(ctx, params.Username)
+		if err != nil {
+			return err
+		}
+
+		user, err = q.AnonymizeUser(ctx, AnonymizeUserParams{
+			DeletedAt: params.DeletedAt,
+			Username:  params.Username,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.AnonymizeUserSessions(ctx, params.Username)
+		if err != nil {
+			return err
+		}
+		// Clears the document details and takes pending submissions out of the review queue
+		err = q.AnonymizeUserKYCSubmissions(ctx, params.Username)
+		if err != nil {
+			return err
+		}
+		err = q.DeleteUserAccountHolders(ctx, params.Username)
+		if err != nil {
+			return err
+		}
+		err = q.RevokeUserAccountGrants(ctx, params.Username)
+		if err != nil {
+			return err
+		}
+		err = q.DeleteUserRecoveryCodes(ctx, params.Username)
+		if err != nil {
+			return err
+		}
+		err = q.DeleteUserLoginAttempts(ctx, params.Username)
+		if err != nil {
+			return err
+		}
+		err = q.DeleteUserPasswordResetTokens(ctx, params.Username)
+		if err != nil {
+			return err
+		}
+		err = q.DeleteUserEmailVerificationTokens(ctx, params.Username)
+		if err != nil {
+			return err
+		}
+		return q.DeleteUserWebhooks(ctx, params.Username)
+	})
+
+	if err != nil {
+		return user, fmt.Errorf("unable to execute transaction: %w", err)
+	}
+	return
+}
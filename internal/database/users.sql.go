@@ -7,8 +7,70 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
+const advanceUserTOTPStep = `-- name: AdvanceUserTOTPStep :execrows
+UPDATE "users"
+	SET totp_last_step=$2
+	WHERE username=$1 AND totp_last_step < $2
+`
+
+type AdvanceUserTOTPStepParams struct {
+	Username     string `json:"username"`
+	TotpLastStep int64  `json:"totpLastStep"`
+}
+
+func (q *Queries) AdvanceUserTOTPStep(ctx context.Context, arg AdvanceUserTOTPStepParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, advanceUserTOTPStep, arg.Username, arg.TotpLastStep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const anonymizeUser = `-- name: AnonymizeUser :one
+UPDATE "users"
+	SET
+		full_name='Deleted user',
+		email=username || '@deleted.invalid',
+		hashed_password='',
+		password_changed_at=$1,
+		totp_secret='',
+		totp_enabled=FALSE,
+		email_verified_at=NULL,
+		deleted_at=$1
+	WHERE username=$2 AND deleted_at IS NULL
+	RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, totp_secret, totp_enabled, email_verified_at, deleted_at, kyc_status, totp_last_step
+`
+
+type AnonymizeUserParams struct {
+	DeletedAt time.Time `json:"deletedAt"`
+	Username  string    `json:"username"`
+}
+
+func (q *Queries) AnonymizeUser(ctx context.Context, arg AnonymizeUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, anonymizeUser, arg.DeletedAt, arg.Username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.EmailVerifiedAt,
+		&i.DeletedAt,
+		&i.KycStatus,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
 	username, 
@@ -16,7 +78,7 @@ INSERT INTO users (
 	full_name,
 	email
 ) VALUES ( $1, $2, $3, $4 )
-RETURNING username, hashed_password, full_name, email, password_changed_at, created_at
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, totp_secret, totp_enabled, email_verified_at, deleted_at, kyc_status, totp_last_step
 `
 
 type CreateUserParams struct {
@@ -41,12 +103,47 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.EmailVerifiedAt,
+		&i.DeletedAt,
+		&i.KycStatus,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const enableUserTOTP = `-- name: EnableUserTOTP :one
+UPDATE "users"
+	SET totp_enabled=TRUE
+	WHERE username=$1
+	RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, totp_secret, totp_enabled, email_verified_at, deleted_at, kyc_status, totp_last_step
+`
+
+func (q *Queries) EnableUserTOTP(ctx context.Context, username string) (User, error) {
+	row := q.db.QueryRowContext(ctx, enableUserTOTP, username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.EmailVerifiedAt,
+		&i.DeletedAt,
+		&i.KycStatus,
+		&i.TotpLastStep,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT username, hashed_password, full_name, email, password_changed_at, created_at FROM "users" WHERE username=$1 LIMIT 1
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, role, totp_secret, totp_enabled, email_verified_at, deleted_at, kyc_status, totp_last_step FROM "users" WHERE username=$1 LIMIT 1
 `
 
 func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
@@ -59,6 +156,283 @@ func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.EmailVerifiedAt,
+		&i.DeletedAt,
+		&i.KycStatus,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, role, totp_secret, totp_enabled, email_verified_at, deleted_at, kyc_status, totp_last_step FROM "users" WHERE email=$1 LIMIT 1
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.EmailVerifiedAt,
+		&i.DeletedAt,
+		&i.KycStatus,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const getUserPasswordChangedAt = `-- name: GetUserPasswordChangedAt :one
+SELECT password_changed_at FROM "users" WHERE username=$1 LIMIT 1
+`
+
+func (q *Queries) GetUserPasswordChangedAt(ctx context.Context, username string) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, getUserPasswordChangedAt, username)
+	var password_changed_at time.Time
+	err := row.Scan(&password_changed_at)
+	return password_changed_at, err
+}
+
+const getUserRole = `-- name: GetUserRole :one
+SELECT role FROM "users" WHERE username=$1 LIMIT 1
+`
+
+func (q *Queries) GetUserRole(ctx context.Context, username string) (string, error) {
+	row := q.db.QueryRowContext(ctx, getUserRole, username)
+	var role string
+	err := row.Scan(&role)
+	return role, err
+}
+
+const setUserEmailVerified = `-- name: SetUserEmailVerified :one
+UPDATE "users"
+	SET email_verified_at=now()
+	WHERE username=$1 AND email=$2
+	RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, totp_secret, totp_enabled, email_verified_at, deleted_at, kyc_status, totp_last_step
+`
+
+type SetUserEmailVerifiedParams struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+func (q *Queries) SetUserEmailVerified(ctx context.Context, arg SetUserEmailVerifiedParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, setUserEmailVerified, arg.Username, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.EmailVerifiedAt,
+		&i.DeletedAt,
+		&i.KycStatus,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const updateUserKYCStatus = `-- name: UpdateUserKYCStatus :one
+UPDATE "users"
+	SET kyc_status=$2
+	WHERE username=$1
+	RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, totp_secret, totp_enabled, email_verified_at, deleted_at, kyc_status, totp_last_step
+`
+
+type UpdateUserKYCStatusParams struct {
+	Username  string `json:"username"`
+	KycStatus string `json:"kycStatus"`
+}
+
+func (q *Queries) UpdateUserKYCStatus(ctx context.Context, arg UpdateUserKYCStatusParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserKYCStatus, arg.Username, arg.KycStatus)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.EmailVerifiedAt,
+		&i.DeletedAt,
+		&i.KycStatus,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :one
+UPDATE "users"
+	SET hashed_password=$2, password_changed_at=$3
+	WHERE username=$1
+	RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, totp_secret, totp_enabled, email_verified_at, deleted_at, kyc_status, totp_last_step
+`
+
+type UpdateUserPasswordParams struct {
+	Username          string    `json:"username"`
+	HashedPassword    string    `json:"hashedPassword"`
+	PasswordChangedAt time.Time `json:"passwordChangedAt"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserPassword, arg.Username, arg.HashedPassword, arg.PasswordChangedAt)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.EmailVerifiedAt,
+		&i.DeletedAt,
+		&i.KycStatus,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const updateUserPasswordHash = `-- name: UpdateUserPasswordHash :exec
+UPDATE "users"
+	SET hashed_password=$1
+	WHERE username=$2 AND hashed_password=$3
+`
+
+type UpdateUserPasswordHashParams struct {
+	NewHash  string `json:"newHash"`
+	Username string `json:"username"`
+	OldHash  string `json:"oldHash"`
+}
+
+func (q *Queries) UpdateUserPasswordHash(ctx context.Context, arg UpdateUserPasswordHashParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserPasswordHash, arg.NewHash, arg.Username, arg.OldHash)
+	return err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE "users"
+	SET
+		full_name=COALESCE($1, full_name),
+		email=COALESCE($2, email),
+		email_verified_at=CASE
+			WHEN $2 IS NULL OR $2=email THEN email_verified_at
+			ELSE NULL
+		END
+	WHERE username=$3
+	RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, totp_secret, totp_enabled, email_verified_at, deleted_at, kyc_status, totp_last_step
+`
+
+type UpdateUserProfileParams struct {
+	FullName sql.NullString `json:"fullName"`
+	Email    sql.NullString `json:"email"`
+	Username string         `json:"username"`
+}
+
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserProfile, arg.FullName, arg.Email, arg.Username)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.EmailVerifiedAt,
+		&i.DeletedAt,
+		&i.KycStatus,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const updateUserRole = `-- name: UpdateUserRole :one
+UPDATE "users"
+	SET role=$2
+	WHERE username=$1
+	RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, totp_secret, totp_enabled, email_verified_at, deleted_at, kyc_status, totp_last_step
+`
+
+type UpdateUserRoleParams struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserRole, arg.Username, arg.Role)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.EmailVerifiedAt,
+		&i.DeletedAt,
+		&i.KycStatus,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const updateUserTOTPSecret = `-- name: UpdateUserTOTPSecret :one
+UPDATE "users"
+	SET totp_secret=$2, totp_enabled=FALSE
+	WHERE username=$1
+	RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, role, totp_secret, totp_enabled, email_verified_at, deleted_at, kyc_status, totp_last_step
+`
+
+type UpdateUserTOTPSecretParams struct {
+	Username   string `json:"username"`
+	TotpSecret string `json:"totpSecret"`
+}
+
+func (q *Queries) UpdateUserTOTPSecret(ctx context.Context, arg UpdateUserTOTPSecretParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserTOTPSecret, arg.Username, arg.TotpSecret)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.EmailVerifiedAt,
+		&i.DeletedAt,
+		&i.KycStatus,
+		&i.TotpLastStep,
 	)
 	return i, err
 }
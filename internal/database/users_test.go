@@ -2,6 +2,8 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"testing"
 	"time"
 
@@ -28,6 +30,7 @@ func createRandomUser(t *testing.T) User {
 	require.Equal(t, usr.FullName, params.FullName)
 	require.Equal(t, usr.Email, params.Email)
 
+	require.Equal(t, usr.Role, util.CustomerRole)
 	require.True(t, usr.PasswordChangedAt.IsZero())
 	require.NotZero(t, usr.CreatedAt)
 
@@ -52,3 +55,132 @@ func TestGetUser(t *testing.T) {
 	require.WithinDuration(t, user1.PasswordChangedAt, user2.PasswordChangedAt, time.Second)
 	require.WithinDuration(t, user1.CreatedAt, user2.CreatedAt, time.Second)
 }
+
+func TestUpdateUserRole(t *testing.T) {
+	user1 := createRandomUser(t)
+	user2, err := testQueries.UpdateUserRole(context.Background(), UpdateUserRoleParams{
+		Username: user1.Username,
+		Role:     util.AdminRole,
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, user1.Username, user2.Username)
+	require.Equal(t, util.AdminRole, user2.Role)
+}
+
+func TestUpdateUserProfile(t *testing.T) {
+	user1 := createRandomUser(t)
+	user1, err := testQueries.SetUserEmailVerified(context.Background(), SetUserEmailVerifiedParams{
+		Username: user1.Username,
+		Email:    user1.Email,
+	})
+	require.NoError(t, err)
+	require.True(t, user1.EmailVerifiedAt.Valid)
+
+	// Names are not unique anymore
+	user2 := createRandomUser(t)
+	renamed, err := testQueries.UpdateUserProfile(context.Background(), UpdateUserProfileParams{
+		FullName: sql.NullString{String: user2.FullName, Valid: true},
+		Username: user1.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, user2.FullName, renamed.FullName)
+	require.Equal(t, user1.Email, renamed.Email)
+	require.True(t, renamed.EmailVerifiedAt.Valid)
+
+	newEmail := util.RandomEmail()
+	moved, err := testQueries.UpdateUserProfile(context.Background(), UpdateUserProfileParams{
+		Email:    sql.NullString{String: newEmail, Valid: true},
+		Username: user1.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, newEmail, moved.Email)
+	require.False(t, moved.EmailVerifiedAt.Valid)
+}
+
+func TestChangePasswordTx(t *testing.T) {
+	store := NewStore(testDB)
+	user1 := createRandomUser(t)
+
+	hash, err := util.HashPassword(util.RandomString(8))
+	require.NoError(t, err)
+	// The column keeps the instant, whatever zone the writer runs in
+	changedAt := time.Now().In(time.FixedZone("UTC-3", -3*60*60))
+
+	user2, err := store.ChangePasswordTx(context.Background(), UpdateUserPasswordParams{
+		Username:          user1.Username,
+		HashedPassword:    hash,
+		PasswordChangedAt: changedAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, hash, user2.HashedPassword)
+	require.WithinDuration(t, changedAt, user2.PasswordChangedAt, time.Second)
+
+	got, err := store.GetUserPasswordChangedAt(context.Background(), user1.Username)
+	require.NoError(t, err)
+	require.WithinDuration(t, changedAt, got, time.Second)
+
+	// The cause stays matchable through the transaction error
+	_, err = store.ChangePasswordTx(context.Background(), UpdateUserPasswordParams{
+		Username:          util.RandomOwner(),
+		HashedPassword:    hash,
+		PasswordChangedAt: changedAt,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestDeleteUserTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	account, err := store.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Balance:  100,
+		Currency: util.RandomCurrency(),
+	})
+	require.NoError(t, err)
+
+	params := DeleteUserTxParams{
+		Username:  user.Username,
+		DeletedAt: time.Now().UTC(),
+	}
+
+	// Money has to be moved out first
+	_, err = store.DeleteUserTx(context.Background(), params)
+	require.True(t, errors.Is(err, ErrNonZeroBalance))
+
+	_, err = store.UpdateAccountBalance(context.Background(), UpdateAccountBalanceParams{
+		ID:      account.ID,
+		Balance: 0,
+	})
+	require.NoError(t, err)
+
+	deleted, err := store.DeleteUserTx(context.Background(), params)
+	require.NoError(t, err)
+	require.True(t, deleted.DeletedAt.Valid)
+	require.NotEqual(t, user.FullName, deleted.FullName)
+	require.NotEqual(t, user.Email, deleted.Email)
+	require.Empty(t, deleted.HashedPassword)
+
+	// The ledger is kept, the accounts can't receive money anymore
+	accounts, err := store.ListOwnerAccounts(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	require.True(t, accounts[0].ClosedAt.Valid)
+
+	sender, err := store.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    createRandomUser(t).Username,
+		Balance:  100,
+		Currency: account.Currency,
+	})
+	require.NoError(t, err)
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: sender.ID,
+		ToAccountID:   account.ID,
+		Amount:        1,
+	})
+	require.ErrorIs(t, err, ErrAccountClosed)
+
+	// A user can only be deleted once
+	_, err = store.DeleteUserTx(context.Background(), params)
+	require.True(t, errors.Is(err, sql.ErrNoRows))
+}
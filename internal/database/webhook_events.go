@@ -0,0 +1,44 @@
+package database
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Events webhooks can subscribe to
+const (
+	EventTransferCreated  = "transfer.created"  // money left one of the user's accounts
+	EventTransferReceived = "transfer.received" // money arrived to one of the user's accounts
+	EventAccountCreated   = "account.created"
+)
+
+var WebhookEventTypes = []string{EventTransferCreated, EventTransferReceived, EventAccountCreated}
+
+// Reports whether webhooks can subscribe to an event type
+func IsWebhookEventType(eventType string) bool {
+	return slices.Contains(WebhookEventTypes, eventType)
+}
+
+// Delivery states
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Body of every webhook request. The ID is the one of the outbox event the delivery was fanned out from.
+type WebhookEvent struct {
+	ID        uuid.UUID `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      any       `json:"data"`
+}
+
+// Data of the transfer events, seen from the account the event is about
+type TransferEventData struct {
+	Transfer Transfer `json:"transfer"`
+	Account  Account  `json:"account"`
+	Entry    Entry    `json:"entry"`
+}
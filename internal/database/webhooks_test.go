@@ -0,0 +1,134 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDeliveries(t *testing.T) {
+	store := NewStore(testDB)
+	sender := createRandomUser(t)
+	receiver := createRandomUser(t)
+
+	senderHook, err := store.CreateWebhook(context.Background(), CreateWebhookParams{
+		Username:   sender.Username,
+		Url:        "https://example.com/hooks",
+		Secret:     util.RandomString(32),
+		EventTypes: []string{EventTransferCreated},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{EventTransferCreated}, senderHook.EventTypes)
+
+	receiverHook, err := store.CreateWebhook(context.Background(), CreateWebhookParams{
+		Username:   receiver.Username,
+		Url:        "https://example.com/hooks",
+		Secret:     util.RandomString(32),
+		EventTypes: []string{EventTransferReceived, EventAccountCreated},
+	})
+	require.NoError(t, err)
+
+	from, err := store.CreateAccountTx(context.Background(), CreateAccountParams{Owner: sender.Username, Balance: 10, Currency: util.USD})
+	require.NoError(t, err)
+	to, err := store.CreateAccountTx(context.Background(), CreateAccountParams{Owner: receiver.Username, Currency: util.USD})
+	require.NoError(t, err)
+
+	// Transactions only append to the outbox, the dispatcher queues the deliveries
+	result, err := store.TransferTx(context.Background(), TransferTxParams{FromAccountID: from.ID, ToAccountID: to.ID, Amount: 4})
+	require.NoError(t, err)
+	page := ListWebhookDeliveriesAfterParams{WebhookID: senderHook.ID, PageSize: 10}
+	sent, err := store.ListWebhookDeliveriesAfter(context.Background(), page)
+	require.NoError(t, err)
+	require.Empty(t, sent)
+
+	queue := func(accountID uuid.UUID, eventType string, data any) CreateAccountWebhookDeliveriesParams {
+		event := WebhookEvent{ID: uuid.New(), Type: eventType, CreatedAt: time.Now().UTC(), Data: data}
+		payload, err := json.Marshal(event)
+		require.NoError(t, err)
+		return CreateAccountWebhookDeliveriesParams{EventID: event.ID, EventType: eventType, Payload: payload, AccountID: accountID}
+	}
+	sentEvent := queue(from.ID, EventTransferCreated, TransferEventData{Transfer: result.Transfer, Account: result.FromAccount, Entry: result.FromEntry})
+	for _, params := range []CreateAccountWebhookDeliveriesParams{
+		queue(to.ID, EventAccountCreated, to),
+		sentEvent,
+		queue(to.ID, EventTransferReceived, TransferEventData{Transfer: result.Transfer, Account: result.ToAccount, Entry: result.ToEntry}),
+		// Nobody among the holders subscribed to it
+		queue(from.ID, EventAccountCreated, from),
+	} {
+		_, err = store.CreateAccountWebhookDeliveries(context.Background(), params)
+		require.NoError(t, err)
+	}
+
+	// Queuing an event again is a no-op, the fan-out may read an outbox batch twice
+	n, err := store.CreateAccountWebhookDeliveries(context.Background(), sentEvent)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	sent, err = store.ListWebhookDeliveriesAfter(context.Background(), page)
+	require.NoError(t, err)
+	require.Len(t, sent, 1)
+	require.Equal(t, EventTransferCreated, sent[0].EventType)
+	require.Equal(t, WebhookDeliveryPending, sent[0].Status)
+
+	var event struct {
+		WebhookEvent
+		Data TransferEventData `json:"data"`
+	}
+	err = json.Unmarshal(sent[0].Payload, &event)
+	require.NoError(t, err)
+	require.Equal(t, sent[0].EventID, event.ID)
+	require.Equal(t, result.Transfer.ID, event.Data.Transfer.ID)
+	require.Equal(t, float64(6), event.Data.Account.Balance)
+
+	page.WebhookID = receiverHook.ID
+	received, err := store.ListWebhookDeliveriesAfter(context.Background(), page)
+	require.NoError(t, err)
+	require.Len(t, received, 2)
+	require.Equal(t, EventAccountCreated, received[0].EventType)
+	require.Equal(t, EventTransferReceived, received[1].EventType)
+
+	claimed, err := store.ClaimWebhookDeliveries(context.Background(), ClaimWebhookDeliveriesParams{
+		LeaseUntil: time.Now().Add(time.Minute),
+		BatchSize:  1000,
+	})
+	require.NoError(t, err)
+	var ours []WebhookDelivery
+	for _, delivery := range claimed {
+		if delivery.WebhookID == senderHook.ID {
+			ours = append(ours, delivery)
+		}
+	}
+	require.Len(t, ours, 1)
+	require.Equal(t, int32(1), ours[0].Attempts)
+
+	// Leased deliveries aren't claimed twice
+	claimed, err = store.ClaimWebhookDeliveries(context.Background(), ClaimWebhookDeliveriesParams{
+		LeaseUntil: time.Now().Add(time.Minute),
+		BatchSize:  1000,
+	})
+	require.NoError(t, err)
+	for _, delivery := range claimed {
+		require.NotEqual(t, ours[0].ID, delivery.ID)
+	}
+
+	err = store.MarkWebhookDelivered(context.Background(), MarkWebhookDeliveredParams{ID: ours[0].ID})
+	require.NoError(t, err)
+
+	page.WebhookID = senderHook.ID
+	sent, err = store.ListWebhookDeliveriesAfter(context.Background(), page)
+	require.NoError(t, err)
+	require.Equal(t, WebhookDeliveryDelivered, sent[0].Status)
+	require.True(t, sent[0].DeliveredAt.Valid)
+
+	// Deleting the webhook drops its delivery log
+	_, err = store.DeleteWebhook(context.Background(), DeleteWebhookParams{ID: senderHook.ID, Username: sender.Username})
+	require.NoError(t, err)
+	sent, err = store.ListWebhookDeliveriesAfter(context.Background(), page)
+	require.NoError(t, err)
+	require.Empty(t, sent)
+}
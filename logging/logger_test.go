@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "")
+	require.NoError(t, err)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	require.Equal(t, "req-1", RequestID(ctx))
+	logger.With("component", "test").InfoContext(ctx, "hello")
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	require.Equal(t, "hello", entry["msg"])
+	require.Equal(t, "req-1", entry["requestId"])
+	require.Equal(t, "test", entry["component"])
+
+	buf.Reset()
+	logger.Info("no request")
+	require.NotContains(t, buf.String(), "requestId")
+}
+
+func TestLoggerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "warn")
+	require.NoError(t, err)
+
+	logger.Info("dropped")
+	require.Zero(t, buf.Len())
+	logger.Warn("kept")
+	require.Contains(t, buf.String(), "kept")
+
+	_, err = New(&buf, "loud")
+	require.Error(t, err)
+}
+
+func TestLoggerRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, slog.LevelInfo))
+
+	logger.Info("login",
+		"username", "alice",
+		"password", "hunter2",
+		"refreshToken", "v2.local.abc",
+		slog.Group("webhook", "url", "https://example.com", "secret", "whsec"),
+	)
+
+	require.NotContains(t, buf.String(), "hunter2")
+	require.NotContains(t, buf.String(), "v2.local.abc")
+	require.NotContains(t, buf.String(), "whsec")
+
+	var entry struct {
+		Username     string            `json:"username"`
+		Password     string            `json:"password"`
+		RefreshToken string            `json:"refreshToken"`
+		Webhook      map[string]string `json:"webhook"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	require.Equal(t, "alice", entry.Username)
+	require.Equal(t, redacted, entry.Password)
+	require.Equal(t, redacted, entry.RefreshToken)
+	require.Equal(t, redacted, entry.Webhook["secret"])
+	require.Equal(t, "https://example.com", entry.Webhook["url"])
+}
+
+func TestRedactQuery(t *testing.T) {
+	require.Empty(t, RedactQuery(url.Values{}))
+
+	query := url.Values{"cursor": {"abc"}, "token": {"t1", "t2"}, "newPassword": {"p"}}
+	require.Equal(t, "cursor=abc&newPassword=%5BREDACTED%5D&token=%5BREDACTED%5D", RedactQuery(query))
+	// The original values are left alone
+	require.Equal(t, []string{"t1", "t2"}, query["token"])
+}
+
+func TestValidRequestID(t *testing.T) {
+	require.True(t, ValidRequestID(NewRequestID()))
+	require.True(t, ValidRequestID("trace:abc_1.2-3"))
+	require.False(t, ValidRequestID(""))
+	require.False(t, ValidRequestID("with space"))
+	require.False(t, ValidRequestID("line\nbreak"))
+	require.False(t, ValidRequestID(strings.Repeat("a", maxRequestIDLength+1)))
+}
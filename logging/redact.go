@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"log/slog"
+	"net/url"
+	"strings"
+)
+
+// Replaces the value of the secrets that end up in the logs
+const redacted = "[REDACTED]"
+
+// Keys are sensitive when, lower cased, they contain any of these
+var sensitiveKeyParts = []string{"password", "token", "secret", "authorization", "cookie", "otp"}
+
+// Reports whether a log attribute or query parameter with this key holds a secret
+func IsSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// Encodes a query string with the values of the sensitive parameters redacted
+func RedactQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+	clean := make(url.Values, len(query))
+	for key, values := range query {
+		if IsSensitive(key) {
+			values = []string{redacted}
+		}
+		clean[key] = values
+	}
+	return clean.Encode()
+}
+
+func redactAttr(_ []string, attr slog.Attr) slog.Attr {
+	if attr.Value.Kind() != slog.KindGroup && IsSensitive(attr.Key) {
+		return slog.String(attr.Key, redacted)
+	}
+	return attr
+}
@@ -0,0 +1,43 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// A Mailer for local development that writes every message to a file (or stdout) instead of delivering it
+type LocalMailer struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// Creates a LocalMailer appending to the file at path. An empty path writes to stdout.
+func NewLocalMailer(path string) (*LocalMailer, error) {
+	if path == "" {
+		return &LocalMailer{out: os.Stdout}, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open mail output file: %w", err)
+	}
+	return &LocalMailer{out: file}, nil
+}
+
+// Writes the message with its headers. Implements the Mailer interface.
+func (mlr *LocalMailer) Send(ctx context.Context, msg Message) error {
+	mlr.mu.Lock()
+	defer mlr.mu.Unlock()
+
+	_, err := fmt.Fprintf(mlr.out, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().Format(time.RFC1123Z),
+		msg.To,
+		msg.Subject,
+		msg.Body,
+	)
+	return err
+}
@@ -0,0 +1,16 @@
+package mail
+
+import "context"
+
+// A message to be delivered to a single recipient
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Interface to deliver emails to users
+type Mailer interface {
+	// Sends a message. Implementations must be safe for concurrent use.
+	Send(ctx context.Context, msg Message) error
+}
@@ -1,35 +1,144 @@
 package main
 
 import (
+	"context"
 	"database/sql"
-	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 
 	_ "github.com/golang/mock/mockgen/model"
 	"github.com/julianinsua/the_simp_bank/api"
+	"github.com/julianinsua/the_simp_bank/gapi"
 	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/logging"
+	"github.com/julianinsua/the_simp_bank/mail"
+	"github.com/julianinsua/the_simp_bank/metrics"
+	"github.com/julianinsua/the_simp_bank/outbox"
+	"github.com/julianinsua/the_simp_bank/token"
 	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/julianinsua/the_simp_bank/webhook"
 	_ "github.com/lib/pq"
 )
 
 func main() {
 	config, err := util.LoadConfig(".")
 	if err != nil {
-		log.Fatal("failed to log config file: ", err)
+		fatal("failed to log config file", err)
 	}
 
+	logger, err := logging.New(os.Stdout, config.LogLevel)
+	if err != nil {
+		fatal("invalid log level", err)
+	}
+	slog.SetDefault(logger)
+
 	db, err := sql.Open(config.DBDriver, config.DBSource)
 	if err != nil {
-		log.Fatal("unable to create database connection: ", err)
+		fatal("unable to create database connection", err)
+	}
+	err = metrics.RegisterDB(db, config.DBDriver)
+	if err != nil {
+		fatal("unable to expose the database metrics", err)
+	}
+
+	mailer, err := mail.NewLocalMailer(config.MailerOutput)
+	if err != nil {
+		fatal("unable to create mailer", err)
+	}
+
+	tokenMaker, err := token.NewPASETOMaker(config.SymetricKey)
+	if err != nil {
+		fatal("couldn't initialize token maker", err)
 	}
 
 	store := database.NewStore(db)
-	server, err := api.NewServer(config, store)
+	if config.WebhookPollInterval > 0 {
+		go webhook.NewDispatcher(config, store).Run(context.Background())
+	}
+	if config.OutboxRelayInterval > 0 {
+		publisher, err := outbox.NewPublisher(config)
+		if err != nil {
+			fatal("unable to create outbox publisher", err)
+		}
+		go outbox.NewRelay(config, store, config.OutboxConsumer, publisher).Run(context.Background())
+	}
+	if config.OutboxSweepInterval > 0 {
+		go outbox.NewSweeper(config, store).Run(context.Background())
+	}
+	if config.MetricsAddr != "" {
+		go runMetricsServer(config.MetricsAddr)
+	}
+	go runGRPCServer(config, store, tokenMaker, mailer)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	runHTTPServer(ctx, config, store, tokenMaker, mailer)
+}
+
+/*
+Starts the gin HTTP server, blocks until it stops. Once ctx is done the server is shut down gracefully, finishing the
+requests in flight and the work they left running.
+*/
+func runHTTPServer(ctx context.Context, config util.Config, store database.Store, tokenMaker token.PASETOMaker, mailer mail.Mailer) {
+	server, err := api.NewServer(config, store, tokenMaker, mailer)
 	if err != nil {
-		log.Fatal("failed to create new server: ", err)
+		fatal("failed to create new server", err)
 	}
 
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+		slog.Info("shutting down HTTP server")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+		defer cancel()
+		err := server.Shutdown(shutdownCtx)
+		if err != nil {
+			slog.Error("unable to shut down HTTP server gracefully", "error", err)
+		}
+	}()
+
+	slog.Info("starting HTTP server", "address", config.ServerAddr)
 	err = server.Start(config.ServerAddr)
 	if err != nil {
-		log.Fatal("failed to initialize server: ", err)
+		fatal("failed to initialize server", err)
+	}
+	<-stopped
+}
+
+/*
+Starts the gRPC server, blocks until it stops
+*/
+func runGRPCServer(config util.Config, store database.Store, tokenMaker token.PASETOMaker, mailer mail.Mailer) {
+	server, err := gapi.NewServer(config, store, tokenMaker, mailer)
+	if err != nil {
+		fatal("failed to create new gRPC server", err)
+	}
+
+	slog.Info("starting gRPC server", "address", config.GRPCServerAddr)
+	err = server.Start(config.GRPCServerAddr)
+	if err != nil {
+		fatal("failed to initialize gRPC server", err)
+	}
+}
+
+/*
+Starts the internal server Prometheus scrapes, blocks until it stops
+*/
+func runMetricsServer(addr string) {
+	slog.Info("starting metrics server", "address", addr)
+	err := metrics.Serve(addr)
+	if err != nil {
+		fatal("failed to initialize metrics server", err)
 	}
 }
+
+/*
+Logs the error that keeps the application from starting and exits
+*/
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
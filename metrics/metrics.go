@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "simplebank"
+
+// Outcomes of a TransferTx call
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+// Holds every metric the application exposes, along with the Go runtime and process metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+	// Latency of the HTTP requests by method, route template and status code
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the HTTP requests by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// Duration of the TransferTx calls
+	TransferTxDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "transfer_tx",
+		Name:      "duration_seconds",
+		Help:      "Duration of the transfer transactions.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"result"})
+
+	// Transfer transactions that failed, by reason. Deadlocks and serialization failures are counted here too
+	TransferTxFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "transfer_tx",
+		Name:      "failures_total",
+		Help:      "Transfer transactions that failed, by reason.",
+	}, []string{"reason"})
+
+	// Committed transfers by currency
+	Transfers = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transfers_total",
+		Help:      "Committed transfers by currency.",
+	}, []string{"currency"})
+
+	// Money moved by the committed transfers, by currency
+	TransferredAmount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transferred_amount_total",
+		Help:      "Amount moved by the committed transfers, by currency.",
+	}, []string{"currency"})
+
+	// Failed logins by reason
+	LoginFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "login_failures_total",
+		Help:      "Failed login attempts by reason.",
+	}, []string{"reason"})
+
+	// Sessions created by logins
+	SessionsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sessions_created_total",
+		Help:      "Sessions created by successful logins.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestDuration,
+		TransferTxDuration,
+		TransferTxFailures,
+		Transfers,
+		TransferredAmount,
+		LoginFailures,
+		SessionsCreated,
+	)
+}
+
+// Exposes the connection pool stats of the database, meant to be called once per pool
+func RegisterDB(db *sql.DB, name string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// Records a committed transfer
+func ObserveTransfer(currency string, amount float64) {
+	Transfers.WithLabelValues(currency).Inc()
+	TransferredAmount.WithLabelValues(currency).Add(amount)
+}
+
+// Records the duration of a TransferTx call and, when it failed, the reason why
+func ObserveTransferTx(start time.Time, failureReason string) {
+	result := ResultSuccess
+	if failureReason != "" {
+		result = ResultError
+		TransferTxFailures.WithLabelValues(failureReason).Inc()
+	}
+	TransferTxDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+}
+
+// Serves the metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// Serves the metrics on their own listener, kept apart from the public API so only the scraper can reach them. Blocks
+// until the server stops.
+func Serve(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return server.ListenAndServe()
+}
@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserveTransfer(t *testing.T) {
+	transfers := testutil.ToFloat64(Transfers.WithLabelValues("ARS"))
+	amount := testutil.ToFloat64(TransferredAmount.WithLabelValues("ARS"))
+
+	ObserveTransfer("ARS", 12.5)
+	ObserveTransfer("ARS", 2.5)
+
+	require.Equal(t, transfers+2, testutil.ToFloat64(Transfers.WithLabelValues("ARS")))
+	require.Equal(t, amount+15, testutil.ToFloat64(TransferredAmount.WithLabelValues("ARS")))
+}
+
+func TestObserveTransferTx(t *testing.T) {
+	failures := testutil.ToFloat64(TransferTxFailures.WithLabelValues("insufficient_funds"))
+
+	ObserveTransferTx(time.Now(), "")
+	ObserveTransferTx(time.Now(), "insufficient_funds")
+
+	require.Equal(t, failures+1, testutil.ToFloat64(TransferTxFailures.WithLabelValues("insufficient_funds")))
+	require.Equal(t, 2, testutil.CollectAndCount(TransferTxDuration))
+}
+
+func TestHandler(t *testing.T) {
+	db, err := sql.Open("postgres", "")
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, RegisterDB(db, "test"))
+	require.Error(t, RegisterDB(db, "test"))
+
+	SessionsCreated.Inc()
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	body, err := io.ReadAll(recorder.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "simplebank_sessions_created_total")
+	require.Contains(t, string(body), `go_sql_open_connections{db_name="test"}`)
+	require.Contains(t, string(body), "go_goroutines")
+}
@@ -0,0 +1,44 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// A Publisher writing every message as a JSON line to a file (or stdout), a stand-in for a broker in local development
+type FilePublisher struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// Creates a FilePublisher appending to the file at path. An empty path writes to stdout.
+func NewFilePublisher(path string) (*FilePublisher, error) {
+	if path == "" {
+		return &FilePublisher{out: os.Stdout}, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open outbox file: %w", err)
+	}
+	return &FilePublisher{out: file}, nil
+}
+
+// Writes the messages one per line. Implements the Publisher interface.
+func (pub *FilePublisher) Publish(ctx context.Context, msgs []Message) error {
+	pub.mu.Lock()
+	defer pub.mu.Unlock()
+
+	enc := json.NewEncoder(pub.out)
+	for _, msg := range msgs {
+		err := enc.Encode(msg)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
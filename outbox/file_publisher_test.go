@@ -0,0 +1,42 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilePublisher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+	publisher, err := NewPublisher(util.Config{OutboxPublisher: PublisherFile, OutboxFile: path})
+	require.NoError(t, err)
+
+	msgs := []Message{newMessage(randomOutboxEvent(1, 1)), newMessage(randomOutboxEvent(1, 2))}
+	err = publisher.Publish(context.Background(), msgs)
+	require.NoError(t, err)
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var got []Message
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var msg Message
+		err = json.Unmarshal(scanner.Bytes(), &msg)
+		require.NoError(t, err)
+		got = append(got, msg)
+	}
+	require.Len(t, got, 2)
+	require.Equal(t, msgs[0].ID, got[0].ID)
+	require.JSONEq(t, string(msgs[1].Data), string(got[1].Data))
+
+	_, err = NewPublisher(util.Config{OutboxPublisher: "kafka"})
+	require.Error(t, err)
+}
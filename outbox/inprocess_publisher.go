@@ -0,0 +1,44 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+)
+
+// Handles a message published in process. Returning an error makes the relay publish the message again later, to
+// every handler.
+type Handler func(ctx context.Context, msg Message) error
+
+// A Publisher calling the handlers subscribed in the same process, one message at a time in outbox order
+type InProcessPublisher struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+func NewInProcessPublisher() *InProcessPublisher {
+	return &InProcessPublisher{}
+}
+
+// Registers a handler for every message published from now on
+func (pub *InProcessPublisher) Subscribe(handler Handler) {
+	pub.mu.Lock()
+	defer pub.mu.Unlock()
+
+	pub.handlers = append(pub.handlers, handler)
+}
+
+// Runs every handler on each message, stopping at the first error. Implements the Publisher interface.
+func (pub *InProcessPublisher) Publish(ctx context.Context, msgs []Message) error {
+	pub.mu.RLock()
+	defer pub.mu.RUnlock()
+
+	for _, msg := range msgs {
+		for _, handler := range pub.handlers {
+			err := handler(ctx, msg)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/util"
+)
+
+// Publisher kinds the relay can be configured with
+const (
+	PublisherFile      = "file"
+	PublisherInProcess = "inprocess"
+)
+
+// A domain event read from the outbox. Delivery is at least once, consumers drop the events whose ID they've seen.
+type Message struct {
+	ID            uuid.UUID       `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregateType"`
+	AggregateID   uuid.UUID       `json:"aggregateId"`
+	Data          json.RawMessage `json:"data"`
+	CreatedAt     time.Time       `json:"createdAt"`
+}
+
+func newMessage(event database.Outbox) Message {
+	return Message{
+		ID:            event.EventID,
+		Type:          event.EventType,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		Data:          event.Payload,
+		CreatedAt:     event.CreatedAt,
+	}
+}
+
+// Hands outbox events to a broker, a log or in-process subscribers. Publish only returns nil once every message is
+// safely handed over, the relay moves the consumer offset past them right after. Messages come in outbox order.
+type Publisher interface {
+	Publish(ctx context.Context, msgs []Message) error
+}
+
+// Creates the publisher chosen in the configuration
+func NewPublisher(config util.Config) (Publisher, error) {
+	switch config.OutboxPublisher {
+	case PublisherFile:
+		return NewFilePublisher(config.OutboxFile)
+	case PublisherInProcess:
+		return NewInProcessPublisher(), nil
+	}
+	return nil, fmt.Errorf("unknown outbox publisher %q", config.OutboxPublisher)
+}
@@ -0,0 +1,98 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/util"
+)
+
+// Moves the events appended to the outbox to a Publisher. Each consumer keeps its own offset in the database, so
+// several relays can feed different publishers from the same outbox. Run a single relay per consumer.
+//
+// Delivery is at least once: the offset is saved after the publisher accepts a batch, a crash in between publishes
+// the batch again.
+type Relay struct {
+	store     database.Store
+	publisher Publisher
+	consumer  string
+	config    util.Config
+}
+
+// Creates a Relay publishing the outbox to publisher under the consumer name
+func NewRelay(config util.Config, store database.Store, consumer string, publisher Publisher) *Relay {
+	return &Relay{
+		store:     store,
+		publisher: publisher,
+		consumer:  consumer,
+		config:    config,
+	}
+}
+
+// Publishes new events every relay interval until the context is done
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.OutboxRelayInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Keep going while there are full batches waiting
+		for {
+			published, err := r.RelayPending(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "unable to relay outbox events", "consumer", r.consumer, "error", err)
+				break
+			}
+			if published < int(r.config.OutboxBatchSize) {
+				break
+			}
+		}
+	}
+}
+
+// Publishes the next batch of events after the consumer offset and moves the offset past them, returns how many
+// were published
+func (r *Relay) RelayPending(ctx context.Context) (int, error) {
+	offset, err := r.store.GetOutboxOffset(ctx, r.consumer)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+
+	events, err := r.store.ListOutboxEvents(ctx, database.ListOutboxEventsParams{
+		AfterTransactionID: offset.TransactionID,
+		AfterID:            offset.OutboxID,
+		BatchSize:          r.config.OutboxBatchSize,
+	})
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+
+	msgs := make([]Message, 0, len(events))
+	for _, event := range events {
+		msgs = append(msgs, newMessage(event))
+	}
+	err = r.publisher.Publish(ctx, msgs)
+	if err != nil {
+		return 0, err
+	}
+
+	last := events[len(events)-1]
+	err = r.store.SetOutboxOffset(ctx, database.SetOutboxOffsetParams{
+		Consumer:      r.consumer,
+		TransactionID: last.TransactionID,
+		OutboxID:      last.ID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
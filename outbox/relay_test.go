@@ -0,0 +1,137 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomOutboxEvent(transactionID, id int64) database.Outbox {
+	return database.Outbox{
+		ID:            id,
+		EventID:       uuid.New(),
+		EventType:     database.EventAccountCreated,
+		AggregateType: database.AggregateAccount,
+		AggregateID:   uuid.New(),
+		Payload:       json.RawMessage(`{"currency":"USD"}`),
+		TransactionID: transactionID,
+		CreatedAt:     time.Now(),
+	}
+}
+
+func TestRelayPending(t *testing.T) {
+	config := util.Config{OutboxBatchSize: 10}
+	offset := database.OutboxOffset{Consumer: "test", TransactionID: 700, OutboxID: 12}
+	events := []database.Outbox{randomOutboxEvent(702, 11), randomOutboxEvent(702, 14), randomOutboxEvent(705, 13)}
+	errPublish := errors.New("broker unavailable")
+
+	testCases := []struct {
+		name       string
+		publishErr error
+		buildStubs func(store *mock_db.MockStore)
+		check      func(t *testing.T, published []Message, n int, err error)
+	}{
+		{
+			name: "Published",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetOutboxOffset(gomock.Any(), gomock.Eq("test")).Times(1).Return(offset, nil)
+				store.EXPECT().
+					ListOutboxEvents(gomock.Any(), gomock.Eq(database.ListOutboxEventsParams{AfterTransactionID: 700, AfterID: 12, BatchSize: 10})).
+					Times(1).
+					Return(events, nil)
+				store.EXPECT().
+					SetOutboxOffset(gomock.Any(), gomock.Eq(database.SetOutboxOffsetParams{Consumer: "test", TransactionID: 705, OutboxID: 13})).
+					Times(1).
+					Return(nil)
+			},
+			check: func(t *testing.T, published []Message, n int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 3, n)
+				require.Len(t, published, 3)
+				for i, msg := range published {
+					require.Equal(t, events[i].EventID, msg.ID)
+					require.Equal(t, events[i].AggregateID, msg.AggregateID)
+					require.Equal(t, events[i].Payload, msg.Data)
+				}
+			},
+		},
+		{
+			name: "FirstRun",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetOutboxOffset(gomock.Any(), gomock.Any()).Times(1).Return(database.OutboxOffset{}, sql.ErrNoRows)
+				store.EXPECT().
+					ListOutboxEvents(gomock.Any(), gomock.Eq(database.ListOutboxEventsParams{BatchSize: 10})).
+					Times(1).
+					Return(events[:1], nil)
+				store.EXPECT().
+					SetOutboxOffset(gomock.Any(), gomock.Eq(database.SetOutboxOffsetParams{Consumer: "test", TransactionID: 702, OutboxID: 11})).
+					Times(1).
+					Return(nil)
+			},
+			check: func(t *testing.T, published []Message, n int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 1, n)
+			},
+		},
+		{
+			name: "NothingNew",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetOutboxOffset(gomock.Any(), gomock.Any()).Times(1).Return(offset, nil)
+				store.EXPECT().ListOutboxEvents(gomock.Any(), gomock.Any()).Times(1).Return([]database.Outbox{}, nil)
+				store.EXPECT().SetOutboxOffset(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, published []Message, n int, err error) {
+				require.NoError(t, err)
+				require.Zero(t, n)
+				require.Empty(t, published)
+			},
+		},
+		{
+			name:       "PublishFailed",
+			publishErr: errPublish,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetOutboxOffset(gomock.Any(), gomock.Any()).Times(1).Return(offset, nil)
+				store.EXPECT().ListOutboxEvents(gomock.Any(), gomock.Any()).Times(1).Return(events, nil)
+				// The offset stays put so the batch is published again
+				store.EXPECT().SetOutboxOffset(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, published []Message, n int, err error) {
+				require.ErrorIs(t, err, errPublish)
+				require.Zero(t, n)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			var published []Message
+			publisher := NewInProcessPublisher()
+			publisher.Subscribe(func(ctx context.Context, msg Message) error {
+				if tc.publishErr != nil {
+					return tc.publishErr
+				}
+				published = append(published, msg)
+				return nil
+			})
+
+			n, err := NewRelay(config, store, "test", publisher).RelayPending(context.Background())
+			tc.check(t, published, n, err)
+		})
+	}
+}
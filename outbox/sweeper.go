@@ -0,0 +1,63 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/util"
+)
+
+// Deletes the outbox events every consumer has published, once they are older than the retention. The retention keeps
+// recent events around for the balance streams resuming from a Last-Event-ID.
+//
+// The slowest consumer holds the sweep back, so the offset of a consumer that no longer runs has to be deleted from
+// outbox_offsets, and a new consumer only sees the events that weren't swept before it saved its first offset.
+type Sweeper struct {
+	store  database.Store
+	config util.Config
+}
+
+// Creates a Sweeper for the store outbox
+func NewSweeper(config util.Config, store database.Store) *Sweeper {
+	return &Sweeper{
+		store:  store,
+		config: config,
+	}
+}
+
+// Sweeps the outbox every sweep interval until the context is done
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.OutboxSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Keep going while there are full batches to delete
+		for {
+			deleted, err := s.SweepConsumed(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "unable to sweep the outbox", "error", err)
+				break
+			}
+			if deleted < int64(s.config.OutboxBatchSize) {
+				break
+			}
+		}
+	}
+}
+
+// Deletes a batch of events at or below the lowest consumer offset and older than the retention, returns how many
+// were deleted
+func (s *Sweeper) SweepConsumed(ctx context.Context) (int64, error) {
+	return s.store.DeleteConsumedOutboxEvents(ctx, database.DeleteConsumedOutboxEventsParams{
+		CreatedBefore: time.Now().Add(-s.config.OutboxRetention),
+		BatchSize:     s.config.OutboxBatchSize,
+	})
+}
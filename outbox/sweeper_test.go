@@ -0,0 +1,32 @@
+package outbox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSweepConsumed(t *testing.T) {
+	config := util.Config{OutboxBatchSize: 10, OutboxRetention: time.Hour}
+
+	ctrl := gomock.NewController(t)
+	store := mock_db.NewMockStore(ctrl)
+	store.EXPECT().
+		DeleteConsumedOutboxEvents(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, params database.DeleteConsumedOutboxEventsParams) (int64, error) {
+			require.Equal(t, int32(10), params.BatchSize)
+			require.WithinDuration(t, time.Now().Add(-time.Hour), params.CreatedBefore, time.Second)
+			return 4, nil
+		})
+
+	deleted, err := NewSweeper(config, store).SweepConsumed(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(4), deleted)
+}
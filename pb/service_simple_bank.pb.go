@@ -0,0 +1,109 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: service_simple_bank.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+var File_service_simple_bank_proto protoreflect.FileDescriptor
+
+const file_service_simple_bank_proto_rawDesc = "" +
+	"\n" +
+	"\x19service_simple_bank.proto\x12\x02pb\x1a\raccount.proto\x1a\rsession.proto\x1a\x0etransfer.proto\x1a\n" +
+	"user.proto2\xaa\x04\n" +
+	"\n" +
+	"SimpleBank\x12;\n" +
+	"\n" +
+	"CreateUser\x12\x15.pb.CreateUserRequest\x1a\x16.pb.CreateUserResponse\x12G\n" +
+	"\x0eGetCurrentUser\x12\x19.pb.GetCurrentUserRequest\x1a\x1a.pb.GetCurrentUserResponse\x128\n" +
+	"\tLoginUser\x12\x14.pb.LoginUserRequest\x1a\x15.pb.LoginUserResponse\x12M\n" +
+	"\x10RenewAccessToken\x12\x1b.pb.RenewAccessTokenRequest\x1a\x1c.pb.RenewAccessTokenResponse\x12D\n" +
+	"\rCreateAccount\x12\x18.pb.CreateAccountRequest\x1a\x19.pb.CreateAccountResponse\x12;\n" +
+	"\n" +
+	"GetAccount\x12\x15.pb.GetAccountRequest\x1a\x16.pb.GetAccountResponse\x12A\n" +
+	"\fListAccounts\x12\x17.pb.ListAccountsRequest\x1a\x18.pb.ListAccountsResponse\x12G\n" +
+	"\x0eCreateTransfer\x12\x19.pb.CreateTransferRequest\x1a\x1a.pb.CreateTransferResponseB)Z'github.com/julianinsua/the_simp_bank/pbb\x06proto3"
+
+var file_service_simple_bank_proto_goTypes = []any{
+	(*CreateUserRequest)(nil),        // 0: pb.CreateUserRequest
+	(*GetCurrentUserRequest)(nil),    // 1: pb.GetCurrentUserRequest
+	(*LoginUserRequest)(nil),         // 2: pb.LoginUserRequest
+	(*RenewAccessTokenRequest)(nil),  // 3: pb.RenewAccessTokenRequest
+	(*CreateAccountRequest)(nil),     // 4: pb.CreateAccountRequest
+	(*GetAccountRequest)(nil),        // 5: pb.GetAccountRequest
+	(*ListAccountsRequest)(nil),      // 6: pb.ListAccountsRequest
+	(*CreateTransferRequest)(nil),    // 7: pb.CreateTransferRequest
+	(*CreateUserResponse)(nil),       // 8: pb.CreateUserResponse
+	(*GetCurrentUserResponse)(nil),   // 9: pb.GetCurrentUserResponse
+	(*LoginUserResponse)(nil),        // 10: pb.LoginUserResponse
+	(*RenewAccessTokenResponse)(nil), // 11: pb.RenewAccessTokenResponse
+	(*CreateAccountResponse)(nil),    // 12: pb.CreateAccountResponse
+	(*GetAccountResponse)(nil),       // 13: pb.GetAccountResponse
+	(*ListAccountsResponse)(nil),     // 14: pb.ListAccountsResponse
+	(*CreateTransferResponse)(nil),   // 15: pb.CreateTransferResponse
+}
+var file_service_simple_bank_proto_depIdxs = []int32{
+	0,  // 0: pb.SimpleBank.CreateUser:input_type -> pb.CreateUserRequest
+	1,  // 1: pb.SimpleBank.GetCurrentUser:input_type -> pb.GetCurrentUserRequest
+	2,  // 2: pb.SimpleBank.LoginUser:input_type -> pb.LoginUserRequest
+	3,  // 3: pb.SimpleBank.RenewAccessToken:input_type -> pb.RenewAccessTokenRequest
+	4,  // 4: pb.SimpleBank.CreateAccount:input_type -> pb.CreateAccountRequest
+	5,  // 5: pb.SimpleBank.GetAccount:input_type -> pb.GetAccountRequest
+	6,  // 6: pb.SimpleBank.ListAccounts:input_type -> pb.ListAccountsRequest
+	7,  // 7: pb.SimpleBank.CreateTransfer:input_type -> pb.CreateTransferRequest
+	8,  // 8: pb.SimpleBank.CreateUser:output_type -> pb.CreateUserResponse
+	9,  // 9: pb.SimpleBank.GetCurrentUser:output_type -> pb.GetCurrentUserResponse
+	10, // 10: pb.SimpleBank.LoginUser:output_type -> pb.LoginUserResponse
+	11, // 11: pb.SimpleBank.RenewAccessToken:output_type -> pb.RenewAccessTokenResponse
+	12, // 12: pb.SimpleBank.CreateAccount:output_type -> pb.CreateAccountResponse
+	13, // 13: pb.SimpleBank.GetAccount:output_type -> pb.GetAccountResponse
+	14, // 14: pb.SimpleBank.ListAccounts:output_type -> pb.ListAccountsResponse
+	15, // 15: pb.SimpleBank.CreateTransfer:output_type -> pb.CreateTransferResponse
+	8,  // [8:16] is the sub-list for method output_type
+	0,  // [0:8] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_service_simple_bank_proto_init() }
+func file_service_simple_bank_proto_init() {
+	if File_service_simple_bank_proto != nil {
+		return
+	}
+	file_account_proto_init()
+	file_session_proto_init()
+	file_transfer_proto_init()
+	file_user_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_service_simple_bank_proto_rawDesc), len(file_service_simple_bank_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_service_simple_bank_proto_goTypes,
+		DependencyIndexes: file_service_simple_bank_proto_depIdxs,
+	}.Build()
+	File_service_simple_bank_proto = out.File
+	file_service_simple_bank_proto_goTypes = nil
+	file_service_simple_bank_proto_depIdxs = nil
+}
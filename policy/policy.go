@@ -0,0 +1,321 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/util"
+)
+
+// Errors returned when a rule forbids an operation, wrapped with the details. Transports map them to their own status
+// codes, any other error returned by a check means the check itself couldn't run.
+var (
+	ErrAccountNotFound     = errors.New("account not found")
+	ErrCurrencyMismatch    = errors.New("currency mismatch")
+	ErrAccessDenied        = errors.New("unauthorized to access account")
+	ErrGrantViewOnly       = errors.New("grant only allows viewing this account")
+	ErrGrantLimitExceeded  = errors.New("transfer exceeds the limit of the grant")
+	ErrGrantApproveMissing = errors.New("grant does not allow approving transfers")
+	ErrEmailNotVerified    = errors.New("email must be verified before making transfers")
+	ErrKYCRequired         = errors.New("identity verification is required for this operation")
+	ErrKYCBalanceLimit     = errors.New("transfer exceeds the balance allowed for accounts of unverified users")
+	ErrInvalidParent       = errors.New("pots can only be created inside a top level account you own with the same currency")
+	ErrNotOwnPot           = errors.New("internal moves are only allowed between an account and its own pots")
+	ErrAccountShared       = errors.New("internal moves need accounts without other holders, grants or an approval threshold")
+)
+
+// Roles allowed to move money out of an account
+var TransferRoles = []string{database.AccountHolderOwner, database.AccountHolderCoOwner}
+
+// Decides who can act on which account and which transfers can go through. Both the HTTP and the gRPC servers check
+// their requests here so the rules are the same whatever the transport.
+type Policy struct {
+	config util.Config
+	store  database.Store
+}
+
+// Creates a policy enforcing the limits in config
+func New(config util.Config, store database.Store) Policy {
+	return Policy{config: config, store: store}
+}
+
+// Reports whether a user passed identity verification
+func KYCVerified(usr database.User) bool {
+	return usr.KycStatus == database.KYCStatusVerified
+}
+
+// Reports whether a transfer out of an account has to wait for a second approval
+func RequiresApproval(acc database.Account, amount float64) bool {
+	return acc.ApprovalThreshold > 0 && amount >= acc.ApprovalThreshold
+}
+
+// Reports whether a transfer amount needs a fresh proof of identity
+func (p Policy) RequiresStepUp(amount float64) bool {
+	return p.config.StepUpThreshold > 0 && amount >= p.config.StepUpThreshold
+}
+
+// Returns an account a transfer in the given currency can use
+func (p Policy) ValidAccount(ctx context.Context, accID uuid.UUID, currency string) (database.Account, error) {
+	acc, err := p.store.GetAccount(ctx, accID)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return acc, fmt.Errorf("%w: %s", ErrAccountNotFound, accID)
+		}
+		return acc, err
+	}
+	if acc.Currency != currency {
+		return acc, fmt.Errorf("Account [%s] %w: has %s vs. %s", accID, ErrCurrencyMismatch, acc.Currency, currency)
+	}
+	return acc, nil
+}
+
+// Checks that a user accepted a holding on an account with one of the given roles, any role when none are given
+func (p Policy) HolderAllowed(ctx context.Context, accID uuid.UUID, username string, roles ...string) (database.AccountHolder, error) {
+	holder, err := p.store.GetAccountHolder(ctx, database.GetAccountHolderParams{
+		AccountID: accID,
+		Username:  username,
+	})
+	if err != nil && !errors.Is(err, database.ErrRecordNotFound) {
+		return holder, err
+	}
+	if err != nil || !holder.AcceptedAt.Valid || !holderHasRole(holder, roles) {
+		return holder, accessDenied(accID, username)
+	}
+	return holder, nil
+}
+
+// Checks that a user can see an account, as any of its holders or through any active grant
+func (p Policy) ViewAllowed(ctx context.Context, accID uuid.UUID, username string) error {
+	_, err := p.accessAllowed(ctx, accID, username, nil, func(database.AccountGrant) error {
+		return nil
+	})
+	return err
+}
+
+// Checks that a user can move money out of an account, as an owner or co-owner or through a grant with enough of its
+// transfer limit left
+func (p Policy) TransferAllowed(ctx context.Context, accID uuid.UUID, username string, amount float64) error {
+	_, err := p.TransferGrant(ctx, accID, username, amount)
+	return err
+}
+
+// Checks that a user can move money out of an account like TransferAllowed and returns the grant the transfer is made
+// under, invalid when the user holds the account. The transfer charges the grant with its amount, so its limit also
+// holds against transfers made concurrently.
+func (p Policy) TransferGrant(ctx context.Context, accID uuid.UUID, username string, amount float64) (uuid.NullUUID, error) {
+	return p.accessAllowed(ctx, accID, username, TransferRoles, func(grant database.AccountGrant) error {
+		if grant.TransferLimit <= 0 {
+			return ErrGrantViewOnly
+		}
+		if left := grant.TransferLimit - grant.Transferred; amount > left {
+			return fmt.Errorf("%w (%v left)", ErrGrantLimitExceeded, left)
+		}
+		return nil
+	})
+}
+
+// Checks that a user can approve or reject transfers out of an account, as an owner or co-owner or through a grant
+// that allows approvals
+func (p Policy) ApproveAllowed(ctx context.Context, accID uuid.UUID, username string) error {
+	_, err := p.accessAllowed(ctx, accID, username, TransferRoles, func(grant database.AccountGrant) error {
+		if !grant.CanApprove {
+			return ErrGrantApproveMissing
+		}
+		return nil
+	})
+	return err
+}
+
+// Enforces the sender policies for outgoing transfers, a verified email and a verified identity
+func (p Policy) TransfersAllowed(ctx context.Context, username string) error {
+	if !p.config.RequireVerifiedEmailTransfers && !p.config.KYCRequiredForTransfers {
+		return nil
+	}
+
+	usr, err := p.store.GetUser(ctx, username)
+	if err != nil {
+		return err
+	}
+	if p.config.RequireVerifiedEmailTransfers && !usr.EmailVerifiedAt.Valid {
+		return ErrEmailNotVerified
+	}
+	if p.config.KYCRequiredForTransfers && !KYCVerified(usr) {
+		return ErrKYCRequired
+	}
+	return nil
+}
+
+// Enforces the maximum balance of accounts owned by unverified users
+func (p Policy) IncomingTransferAllowed(ctx context.Context, toAcc database.Account, amount float64) error {
+	limit := p.config.KYCUnverifiedMaxBalance
+	if limit <= 0 || toAcc.Balance+amount <= limit {
+		return nil
+	}
+
+	owner, err := p.store.GetUser(ctx, toAcc.Owner)
+	if err != nil {
+		return err
+	}
+	if !KYCVerified(owner) {
+		return fmt.Errorf("%w (%v)", ErrKYCBalanceLimit, limit)
+	}
+	return nil
+}
+
+// Checks a transfer request again when it's approved. Requests wait up to the approval window, so the requester may
+// have lost access to the account or failed verification in the meantime, the destination may have reached the
+// balance limit of unverified users, and other transfers may have used up the requester's grant. Returns the grant the
+// transfer is made under like TransferGrant.
+func (p Policy) ApprovedTransferAllowed(ctx context.Context, request database.TransferRequest) (uuid.NullUUID, error) {
+	grantID, err := p.TransferGrant(ctx, request.FromAccountID, request.RequestedBy, request.Amount)
+	if err != nil {
+		return grantID, err
+	}
+	err = p.TransfersAllowed(ctx, request.RequestedBy)
+	if err != nil {
+		return grantID, err
+	}
+
+	toAcc, err := p.store.GetAccount(ctx, request.ToAccountID)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return grantID, fmt.Errorf("%w: %s", ErrAccountNotFound, request.ToAccountID)
+		}
+		return grantID, err
+	}
+	return grantID, p.IncomingTransferAllowed(ctx, toAcc, request.Amount)
+}
+
+// Enforces the KYC gate on account creation
+func (p Policy) AccountCreationAllowed(ctx context.Context, username string) error {
+	if !p.config.KYCRequiredForAccounts {
+		return nil
+	}
+
+	usr, err := p.store.GetUser(ctx, username)
+	if err != nil {
+		return err
+	}
+	if !KYCVerified(usr) {
+		return ErrKYCRequired
+	}
+	return nil
+}
+
+// Checks that a pot can be created inside an account: the user owns it, it has the pot's currency and it isn't a pot
+// itself
+func (p Policy) ValidParentAccount(ctx context.Context, parentID uuid.UUID, username, currency string) error {
+	parent, err := p.store.GetAccount(ctx, parentID)
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return ErrInvalidParent
+		}
+		return err
+	}
+	if parent.Owner != username || parent.Currency != currency || parent.ParentID.Valid {
+		return ErrInvalidParent
+	}
+	return nil
+}
+
+// Checks that money can move instantly between two accounts, skipping the step-up, approval and verification checks of
+// regular transfers. That's only safe while the money stays with a single person under the same rules: the accounts
+// have to be a parent and its pots, or pots of the same parent, none of them can have other holders or active grants
+// and none of them can have an approval threshold to get around.
+func (p Policy) InternalTransferAllowed(ctx context.Context, from, to database.Account, username string) error {
+	if !sameFamily(from, to) {
+		return ErrNotOwnPot
+	}
+
+	for _, acc := range []database.Account{from, to} {
+		if acc.Owner != username || acc.ApprovalThreshold > 0 {
+			return fmt.Errorf("%w: %s", ErrAccountShared, acc.ID)
+		}
+
+		holders, err := p.store.ListAccountHolders(ctx, acc.ID)
+		if err != nil {
+			return err
+		}
+		for _, holder := range holders {
+			if holder.Username != username {
+				return fmt.Errorf("%w: %s", ErrAccountShared, acc.ID)
+			}
+		}
+
+		grants, err := p.store.ListAccountGrants(ctx, acc.ID)
+		if err != nil {
+			return err
+		}
+		for _, grant := range grants {
+			if !grant.RevokedAt.Valid && grant.ExpiresAt.After(time.Now()) {
+				return fmt.Errorf("%w: %s", ErrAccountShared, acc.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// Reports whether one account is a pot of the other or both are pots of the same parent
+func sameFamily(a, b database.Account) bool {
+	switch {
+	case a.ParentID.Valid && b.ParentID.Valid:
+		return a.ParentID.UUID == b.ParentID.UUID
+	case a.ParentID.Valid:
+		return a.ParentID.UUID == b.ID
+	case b.ParentID.Valid:
+		return b.ParentID.UUID == a.ID
+	}
+	return false
+}
+
+// Checks that a user can act on an account, either as a holder with one of the given roles (any role when none are
+// given) or through an active grant accepted by grantCheck. Holdings take precedence over grants. Returns the grant
+// access was given through, invalid for holders. A grantee holds at most one active grant on an account.
+func (p Policy) accessAllowed(
+	ctx context.Context,
+	accID uuid.UUID,
+	username string,
+	holderRoles []string,
+	grantCheck func(grant database.AccountGrant) error,
+) (uuid.NullUUID, error) {
+	_, err := p.HolderAllowed(ctx, accID, username, holderRoles...)
+	if !errors.Is(err, ErrAccessDenied) {
+		return uuid.NullUUID{}, err
+	}
+
+	grant, err := p.store.GetActiveAccountGrant(ctx, database.GetActiveAccountGrantParams{
+		AccountID: accID,
+		Grantee:   username,
+	})
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return uuid.NullUUID{}, accessDenied(accID, username)
+		}
+		return uuid.NullUUID{}, err
+	}
+	err = grantCheck(grant)
+	if err != nil {
+		return uuid.NullUUID{}, err
+	}
+	return uuid.NullUUID{UUID: grant.ID, Valid: true}, nil
+}
+
+func accessDenied(accID uuid.UUID, username string) error {
+	return fmt.Errorf("User %s declared in token is %w %s", username, ErrAccessDenied, accID.String())
+}
+
+func holderHasRole(holder database.AccountHolder, roles []string) bool {
+	if len(roles) == 0 {
+		return true
+	}
+	for _, role := range roles {
+		if holder.Role == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,291 @@
+package policy
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferAllowed(t *testing.T) {
+	accID := uuid.New()
+	username := util.RandomOwner()
+	accepted := sql.NullTime{Time: time.Now(), Valid: true}
+
+	testCases := []struct {
+		name       string
+		amount     float64
+		buildStubs func(store *mock_db.MockStore)
+		checkErr   func(t *testing.T, err error)
+	}{
+		{
+			name:   "CoOwner",
+			amount: 100,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccountHolder(gomock.Any(), gomock.Any()).Times(1).
+					Return(database.AccountHolder{Role: database.AccountHolderCoOwner, AcceptedAt: accepted}, nil)
+				store.EXPECT().GetActiveAccountGrant(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "ViewerWithinGrant",
+			amount: 10,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccountHolder(gomock.Any(), gomock.Any()).Times(1).
+					Return(database.AccountHolder{Role: database.AccountHolderViewer, AcceptedAt: accepted}, nil)
+				store.EXPECT().GetActiveAccountGrant(gomock.Any(), gomock.Any()).Times(1).
+					Return(database.AccountGrant{TransferLimit: 10}, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "PendingInvitation",
+			amount: 10,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccountHolder(gomock.Any(), gomock.Any()).Times(1).
+					Return(database.AccountHolder{Role: database.AccountHolderCoOwner}, nil)
+				store.EXPECT().GetActiveAccountGrant(gomock.Any(), gomock.Any()).Times(1).
+					Return(database.AccountGrant{}, sql.ErrNoRows)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrAccessDenied)
+			},
+		},
+		{
+			name:   "GrantLimitExceeded",
+			amount: 11,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccountHolder(gomock.Any(), gomock.Any()).Times(1).Return(database.AccountHolder{}, sql.ErrNoRows)
+				store.EXPECT().GetActiveAccountGrant(gomock.Any(), gomock.Any()).Times(1).
+					Return(database.AccountGrant{TransferLimit: 10}, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrGrantLimitExceeded)
+			},
+		},
+		{
+			name:   "GrantPartlyUsed",
+			amount: 10,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccountHolder(gomock.Any(), gomock.Any()).Times(1).Return(database.AccountHolder{}, sql.ErrNoRows)
+				store.EXPECT().GetActiveAccountGrant(gomock.Any(), gomock.Any()).Times(1).
+					Return(database.AccountGrant{TransferLimit: 100, Transferred: 95}, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrGrantLimitExceeded)
+			},
+		},
+		{
+			name:   "ViewOnlyGrant",
+			amount: 1,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccountHolder(gomock.Any(), gomock.Any()).Times(1).Return(database.AccountHolder{}, sql.ErrNoRows)
+				store.EXPECT().GetActiveAccountGrant(gomock.Any(), gomock.Any()).Times(1).Return(database.AccountGrant{}, nil)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrGrantViewOnly)
+			},
+		},
+		{
+			name:   "StoreError",
+			amount: 1,
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccountHolder(gomock.Any(), gomock.Any()).Times(1).Return(database.AccountHolder{}, sql.ErrConnDone)
+			},
+			checkErr: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+				require.False(t, errors.Is(err, ErrAccessDenied))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			err := New(util.Config{}, store).TransferAllowed(context.Background(), accID, username, tc.amount)
+			tc.checkErr(t, err)
+		})
+	}
+}
+
+func TestValidAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	acc := database.Account{ID: uuid.New(), Currency: util.USD}
+	store := mock_db.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(acc.ID)).Times(2).Return(acc, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Not(acc.ID)).Times(1).Return(database.Account{}, sql.ErrNoRows)
+	checks := New(util.Config{}, store)
+
+	got, err := checks.ValidAccount(context.Background(), acc.ID, util.USD)
+	require.NoError(t, err)
+	require.Equal(t, acc, got)
+
+	_, err = checks.ValidAccount(context.Background(), acc.ID, util.EUR)
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = checks.ValidAccount(context.Background(), uuid.New(), util.USD)
+	require.ErrorIs(t, err, ErrAccountNotFound)
+}
+
+func TestTransfersAllowed(t *testing.T) {
+	unverified := database.User{Username: util.RandomOwner()}
+	verified := database.User{
+		Username:        util.RandomOwner(),
+		EmailVerifiedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		KycStatus:       database.KYCStatusVerified,
+	}
+
+	testCases := []struct {
+		name        string
+		config      util.Config
+		user        database.User
+		expectedErr error
+	}{
+		{name: "NoRequirements", user: unverified},
+		{name: "EmailRequired", config: util.Config{RequireVerifiedEmailTransfers: true}, user: unverified, expectedErr: ErrEmailNotVerified},
+		{name: "KYCRequired", config: util.Config{KYCRequiredForTransfers: true}, user: unverified, expectedErr: ErrKYCRequired},
+		{name: "Verified", config: util.Config{RequireVerifiedEmailTransfers: true, KYCRequiredForTransfers: true}, user: verified},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(tc.user.Username)).AnyTimes().Return(tc.user, nil)
+
+			err := New(tc.config, store).TransfersAllowed(context.Background(), tc.user.Username)
+			if tc.expectedErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestIncomingTransferAllowed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	owner := database.User{Username: util.RandomOwner()}
+	toAcc := database.Account{ID: uuid.New(), Owner: owner.Username, Balance: 900}
+	store := mock_db.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(owner.Username)).Times(1).Return(owner, nil)
+	checks := New(util.Config{KYCUnverifiedMaxBalance: 1000}, store)
+
+	require.NoError(t, checks.IncomingTransferAllowed(context.Background(), toAcc, 100))
+	require.ErrorIs(t, checks.IncomingTransferAllowed(context.Background(), toAcc, 101), ErrKYCBalanceLimit)
+}
+
+func TestApprovedTransferAllowed(t *testing.T) {
+	requester := database.User{Username: util.RandomOwner(), EmailVerifiedAt: sql.NullTime{Time: time.Now(), Valid: true}}
+	recipient := database.User{Username: util.RandomOwner()}
+	request := database.TransferRequest{
+		FromAccountID: uuid.New(),
+		ToAccountID:   uuid.New(),
+		Amount:        100,
+		RequestedBy:   requester.Username,
+	}
+	accepted := sql.NullTime{Time: time.Now(), Valid: true}
+
+	testCases := []struct {
+		name        string
+		config      util.Config
+		buildStubs  func(store *mock_db.MockStore)
+		expectedErr error
+	}{
+		{
+			name:   "StillAllowed",
+			config: util.Config{RequireVerifiedEmailTransfers: true},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccountHolder(gomock.Any(), gomock.Any()).Times(1).
+					Return(database.AccountHolder{Role: database.AccountHolderOwner, AcceptedAt: accepted}, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(requester.Username)).Times(1).Return(requester, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(request.ToAccountID)).Times(1).Return(database.Account{}, nil)
+			},
+		},
+		{
+			name: "RequesterRemoved",
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccountHolder(gomock.Any(), gomock.Any()).Times(1).Return(database.AccountHolder{}, sql.ErrNoRows)
+				store.EXPECT().GetActiveAccountGrant(gomock.Any(), gomock.Any()).Times(1).Return(database.AccountGrant{}, sql.ErrNoRows)
+			},
+			expectedErr: ErrAccessDenied,
+		},
+		{
+			name:   "RequesterNotVerified",
+			config: util.Config{KYCRequiredForTransfers: true},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccountHolder(gomock.Any(), gomock.Any()).Times(1).
+					Return(database.AccountHolder{Role: database.AccountHolderOwner, AcceptedAt: accepted}, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(requester.Username)).Times(1).Return(requester, nil)
+			},
+			expectedErr: ErrKYCRequired,
+		},
+		{
+			name:   "DestinationOverLimit",
+			config: util.Config{KYCUnverifiedMaxBalance: 1000},
+			buildStubs: func(store *mock_db.MockStore) {
+				store.EXPECT().GetAccountHolder(gomock.Any(), gomock.Any()).Times(1).
+					Return(database.AccountHolder{Role: database.AccountHolderOwner, AcceptedAt: accepted}, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(request.ToAccountID)).Times(1).
+					Return(database.Account{Owner: recipient.Username, Balance: 950}, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(recipient.Username)).Times(1).Return(recipient, nil)
+			},
+			expectedErr: ErrKYCBalanceLimit,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			_, err := New(tc.config, store).ApprovedTransferAllowed(context.Background(), request)
+			if tc.expectedErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestSameFamily(t *testing.T) {
+	parent := database.Account{ID: uuid.New()}
+	pot := database.Account{ID: uuid.New(), ParentID: uuid.NullUUID{UUID: parent.ID, Valid: true}}
+	sibling := database.Account{ID: uuid.New(), ParentID: uuid.NullUUID{UUID: parent.ID, Valid: true}}
+	otherPot := database.Account{ID: uuid.New(), ParentID: uuid.NullUUID{UUID: uuid.New(), Valid: true}}
+
+	require.True(t, sameFamily(parent, pot))
+	require.True(t, sameFamily(pot, parent))
+	require.True(t, sameFamily(pot, sibling))
+	require.False(t, sameFamily(parent, database.Account{ID: uuid.New()}))
+	require.False(t, sameFamily(pot, otherPot))
+	require.False(t, sameFamily(parent, otherPot))
+}
@@ -24,8 +24,8 @@ func NewJWTMaker(secretKey string) (JWTMaker, error) {
 }
 
 // JSON web token maker. Implements the Maker interface.
-func (mkr *JWTMaker) CreateToken(username string, duration time.Duration) (string, *JWTPayload, error) {
-	payload, err := NewJWTPayload(username, duration)
+func (mkr *JWTMaker) CreateToken(username, role string, duration time.Duration) (string, *JWTPayload, error) {
+	payload, err := NewJWTPayload(username, role, duration)
 	if err != nil {
 		return "", payload, fmt.Errorf("unable to cretae token payload")
 	}
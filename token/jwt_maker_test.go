@@ -18,7 +18,7 @@ func TestJWTMaker(t *testing.T) {
 	duration := time.Minute
 	issuedAt := time.Now()
 	expiredAt := issuedAt.Add(duration)
-	token, payload, err := maker.CreateToken(username, duration)
+	token, payload, err := maker.CreateToken(username, util.CustomerRole, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
@@ -28,6 +28,8 @@ func TestJWTMaker(t *testing.T) {
 	require.NotEmpty(t, claims)
 	require.NotZero(t, claims.Payload.ID)
 	require.Equal(t, claims.Username, username)
+	require.Equal(t, claims.Role, util.CustomerRole)
+	require.Equal(t, claims.Scopes, util.RoleScopes(util.CustomerRole))
 	require.WithinDuration(t, issuedAt, claims.IssuedAt.Time, time.Second)
 	require.WithinDuration(t, expiredAt, claims.ExpiresAt.Time, time.Second)
 }
@@ -37,7 +39,7 @@ func TestJWTMakerExpired(t *testing.T) {
 	require.NoError(t, err)
 
 	username := util.RandomOwner()
-	token, payload, err := maker.CreateToken(username, -time.Minute)
+	token, payload, err := maker.CreateToken(username, util.CustomerRole, -time.Minute)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
@@ -49,7 +51,7 @@ func TestJWTMakerExpired(t *testing.T) {
 }
 
 func TestJWTMakerNoneAlgorithm(t *testing.T) {
-	payload, err := NewJWTPayload(util.RandomOwner(), time.Minute)
+	payload, err := NewJWTPayload(util.RandomOwner(), util.CustomerRole, time.Minute)
 	require.NoError(t, err)
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodNone, payload)
 	tokenString, err := jwtToken.SignedString(jwt.UnsafeAllowNoneSignatureType)
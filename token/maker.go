@@ -4,8 +4,8 @@ import "time"
 
 // Interface to manage tokens
 type Maker interface {
-	// Creates a new tokenfor a specific user and for a given duration
-	CreateToken(username string, duration time.Duration) (string, *Payload, error)
+	// Creates a new tokenfor a specific user, scoped to its role, and for a given duration
+	CreateToken(username, role string, duration time.Duration) (string, *Payload, error)
 	// Verifies a token string
 	VerifyToken(token string) (*Payload, error)
 }
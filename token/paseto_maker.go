@@ -22,8 +22,8 @@ func NewPASETOMaker(symetricKey string) (PASETOMaker, error) {
 }
 
 // Creates a new PASETO V2 symetric token. Implements the Maker Interface
-func (mkr *PASETOMaker) CreateToken(username string, duration time.Duration) (string, *PASETOPayload, error) {
-	payload, err := NewPASETOPayload(username, duration)
+func (mkr *PASETOMaker) CreateToken(username, role string, duration time.Duration) (string, *PASETOPayload, error) {
+	payload, err := NewPASETOPayload(username, role, duration)
 	if err != nil {
 		return "", payload, err
 	}
@@ -31,6 +31,18 @@ func (mkr *PASETOMaker) CreateToken(username string, duration time.Duration) (st
 	return token, payload, err
 }
 
+// Creates a token restricted to a single purpose and optionally bound to a specific operation. It carries no role and no scopes.
+func (mkr *PASETOMaker) CreatePurposeToken(username, purpose, binding string, duration time.Duration) (string, *PASETOPayload, error) {
+	payload, err := NewPASETOPayload(username, "", duration)
+	if err != nil {
+		return "", payload, err
+	}
+	payload.Purpose = purpose
+	payload.Binding = binding
+	token, err := mkr.paseto.Encrypt(mkr.symetricKey, payload, nil)
+	return token, payload, err
+}
+
 // Verifies a token string using PASETO V2 Symetric encoding. Implements the Maker Interface.
 func (mkr *PASETOMaker) VerifyToken(token string) (*PASETOPayload, error) {
 	payload := &PASETOPayload{}
@@ -45,3 +57,15 @@ func (mkr *PASETOMaker) VerifyToken(token string) (*PASETOPayload, error) {
 	}
 	return payload, nil
 }
+
+// Verifies a token string and checks it was created for the given purpose.
+func (mkr *PASETOMaker) VerifyPurposeToken(token, purpose string) (*PASETOPayload, error) {
+	payload, err := mkr.VerifyToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Purpose != purpose {
+		return nil, ErrInvalidToken
+	}
+	return payload, nil
+}
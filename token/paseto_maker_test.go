@@ -17,7 +17,7 @@ func TestPASETOMaker(t *testing.T) {
 	duration := time.Minute
 	issuedAt := time.Now()
 	expiredAt := issuedAt.Add(duration)
-	token, payload, err := maker.CreateToken(username, duration)
+	token, payload, err := maker.CreateToken(username, util.CustomerRole, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
@@ -27,6 +27,8 @@ func TestPASETOMaker(t *testing.T) {
 	require.NotEmpty(t, claims)
 	require.NotZero(t, claims.ID)
 	require.Equal(t, claims.Username, username)
+	require.Equal(t, claims.Role, util.CustomerRole)
+	require.Equal(t, claims.Scopes, util.RoleScopes(util.CustomerRole))
 	require.WithinDuration(t, issuedAt, claims.IssuedAt, time.Second)
 	require.WithinDuration(t, expiredAt, claims.ExpiresAt, time.Second)
 }
@@ -36,7 +38,7 @@ func TestPASETOMakerExpired(t *testing.T) {
 	require.NoError(t, err)
 
 	username := util.RandomOwner()
-	token, payload, err := maker.CreateToken(username, -time.Minute)
+	token, payload, err := maker.CreateToken(username, util.CustomerRole, -time.Minute)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
@@ -46,3 +48,30 @@ func TestPASETOMakerExpired(t *testing.T) {
 	require.EqualError(t, err, ErrInvalidToken.Error())
 	require.Nil(t, payload)
 }
+
+func TestPASETOMakerPurpose(t *testing.T) {
+	maker, err := NewPASETOMaker(util.RandomString(33))
+	require.NoError(t, err)
+
+	username := util.RandomOwner()
+	token, payload, err := maker.CreatePurposeToken(username, PurposeMFAChallenge, "", time.Minute)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	require.Empty(t, payload.Scopes)
+
+	claims, err := maker.VerifyPurposeToken(token, PurposeMFAChallenge)
+	require.NoError(t, err)
+	require.Equal(t, username, claims.Username)
+	require.Equal(t, PurposeMFAChallenge, claims.Purpose)
+
+	claims, err = maker.VerifyPurposeToken(token, "other")
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, claims)
+
+	// Regular tokens have no purpose
+	token, _, err = maker.CreateToken(username, util.CustomerRole, time.Minute)
+	require.NoError(t, err)
+	claims, err = maker.VerifyPurposeToken(token, PurposeMFAChallenge)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, claims)
+}
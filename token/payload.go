@@ -6,16 +6,46 @@ import (
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/util"
 )
 
 var (
 	ErrInvalidToken = errors.New("token is invalid")
 )
 
+// Purposes for single use tokens that must never be accepted as access tokens
+const (
+	PurposeMFAChallenge = "mfa_challenge"
+	PurposeStepUp       = "step_up"
+)
+
 // The basic struct that holds the tokens related to the claims in a token
 type Payload struct {
 	ID       uuid.UUID `json:"id"`
 	Username string    `json:"username"`
+	Role     string    `json:"role"`
+	Scopes   []string  `json:"scopes"`
+	Purpose  string    `json:"purpose,omitempty"` // Empty for access and refresh tokens
+	Binding  string    `json:"binding,omitempty"` // Digest of the operation a purpose token was issued for
+}
+
+// Creates the common claims for a user, granting the scopes associated with its role.
+func newPayload(username, role string) (Payload, error) {
+	tokenId, err := uuid.NewRandom()
+	if err != nil {
+		return Payload{}, err
+	}
+	return Payload{ID: tokenId, Username: username, Role: role, Scopes: util.RoleScopes(role)}, nil
+}
+
+// Reports whether the token grants a given scope
+func (p Payload) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
 }
 
 // Holds the payload that is encoded inside the JWT token
@@ -24,20 +54,20 @@ type JWTPayload struct {
 	jwt.RegisteredClaims
 }
 
-// Creates a new JWT token payload using a username and a duration.
-func NewJWTPayload(username string, duration time.Duration) (*JWTPayload, error) {
-	tokenId, err := uuid.NewRandom()
+// Creates a new JWT token payload using a username, its role and a duration.
+func NewJWTPayload(username, role string, duration time.Duration) (*JWTPayload, error) {
+	common, err := newPayload(username, role)
 	if err != nil {
 		return nil, err
 	}
 
-	payload := JWTPayload{Payload{tokenId, username}, jwt.RegisteredClaims{
+	payload := JWTPayload{common, jwt.RegisteredClaims{
 		ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
 		IssuedAt:  jwt.NewNumericDate(time.Now()),
 		NotBefore: jwt.NewNumericDate(time.Now()),
 		Issuer:    "the_simp_bank",
 		Subject:   username,
-		ID:        tokenId.String(),
+		ID:        common.ID.String(),
 	}}
 	return &payload, nil
 }
@@ -49,13 +79,13 @@ type PASETOPayload struct {
 	IssuedAt  time.Time `json:"IssuedAt"`
 }
 
-// Creates a new PASETO token payload using username and duration.
-func NewPASETOPayload(username string, duration time.Duration) (*PASETOPayload, error) {
-	tokenId, err := uuid.NewRandom()
+// Creates a new PASETO token payload using username, role and duration.
+func NewPASETOPayload(username, role string, duration time.Duration) (*PASETOPayload, error) {
+	common, err := newPayload(username, role)
 	if err != nil {
 		return nil, err
 	}
 
-	payload := &PASETOPayload{Payload{tokenId, username}, time.Now().Add(duration), time.Now()}
+	payload := &PASETOPayload{common, time.Now().Add(duration), time.Now()}
 	return payload, nil
 }
@@ -10,12 +10,68 @@ import (
 Stores the configuration for the application
 */
 type Config struct {
-	DBDriver             string        `mapstructure:"DB_DRIVER"`
-	DBSource             string        `mapstructure:"DB_SOURCE"`
-	ServerAddr           string        `mapstructure:"SERVER_ADDRESS"`
-	SymetricKey          string        `mapstructure:"SYMETRIC_KEY"`
-	TokenDuration        time.Duration `mapstructure:"TOKEN_DURATION"`
-	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	DBDriver                      string        `mapstructure:"DB_DRIVER"`
+	DBSource                      string        `mapstructure:"DB_SOURCE"`
+	ServerAddr                    string        `mapstructure:"SERVER_ADDRESS"`
+	GRPCServerAddr                string        `mapstructure:"GRPC_SERVER_ADDRESS"`
+	MetricsAddr                   string        `mapstructure:"METRICS_ADDRESS"`
+	SymetricKey                   string        `mapstructure:"SYMETRIC_KEY"`
+	TokenDuration                 time.Duration `mapstructure:"TOKEN_DURATION"`
+	RefreshTokenDuration          time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	LoginAttemptWindow            time.Duration `mapstructure:"LOGIN_ATTEMPT_WINDOW"`
+	LoginMaxAttempts              int64         `mapstructure:"LOGIN_MAX_ATTEMPTS"`
+	LoginMaxIPAttempts            int64         `mapstructure:"LOGIN_MAX_IP_ATTEMPTS"`
+	LoginBackoffBase              time.Duration `mapstructure:"LOGIN_BACKOFF_BASE"`
+	LoginLockoutDuration          time.Duration `mapstructure:"LOGIN_LOCKOUT_DURATION"`
+	MFAChallengeDuration          time.Duration `mapstructure:"MFA_CHALLENGE_DURATION"`
+	StepUpThreshold               float64       `mapstructure:"STEP_UP_THRESHOLD"`
+	StepUpTokenDuration           time.Duration `mapstructure:"STEP_UP_TOKEN_DURATION"`
+	PasswordResetDuration         time.Duration `mapstructure:"PASSWORD_RESET_DURATION"`
+	PasswordResetWindow           time.Duration `mapstructure:"PASSWORD_RESET_WINDOW"`
+	PasswordResetMaxRequests      int64         `mapstructure:"PASSWORD_RESET_MAX_REQUESTS"`
+	PasswordResetMaxIPRequests    int64         `mapstructure:"PASSWORD_RESET_MAX_IP_REQUESTS"`
+	PasswordResetBackoffBase      time.Duration `mapstructure:"PASSWORD_RESET_BACKOFF_BASE"`
+	PasswordResetLockoutDuration  time.Duration `mapstructure:"PASSWORD_RESET_LOCKOUT_DURATION"`
+	MailerOutput                  string        `mapstructure:"MAILER_OUTPUT"`
+	EmailVerificationDuration     time.Duration `mapstructure:"EMAIL_VERIFICATION_DURATION"`
+	EmailVerificationResendLimit  int64         `mapstructure:"EMAIL_VERIFICATION_RESEND_LIMIT"`
+	EmailVerificationResendWindow time.Duration `mapstructure:"EMAIL_VERIFICATION_RESEND_WINDOW"`
+	RequireVerifiedEmailTransfers bool          `mapstructure:"REQUIRE_VERIFIED_EMAIL_TRANSFERS"`
+	PasswordMinLength             int           `mapstructure:"PASSWORD_MIN_LENGTH"`
+	PasswordRequireUpper          bool          `mapstructure:"PASSWORD_REQUIRE_UPPER"`
+	PasswordRequireLower          bool          `mapstructure:"PASSWORD_REQUIRE_LOWER"`
+	PasswordRequireDigit          bool          `mapstructure:"PASSWORD_REQUIRE_DIGIT"`
+	PasswordRequireSymbol         bool          `mapstructure:"PASSWORD_REQUIRE_SYMBOL"`
+	PasswordBannedListPath        string        `mapstructure:"PASSWORD_BANNED_LIST_PATH"`
+	DocsAssetsPath                string        `mapstructure:"DOCS_ASSETS_PATH"`
+	PasswordHashAlgorithm         string        `mapstructure:"PASSWORD_HASH_ALGORITHM"`
+	PasswordBcryptCost            int           `mapstructure:"PASSWORD_BCRYPT_COST"`
+	PasswordArgon2Time            uint32        `mapstructure:"PASSWORD_ARGON2_TIME"`
+	PasswordArgon2Memory          uint32        `mapstructure:"PASSWORD_ARGON2_MEMORY"`
+	PasswordArgon2Threads         uint8         `mapstructure:"PASSWORD_ARGON2_THREADS"`
+	KYCRequiredForAccounts        bool          `mapstructure:"KYC_REQUIRED_FOR_ACCOUNTS"`
+	KYCRequiredForTransfers       bool          `mapstructure:"KYC_REQUIRED_FOR_TRANSFERS"`
+	KYCUnverifiedMaxBalance       float64       `mapstructure:"KYC_UNVERIFIED_MAX_BALANCE"`
+	TransferRequestDuration       time.Duration `mapstructure:"TRANSFER_REQUEST_DURATION"`
+	TransferRequestSweepInterval  time.Duration `mapstructure:"TRANSFER_REQUEST_SWEEP_INTERVAL"`
+	PageDefaultSize               int32         `mapstructure:"PAGE_DEFAULT_SIZE"`
+	PageMaxSize                   int32         `mapstructure:"PAGE_MAX_SIZE"`
+	WebhookPollInterval           time.Duration `mapstructure:"WEBHOOK_POLL_INTERVAL"`
+	WebhookTimeout                time.Duration `mapstructure:"WEBHOOK_TIMEOUT"`
+	WebhookBatchSize              int32         `mapstructure:"WEBHOOK_BATCH_SIZE"`
+	WebhookMaxAttempts            int32         `mapstructure:"WEBHOOK_MAX_ATTEMPTS"`
+	WebhookBackoffBase            time.Duration `mapstructure:"WEBHOOK_BACKOFF_BASE"`
+	WebhookBackoffMax             time.Duration `mapstructure:"WEBHOOK_BACKOFF_MAX"`
+	OutboxRelayInterval           time.Duration `mapstructure:"OUTBOX_RELAY_INTERVAL"`
+	OutboxBatchSize               int32         `mapstructure:"OUTBOX_BATCH_SIZE"`
+	OutboxPublisher               string        `mapstructure:"OUTBOX_PUBLISHER"`
+	OutboxConsumer                string        `mapstructure:"OUTBOX_CONSUMER"`
+	OutboxFile                    string        `mapstructure:"OUTBOX_FILE"`
+	OutboxRetention               time.Duration `mapstructure:"OUTBOX_RETENTION"`
+	OutboxSweepInterval           time.Duration `mapstructure:"OUTBOX_SWEEP_INTERVAL"`
+	StreamPollInterval            time.Duration `mapstructure:"STREAM_POLL_INTERVAL"`
+	LogLevel                      string        `mapstructure:"LOG_LEVEL"`
+	ShutdownTimeout               time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
 }
 
 /*
@@ -0,0 +1,46 @@
+package util
+
+import "time"
+
+/*
+Returns how long the client still has to wait before trying again, given the failures recorded inside the attempt window.
+The wait doubles with every failure starting at the backoff base and becomes a full lockout once maxAttempts is reached.
+*/
+func LoginRetryAfter(failures int64, lastFailureAt time.Time, maxAttempts int64, config Config) time.Duration {
+	return RetryAfter(failures, lastFailureAt, maxAttempts, config.LoginBackoffBase, config.LoginLockoutDuration)
+}
+
+/*
+Returns how long the client still has to wait before asking for another password reset, given the requests recorded
+inside the reset window. Throttled like logins, with its own settings.
+*/
+func PasswordResetRetryAfter(requests int64, lastRequestAt time.Time, maxRequests int64, config Config) time.Duration {
+	return RetryAfter(requests, lastRequestAt, maxRequests, config.PasswordResetBackoffBase, config.PasswordResetLockoutDuration)
+}
+
+/*
+Returns how long to wait after the last of attempts before trying again. The wait doubles with every attempt starting at
+backoffBase, capped at lockout, and becomes the full lockout once maxAttempts is reached.
+*/
+func RetryAfter(attempts int64, lastAttemptAt time.Time, maxAttempts int64, backoffBase, lockout time.Duration) time.Duration {
+	if attempts == 0 {
+		return 0
+	}
+
+	var delay time.Duration
+	if maxAttempts > 0 && attempts >= maxAttempts {
+		delay = lockout
+	} else {
+		// keep the shift small enough not to overflow the duration
+		shift := attempts - 1
+		if shift > 20 {
+			shift = 20
+		}
+		delay = backoffBase << shift
+		if lockout > 0 && delay > lockout {
+			delay = lockout
+		}
+	}
+
+	return time.Until(lastAttemptAt.Add(delay))
+}
@@ -0,0 +1,48 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginRetryAfter(t *testing.T) {
+	config := Config{
+		LoginBackoffBase:     time.Second,
+		LoginLockoutDuration: time.Minute,
+	}
+
+	testCases := []struct {
+		name     string
+		failures int64
+		expected time.Duration
+	}{
+		{name: "NoFailures", failures: 0, expected: 0},
+		{name: "FirstFailure", failures: 1, expected: time.Second},
+		{name: "Backoff", failures: 4, expected: 8 * time.Second},
+		{name: "CappedBackoff", failures: 9, expected: time.Minute},
+		{name: "Lockout", failures: 10, expected: time.Minute},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			wait := LoginRetryAfter(tc.failures, time.Now(), 10, config)
+			require.InDelta(t, tc.expected, wait, float64(100*time.Millisecond))
+		})
+	}
+}
+
+func TestPasswordResetRetryAfter(t *testing.T) {
+	// Reset requests have their own settings, the login ones don't apply
+	config := Config{
+		LoginBackoffBase:             time.Second,
+		LoginLockoutDuration:         time.Minute,
+		PasswordResetBackoffBase:     time.Minute,
+		PasswordResetLockoutDuration: time.Hour,
+	}
+
+	require.InDelta(t, time.Minute, PasswordResetRetryAfter(1, time.Now(), 3, config), float64(100*time.Millisecond))
+	require.InDelta(t, 2*time.Minute, PasswordResetRetryAfter(2, time.Now(), 3, config), float64(100*time.Millisecond))
+	require.InDelta(t, time.Hour, PasswordResetRetryAfter(3, time.Now(), 3, config), float64(100*time.Millisecond))
+}
@@ -0,0 +1,108 @@
+package util
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidCursor    = errors.New("pagination cursor is invalid")
+	ErrPageSizeTooLarge = errors.New("page size is too large")
+)
+
+/*
+Position of a row in a list ordered by (created_at, id). Clients get it as an opaque string and send it back to
+move to the next or previous page.
+*/
+type PageCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"i"`
+	Before    bool      `json:"b,omitempty"` // Pages backwards, to the rows before this one
+}
+
+/*
+Reports whether the cursor points at the start of the list, the first page has no cursor
+*/
+func (cur PageCursor) IsZero() bool {
+	return cur.ID == uuid.Nil
+}
+
+func (cur PageCursor) Encode() string {
+	data, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+/*
+Decodes a cursor sent by a client, an empty string is the first page
+*/
+func DecodePageCursor(s string) (cur PageCursor, err error) {
+	if s == "" {
+		return
+	}
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cur, ErrInvalidCursor
+	}
+	err = json.Unmarshal(data, &cur)
+	if err != nil || cur.IsZero() {
+		return PageCursor{}, ErrInvalidCursor
+	}
+	return
+}
+
+/*
+Resolves the page size a client asked for, zero means the default size
+*/
+func PageSize(requested, defaultSize, maxSize int32) (int32, error) {
+	if requested <= 0 {
+		return defaultSize, nil
+	}
+	if requested > maxSize {
+		return 0, fmt.Errorf("%w, the maximum is %d", ErrPageSizeTooLarge, maxSize)
+	}
+	return requested, nil
+}
+
+/*
+Builds a page out of rows fetched with a limit of size+1 in the direction of the cursor. Rows fetched backwards come
+in reverse order and are returned in list order. The extra row only tells whether there's another page, it's never
+returned. Next and prev are nil when there's no page in that direction.
+*/
+func Paginate[T any](rows []T, size int32, cur PageCursor, key func(T) PageCursor) (page []T, next, prev *PageCursor) {
+	more := len(rows) > int(size)
+	if more {
+		rows = rows[:size]
+	}
+	if cur.Before {
+		slices.Reverse(rows)
+	}
+	if len(rows) == 0 {
+		return rows, nil, nil
+	}
+
+	first, last := key(rows[0]), key(rows[len(rows)-1])
+	first.Before = true
+	last.Before = false
+
+	// Coming from a cursor means there are rows on the side it came from
+	if cur.Before {
+		next = &last
+		if more {
+			prev = &first
+		}
+	} else {
+		if more {
+			next = &last
+		}
+		if !cur.IsZero() {
+			prev = &first
+		}
+	}
+	return rows, next, prev
+}
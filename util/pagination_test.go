@@ -0,0 +1,91 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageCursor(t *testing.T) {
+	cur := PageCursor{CreatedAt: time.Now().UTC().Truncate(time.Microsecond), ID: uuid.New(), Before: true}
+
+	decoded, err := DecodePageCursor(cur.Encode())
+	require.NoError(t, err)
+	require.Equal(t, cur, decoded)
+
+	decoded, err = DecodePageCursor("")
+	require.NoError(t, err)
+	require.True(t, decoded.IsZero())
+
+	_, err = DecodePageCursor("not a cursor")
+	require.ErrorIs(t, err, ErrInvalidCursor)
+
+	_, err = DecodePageCursor(PageCursor{CreatedAt: time.Now()}.Encode())
+	require.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestPageSize(t *testing.T) {
+	size, err := PageSize(0, 20, 100)
+	require.NoError(t, err)
+	require.Equal(t, int32(20), size)
+
+	size, err = PageSize(100, 20, 100)
+	require.NoError(t, err)
+	require.Equal(t, int32(100), size)
+
+	_, err = PageSize(101, 20, 100)
+	require.ErrorIs(t, err, ErrPageSizeTooLarge)
+}
+
+func TestPaginate(t *testing.T) {
+	rows := make([]PageCursor, 5)
+	for i := range rows {
+		rows[i] = PageCursor{CreatedAt: time.Now().Add(time.Duration(i) * time.Second), ID: uuid.New()}
+	}
+	key := func(row PageCursor) PageCursor { return row }
+	backwards := func(rows []PageCursor) []PageCursor {
+		out := make([]PageCursor, len(rows))
+		for i, row := range rows {
+			out[len(rows)-1-i] = row
+		}
+		return out
+	}
+
+	// First page, one row left over
+	page, next, prev := Paginate(rows[:3], 2, PageCursor{}, key)
+	require.Equal(t, rows[:2], page)
+	require.Equal(t, &rows[1], next)
+	require.Nil(t, prev)
+
+	// Last page going forward
+	page, next, prev = Paginate(rows[2:], 3, rows[1], key)
+	require.Equal(t, rows[2:], page)
+	require.Nil(t, next)
+	require.NotNil(t, prev)
+	require.Equal(t, rows[2].ID, prev.ID)
+	require.True(t, prev.Before)
+
+	// Going back from the last page, rows come newest first
+	before := rows[4]
+	before.Before = true
+	page, next, prev = Paginate(backwards(rows[1:4]), 2, before, key)
+	require.Equal(t, rows[2:4], page)
+	require.Equal(t, &rows[3], next)
+	require.NotNil(t, prev)
+	require.Equal(t, rows[2].ID, prev.ID)
+
+	// Going back to the first page
+	before = rows[2]
+	before.Before = true
+	page, next, prev = Paginate(backwards(rows[:2]), 2, before, key)
+	require.Equal(t, rows[:2], page)
+	require.Equal(t, &rows[1], next)
+	require.Nil(t, prev)
+
+	page, next, prev = Paginate([]PageCursor{}, 2, PageCursor{}, key)
+	require.Empty(t, page)
+	require.Nil(t, next)
+	require.Nil(t, prev)
+}
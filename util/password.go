@@ -1,21 +1,201 @@
 package util
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"strings"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// Returns the BCypt hash from a given password string
+// Supported password hashing algorithms
+const (
+	Argon2idAlgorithm = "argon2id"
+	BcryptAlgorithm   = "bcrypt"
+)
+
+// Same value bcrypt returns so callers comparing against it keep working for argon2id hashes
+var ErrPasswordMismatch = bcrypt.ErrMismatchedHashAndPassword
+
+var errUnknownPasswordHash = errors.New("unknown password hash format")
+
+/*
+Algorithm and parameters used to hash new passwords. Hashes are stored in a self describing format,
+the PHC string for argon2id ($argon2id$v=19$m=...,t=...,p=...$salt$key) and the usual $2a$ format for bcrypt,
+so stored hashes can be checked no matter which parameters were current when they were created.
+*/
+type PasswordHasher struct {
+	Algorithm     string
+	BcryptCost    int
+	Argon2Time    uint32
+	Argon2Memory  uint32 // KiB
+	Argon2Threads uint8
+	Argon2KeyLen  uint32
+	Argon2SaltLen uint32
+}
+
+/*
+Argon2id with the second recommended option from RFC 9106
+*/
+func DefaultPasswordHasher() PasswordHasher {
+	return PasswordHasher{
+		Algorithm:     Argon2idAlgorithm,
+		BcryptCost:    bcrypt.DefaultCost,
+		Argon2Time:    3,
+		Argon2Memory:  64 * 1024,
+		Argon2Threads: 4,
+		Argon2KeyLen:  32,
+		Argon2SaltLen: 16,
+	}
+}
+
+/*
+Builds the hasher from the configuration, unset values fall back to the defaults
+*/
+func NewPasswordHasher(config Config) (PasswordHasher, error) {
+	hasher := DefaultPasswordHasher()
+	if config.PasswordHashAlgorithm != "" {
+		hasher.Algorithm = config.PasswordHashAlgorithm
+	}
+	if config.PasswordBcryptCost != 0 {
+		hasher.BcryptCost = config.PasswordBcryptCost
+	}
+	if config.PasswordArgon2Time != 0 {
+		hasher.Argon2Time = config.PasswordArgon2Time
+	}
+	if config.PasswordArgon2Memory != 0 {
+		hasher.Argon2Memory = config.PasswordArgon2Memory
+	}
+	if config.PasswordArgon2Threads != 0 {
+		hasher.Argon2Threads = config.PasswordArgon2Threads
+	}
+
+	switch hasher.Algorithm {
+	case Argon2idAlgorithm:
+	case BcryptAlgorithm:
+		if hasher.BcryptCost < bcrypt.MinCost || hasher.BcryptCost > bcrypt.MaxCost {
+			return hasher, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	default:
+		return hasher, fmt.Errorf("unsupported password hash algorithm %q", hasher.Algorithm)
+	}
+	return hasher, nil
+}
+
+// Returns the hash of a given password string using the default hasher
 func HashPassword(pswd string) (string, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(pswd), bcrypt.DefaultCost)
+	return DefaultPasswordHasher().Hash(pswd)
+}
+
+// Returns the hash of a given password string with the hasher's algorithm and parameters
+func (h PasswordHasher) Hash(pswd string) (string, error) {
+	if h.Algorithm == BcryptAlgorithm {
+		hash, err := bcrypt.GenerateFromPassword([]byte(pswd), h.BcryptCost)
+		if err != nil {
+			return "", fmt.Errorf("failed to hash password: %w", err)
+		}
+		return string(hash), nil
+	}
+
+	salt := make([]byte, h.Argon2SaltLen)
+	_, err := rand.Read(salt)
 	if err != nil {
 		return "", fmt.Errorf("failed to hash password: %w", err)
 	}
-	return string(hash), nil
+	key := argon2.IDKey([]byte(pswd), salt, h.Argon2Time, h.Argon2Memory, h.Argon2Threads, h.Argon2KeyLen)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		h.Argon2Memory,
+		h.Argon2Time,
+		h.Argon2Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
 }
 
-// Checks if the provided password matches a given hash
+/*
+Reports whether a stored hash was made with a different algorithm or weaker parameters than the hasher's
+*/
+func (h PasswordHasher) NeedsRehash(hash string) bool {
+	if isBcryptHash(hash) {
+		if h.Algorithm != BcryptAlgorithm {
+			return true
+		}
+		cost, err := bcrypt.Cost([]byte(hash))
+		return err != nil || cost < h.BcryptCost
+	}
+
+	params, _, key, err := decodeArgon2idHash(hash)
+	if err != nil {
+		return true
+	}
+	if h.Algorithm != Argon2idAlgorithm {
+		return true
+	}
+	return params.Argon2Memory < h.Argon2Memory ||
+		params.Argon2Time < h.Argon2Time ||
+		params.Argon2Threads < h.Argon2Threads ||
+		uint32(len(key)) < h.Argon2KeyLen
+}
+
+// Checks if the provided password matches a given argon2id or bcrypt hash
 func CheckPassword(pswd, hash string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(pswd))
+	if isBcryptHash(hash) {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(pswd))
+	}
+
+	params, salt, key, err := decodeArgon2idHash(hash)
+	if err != nil {
+		return err
+	}
+	got := argon2.IDKey([]byte(pswd), salt, params.Argon2Time, params.Argon2Memory, params.Argon2Threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(got, key) != 1 {
+		return ErrPasswordMismatch
+	}
+	return nil
+}
+
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+/*
+Parses a PHC argon2id string into its parameters, salt and key
+*/
+func decodeArgon2idHash(hash string) (params PasswordHasher, salt, key []byte, err error) {
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != Argon2idAlgorithm {
+		return params, nil, nil, errUnknownPasswordHash
+	}
+
+	var version int
+	_, err = fmt.Sscanf(parts[2], "v=%d", &version)
+	if err != nil || version != argon2.Version {
+		return params, nil, nil, errUnknownPasswordHash
+	}
+
+	params.Algorithm = Argon2idAlgorithm
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Argon2Memory, &params.Argon2Time, &params.Argon2Threads)
+	if err != nil {
+		return params, nil, nil, errUnknownPasswordHash
+	}
+
+	salt, err = base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, errUnknownPasswordHash
+	}
+	key, err = base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, errUnknownPasswordHash
+	}
+	params.Argon2SaltLen = uint32(len(salt))
+	params.Argon2KeyLen = uint32(len(key))
+	return params, salt, key, nil
 }
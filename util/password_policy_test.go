@@ -0,0 +1,64 @@
+package util
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordPolicy(t *testing.T) {
+	listPath := filepath.Join(t.TempDir(), "banned.txt")
+	err := os.WriteFile(listPath, []byte("# comment\n\nP@ssw0rd1\nqwerty\n"), 0o600)
+	require.NoError(t, err)
+
+	policy, err := NewPasswordPolicy(Config{
+		PasswordMinLength:      8,
+		PasswordRequireUpper:   true,
+		PasswordRequireLower:   true,
+		PasswordRequireDigit:   true,
+		PasswordRequireSymbol:  true,
+		PasswordBannedListPath: listPath,
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		password   string
+		violations int
+	}{
+		{name: "OK", password: "c0rrect-Horse", violations: 0},
+		{name: "TooShort", password: "aB1!", violations: 1},
+		{name: "MissingClasses", password: "abcdefghij", violations: 3},
+		{name: "Banned", password: "p@SSW0RD1", violations: 1},
+		{name: "Empty", password: "", violations: 5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := policy.Validate(tc.password)
+			if tc.violations == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			var policyErr *PasswordPolicyError
+			require.True(t, errors.As(err, &policyErr))
+			require.Len(t, policyErr.Violations, tc.violations)
+		})
+	}
+}
+
+func TestPasswordPolicyMissingList(t *testing.T) {
+	_, err := NewPasswordPolicy(Config{PasswordBannedListPath: filepath.Join(t.TempDir(), "missing.txt")})
+	require.Error(t, err)
+}
+
+func TestBannedPasswordsFile(t *testing.T) {
+	banned, err := LoadBannedPasswords("../assets/banned_passwords.txt")
+	require.NoError(t, err)
+	require.Contains(t, banned, "password")
+	require.Contains(t, banned, "password1")
+}
@@ -1,6 +1,7 @@
 package util
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -26,3 +27,59 @@ func TestPassword(t *testing.T) {
 	require.NotEmpty(t, hashedPassword2)
 	require.NotEqual(t, hashedPassword1, hashedPassword2)
 }
+
+func TestPasswordHasher(t *testing.T) {
+	password := RandomString(10)
+
+	argon := PasswordHasher{
+		Algorithm:     Argon2idAlgorithm,
+		Argon2Time:    1,
+		Argon2Memory:  1024,
+		Argon2Threads: 1,
+		Argon2KeyLen:  32,
+		Argon2SaltLen: 16,
+	}
+	argonHash, err := argon.Hash(password)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(argonHash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	require.NoError(t, CheckPassword(password, argonHash))
+	require.ErrorIs(t, CheckPassword(RandomString(10), argonHash), ErrPasswordMismatch)
+
+	bcryptHasher := PasswordHasher{Algorithm: BcryptAlgorithm, BcryptCost: bcrypt.MinCost}
+	bcryptHash, err := bcryptHasher.Hash(password)
+	require.NoError(t, err)
+	require.NoError(t, CheckPassword(password, bcryptHash))
+
+	// Weaker or different hashes get upgraded, equal or stronger ones don't
+	require.False(t, argon.NeedsRehash(argonHash))
+	require.True(t, argon.NeedsRehash(bcryptHash))
+	require.False(t, bcryptHasher.NeedsRehash(bcryptHash))
+	require.True(t, bcryptHasher.NeedsRehash(argonHash))
+
+	stronger := argon
+	stronger.Argon2Memory = 2048
+	require.True(t, stronger.NeedsRehash(argonHash))
+
+	weaker := argon
+	weaker.Argon2Time = 0
+	require.False(t, weaker.NeedsRehash(argonHash))
+
+	require.Error(t, CheckPassword(password, "not-a-hash"))
+}
+
+func TestNewPasswordHasher(t *testing.T) {
+	hasher, err := NewPasswordHasher(Config{})
+	require.NoError(t, err)
+	require.Equal(t, DefaultPasswordHasher(), hasher)
+
+	hasher, err = NewPasswordHasher(Config{PasswordHashAlgorithm: BcryptAlgorithm, PasswordBcryptCost: 12})
+	require.NoError(t, err)
+	require.Equal(t, BcryptAlgorithm, hasher.Algorithm)
+	require.Equal(t, 12, hasher.BcryptCost)
+
+	_, err = NewPasswordHasher(Config{PasswordHashAlgorithm: "md5"})
+	require.Error(t, err)
+
+	_, err = NewPasswordHasher(Config{PasswordHashAlgorithm: BcryptAlgorithm, PasswordBcryptCost: 99})
+	require.Error(t, err)
+}
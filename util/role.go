@@ -0,0 +1,47 @@
+package util
+
+const (
+	CustomerRole = "customer"
+	SupportRole  = "support"
+	AdminRole    = "admin"
+)
+
+// Scopes carried inside access tokens. Routes declare the scopes they require.
+const (
+	ScopeAccountsRead   = "accounts:read"
+	ScopeAccountsWrite  = "accounts:write"
+	ScopeAccountsFreeze = "accounts:freeze"
+	ScopeTransfersWrite = "transfers:write"
+	ScopeUsersRead      = "users:read"
+	ScopeUsersWrite     = "users:write"
+	ScopeReportsRead    = "reports:read"
+	ScopeKYCReview      = "kyc:review"
+)
+
+var roleScopes = map[string][]string{
+	CustomerRole: {ScopeAccountsRead, ScopeAccountsWrite, ScopeTransfersWrite},
+	SupportRole:  {ScopeAccountsRead, ScopeAccountsWrite, ScopeTransfersWrite, ScopeUsersRead, ScopeKYCReview},
+	AdminRole: {
+		ScopeAccountsRead,
+		ScopeAccountsWrite,
+		ScopeAccountsFreeze,
+		ScopeTransfersWrite,
+		ScopeUsersRead,
+		ScopeUsersWrite,
+		ScopeReportsRead,
+		ScopeKYCReview,
+	},
+}
+
+func IsSupportedRole(role string) bool {
+	_, ok := roleScopes[role]
+	return ok
+}
+
+// Returns the scopes granted to a role. Unknown roles get no scopes at all.
+func RoleScopes(role string) []string {
+	scopes := roleScopes[role]
+	res := make([]string, len(scopes))
+	copy(res, scopes)
+	return res
+}
@@ -0,0 +1,21 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecureToken(t *testing.T) {
+	token1, err := GenerateSecureToken(32)
+	require.NoError(t, err)
+	require.Len(t, token1, 43)
+
+	token2, err := GenerateSecureToken(32)
+	require.NoError(t, err)
+	require.NotEqual(t, token1, token2)
+
+	require.Equal(t, HashSecureToken(token1), HashSecureToken(token1))
+	require.NotEqual(t, HashSecureToken(token1), HashSecureToken(token2))
+	require.Len(t, HashSecureToken(token1), 64)
+}
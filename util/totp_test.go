@@ -0,0 +1,69 @@
+package util
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 appendix B SHA1 vectors, truncated to 6 digits
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	code, err := TOTPCode(secret, time.Unix(59, 0))
+	require.NoError(t, err)
+	require.Equal(t, "287082", code)
+
+	code, err = TOTPCode(secret, time.Unix(1111111109, 0))
+	require.NoError(t, err)
+	require.Equal(t, "081804", code)
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	require.NoError(t, err)
+	require.NotEmpty(t, secret)
+
+	now := time.Now()
+	code, err := TOTPCode(secret, now)
+	require.NoError(t, err)
+
+	require.True(t, ValidateTOTP(secret, code, now))
+	require.True(t, ValidateTOTP(secret, code, now.Add(totpPeriod)))
+	require.False(t, ValidateTOTP(secret, code, now.Add(5*totpPeriod)))
+	require.False(t, ValidateTOTP(secret, "12345", now))
+	require.False(t, ValidateTOTP("not base32!", code, now))
+}
+
+func TestMatchTOTP(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	step, ok := MatchTOTP(secret, "081804", time.Unix(1111111109, 0))
+	require.True(t, ok)
+	require.Equal(t, int64(1111111109/30), step)
+
+	// Codes of the previous step still match, with their own step
+	step, ok = MatchTOTP(secret, "081804", time.Unix(1111111109+30, 0))
+	require.True(t, ok)
+	require.Equal(t, int64(1111111109/30), step)
+
+	_, ok = MatchTOTP(secret, "000000", time.Unix(1111111109, 0))
+	require.False(t, ok)
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := TOTPURI("user", "SECRET")
+	require.True(t, strings.HasPrefix(uri, "otpauth://totp/the_simp_bank:user?"))
+	require.Contains(t, uri, "secret=SECRET")
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	require.NoError(t, err)
+	require.Len(t, codes, 10)
+	require.Len(t, codes[0], 11)
+	require.NotEqual(t, codes[0], codes[1])
+}
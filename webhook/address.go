@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrUnsafeURL     = errors.New("webhook URL must use https and point to a public address")
+	errUnsafeAddress = errors.New("receiver address is not public")
+)
+
+// Shared address space for carrier grade NAT, not covered by net.IP.IsPrivate
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// Checks that a webhook URL uses https and that every address its host resolves to is public, so receivers can't be
+// pointed at the bank's own network. Resolution can change later, the dispatcher checks the address again on dial.
+func CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return ErrUnsafeURL
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return fmt.Errorf("%w: unable to resolve %s", ErrUnsafeURL, u.Hostname())
+	}
+	for _, addr := range addrs {
+		if !publicIP(addr.IP) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrUnsafeURL, u.Hostname(), addr.IP)
+		}
+	}
+	return nil
+}
+
+// Reports whether an address is reachable on the public internet, rejecting loopback, private, unique local, link
+// local, shared and multicast addresses
+func publicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() ||
+		sharedAddressSpace.Contains(ip))
+}
+
+// Refuses connections to addresses that aren't public. Runs after resolution, right before connecting, so a host that
+// resolved to a public address at registration can't be switched to an internal one later.
+func dialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !publicIP(ip) {
+		return fmt.Errorf("%w: %s", errUnsafeAddress, address)
+	}
+	return nil
+}
+
+// Creates the client deliveries are sent with. It only connects to public addresses, never goes through a proxy and
+// doesn't follow redirects, a redirect answer counts as a failed delivery.
+func newClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: dialControl}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
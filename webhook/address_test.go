@@ -0,0 +1,49 @@
+package webhook
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckURL(t *testing.T) {
+	testCases := []struct {
+		url string
+		ok  bool
+	}{
+		{url: "https://93.184.215.14/hooks", ok: true},
+		{url: "http://93.184.215.14/hooks"},
+		{url: "ftp://93.184.215.14/hooks"},
+		{url: "https:///hooks"},
+		{url: "https://127.0.0.1/hooks"},
+		{url: "https://localhost/hooks"},
+		{url: "https://10.1.2.3/hooks"},
+		{url: "https://192.168.0.10:8443/hooks"},
+		{url: "https://169.254.169.254/latest/meta-data"},
+		{url: "https://100.64.0.1/hooks"},
+		{url: "https://[::1]/hooks"},
+		{url: "https://[fd00::1]/hooks"},
+		{url: "https://[fe80::1]/hooks"},
+		{url: "https://[::ffff:10.0.0.1]/hooks"},
+		{url: "https://0.0.0.0/hooks"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.url, func(t *testing.T) {
+			err := CheckURL(context.Background(), tc.url)
+			if tc.ok {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrUnsafeURL)
+		})
+	}
+}
+
+func TestDialControl(t *testing.T) {
+	require.NoError(t, dialControl("tcp", net.JoinHostPort("93.184.215.14", "443"), nil))
+	require.ErrorIs(t, dialControl("tcp", net.JoinHostPort("127.0.0.1", "443"), nil), errUnsafeAddress)
+	require.ErrorIs(t, dialControl("tcp6", net.JoinHostPort("fc00::1", "443"), nil), errUnsafeAddress)
+}
@@ -0,0 +1,204 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/outbox"
+	"github.com/julianinsua/the_simp_bank/util"
+)
+
+// Sends webhook deliveries. The dispatcher is a consumer of the outbox: it fans the account events out into
+// deliveries for the subscribed webhooks, then sends the due ones. Deliveries are claimed with a lease so several
+// dispatchers can send side by side, and a dispatcher that dies mid attempt only delays the delivery until the lease
+// runs out.
+type Dispatcher struct {
+	store  database.Store
+	relay  *outbox.Relay
+	client *http.Client
+	config util.Config
+}
+
+// Creates a Dispatcher whose requests time out after the configured webhook timeout and only reach public addresses
+func NewDispatcher(config util.Config, store database.Store) *Dispatcher {
+	return &Dispatcher{
+		store:  store,
+		relay:  outbox.NewRelay(config, store, OutboxConsumer, fanout{store: store}),
+		client: newClient(config.WebhookTimeout),
+		config: config,
+	}
+}
+
+// Fans out new outbox events and delivers pending webhooks every poll interval until the context is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.config.WebhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Keep going while there are full batches waiting
+		for {
+			queued, err := d.FanOut(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "unable to fan out webhook events", "error", err)
+				break
+			}
+			if queued < int(d.config.OutboxBatchSize) {
+				break
+			}
+		}
+		for {
+			sent, err := d.DeliverPending(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "unable to deliver webhooks", "error", err)
+				break
+			}
+			if sent < int(d.config.WebhookBatchSize) {
+				break
+			}
+		}
+	}
+}
+
+// Queues the deliveries of the next batch of outbox events after the dispatcher offset, returns how many events were
+// read
+func (d *Dispatcher) FanOut(ctx context.Context) (int, error) {
+	return d.relay.RelayPending(ctx)
+}
+
+// Claims a batch of due deliveries and attempts each of them once, returns how many were attempted
+func (d *Dispatcher) DeliverPending(ctx context.Context) (int, error) {
+	deliveries, err := d.store.ClaimWebhookDeliveries(ctx, database.ClaimWebhookDeliveriesParams{
+		// Twice the request timeout leaves room to record the outcome before another dispatcher retries
+		LeaseUntil: time.Now().Add(2 * d.config.WebhookTimeout),
+		BatchSize:  d.config.WebhookBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	webhooks := map[uuid.UUID]database.Webhook{}
+	for _, delivery := range deliveries {
+		hook, ok := webhooks[delivery.WebhookID]
+		if !ok {
+			hook, err = d.store.GetWebhook(ctx, delivery.WebhookID)
+			if errors.Is(err, sql.ErrNoRows) {
+				// Deleted after the batch was claimed, its deliveries went with it
+				continue
+			}
+			if err != nil {
+				return 0, err
+			}
+			webhooks[hook.ID] = hook
+		}
+
+		err = d.deliver(ctx, hook, delivery)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// Makes a single delivery attempt and records its outcome
+func (d *Dispatcher) deliver(ctx context.Context, hook database.Webhook, delivery database.WebhookDelivery) error {
+	code, sendErr := d.send(ctx, hook, delivery)
+	if sendErr != nil {
+		slog.WarnContext(ctx, "webhook delivery failed", "delivery", delivery.ID, "error", sendErr)
+	}
+	responseCode := sql.NullInt32{Int32: int32(code), Valid: code != 0}
+	if sendErr == nil {
+		return d.store.MarkWebhookDelivered(ctx, database.MarkWebhookDeliveredParams{
+			ID:           delivery.ID,
+			ResponseCode: responseCode,
+		})
+	}
+
+	status := database.WebhookDeliveryPending
+	if delivery.Attempts >= d.config.WebhookMaxAttempts {
+		status = database.WebhookDeliveryFailed
+	}
+	return d.store.MarkWebhookDeliveryFailed(ctx, database.MarkWebhookDeliveryFailedParams{
+		ID:            delivery.ID,
+		Status:        status,
+		ResponseCode:  responseCode,
+		LastError:     sql.NullString{String: failureMessage(code, sendErr), Valid: true},
+		NextAttemptAt: time.Now().Add(RetryDelay(delivery.Attempts, d.config)),
+	})
+}
+
+// Posts the payload to the webhook URL, any 2xx answer counts as delivered
+func (d *Dispatcher) send(ctx context.Context, hook database.Webhook, delivery database.WebhookDelivery) (int, error) {
+	// Webhooks registered before https was required are never called over plain http
+	if u, err := url.Parse(hook.Url); err != nil || u.Scheme != "https" {
+		return 0, ErrUnsafeURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(EventIDHeader, delivery.EventID.String())
+	req.Header.Set(SignatureHeader, Sign(hook.Secret, time.Now(), delivery.Payload))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("receiver answered %s", res.Status)
+	}
+	return res.StatusCode, nil
+}
+
+// Describes a failed attempt for the delivery log. Users can read the log, so it never carries what the receiver
+// answered or what its host resolved to, only the kind of failure.
+func failureMessage(code int, err error) string {
+	var netErr net.Error
+	switch {
+	case code != 0:
+		return fmt.Sprintf("receiver answered with status %d", code)
+	case errors.Is(err, ErrUnsafeURL), errors.Is(err, errUnsafeAddress):
+		return "receiver address is not allowed"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "request timed out"
+	}
+	return "unable to reach the receiver"
+}
+
+// Returns how long to wait before the next attempt after a failed one. The wait doubles with every attempt starting
+// at the backoff base, up to the backoff max.
+func RetryDelay(attempts int32, config util.Config) time.Duration {
+	// keep the shift small enough not to overflow the duration
+	shift := attempts - 1
+	if shift < 0 {
+		shift = 0
+	}
+	if shift > 20 {
+		shift = 20
+	}
+	delay := config.WebhookBackoffBase << shift
+	if config.WebhookBackoffMax > 0 && delay > config.WebhookBackoffMax {
+		delay = config.WebhookBackoffMax
+	}
+	return delay
+}
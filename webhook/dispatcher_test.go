@@ -0,0 +1,218 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig() util.Config {
+	return util.Config{
+		WebhookTimeout:     time.Second,
+		WebhookBatchSize:   10,
+		WebhookMaxAttempts: 3,
+		WebhookBackoffBase: time.Second,
+		WebhookBackoffMax:  time.Minute,
+	}
+}
+
+func TestDeliverPending(t *testing.T) {
+	hook := database.Webhook{
+		ID:         uuid.New(),
+		Username:   util.RandomOwner(),
+		Secret:     util.RandomString(32),
+		EventTypes: []string{database.EventTransferCreated},
+	}
+	newDelivery := func(attempts int32) database.WebhookDelivery {
+		return database.WebhookDelivery{
+			ID:        uuid.New(),
+			WebhookID: hook.ID,
+			EventID:   uuid.New(),
+			EventType: database.EventTransferCreated,
+			Payload:   json.RawMessage(`{"type":"transfer.created"}`),
+			Status:    database.WebhookDeliveryPending,
+			Attempts:  attempts,
+		}
+	}
+
+	testCases := []struct {
+		name       string
+		status     int
+		delivery   database.WebhookDelivery
+		buildStubs func(store *mock_db.MockStore, delivery database.WebhookDelivery)
+	}{
+		{
+			name:     "Delivered",
+			status:   http.StatusNoContent,
+			delivery: newDelivery(1),
+			buildStubs: func(store *mock_db.MockStore, delivery database.WebhookDelivery) {
+				store.EXPECT().
+					MarkWebhookDelivered(gomock.Any(), gomock.Eq(database.MarkWebhookDeliveredParams{
+						ID:           delivery.ID,
+						ResponseCode: sql.NullInt32{Int32: http.StatusNoContent, Valid: true},
+					})).
+					Times(1).
+					Return(nil)
+			},
+		},
+		{
+			name:     "Retried",
+			status:   http.StatusInternalServerError,
+			delivery: newDelivery(2),
+			buildStubs: func(store *mock_db.MockStore, delivery database.WebhookDelivery) {
+				store.EXPECT().
+					MarkWebhookDeliveryFailed(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, params database.MarkWebhookDeliveryFailedParams) error {
+						require.Equal(t, delivery.ID, params.ID)
+						require.Equal(t, database.WebhookDeliveryPending, params.Status)
+						require.Equal(t, int32(http.StatusInternalServerError), params.ResponseCode.Int32)
+						require.Contains(t, params.LastError.String, "500")
+						require.NotContains(t, params.LastError.String, "stack trace")
+						require.WithinDuration(t, time.Now().Add(2*time.Second), params.NextAttemptAt, time.Second)
+						return nil
+					})
+			},
+		},
+		{
+			name:     "GivenUp",
+			status:   http.StatusBadRequest,
+			delivery: newDelivery(3),
+			buildStubs: func(store *mock_db.MockStore, delivery database.WebhookDelivery) {
+				store.EXPECT().
+					MarkWebhookDeliveryFailed(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, params database.MarkWebhookDeliveryFailedParams) error {
+						require.Equal(t, database.WebhookDeliveryFailed, params.Status)
+						return nil
+					})
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			receiver := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.Equal(t, []byte(tc.delivery.Payload), body)
+				require.Equal(t, tc.delivery.EventType, r.Header.Get(EventHeader))
+				require.Equal(t, tc.delivery.EventID.String(), r.Header.Get(EventIDHeader))
+				require.NoError(t, Verify(hook.Secret, r.Header.Get(SignatureHeader), body, time.Minute))
+				w.WriteHeader(tc.status)
+				w.Write([]byte("stack trace of the receiver"))
+			}))
+			defer receiver.Close()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mock_db.NewMockStore(ctrl)
+			store.EXPECT().
+				ClaimWebhookDeliveries(gomock.Any(), gomock.Any()).
+				Times(1).
+				Return([]database.WebhookDelivery{tc.delivery}, nil)
+			receiverHook := hook
+			receiverHook.Url = receiver.URL
+			store.EXPECT().GetWebhook(gomock.Any(), gomock.Eq(hook.ID)).Times(1).Return(receiverHook, nil)
+			tc.buildStubs(store, tc.delivery)
+
+			sent, err := receiverDispatcher(store, receiver).DeliverPending(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, 1, sent)
+		})
+	}
+}
+
+// Creates a dispatcher that trusts the test receiver and is allowed to reach it on loopback
+func receiverDispatcher(store database.Store, receiver *httptest.Server) *Dispatcher {
+	dispatcher := NewDispatcher(testConfig(), store)
+	dispatcher.client.Transport = receiver.Client().Transport
+	return dispatcher
+}
+
+func TestDeliverPendingRefused(t *testing.T) {
+	redirect := httptest.NewTLSServer(http.RedirectHandler("https://169.254.169.254/latest/meta-data", http.StatusFound))
+	defer redirect.Close()
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer plain.Close()
+
+	testCases := []struct {
+		name       string
+		url        string
+		dispatcher func(store database.Store) *Dispatcher
+		lastError  string
+		code       int32
+	}{
+		{
+			name: "LoopbackAddress",
+			url:  redirect.URL,
+			dispatcher: func(store database.Store) *Dispatcher {
+				return NewDispatcher(testConfig(), store)
+			},
+			lastError: "receiver address is not allowed",
+		},
+		{
+			name: "PlainHTTP",
+			url:  plain.URL,
+			dispatcher: func(store database.Store) *Dispatcher {
+				return receiverDispatcher(store, plain)
+			},
+			lastError: "receiver address is not allowed",
+		},
+		{
+			name: "Redirect",
+			url:  redirect.URL,
+			dispatcher: func(store database.Store) *Dispatcher {
+				return receiverDispatcher(store, redirect)
+			},
+			lastError: "receiver answered with status 302",
+			code:      http.StatusFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			hook := database.Webhook{ID: uuid.New(), Url: tc.url, Secret: util.RandomString(32)}
+			delivery := database.WebhookDelivery{ID: uuid.New(), WebhookID: hook.ID, Payload: json.RawMessage(`{}`), Attempts: 1}
+
+			store := mock_db.NewMockStore(ctrl)
+			store.EXPECT().ClaimWebhookDeliveries(gomock.Any(), gomock.Any()).Times(1).Return([]database.WebhookDelivery{delivery}, nil)
+			store.EXPECT().GetWebhook(gomock.Any(), gomock.Eq(hook.ID)).Times(1).Return(hook, nil)
+			store.EXPECT().
+				MarkWebhookDeliveryFailed(gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(_ context.Context, params database.MarkWebhookDeliveryFailedParams) error {
+					require.Equal(t, tc.lastError, params.LastError.String)
+					require.Equal(t, tc.code, params.ResponseCode.Int32)
+					return nil
+				})
+
+			_, err := tc.dispatcher(store).DeliverPending(context.Background())
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	config := testConfig()
+	require.Equal(t, time.Second, RetryDelay(1, config))
+	require.Equal(t, 4*time.Second, RetryDelay(3, config))
+	require.Equal(t, time.Minute, RetryDelay(10, config))
+	require.Equal(t, time.Minute, RetryDelay(100, config))
+}
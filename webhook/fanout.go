@@ -0,0 +1,64 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/outbox"
+)
+
+// Name the dispatcher keeps its outbox offset under
+const OutboxConsumer = "webhooks"
+
+// Turns outbox events into webhook deliveries for the holders of the account each event is about. Deliveries reuse
+// the ID of the outbox event, so a batch the relay publishes twice still queues each delivery once.
+type fanout struct {
+	store database.Store
+}
+
+func (f fanout) Publish(ctx context.Context, msgs []outbox.Message) error {
+	for _, msg := range msgs {
+		var err error
+		switch msg.Type {
+		case database.EventAccountCreated:
+			err = f.enqueue(ctx, msg, database.EventAccountCreated)
+		case database.EventBalanceChanged:
+			var data database.TransferEventData
+			err = json.Unmarshal(msg.Data, &data)
+			if err != nil {
+				return err
+			}
+			eventType := database.EventTransferReceived
+			if data.Account.ID == data.Transfer.FromAccountID {
+				eventType = database.EventTransferCreated
+			}
+			err = f.enqueue(ctx, msg, eventType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Queues a delivery of the event for every webhook subscribed to it among the accepted holders of the account
+func (f fanout) enqueue(ctx context.Context, msg outbox.Message, eventType string) error {
+	payload, err := json.Marshal(database.WebhookEvent{
+		ID:        msg.ID,
+		Type:      eventType,
+		CreatedAt: msg.CreatedAt.UTC(),
+		Data:      msg.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = f.store.CreateAccountWebhookDeliveries(ctx, database.CreateAccountWebhookDeliveriesParams{
+		EventID:   msg.ID,
+		EventType: eventType,
+		Payload:   payload,
+		AccountID: msg.AggregateID,
+	})
+	return err
+}
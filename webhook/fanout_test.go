@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_db "github.com/julianinsua/the_simp_bank/db/mock"
+	"github.com/julianinsua/the_simp_bank/internal/database"
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func outboxEvent(t *testing.T, id int64, eventType string, aggregateID uuid.UUID, data any) database.Outbox {
+	payload, err := json.Marshal(data)
+	require.NoError(t, err)
+	return database.Outbox{
+		ID:            id,
+		EventID:       uuid.New(),
+		EventType:     eventType,
+		AggregateType: database.AggregateAccount,
+		AggregateID:   aggregateID,
+		Payload:       payload,
+		TransactionID: 900,
+		CreatedAt:     time.Now(),
+	}
+}
+
+func TestFanOut(t *testing.T) {
+	config := testConfig()
+	config.OutboxBatchSize = 10
+
+	from := database.Account{ID: uuid.New(), Owner: util.RandomOwner(), Currency: util.USD}
+	to := database.Account{ID: uuid.New(), Owner: util.RandomOwner(), Currency: util.USD}
+	transfer := database.Transfer{ID: uuid.New(), FromAccountID: from.ID, ToAccountID: to.ID, Amount: 5}
+	events := []database.Outbox{
+		outboxEvent(t, 1, database.EventAccountCreated, to.ID, to),
+		outboxEvent(t, 2, database.EventTransferCreated, transfer.ID, database.TransferTxResult{Transfer: transfer}),
+		outboxEvent(t, 3, database.EventBalanceChanged, from.ID, database.TransferEventData{Transfer: transfer, Account: from}),
+		outboxEvent(t, 4, database.EventBalanceChanged, to.ID, database.TransferEventData{Transfer: transfer, Account: to}),
+		outboxEvent(t, 5, database.EventSessionCreated, uuid.New(), database.SessionEventData{}),
+	}
+	expected := []struct {
+		eventType string
+		accountID uuid.UUID
+		event     database.Outbox
+	}{
+		{database.EventAccountCreated, to.ID, events[0]},
+		{database.EventTransferCreated, from.ID, events[2]},
+		{database.EventTransferReceived, to.ID, events[3]},
+	}
+
+	ctrl := gomock.NewController(t)
+	store := mock_db.NewMockStore(ctrl)
+	store.EXPECT().GetOutboxOffset(gomock.Any(), gomock.Eq(OutboxConsumer)).Times(1).Return(database.OutboxOffset{}, sql.ErrNoRows)
+	store.EXPECT().ListOutboxEvents(gomock.Any(), gomock.Any()).Times(1).Return(events, nil)
+
+	var queued []database.CreateAccountWebhookDeliveriesParams
+	store.EXPECT().
+		CreateAccountWebhookDeliveries(gomock.Any(), gomock.Any()).
+		Times(len(expected)).
+		DoAndReturn(func(_ context.Context, params database.CreateAccountWebhookDeliveriesParams) (int64, error) {
+			queued = append(queued, params)
+			return 1, nil
+		})
+	store.EXPECT().
+		SetOutboxOffset(gomock.Any(), gomock.Eq(database.SetOutboxOffsetParams{Consumer: OutboxConsumer, TransactionID: 900, OutboxID: 5})).
+		Times(1).
+		Return(nil)
+
+	n, err := NewDispatcher(config, store).FanOut(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, len(events), n)
+
+	require.Len(t, queued, len(expected))
+	for i, want := range expected {
+		require.Equal(t, want.eventType, queued[i].EventType)
+		require.Equal(t, want.accountID, queued[i].AccountID)
+		// Deliveries take the ID of the outbox event, a batch relayed twice doesn't queue them twice
+		require.Equal(t, want.event.EventID, queued[i].EventID)
+
+		var body database.WebhookEvent
+		err = json.Unmarshal(queued[i].Payload, &body)
+		require.NoError(t, err)
+		require.Equal(t, want.event.EventID, body.ID)
+		require.Equal(t, want.eventType, body.Type)
+		require.JSONEq(t, string(want.event.Payload), string(mustMarshal(t, body.Data)))
+	}
+}
+
+func mustMarshal(t *testing.T, v any) []byte {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return data
+}
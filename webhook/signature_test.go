@@ -0,0 +1,25 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julianinsua/the_simp_bank/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignature(t *testing.T) {
+	secret := util.RandomString(32)
+	body := []byte(`{"type":"transfer.created"}`)
+
+	header := Sign(secret, time.Now(), body)
+	require.Regexp(t, `^t=\d+,v1=[0-9a-f]{64}$`, header)
+	require.NoError(t, Verify(secret, header, body, time.Minute))
+
+	require.ErrorIs(t, Verify(util.RandomString(32), header, body, time.Minute), ErrInvalidSignature)
+	require.ErrorIs(t, Verify(secret, header, []byte(`{"type":"transfer.received"}`), time.Minute), ErrInvalidSignature)
+	require.ErrorIs(t, Verify(secret, "v1=abc", body, time.Minute), ErrInvalidSignature)
+
+	old := Sign(secret, time.Now().Add(-time.Hour), body)
+	require.ErrorIs(t, Verify(secret, old, body, time.Minute), ErrSignatureExpired)
+}